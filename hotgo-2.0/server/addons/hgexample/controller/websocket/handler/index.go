@@ -23,3 +23,8 @@ func (c *cIndex) TestMessage(client *websocket.Client, req *websocket.WRequest)
 	// 将收到的消息原样发送给客户端
 	websocket.SendSuccess(client, req.Event, req.Data)
 }
+
+// Echo 请求消息，原样返回收到的数据
+func (c *cIndex) Echo(client *websocket.Client, req *websocket.WRequest) (data interface{}, err error) {
+	return req.Data, nil
+}
@@ -19,6 +19,7 @@ import (
 	"hotgo/internal/library/contexts"
 	"hotgo/internal/library/hgorm"
 	"hotgo/internal/library/hgorm/handler"
+	"hotgo/internal/library/storager"
 	"hotgo/internal/model/input/form"
 	"hotgo/utility/convert"
 	"hotgo/utility/excel"
@@ -33,6 +34,12 @@ func NewSysTable() *sSysTable {
 
 func init() {
 	service.RegisterSysTable(NewSysTable())
+
+	// 声明引用附件的字段，避免被附件回收任务删除
+	cols := dao.AddonHgexampleTable.Columns()
+	for _, column := range []string{cols.Content, cols.Image, cols.Images, cols.Attachfile, cols.Attachfiles, cols.Avatar} {
+		storager.RegisterRef(dao.AddonHgexampleTable.Table(), column, storager.RefTypeUrl)
+	}
 }
 
 // Model Orm模型
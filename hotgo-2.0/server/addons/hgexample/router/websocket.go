@@ -37,4 +37,9 @@ func WebSocket(ctx context.Context, group *ghttp.RouterGroup) {
 	ws.RegisterMsg(ws.EventHandlers{
 		"admin/addons/hgexample/testMessage": handler.Index.TestMessage, // 测试消息
 	})
+
+	// 注册请求消息
+	ws.RegisterRequest(ws.RequestHandlers{
+		"admin/addons/hgexample/echo": handler.Index.Echo, // 测试请求
+	})
 }
@@ -47,3 +47,31 @@ type ClearKindReq struct {
 }
 
 type ClearKindRes struct{}
+
+// UsageReq 获取存储用量
+type UsageReq struct {
+	g.Meta `path:"/attachment/usage" method:"get" tags:"附件" summary:"获取存储用量"`
+	sysin.AttachmentUsageInp
+}
+
+type UsageRes struct {
+	*sysin.AttachmentUsageModel
+}
+
+// MigrateReq 存储迁移
+type MigrateReq struct {
+	g.Meta `path:"/attachment/migrate" method:"post" tags:"附件" summary:"存储迁移"`
+	sysin.AttachmentMigrateInp
+}
+
+type MigrateRes struct{}
+
+// MigrateStatusReq 获取存储迁移进度
+type MigrateStatusReq struct {
+	g.Meta `path:"/attachment/migrateStatus" method:"get" tags:"附件" summary:"获取存储迁移进度"`
+	sysin.AttachmentMigrateStatusInp
+}
+
+type MigrateStatusRes struct {
+	*sysin.AttachmentMigrateStatusModel
+}
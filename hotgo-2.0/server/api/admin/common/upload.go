@@ -37,6 +37,24 @@ type UploadPartRes struct {
 	*sysin.UploadPartModel
 }
 
+// PresignUploadReq 申请直传
+type PresignUploadReq struct {
+	g.Meta `path:"/upload/presign" tags:"附件" method:"post" summary:"申请客户端直传"`
+	sysin.PresignUploadInp
+}
+
+type PresignUploadRes struct {
+	*sysin.PresignUploadModel
+}
+
+// CompleteUploadReq 直传完成
+type CompleteUploadReq struct {
+	g.Meta `path:"/upload/complete" tags:"附件" method:"post" summary:"客户端直传完成"`
+	sysin.CompleteUploadInp
+}
+
+type CompleteUploadRes *sysin.AttachmentListModel
+
 // ImageTransferStorageReq 图片链接转存
 type ImageTransferStorageReq struct {
 	g.Meta `path:"/upload/imageTransferStorage" tags:"附件" method:"post" summary:"图片链接转存"`
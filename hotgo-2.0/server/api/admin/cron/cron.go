@@ -91,3 +91,24 @@ type DispatchLogReq struct {
 type DispatchLogRes struct {
 	*sysin.DispatchLogModel
 }
+
+// LogListReq 执行记录列表
+type LogListReq struct {
+	g.Meta `path:"/cron/logList" method:"get" tags:"定时任务" summary:"获取执行记录列表"`
+	sysin.CronLogListInp
+}
+
+type LogListRes struct {
+	List []*sysin.CronLogListModel `json:"list"   dc:"数据列表"`
+	form.PageRes
+}
+
+// LogViewReq 执行记录详情
+type LogViewReq struct {
+	g.Meta `path:"/cron/logView" method:"get" tags:"定时任务" summary:"获取执行记录详情"`
+	sysin.CronLogViewInp
+}
+
+type LogViewRes struct {
+	*sysin.CronLogViewModel
+}
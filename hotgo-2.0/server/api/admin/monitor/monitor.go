@@ -10,6 +10,7 @@ import (
 	"github.com/gogf/gf/v2/os/gtime"
 	"hotgo/internal/library/network/tcp"
 	"hotgo/internal/model/input/form"
+	"hotgo/internal/websocket"
 )
 
 // UserOfflineReq 下线用户
@@ -31,11 +32,13 @@ type UserOnlineListReq struct {
 }
 
 type UserOnlineListRes struct {
-	List []*UserOnlineModel `json:"list"   description:"数据列表"`
+	List  []*UserOnlineModel    `json:"list"   description:"数据列表"`
+	Limit *websocket.LimitStats `json:"limit"  description:"超出限制统计"`
 	form.PageRes
 }
 
 type UserOnlineModel struct {
+	Node          string `json:"node"`          // 所在节点
 	ID            string `json:"id"`            // 连接唯一标识
 	IP            string `json:"ip"`            // 客户端IP
 	Os            string `json:"os"`            // 客户端系统名称
@@ -46,6 +49,8 @@ type UserOnlineModel struct {
 	UserId        int64  `json:"userId"`        // 用户ID
 	Username      string `json:"username"`      // 用户名
 	Avatar        string `json:"avatar"`        // 头像
+	RecvMsgs      int64  `json:"recvMsgs"`      // 已接收的消息数
+	Violations    int64  `json:"violations"`    // 超出限制次数
 }
 
 // NetOnlineListReq 获取在线服务列表
@@ -66,12 +71,15 @@ type NetOnlineListRes struct {
 
 type NetOnlineModel struct {
 	*tcp.AuthMeta
+	Node          string `json:"node"             description:"所在节点"`
 	Id            int64  `json:"id"               description:"连接ID"`
 	IsAuth        bool   `json:"isAuth"           description:"是否认证"`
 	Addr          string `json:"addr"             description:"登录地址"`
 	Port          string `json:"port"             description:"连接端口"`
 	FirstTime     int64  `json:"firstTime"        description:"首次连接时间"`
 	HeartbeatTime int64  `json:"heartbeatTime"    description:"上次心跳时间"`
+	QueuedBytes   int64  `json:"queuedBytes"      description:"待发送字节数"`
+	DroppedMsgs   int64  `json:"droppedMsgs"      description:"溢出丢弃消息数"`
 	LicenseId     int64  `json:"licenseId"        description:"许可ID"`
 	LicenseName   string `json:"licenseName"      description:"许可名称"`
 	LoginTimes    int64  `json:"loginTimes"       description:"许可累计登录次数"`
@@ -100,7 +108,8 @@ type RouteSelect struct {
 // NetOfflineReq 下线服务
 type NetOfflineReq struct {
 	g.Meta `path:"/monitor/netOffline" method:"post" tags:"在线服务" summary:"下线服务"`
-	Id     int64 `json:"id" v:"required#连接ID不能为空" description:"连接ID"`
+	Id     int64  `json:"id" v:"required#连接ID不能为空" description:"连接ID"`
+	Node   string `json:"node" description:"所在节点，为空时为当前节点"`
 }
 
 type NetOfflineRes struct{}
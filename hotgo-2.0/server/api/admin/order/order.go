@@ -91,3 +91,13 @@ type StatusReq struct {
 }
 
 type StatusRes struct{}
+
+// HistoryReq 获取充值订单流转记录
+type HistoryReq struct {
+	g.Meta `path:"/order/history" method:"get" tags:"充值订单" summary:"获取充值订单流转记录"`
+	adminin.OrderHistoryInp
+}
+
+type HistoryRes struct {
+	List []*adminin.OrderHistoryModel `json:"list"   dc:"数据列表"`
+}
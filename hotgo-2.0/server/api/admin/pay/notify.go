@@ -0,0 +1,43 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pay
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/model/input/form"
+	"hotgo/internal/model/input/payin"
+)
+
+// NotifyListReq 查询支付通知列表
+type NotifyListReq struct {
+	g.Meta `path:"/payNotify/list" method:"get" tags:"支付通知" summary:"获取支付通知列表"`
+	payin.PayNotifyListInp
+}
+
+type NotifyListRes struct {
+	form.PageRes
+	List []*payin.PayNotifyListModel `json:"list"   dc:"数据列表"`
+}
+
+// NotifyViewReq 获取支付通知详情
+type NotifyViewReq struct {
+	g.Meta `path:"/payNotify/view" method:"get" tags:"支付通知" summary:"获取支付通知详情"`
+	payin.PayNotifyViewInp
+}
+
+type NotifyViewRes struct {
+	*payin.PayNotifyViewModel
+}
+
+// NotifyReplayReq 重放支付通知
+type NotifyReplayReq struct {
+	g.Meta `path:"/payNotify/replay" method:"post" tags:"支付通知" summary:"使用存档报文重放处理失败的支付通知"`
+	payin.PayNotifyReplayInp
+}
+
+type NotifyReplayRes struct {
+	*payin.PayNotifyReplayModel
+}
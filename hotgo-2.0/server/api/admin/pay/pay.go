@@ -0,0 +1,29 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pay
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/model/input/payin"
+)
+
+// QueryOrderReq 查询支付订单
+type QueryOrderReq struct {
+	g.Meta `path:"/pay/queryOrder" method:"post" tags:"支付订单" summary:"主动查询第三方支付订单"`
+	payin.PayQueryOrderInp
+}
+
+type QueryOrderRes struct {
+	*payin.PayQueryOrderModel
+}
+
+// CloseOrderReq 关闭支付订单
+type CloseOrderReq struct {
+	g.Meta `path:"/pay/closeOrder" method:"post" tags:"支付订单" summary:"关闭第三方支付订单"`
+	payin.PayCloseOrderInp
+}
+
+type CloseOrderRes struct{}
@@ -0,0 +1,41 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pay
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/model/input/form"
+	"hotgo/internal/model/input/payin"
+)
+
+// ReconcileListReq 查询对账差异列表
+type ReconcileListReq struct {
+	g.Meta `path:"/payReconcile/list" method:"get" tags:"交易对账" summary:"获取对账差异列表"`
+	payin.PayReconcileListInp
+}
+
+type ReconcileListRes struct {
+	form.PageRes
+	List []*payin.PayReconcileListModel `json:"list"   dc:"数据列表"`
+}
+
+// ReconcileRunReq 执行对账
+type ReconcileRunReq struct {
+	g.Meta `path:"/payReconcile/run" method:"post" tags:"交易对账" summary:"下载第三方账单执行对账"`
+	payin.PayReconcileInp
+}
+
+type ReconcileRunRes struct {
+	*payin.PayReconcileModel
+}
+
+// ReconcileHandleReq 处理对账差异
+type ReconcileHandleReq struct {
+	g.Meta `path:"/payReconcile/handle" method:"post" tags:"交易对账" summary:"标记对账差异已处理"`
+	payin.PayReconcileHandleInp
+}
+
+type ReconcileHandleRes struct{}
@@ -31,3 +31,13 @@ type RefundExportReq struct {
 }
 
 type RefundExportRes struct{}
+
+// RefundQueryReq 查询第三方退款状态
+type RefundQueryReq struct {
+	g.Meta `path:"/payRefund/query" method:"post" tags:"交易退款" summary:"查询第三方退款状态"`
+	payin.PayRefundQueryInp
+}
+
+type RefundQueryRes struct {
+	*payin.PayRefundQueryModel
+}
@@ -0,0 +1,66 @@
+// Package queue
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package queue
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/model/input/form"
+	"hotgo/internal/model/input/sysin"
+)
+
+// StatsReq 获取主题统计
+type StatsReq struct {
+	g.Meta `path:"/queue/stats" method:"get" tags:"消息队列" summary:"获取主题统计"`
+}
+
+type StatsRes struct {
+	*sysin.QueueStatsModel
+}
+
+// ResetStatsReq 重置主题统计
+type ResetStatsReq struct {
+	g.Meta `path:"/queue/resetStats" method:"post" tags:"消息队列" summary:"重置主题统计"`
+	sysin.QueueResetStatsInp
+}
+
+type ResetStatsRes struct{}
+
+// FailListReq 获取失败消息列表
+type FailListReq struct {
+	g.Meta `path:"/queue/failList" method:"get" tags:"消息队列" summary:"获取失败消息列表"`
+	sysin.QueueFailListInp
+}
+
+type FailListRes struct {
+	form.PageRes
+	List []*sysin.QueueFailListModel `json:"list"   dc:"数据列表"`
+}
+
+// FailViewReq 获取失败消息详情
+type FailViewReq struct {
+	g.Meta `path:"/queue/failView" method:"get" tags:"消息队列" summary:"获取失败消息详情"`
+	sysin.QueueFailViewInp
+}
+
+type FailViewRes struct {
+	*sysin.QueueFailViewModel
+}
+
+// ReplayReq 重放失败消息
+type ReplayReq struct {
+	g.Meta `path:"/queue/replay" method:"post" tags:"消息队列" summary:"重放失败消息"`
+	sysin.QueueReplayInp
+}
+
+type ReplayRes struct{}
+
+// DiscardReq 丢弃失败消息
+type DiscardReq struct {
+	g.Meta `path:"/queue/discard" method:"post" tags:"消息队列" summary:"丢弃失败消息"`
+	sysin.QueueDiscardInp
+}
+
+type DiscardRes struct{}
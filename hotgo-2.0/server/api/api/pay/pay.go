@@ -14,4 +14,7 @@ type IPayV1 interface {
 	NotifyAliPay(ctx context.Context, req *v1.NotifyAliPayReq) (res *v1.NotifyAliPayRes, err error)
 	NotifyWxPay(ctx context.Context, req *v1.NotifyWxPayReq) (res *v1.NotifyWxPayRes, err error)
 	NotifyQQPay(ctx context.Context, req *v1.NotifyQQPayReq) (res *v1.NotifyQQPayRes, err error)
+	NotifyMockPay(ctx context.Context, req *v1.NotifyMockPayReq) (res *v1.NotifyMockPayRes, err error)
+	MockCashier(ctx context.Context, req *v1.MockCashierReq) (res *v1.MockCashierRes, err error)
+	MockSubmit(ctx context.Context, req *v1.MockSubmitReq) (res *v1.MockSubmitRes, err error)
 }
@@ -0,0 +1,31 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// MockCashierReq 模拟支付收银台
+type MockCashierReq struct {
+	g.Meta     `path:"/pay/mock/cashier" method:"get" tags:"模拟支付" summary:"模拟支付收银台"`
+	OutTradeNo string `json:"outTradeNo" v:"required#商户订单号不能为空" dc:"商户订单号"`
+}
+
+type MockCashierRes struct {
+	g.Meta `mime:"text/html" type:"string" example:"<html/>"`
+}
+
+// MockSubmitReq 提交模拟支付结果
+type MockSubmitReq struct {
+	g.Meta     `path:"/pay/mock/submit" method:"post" tags:"模拟支付" summary:"提交模拟支付结果"`
+	OutTradeNo string `json:"outTradeNo" v:"required#商户订单号不能为空" dc:"商户订单号"`
+	Action     string `json:"action"     v:"required|in:pay,fail,cancel,notify#操作不能为空|操作类型有误" dc:"操作：pay支付成功，fail支付失败，cancel取消支付，notify重新通知"`
+}
+
+type MockSubmitRes struct {
+	g.Meta `mime:"text/html" type:"string" example:"<html/>"`
+}
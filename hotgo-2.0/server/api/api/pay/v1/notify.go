@@ -39,3 +39,13 @@ type NotifyQQPayRes struct {
 	g.Meta `mime:"text/xml" type:"string"`
 	*payin.PayNotifyModel
 }
+
+// NotifyMockPayReq 模拟支付回调
+type NotifyMockPayReq struct {
+	g.Meta `path:"/pay/notify/mockpay" method:"post" tags:"支付异步通知" summary:"模拟支付回调"`
+}
+
+type NotifyMockPayRes struct {
+	g.Meta `mime:"text/html" type:"string" example:"success"`
+	*payin.PayNotifyModel
+}
@@ -0,0 +1,22 @@
+// Package storage
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// DownloadReq 下载本地文件
+type DownloadReq struct {
+	g.Meta  `path:"/storage/download" method:"get" tags:"存储" summary:"通过签名地址下载本地文件"`
+	Path    string `json:"path" v:"required#文件路径不能为空" dc:"文件路径"`
+	Expires int64  `json:"expires" v:"required#过期时间不能为空" dc:"过期时间戳"`
+	Sign    string `json:"sign" v:"required#签名不能为空" dc:"签名"`
+}
+
+type DownloadRes struct {
+	g.Meta `mime:"application/octet-stream" type:"string"`
+}
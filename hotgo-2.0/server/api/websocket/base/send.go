@@ -18,3 +18,12 @@ type SendToTagReq struct {
 
 type SendToTagRes struct {
 }
+
+// SendToChannelReq 发送频道消息
+type SendToChannelReq struct {
+	g.Meta `path:"/send/toChannel" method:"post" tags:"WebSocket" summary:"发送频道消息"`
+	websocketin.SendToChannelInp
+}
+
+type SendToChannelRes struct {
+}
@@ -38,6 +38,7 @@ var (
 		>> 释放casbin权限，用于清理无效的权限设置  [go run main.go tools -m=casbin -a1=refresh]
 		>> 打印所有打包的资源文件列表  [go run main.go tools -m=gres -a1=dump]
 		>> 打印指定打包的资源文件内容  [go run main.go tools -m=gres -a1=content -a2=resource/template/home/index.html]
+		>> 迁移附件存储驱动，-c为并发数，-del=1时迁移成功后删除源文件  [go run main.go tools -m=storage -a1=migrate -from=local -to=minio -c=4]
 		---------------------------------------------------------------------------------
 		升级更新
 		>> 修复菜单关系树  [go run main.go up -m=fix -a1=menuTree]
@@ -10,6 +10,7 @@ import (
 	"github.com/gogf/gf/v2/os/gctx"
 	"github.com/gogf/gf/v2/os/gproc"
 	"hotgo/internal/consts"
+	"hotgo/internal/library/queue"
 	"hotgo/utility/simple"
 	"os"
 	"sync"
@@ -39,6 +40,8 @@ func signalListen(ctx context.Context, handler ...gproc.SigHandler) {
 // 区别于服务收到退出信号后的处理，只会执行一次
 func serverCloseEvent(ctx context.Context) {
 	once.Do(func() {
+		// 先停止消费并等待处理中的消息完成，再由各驱动提交位点并断开连接
+		queue.StopConsumersListener(ctx)
 		simple.Event().Call(consts.EventServerClose, ctx)
 	})
 }
@@ -80,8 +80,10 @@ var (
 			// 加载ip访问黑名单
 			service.SysBlacklist().Load(ctx)
 
-			// 注册支付成功回调方法
-			service.Pay().RegisterNotifyCall()
+			// 检查支付成功回调依赖的消息队列
+			if err = service.Pay().CheckQueue(ctx); err != nil {
+				return err
+			}
 
 			serverWg.Add(1)
 
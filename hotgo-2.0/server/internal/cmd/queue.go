@@ -11,6 +11,7 @@ import (
 	"hotgo/internal/global"
 	"hotgo/internal/library/queue"
 	_ "hotgo/internal/queues"
+	"hotgo/internal/service"
 	"hotgo/utility/simple"
 )
 
@@ -29,6 +30,12 @@ var (
 				queue.Logger().Debug(ctx, "start queue consumer success..")
 			})
 
+			// 注册支付成功回调方法，回调由队列消费者执行
+			service.Pay().RegisterNotifyCall()
+
+			// 发件箱中继
+			service.SysQueue().StartOutboxRelay(ctx)
+
 			serverWg.Add(1)
 
 			// 信号监听
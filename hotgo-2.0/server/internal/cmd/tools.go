@@ -12,7 +12,9 @@ import (
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gcmd"
 	"github.com/gogf/gf/v2/os/gres"
+	"github.com/gogf/gf/v2/util/gconv"
 	"hotgo/internal/library/casbin"
+	"hotgo/internal/library/storager"
 )
 
 var (
@@ -39,6 +41,8 @@ var (
 				err = handleCasbin(ctx, args)
 			case "gres":
 				err = handleGRes(ctx, args)
+			case "storage":
+				err = handleStorage(ctx, args)
 			default:
 				err = gerror.Newf("tools method[%v] does not exist", method)
 			}
@@ -105,3 +109,36 @@ func handleGRes(ctx context.Context, args map[string]string) (err error) {
 	}
 	return
 }
+
+// handleStorage 存储工具
+func handleStorage(ctx context.Context, args map[string]string) (err error) {
+	a1, ok := args["a1"]
+	if !ok {
+		err = gerror.New("storage args cannot be empty.")
+		return
+	}
+
+	switch a1 {
+	case "migrate":
+		in := &storager.MigrateParams{
+			From:         args["from"],
+			To:           args["to"],
+			Concurrency:  gconv.Int(args["c"]),
+			DeleteSource: gconv.Bool(args["del"]),
+		}
+
+		res, err := storager.Migrate(ctx, in, func(progress *storager.MigrateProgress) {
+			g.Log().Infof(ctx, "storage migrate %v/%v, success:%v, failed:%v, skipped:%v", progress.Done, progress.Total, progress.Success, progress.Failed, progress.Skipped)
+		})
+		if err != nil {
+			return err
+		}
+
+		if res.Failed > 0 {
+			g.Log().Warningf(ctx, "storage migrate %v attachments failed, last error:%v, please run again to retry", res.Failed, res.LastError)
+		}
+	default:
+		err = gerror.Newf("handleStorage a1 is invalid, a1:%v", a1)
+	}
+	return
+}
@@ -7,7 +7,11 @@ package consts
 
 // cache
 const (
-	CacheToken           = "token"            // 登录token
-	CacheTokenBind       = "token_bind"       // 登录用户身份绑定
-	CacheMultipartUpload = "multipart_upload" // 分片上传
+	CacheToken             = "token"              // 登录token
+	CacheTokenBind         = "token_bind"         // 登录用户身份绑定
+	CacheMultipartUpload   = "multipart_upload"   // 分片上传
+	CacheDirectUpload      = "direct_upload"      // 客户端直传
+	CacheAttachmentGC      = "attachment_gc"      // 附件回收进度
+	CacheAttachmentMigrate = "attachment_migrate" // 附件存储迁移进度
+	CacheQuotaReserve      = "quota_reserve"      // 上传预占的存储容量
 )
@@ -15,3 +15,17 @@ const (
 	CronPolicyOnce   = 3   // 单次策略
 	CronPolicyTimes  = 4   // 多次策略
 )
+
+// 定时任务集群模式
+const (
+	CronClusterAll    = 1 // 所有节点都执行
+	CronClusterSingle = 2 // 每次触发只由一个节点执行
+	CronClusterPinned = 3 // 只在指定标签的节点执行
+)
+
+// 定时任务执行状态
+const (
+	CronLogStatusSuccess = 1 // 成功
+	CronLogStatusFailed  = 2 // 失败
+	CronLogStatusTimeout = 3 // 超时
+)
@@ -44,6 +44,17 @@ const (
 	OrderStatusReturnReject  = 9  // 拒绝退款
 )
 
+// 订单流转事件
+// 订单状态只能通过事件流转，允许的流转在订单状态机中声明
+
+const (
+	OrderEventPay          = "pay"           // 支付成功
+	OrderEventClose        = "close"         // 关闭订单
+	OrderEventApplyRefund  = "apply_refund"  // 申请退款
+	OrderEventAcceptRefund = "accept_refund" // 同意退款
+	OrderEventRejectRefund = "reject_refund" // 拒绝退款
+)
+
 // OrderStatusOptions 订单状态选项
 var OrderStatusOptions = []*model.Option{
 	dict.GenInfoOption(OrderStatusALL, "全部"),
@@ -15,25 +15,30 @@ import (
 func init() {
 	dict.RegisterEnums("payType", "支付方式", PayTypeOptions)
 	dict.RegisterEnums("payStatus", "支付状态", PayStatusOptions)
+	dict.RegisterEnums("payReconcileType", "对账差异类型", PayReconcileTypeOptions)
+	dict.RegisterEnums("payReconcileStatus", "对账处理状态", PayReconcileStatusOptions)
+	dict.RegisterEnums("payNotifyStatus", "支付通知处理状态", PayNotifyStatusOptions)
 }
 
 const (
-	PayTypeALL    = ""       // 全部
-	PayTypeWxPay  = "wxpay"  // 微信支付
-	PayTypeAliPay = "alipay" // 支付宝
-	PayTypeQQPay  = "qqpay"  // QQ支付
+	PayTypeALL     = ""        // 全部
+	PayTypeWxPay   = "wxpay"   // 微信支付
+	PayTypeAliPay  = "alipay"  // 支付宝
+	PayTypeQQPay   = "qqpay"   // QQ支付
+	PayTypeMockPay = "mockpay" // 模拟支付，仅用于本地开发和自动化测试
 )
 
 var (
 	PayTypeSlice = []string{
-		PayTypeWxPay, PayTypeAliPay, PayTypeQQPay,
+		PayTypeWxPay, PayTypeAliPay, PayTypeQQPay, PayTypeMockPay,
 	}
 
 	PayTypeNameMap = map[string]string{
-		PayTypeALL:    "全部",
-		PayTypeWxPay:  "微信支付",
-		PayTypeAliPay: "支付宝",
-		PayTypeQQPay:  "QQ支付",
+		PayTypeALL:     "全部",
+		PayTypeWxPay:   "微信支付",
+		PayTypeAliPay:  "支付宝",
+		PayTypeQQPay:   "QQ支付",
+		PayTypeMockPay: "模拟支付",
 	}
 )
 
@@ -58,25 +63,101 @@ const (
 	// QQ
 	TradeTypeQQWeb = "qqweb" // PC网页
 	TradeTypeQQWap = "qqwap" // 移动端
+
+	// 模拟支付
+	TradeTypeMock = "mock" // 本地收银台
 )
 
 var (
-	TradeTypeWxSlice  = []string{TradeTypeWxMP, TradeTypeWxMini, TradeTypeWxApp, TradeTypeWxScan, TradeTypeWxPos, TradeTypeWxH5}
-	TradeTypeAliSlice = []string{TradeTypeAliWeb, TradeTypeAliApp, TradeTypeAliScan, TradeTypeAliWap, TradeTypeAliPos}
-	TradeTypeQQSlice  = []string{TradeTypeQQWeb, TradeTypeQQWap}
+	TradeTypeWxSlice   = []string{TradeTypeWxMP, TradeTypeWxMini, TradeTypeWxApp, TradeTypeWxScan, TradeTypeWxPos, TradeTypeWxH5}
+	TradeTypeAliSlice  = []string{TradeTypeAliWeb, TradeTypeAliApp, TradeTypeAliScan, TradeTypeAliWap, TradeTypeAliPos}
+	TradeTypeQQSlice   = []string{TradeTypeQQWeb, TradeTypeQQWap}
+	TradeTypeMockSlice = []string{TradeTypeMock}
 )
 
 // 支付状态
 
 const (
-	PayStatusWait = 1 // 待支付
-	PayStatusOk   = 2 // 已支付
+	PayStatusWait  = 1 // 待支付
+	PayStatusOk    = 2 // 已支付
+	PayStatusClose = 3 // 已关闭
 )
 
 // PayStatusOptions 支付状态选项
 var PayStatusOptions = []*model.Option{
 	dict.GenDefaultOption(PayStatusWait, "待支付"),
 	dict.GenSuccessOption(PayStatusOk, "已支付"),
+	dict.GenInfoOption(PayStatusClose, "已关闭"),
+}
+
+// 第三方交易状态，由各支付驱动转换为统一的状态
+
+const (
+	TradeStateWait     = "wait"     // 待支付
+	TradeStateSuccess  = "success"  // 支付成功
+	TradeStateClosed   = "closed"   // 已关闭
+	TradeStateRefund   = "refund"   // 转入退款
+	TradeStateNotExist = "notExist" // 交易不存在
+)
+
+// 第三方退款状态
+
+const (
+	RefundStateProcessing = "processing" // 退款处理中
+	RefundStateSuccess    = "success"    // 退款成功
+	RefundStateFail       = "fail"       // 退款失败
+)
+
+// 账单明细类型
+
+const (
+	BillKindPay    = "pay"    // 支付
+	BillKindRefund = "refund" // 退款
+)
+
+// 对账差异类型
+
+const (
+	PayReconcileTypeLocalMiss  = 1 // 本地缺失，渠道账单有记录，本地没有成功的支付或退款记录
+	PayReconcileTypeBillMiss   = 2 // 渠道缺失，本地有成功的支付或退款记录，渠道账单没有
+	PayReconcileTypeAmountDiff = 3 // 金额不一致
+)
+
+// 对账处理状态
+
+const (
+	PayReconcileStatusWait      = 1 // 待处理
+	PayReconcileStatusProcessed = 2 // 已处理
+)
+
+// PayReconcileTypeOptions 对账差异类型选项
+var PayReconcileTypeOptions = []*model.Option{
+	dict.GenWarningOption(PayReconcileTypeLocalMiss, "本地缺失"),
+	dict.GenWarningOption(PayReconcileTypeBillMiss, "渠道缺失"),
+	dict.GenErrorOption(PayReconcileTypeAmountDiff, "金额不一致"),
+}
+
+// PayReconcileStatusOptions 对账处理状态选项
+var PayReconcileStatusOptions = []*model.Option{
+	dict.GenWarningOption(PayReconcileStatusWait, "待处理"),
+	dict.GenSuccessOption(PayReconcileStatusProcessed, "已处理"),
+}
+
+// 支付通知处理状态
+
+const (
+	PayNotifyStatusWait      = 1 // 处理中
+	PayNotifyStatusSuccess   = 2 // 处理成功
+	PayNotifyStatusDuplicate = 3 // 重复通知，订单此前已处理成功
+	PayNotifyStatusFail      = 4 // 处理失败
+)
+
+// PayNotifyStatusOptions 支付通知处理状态选项
+var PayNotifyStatusOptions = []*model.Option{
+	dict.GenInfoOption(PayNotifyStatusWait, "处理中"),
+	dict.GenSuccessOption(PayNotifyStatusSuccess, "处理成功"),
+	dict.GenDefaultOption(PayNotifyStatusDuplicate, "重复通知"),
+	dict.GenErrorOption(PayNotifyStatusFail, "处理失败"),
 }
 
 // 退款状态
@@ -93,4 +174,5 @@ var PayTypeOptions = []*model.Option{
 	dict.GenSuccessOption(PayTypeWxPay, "微信支付"),
 	dict.GenInfoOption(PayTypeAliPay, "支付宝"),
 	dict.GenDefaultOption(PayTypeQQPay, "QQ支付"),
+	dict.GenWarningOption(PayTypeMockPay, "模拟支付"),
 }
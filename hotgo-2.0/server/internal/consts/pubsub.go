@@ -9,4 +9,5 @@ const (
 	ClusterSyncSysconfig     = "cluster.sync.sysConfig"    // 系统配置
 	ClusterSyncSysBlacklist  = "cluster.sync.sysBlacklist" // 系统黑名单
 	ClusterSyncSysSuperAdmin = "cluster.sync.superAdmin"   // 超管
+	ClusterSyncWebsocket     = "cluster.sync.websocket"    // websocket消息
 )
@@ -3,12 +3,45 @@
 // @Copyright  Copyright (c) 2023 HotGo CLI
 // @Author  Ms <133814250@qq.com>
 // @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
-//
 package consts
 
+import (
+	"hotgo/internal/library/dict"
+	"hotgo/internal/model"
+)
+
+func init() {
+	dict.RegisterEnums("queueFailStatus", "队列失败消息状态", QueueFailStatusOptions)
+}
+
 // 消息队列
 const (
-	QueueLogTopic      = `request_log` // 访问日志
-	QueueLoginLogTopic = `login_log`   // 登录日志
-	QueueServeLogTopic = `serve_log`   // 服务日志
+	QueueLogTopic       = `request_log` // 访问日志
+	QueueLoginLogTopic  = `login_log`   // 登录日志
+	QueueServeLogTopic  = `serve_log`   // 服务日志
+	QueueOrderTopic     = `order`       // 订单状态流转
+	QueuePayNotifyTopic = `pay_notify`  // 支付成功回调业务
+)
+
+// 队列失败消息状态
+const (
+	QueueFailStatusFailed    = 1 // 消费失败
+	QueueFailStatusDead      = 2 // 死信
+	QueueFailStatusReplayed  = 3 // 已重放
+	QueueFailStatusDiscarded = 4 // 已丢弃
 )
+
+// 队列发件箱消息状态
+const (
+	QueueOutboxStatusPending = 1 // 待投递
+	QueueOutboxStatusSending = 2 // 投递中
+	QueueOutboxStatusSent    = 3 // 已投递
+)
+
+// QueueFailStatusOptions 队列失败消息状态选项
+var QueueFailStatusOptions = []*model.Option{
+	dict.GenWarningOption(QueueFailStatusFailed, "消费失败"),
+	dict.GenErrorOption(QueueFailStatusDead, "死信"),
+	dict.GenSuccessOption(QueueFailStatusReplayed, "已重放"),
+	dict.GenInfoOption(QueueFailStatusDiscarded, "已丢弃"),
+}
@@ -13,6 +13,7 @@ import (
 	"hotgo/api/admin/monitor"
 	"hotgo/internal/consts"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/network/tcp"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/form"
 	"hotgo/internal/service"
@@ -23,45 +24,29 @@ import (
 )
 
 // Monitor 监控
-var Monitor = cMonitor{
-	wsManager: websocket.Manager(),
-}
+var Monitor = cMonitor{}
 
-type cMonitor struct {
-	wsManager *websocket.ClientManager
-}
+type cMonitor struct{}
 
-// UserOffline 下线用户
+// UserOffline 下线用户，连接在其他节点时由所在节点处理
 func (c *cMonitor) UserOffline(ctx context.Context, req *monitor.UserOfflineReq) (res *monitor.UserOfflineRes, err error) {
-	client := c.wsManager.GetClient(req.Id)
-	if client == nil {
-		err = gerror.New("客户端已离线")
-		return
-	}
-
-	simple.SafeGo(ctx, func(ctx context.Context) {
-		websocket.SendSuccess(client, "kick")
-		websocket.Close(client)
-	})
+	err = websocket.KickClient(req.Id)
 	return
 }
 
-// UserOnlineList 获取用户在线列表
+// UserOnlineList 获取用户在线列表，开启集群时包含所有节点的连接
 func (c *cMonitor) UserOnlineList(ctx context.Context, req *monitor.UserOnlineListReq) (res *monitor.UserOnlineListRes, err error) {
 	var (
 		clients []*monitor.UserOnlineModel
 		i       int
 	)
 
-	if c.wsManager.GetClientsLen() == 0 {
+	list, err := websocket.ClusterClients(ctx)
+	if err != nil {
 		return
 	}
 
-	for conn := range c.wsManager.GetClients() {
-		if conn.SendClose || conn.User == nil {
-			continue
-		}
-
+	for _, conn := range list {
 		if req.UserId > 0 && req.UserId != conn.User.Id {
 			continue
 		}
@@ -79,6 +64,7 @@ func (c *cMonitor) UserOnlineList(ctx context.Context, req *monitor.UserOnlineLi
 		}
 
 		clients = append(clients, &monitor.UserOnlineModel{
+			Node:          conn.Node,
 			ID:            conn.ID,
 			IP:            conn.IP,
 			Os:            useragent.GetOs(conn.UserAgent),
@@ -89,10 +75,15 @@ func (c *cMonitor) UserOnlineList(ctx context.Context, req *monitor.UserOnlineLi
 			UserId:        conn.User.Id,
 			Username:      conn.User.Username,
 			Avatar:        conn.User.Avatar,
+			RecvMsgs:      conn.RecvMsgs,
+			Violations:    conn.Violations,
 		})
 	}
 
 	res = new(monitor.UserOnlineListRes)
+	if res.Limit, err = websocket.ClusterLimitStats(ctx); err != nil {
+		return
+	}
 	res.PageRes.Pack(req, len(clients))
 
 	sort.Slice(clients, func(i, j int) bool {
@@ -118,34 +109,68 @@ func (c *cMonitor) UserOnlineList(ctx context.Context, req *monitor.UserOnlineLi
 // NetOnlineList 获取服务在线列表
 func (c *cMonitor) NetOnlineList(ctx context.Context, req *monitor.NetOnlineListReq) (res *monitor.NetOnlineListRes, err error) {
 	var (
+		items   []*monitor.NetOnlineModel
 		clients []*monitor.NetOnlineModel
 		i       int
 		cols    = dao.SysServeLicense.Columns()
 		serv    = service.TCPServer().Instance()
 		models  *entity.SysServeLicense
+		online  = make(map[string]int)
 	)
 
-	conns := serv.GetClients()
-	if len(conns) == 0 {
-		res = new(monitor.NetOnlineListRes)
-		res.PageRes.Pack(req, 0)
-		return
-	}
-
-	for _, conn := range conns {
-		v := &monitor.NetOnlineModel{
+	for _, conn := range serv.GetClients() {
+		stats := conn.WriteStats()
+		items = append(items, &monitor.NetOnlineModel{
 			AuthMeta:      conn.Auth,
+			Node:          serv.Node(),
 			Id:            conn.CID,
 			IsAuth:        conn.Auth != nil,
 			Addr:          conn.RemoteAddr().String(),
 			Port:          gstr.SubStrFromEx(conn.LocalAddr().String(), `:`),
 			FirstTime:     conn.FirstTime,
 			HeartbeatTime: conn.Heartbeat,
+			QueuedBytes:   stats.QueuedBytes,
+			DroppedMsgs:   stats.DroppedMsgs,
 			Proto:         "TCP",
+		})
+	}
+
+	// 集群中其他节点已认证的连接
+	if serv.IsCluster() {
+		remotes, err := serv.ClusterClients(ctx)
+		if err != nil {
+			return nil, err
 		}
 
+		for _, client := range remotes {
+			if serv.IsLocalClient(client) {
+				continue
+			}
+			items = append(items, &monitor.NetOnlineModel{
+				AuthMeta:      client.Auth,
+				Node:          client.Node,
+				Id:            client.CID,
+				IsAuth:        true,
+				Addr:          client.Addr,
+				Port:          gstr.SubStrFromEx(client.LocalAddr, `:`),
+				FirstTime:     client.FirstTime,
+				HeartbeatTime: client.Heartbeat,
+				QueuedBytes:   client.Write.QueuedBytes,
+				DroppedMsgs:   client.Write.DroppedMsgs,
+				Proto:         "TCP",
+			})
+		}
+	}
+
+	if len(items) == 0 {
+		res = new(monitor.NetOnlineListRes)
+		res.PageRes.Pack(req, 0)
+		return
+	}
+
+	for _, v := range items {
 		if v.IsAuth {
-			if err = dao.SysServeLicense.Ctx(ctx).Where(cols.Appid, conn.Auth.AppId).Where(cols.Group, conn.Auth.Group).Scan(&models); err != nil {
+			if err = dao.SysServeLicense.Ctx(ctx).Where(cols.Appid, v.AppId).Where(cols.Group, v.Group).Scan(&models); err != nil {
 				return
 			}
 
@@ -153,10 +178,14 @@ func (c *cMonitor) NetOnlineList(ctx context.Context, req *monitor.NetOnlineList
 				continue
 			}
 
+			if _, ok := online[models.Appid]; !ok {
+				online[models.Appid] = serv.ClusterAppIdOnline(ctx, models.Appid)
+			}
+
 			v.LicenseId = models.Id
 			v.LicenseName = models.Name
 			v.LoginTimes = models.LoginTimes
-			v.Online = serv.GetAppIdOnline(models.Appid)
+			v.Online = online[models.Appid]
 			v.OnlineLimit = models.OnlineLimit
 		}
 
@@ -176,7 +205,7 @@ func (c *cMonitor) NetOnlineList(ctx context.Context, req *monitor.NetOnlineList
 			continue
 		}
 
-		ft := gtime.New(conn.FirstTime)
+		ft := gtime.New(v.FirstTime)
 		if len(req.FirstTime) == 2 && (ft.Before(req.FirstTime[0]) || ft.After(req.FirstTime[1])) {
 			continue
 		}
@@ -187,6 +216,9 @@ func (c *cMonitor) NetOnlineList(ctx context.Context, req *monitor.NetOnlineList
 	res.PageRes.Pack(req, len(clients))
 
 	sort.Slice(clients, func(i, j int) bool {
+		if clients[i].FirstTime != clients[j].FirstTime {
+			return clients[i].FirstTime > clients[j].FirstTime
+		}
 		return clients[i].Id > clients[j].Id
 	})
 
@@ -232,12 +264,14 @@ func (c *cMonitor) NetOption(ctx context.Context, req *monitor.NetOptionReq) (re
 
 // NetOffline 下线服务
 func (c *cMonitor) NetOffline(ctx context.Context, req *monitor.NetOfflineReq) (res *monitor.NetOfflineRes, err error) {
-	conn := service.TCPServer().Instance().GetClientById(req.Id)
-	if conn == nil {
-		err = gerror.New("客户端不在线")
-		return
+	serv := service.TCPServer().Instance()
+	if req.Node == "" {
+		req.Node = serv.Node()
+	}
+
+	// 关闭连接，连接在其他节点时通过集群转发
+	if err = serv.CloseClient(ctx, &tcp.ClusterClient{Node: req.Node, CID: req.Id}); err != nil {
+		err = gerror.Wrap(err, "客户端不在线")
 	}
-	// 关闭连接
-	conn.Close()
 	return
 }
@@ -89,3 +89,15 @@ func (c *cOrder) Status(ctx context.Context, req *order.StatusReq) (res *order.S
 	err = service.AdminOrder().Status(ctx, &req.OrderStatusInp)
 	return
 }
+
+// History 获取充值订单流转记录
+func (c *cOrder) History(ctx context.Context, req *order.HistoryReq) (res *order.HistoryRes, err error) {
+	list, err := service.AdminOrder().History(ctx, &req.OrderHistoryInp)
+	if err != nil {
+		return
+	}
+
+	res = new(order.HistoryRes)
+	res.List = list
+	return
+}
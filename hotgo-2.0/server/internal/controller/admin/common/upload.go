@@ -58,6 +58,22 @@ func (c *cUpload) UploadPart(ctx context.Context, req *common.UploadPartReq) (re
 	return
 }
 
+// PresignUpload 申请客户端直传
+func (c *cUpload) PresignUpload(ctx context.Context, req *common.PresignUploadReq) (res *common.PresignUploadRes, err error) {
+	data, err := service.CommonUpload().PresignUpload(ctx, &req.PresignUploadInp)
+	if err != nil {
+		return nil, err
+	}
+	res = new(common.PresignUploadRes)
+	res.PresignUploadModel = data
+	return
+}
+
+// CompleteUpload 客户端直传完成
+func (c *cUpload) CompleteUpload(ctx context.Context, req *common.CompleteUploadReq) (res common.CompleteUploadRes, err error) {
+	return service.CommonUpload().CompleteUpload(ctx, &req.CompleteUploadInp)
+}
+
 // ImageTransferStorage 图片链接转存
 func (c *cUpload) ImageTransferStorage(ctx context.Context, req *common.ImageTransferStorageReq) (res *common.ImageTransferStorageRes, err error) {
 	res = new(common.ImageTransferStorageRes)
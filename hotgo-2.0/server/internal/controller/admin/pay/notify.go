@@ -0,0 +1,55 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pay
+
+import (
+	"context"
+	"hotgo/api/admin/pay"
+	"hotgo/internal/service"
+)
+
+var (
+	Notify = cNotify{}
+)
+
+type cNotify struct{}
+
+// List 查看支付通知列表
+func (c *cNotify) List(ctx context.Context, req *pay.NotifyListReq) (res *pay.NotifyListRes, err error) {
+	list, totalCount, err := service.PayNotify().List(ctx, &req.PayNotifyListInp)
+	if err != nil {
+		return
+	}
+
+	res = new(pay.NotifyListRes)
+	res.List = list
+	res.PageRes.Pack(req, totalCount)
+	return
+}
+
+// View 获取支付通知详情
+func (c *cNotify) View(ctx context.Context, req *pay.NotifyViewReq) (res *pay.NotifyViewRes, err error) {
+	data, err := service.PayNotify().View(ctx, &req.PayNotifyViewInp)
+	if err != nil {
+		return
+	}
+
+	res = new(pay.NotifyViewRes)
+	res.PayNotifyViewModel = data
+	return
+}
+
+// Replay 重放处理失败的支付通知
+func (c *cNotify) Replay(ctx context.Context, req *pay.NotifyReplayReq) (res *pay.NotifyReplayRes, err error) {
+	data, err := service.PayNotify().Replay(ctx, &req.PayNotifyReplayInp)
+	if err != nil {
+		return
+	}
+
+	res = new(pay.NotifyReplayRes)
+	res.PayNotifyReplayModel = data
+	return
+}
@@ -0,0 +1,36 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pay
+
+import (
+	"context"
+	"hotgo/api/admin/pay"
+	"hotgo/internal/service"
+)
+
+var (
+	Pay = cPay{}
+)
+
+type cPay struct{}
+
+// QueryOrder 主动查询第三方支付订单
+func (c *cPay) QueryOrder(ctx context.Context, req *pay.QueryOrderReq) (res *pay.QueryOrderRes, err error) {
+	data, err := service.Pay().QueryOrder(ctx, &req.PayQueryOrderInp)
+	if err != nil {
+		return
+	}
+
+	res = new(pay.QueryOrderRes)
+	res.PayQueryOrderModel = data
+	return
+}
+
+// CloseOrder 关闭第三方支付订单
+func (c *cPay) CloseOrder(ctx context.Context, req *pay.CloseOrderReq) (res *pay.CloseOrderRes, err error) {
+	err = service.Pay().CloseOrder(ctx, &req.PayCloseOrderInp)
+	return
+}
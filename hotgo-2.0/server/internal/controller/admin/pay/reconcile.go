@@ -0,0 +1,49 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pay
+
+import (
+	"context"
+	"hotgo/api/admin/pay"
+	"hotgo/internal/service"
+)
+
+var (
+	Reconcile = cReconcile{}
+)
+
+type cReconcile struct{}
+
+// List 查看对账差异列表
+func (c *cReconcile) List(ctx context.Context, req *pay.ReconcileListReq) (res *pay.ReconcileListRes, err error) {
+	list, totalCount, err := service.PayReconcile().List(ctx, &req.PayReconcileListInp)
+	if err != nil {
+		return
+	}
+
+	res = new(pay.ReconcileListRes)
+	res.List = list
+	res.PageRes.Pack(req, totalCount)
+	return
+}
+
+// Run 下载第三方账单执行对账
+func (c *cReconcile) Run(ctx context.Context, req *pay.ReconcileRunReq) (res *pay.ReconcileRunRes, err error) {
+	data, err := service.PayReconcile().Reconcile(ctx, &req.PayReconcileInp)
+	if err != nil {
+		return
+	}
+
+	res = new(pay.ReconcileRunRes)
+	res.PayReconcileModel = data
+	return
+}
+
+// Handle 标记对账差异已处理
+func (c *cReconcile) Handle(ctx context.Context, req *pay.ReconcileHandleReq) (res *pay.ReconcileHandleRes, err error) {
+	err = service.PayReconcile().Handle(ctx, &req.PayReconcileHandleInp)
+	return
+}
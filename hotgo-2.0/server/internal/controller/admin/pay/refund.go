@@ -37,3 +37,15 @@ func (c *cRefund) Export(ctx context.Context, req *pay.RefundExportReq) (res *pa
 	err = service.PayRefund().Export(ctx, &req.PayRefundListInp)
 	return
 }
+
+// Query 查询第三方退款状态
+func (c *cRefund) Query(ctx context.Context, req *pay.RefundQueryReq) (res *pay.RefundQueryRes, err error) {
+	data, err := service.PayRefund().Query(ctx, &req.PayRefundQueryInp)
+	if err != nil {
+		return
+	}
+
+	res = new(pay.RefundQueryRes)
+	res.PayRefundQueryModel = data
+	return
+}
@@ -53,3 +53,33 @@ func (c *cAttachment) ClearKind(ctx context.Context, req *attachment.ClearKindRe
 	err = service.SysAttachment().ClearKind(ctx, &req.AttachmentClearKindInp)
 	return
 }
+
+// Usage 获取存储用量
+func (c *cAttachment) Usage(ctx context.Context, req *attachment.UsageReq) (res *attachment.UsageRes, err error) {
+	data, err := service.SysAttachment().Usage(ctx, &req.AttachmentUsageInp)
+	if err != nil {
+		return
+	}
+
+	res = new(attachment.UsageRes)
+	res.AttachmentUsageModel = data
+	return
+}
+
+// Migrate 存储迁移
+func (c *cAttachment) Migrate(ctx context.Context, req *attachment.MigrateReq) (res *attachment.MigrateRes, err error) {
+	err = service.SysAttachment().Migrate(ctx, &req.AttachmentMigrateInp)
+	return
+}
+
+// MigrateStatus 获取存储迁移进度
+func (c *cAttachment) MigrateStatus(ctx context.Context, req *attachment.MigrateStatusReq) (res *attachment.MigrateStatusRes, err error) {
+	data, err := service.SysAttachment().MigrateStatus(ctx, &req.AttachmentMigrateStatusInp)
+	if err != nil {
+		return
+	}
+
+	res = new(attachment.MigrateStatusRes)
+	res.AttachmentMigrateStatusModel = data
+	return
+}
@@ -95,3 +95,28 @@ func (c *cCron) DispatchLog(ctx context.Context, req *cron.DispatchLogReq) (res
 	res.Log, err = service.TCPServer().DispatchLog(ctx, &servmsg.CronDispatchLogReq{DispatchLogInp: &req.DispatchLogInp})
 	return
 }
+
+// LogList 执行记录列表
+func (c *cCron) LogList(ctx context.Context, req *cron.LogListReq) (res *cron.LogListRes, err error) {
+	list, totalCount, err := service.SysCron().LogList(ctx, &req.CronLogListInp)
+	if err != nil {
+		return
+	}
+
+	res = new(cron.LogListRes)
+	res.List = list
+	res.PageRes.Pack(req, totalCount)
+	return
+}
+
+// LogView 执行记录详情
+func (c *cCron) LogView(ctx context.Context, req *cron.LogViewReq) (res *cron.LogViewRes, err error) {
+	data, err := service.SysCron().LogView(ctx, &req.CronLogViewInp)
+	if err != nil {
+		return
+	}
+
+	res = new(cron.LogViewRes)
+	res.CronLogViewModel = data
+	return
+}
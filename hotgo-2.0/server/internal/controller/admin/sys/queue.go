@@ -0,0 +1,73 @@
+// Package sys
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sys
+
+import (
+	"context"
+	"hotgo/api/admin/queue"
+	"hotgo/internal/service"
+)
+
+var (
+	Queue = cQueue{}
+)
+
+type cQueue struct{}
+
+// Stats 获取主题统计
+func (c *cQueue) Stats(ctx context.Context, req *queue.StatsReq) (res *queue.StatsRes, err error) {
+	data, err := service.SysQueue().Stats(ctx)
+	if err != nil {
+		return
+	}
+
+	res = new(queue.StatsRes)
+	res.QueueStatsModel = data
+	return
+}
+
+// ResetStats 重置主题统计
+func (c *cQueue) ResetStats(ctx context.Context, req *queue.ResetStatsReq) (res *queue.ResetStatsRes, err error) {
+	err = service.SysQueue().ResetStats(ctx, &req.QueueResetStatsInp)
+	return
+}
+
+// FailList 获取失败消息列表
+func (c *cQueue) FailList(ctx context.Context, req *queue.FailListReq) (res *queue.FailListRes, err error) {
+	list, totalCount, err := service.SysQueue().FailList(ctx, &req.QueueFailListInp)
+	if err != nil {
+		return
+	}
+
+	res = new(queue.FailListRes)
+	res.List = list
+	res.PageRes.Pack(req, totalCount)
+	return
+}
+
+// FailView 获取失败消息详情
+func (c *cQueue) FailView(ctx context.Context, req *queue.FailViewReq) (res *queue.FailViewRes, err error) {
+	data, err := service.SysQueue().FailView(ctx, &req.QueueFailViewInp)
+	if err != nil {
+		return
+	}
+
+	res = new(queue.FailViewRes)
+	res.QueueFailViewModel = data
+	return
+}
+
+// Replay 重放失败消息
+func (c *cQueue) Replay(ctx context.Context, req *queue.ReplayReq) (res *queue.ReplayRes, err error) {
+	err = service.SysQueue().Replay(ctx, &req.QueueReplayInp)
+	return
+}
+
+// Discard 丢弃失败消息
+func (c *cQueue) Discard(ctx context.Context, req *queue.DiscardReq) (res *queue.DiscardRes, err error) {
+	err = service.SysQueue().Discard(ctx, &req.QueueDiscardInp)
+	return
+}
@@ -0,0 +1,22 @@
+package pay
+
+import (
+	"context"
+
+	v1 "hotgo/api/api/pay/v1"
+	"hotgo/internal/library/payment"
+	"hotgo/internal/library/payment/mockpay"
+	"hotgo/internal/library/response"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+func (c *ControllerV1) MockCashier(ctx context.Context, req *v1.MockCashierReq) (res *v1.MockCashierRes, err error) {
+	html, err := mockpay.New(payment.GetConfig()).Cashier(ctx, req.OutTradeNo)
+	if err != nil {
+		return
+	}
+
+	response.RHtml(g.RequestFromCtx(ctx), html)
+	return
+}
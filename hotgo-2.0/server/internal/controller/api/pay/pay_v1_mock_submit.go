@@ -0,0 +1,22 @@
+package pay
+
+import (
+	"context"
+
+	v1 "hotgo/api/api/pay/v1"
+	"hotgo/internal/library/payment"
+	"hotgo/internal/library/payment/mockpay"
+	"hotgo/internal/library/response"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+func (c *ControllerV1) MockSubmit(ctx context.Context, req *v1.MockSubmitReq) (res *v1.MockSubmitRes, err error) {
+	html, err := mockpay.New(payment.GetConfig()).Submit(ctx, req.OutTradeNo, req.Action)
+	if err != nil {
+		return
+	}
+
+	response.RHtml(g.RequestFromCtx(ctx), html)
+	return
+}
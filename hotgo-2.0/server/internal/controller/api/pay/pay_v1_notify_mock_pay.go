@@ -0,0 +1,23 @@
+package pay
+
+import (
+	"context"
+
+	v1 "hotgo/api/api/pay/v1"
+	"hotgo/internal/consts"
+	"hotgo/internal/library/payment/mockpay"
+	"hotgo/internal/library/response"
+	"hotgo/internal/model/input/payin"
+	"hotgo/internal/service"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+func (c *ControllerV1) NotifyMockPay(ctx context.Context, req *v1.NotifyMockPayReq) (res *v1.NotifyMockPayRes, err error) {
+	if _, err = service.Pay().Notify(ctx, &payin.PayNotifyInp{PayType: consts.PayTypeMockPay}); err != nil {
+		return nil, err
+	}
+
+	response.RText(g.RequestFromCtx(ctx), mockpay.NotifySuccess)
+	return
+}
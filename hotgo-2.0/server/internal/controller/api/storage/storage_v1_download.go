@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+
+	v1 "hotgo/api/api/storage/v1"
+	"hotgo/internal/library/storager"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+func (c *ControllerV1) Download(ctx context.Context, req *v1.DownloadReq) (res *v1.DownloadRes, err error) {
+	if err = storager.VerifyLocalSignedURL(ctx, req.Path, req.Expires, req.Sign); err != nil {
+		return
+	}
+
+	filePath, err := storager.LocalFilePath(ctx, req.Path)
+	if err != nil {
+		return
+	}
+
+	r := g.RequestFromCtx(ctx)
+	if !gfile.IsFile(filePath) {
+		r.Response.WriteStatus(404)
+		return
+	}
+
+	r.Response.ServeFile(filePath)
+	return
+}
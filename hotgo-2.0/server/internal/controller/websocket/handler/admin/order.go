@@ -0,0 +1,39 @@
+// Package admin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package admin
+
+import (
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
+	"hotgo/internal/model/input/adminin"
+	"hotgo/internal/service"
+	"hotgo/internal/websocket"
+)
+
+var (
+	Order = cOrder{}
+)
+
+type cOrder struct{}
+
+// AuthChannel 订单频道鉴权，只能订阅有数据权限的订单，频道格式：order:订单ID
+func (c *cOrder) AuthChannel(client *websocket.Client, channel string) error {
+	id := gconv.Int64(gstr.TrimLeftStr(channel, "order:"))
+	if id <= 0 {
+		return gerror.New("订单ID不正确")
+	}
+
+	res, err := service.AdminOrder().View(client.Context(), &adminin.OrderViewInp{Id: id})
+	if err != nil {
+		return err
+	}
+
+	if res == nil {
+		return gerror.New("订单不存在或没有权限")
+	}
+	return nil
+}
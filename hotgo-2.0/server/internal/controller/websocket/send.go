@@ -27,3 +27,14 @@ func (c *send) SendToTag(ctx context.Context, req *base.SendToTagReq) (res *base
 	})
 	return
 }
+
+// SendToChannel 发送频道消息
+func (c *send) SendToChannel(ctx context.Context, req *base.SendToChannelReq) (res *base.SendToChannelRes, err error) {
+	simple.SafeGo(ctx, func(ctx context.Context) {
+		websocket.SendToChannel(req.Channel, &websocket.WResponse{
+			Event: req.Response.Event,
+			Data:  req.Response,
+		})
+	})
+	return
+}
@@ -0,0 +1,52 @@
+// Package crons
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package crons
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/util/gconv"
+	"hotgo/internal/library/cron"
+	"hotgo/internal/model/input/sysin"
+	"hotgo/internal/service"
+	"hotgo/utility/format"
+)
+
+func init() {
+	cron.Register(AttachmentGC)
+}
+
+// AttachmentGC 回收未引用附件
+// 参数：天数,单次处理数量，如：7,500
+var AttachmentGC = &cAttachmentGC{name: "attachment_gc"}
+
+type cAttachmentGC struct {
+	name string
+}
+
+func (c *cAttachmentGC) GetName() string {
+	return c.name
+}
+
+// Execute 执行任务
+func (c *cAttachmentGC) Execute(ctx context.Context, parser *cron.Parser) (err error) {
+	in := &sysin.AttachmentCollectInp{Days: 7}
+	if len(parser.Args) > 0 {
+		in.Days = gconv.Int(parser.Args[0])
+	}
+	if len(parser.Args) > 1 {
+		in.Limit = gconv.Int(parser.Args[1])
+	}
+
+	res, err := service.SysAttachment().Collect(ctx, in)
+	if err != nil {
+		parser.Logger.Warningf(ctx, "cron AttachmentGC Execute err:%+v", err)
+		return
+	}
+
+	parser.Logger.Infof(ctx, "cron AttachmentGC Execute scanned:%v, referenced:%v, deleted:%v, freed:%v, expired:%v",
+		res.Scanned, res.Referenced, res.Deleted, format.FileSize(res.FreedSize), res.Expired)
+	return
+}
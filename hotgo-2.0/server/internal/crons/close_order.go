@@ -7,11 +7,13 @@ package crons
 
 import (
 	"context"
-	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
 	"hotgo/internal/consts"
 	"hotgo/internal/dao"
 	"hotgo/internal/library/cron"
+	"hotgo/internal/library/fsm"
+	"hotgo/internal/model/input/adminin"
+	"hotgo/internal/model/input/payin"
 	"hotgo/internal/service"
 )
 
@@ -31,15 +33,37 @@ func (c *cCloseOrder) GetName() string {
 }
 
 // Execute 执行任务
+// 关闭前会先向第三方关闭支付订单，查询到已支付的订单会被补单而不是关闭
 func (c *cCloseOrder) Execute(ctx context.Context, parser *cron.Parser) (err error) {
-	_, err = service.AdminOrder().Model(ctx).
+	orderSns, err := service.AdminOrder().Model(ctx).
+		Fields(dao.AdminOrder.Columns().OrderSn).
 		Where(dao.AdminOrder.Columns().Status, consts.OrderStatusNotPay).
 		WhereLTE(dao.AdminOrder.Columns().CreatedAt, gtime.Now().AddDate(0, 0, -1)).
-		Data(g.Map{
-			dao.AdminOrder.Columns().Status: consts.OrderStatusClose,
-		}).Update()
+		Array()
 	if err != nil {
 		parser.Logger.Warning(ctx, "cron CloseOrder Execute err:%+v", err)
+		return
+	}
+
+	for _, orderSn := range orderSns {
+		// 已支付或第三方关闭失败的订单留到下次处理
+		if cErr := service.Pay().CloseOrder(ctx, &payin.PayCloseOrderInp{OrderSn: orderSn.String()}); cErr != nil {
+			parser.Logger.Infof(ctx, "cron CloseOrder Execute skip orderSn:%v err:%+v", orderSn, cErr)
+			continue
+		}
+
+		// 关闭第三方订单时可能已被补单，状态不允许关闭的跳过
+		err = service.AdminOrder().Close(ctx, &adminin.OrderCloseInp{OrderSn: orderSn.String(), Remark: "超时未支付自动关闭"})
+		if fsm.IsIllegal(err) {
+			parser.Logger.Infof(ctx, "cron CloseOrder Execute skip orderSn:%v err:%+v", orderSn, err)
+			err = nil
+			continue
+		}
+
+		if err != nil {
+			parser.Logger.Warning(ctx, "cron CloseOrder Execute err:%+v", err)
+			return
+		}
 	}
 	return
 }
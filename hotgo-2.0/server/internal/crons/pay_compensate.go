@@ -0,0 +1,58 @@
+// Package crons
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package crons
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/util/gconv"
+	"hotgo/internal/library/cron"
+	"hotgo/internal/model/input/payin"
+	"hotgo/internal/service"
+)
+
+func init() {
+	cron.Register(PayCompensate)
+}
+
+// PayCompensate 支付掉单补偿
+// 参数：创建超过多少分钟开始查询,超过多少分钟未支付则关闭,单次处理数量，如：5,1440,100
+var PayCompensate = &cPayCompensate{name: "pay_compensate"}
+
+type cPayCompensate struct {
+	name string
+}
+
+func (c *cPayCompensate) GetName() string {
+	return c.name
+}
+
+// Execute 执行任务
+func (c *cPayCompensate) Execute(ctx context.Context, parser *cron.Parser) (err error) {
+	in := new(payin.PayCompensateInp)
+	if len(parser.Args) > 0 {
+		in.Minutes = gconv.Int(parser.Args[0])
+	}
+	if len(parser.Args) > 1 {
+		in.Expire = gconv.Int(parser.Args[1])
+	}
+	if len(parser.Args) > 2 {
+		in.Limit = gconv.Int(parser.Args[2])
+	}
+
+	if err = in.Filter(ctx); err != nil {
+		return
+	}
+
+	res, err := service.Pay().Compensate(ctx, in)
+	if err != nil {
+		parser.Logger.Warningf(ctx, "cron PayCompensate Execute err:%+v", err)
+		return
+	}
+
+	parser.Logger.Infof(ctx, "cron PayCompensate Execute scanned:%v, settled:%v, closed:%v, failed:%v",
+		res.Scanned, res.Settled, res.Closed, res.Failed)
+	return
+}
@@ -0,0 +1,65 @@
+// Package crons
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package crons
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/library/cron"
+	"hotgo/internal/library/payment"
+	"hotgo/internal/model/input/payin"
+	"hotgo/internal/service"
+)
+
+func init() {
+	cron.Register(PayReconcile)
+}
+
+// PayReconcile 每日交易对账，核对前一天的第三方账单
+// 参数：支付方式，多个用,隔开，为空时核对所有已配置的支付方式，如：wxpay,alipay
+var PayReconcile = &cPayReconcile{name: "pay_reconcile"}
+
+type cPayReconcile struct {
+	name string
+}
+
+func (c *cPayReconcile) GetName() string {
+	return c.name
+}
+
+// Execute 执行任务
+func (c *cPayReconcile) Execute(ctx context.Context, parser *cron.Parser) (err error) {
+	var payTypes []string
+	for _, arg := range parser.Args {
+		if arg != "" {
+			payTypes = append(payTypes, arg)
+		}
+	}
+
+	if len(payTypes) == 0 {
+		payTypes = payment.EnabledPayTypes()
+	}
+
+	billDate := gtime.Now().AddDate(0, 0, -1)
+	for _, payType := range payTypes {
+		in := &payin.PayReconcileInp{PayType: payType, BillDate: billDate}
+		if err = in.Filter(ctx); err != nil {
+			return
+		}
+
+		res, rErr := service.PayReconcile().Reconcile(ctx, in)
+		if rErr != nil {
+			parser.Logger.Warningf(ctx, "cron PayReconcile Execute payType:%v err:%+v", payType, rErr)
+			err = gerror.Wrapf(rErr, "%v对账失败", payType)
+			continue
+		}
+
+		parser.Logger.Infof(ctx, "cron PayReconcile Execute payType:%v, date:%v, bill:%v, local:%v, matched:%v, diff:%v",
+			payType, billDate.Format("Y-m-d"), res.BillCount, res.LocalCount, res.Matched, res.DiffCount)
+	}
+	return
+}
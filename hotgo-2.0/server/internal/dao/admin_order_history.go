@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"hotgo/internal/dao/internal"
+)
+
+// adminOrderHistoryDao is the data access object for the table hg_admin_order_history.
+// You can define custom methods on it to extend its functionality as needed.
+type adminOrderHistoryDao struct {
+	*internal.AdminOrderHistoryDao
+}
+
+var (
+	// AdminOrderHistory is a globally accessible object for table hg_admin_order_history operations.
+	AdminOrderHistory = adminOrderHistoryDao{internal.NewAdminOrderHistoryDao()}
+)
+
+// Add your custom methods and functionality below.
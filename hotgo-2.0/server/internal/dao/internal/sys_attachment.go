@@ -24,6 +24,7 @@ type SysAttachmentColumns struct {
 	Id        string // 文件ID
 	AppId     string // 应用ID
 	MemberId  string // 管理员ID
+	TenantId  string // 租户ID
 	CateId    string // 上传分类
 	Drive     string // 上传驱动
 	Name      string // 文件原始名
@@ -33,6 +34,8 @@ type SysAttachmentColumns struct {
 	Path      string // 本地路径
 	FileUrl   string // url
 	Size      string // 文件大小
+	Width     string // 图片宽度
+	Height    string // 图片高度
 	Ext       string // 扩展名
 	Md5       string // md5校验码
 	Status    string // 状态
@@ -45,6 +48,7 @@ var sysAttachmentColumns = SysAttachmentColumns{
 	Id:        "id",
 	AppId:     "app_id",
 	MemberId:  "member_id",
+	TenantId:  "tenant_id",
 	CateId:    "cate_id",
 	Drive:     "drive",
 	Name:      "name",
@@ -54,6 +58,8 @@ var sysAttachmentColumns = SysAttachmentColumns{
 	Path:      "path",
 	FileUrl:   "file_url",
 	Size:      "size",
+	Width:     "width",
+	Height:    "height",
 	Ext:       "ext",
 	Md5:       "md5",
 	Status:    "status",
@@ -21,36 +21,48 @@ type SysCronDao struct {
 
 // SysCronColumns defines and stores column names for the table hg_sys_cron.
 type SysCronColumns struct {
-	Id        string // 任务ID
-	GroupId   string // 分组ID
-	Title     string // 任务标题
-	Name      string // 任务方法
-	Params    string // 函数参数
-	Pattern   string // 表达式
-	Policy    string // 策略
-	Count     string // 执行次数
-	Sort      string // 排序
-	Remark    string // 备注
-	Status    string // 任务状态
-	CreatedAt string // 创建时间
-	UpdatedAt string // 更新时间
+	Id          string // 任务ID
+	GroupId     string // 分组ID
+	Title       string // 任务标题
+	Name        string // 任务方法
+	Params      string // 函数参数
+	Pattern     string // 表达式
+	Policy      string // 策略
+	Count       string // 执行次数
+	ClusterMode string // 集群模式(1所有节点,2单节点,3指定节点)
+	NodeLabel   string // 指定节点标签，多个用,隔开
+	Timeout     string // 执行超时(秒)，0不限制
+	Retry       string // 失败重试次数
+	Alert       string // 失败通知(1开启,2关闭)
+	AlertEmail  string // 通知邮箱，多个用,隔开
+	Sort        string // 排序
+	Remark      string // 备注
+	Status      string // 任务状态
+	CreatedAt   string // 创建时间
+	UpdatedAt   string // 更新时间
 }
 
 // sysCronColumns holds the columns for the table hg_sys_cron.
 var sysCronColumns = SysCronColumns{
-	Id:        "id",
-	GroupId:   "group_id",
-	Title:     "title",
-	Name:      "name",
-	Params:    "params",
-	Pattern:   "pattern",
-	Policy:    "policy",
-	Count:     "count",
-	Sort:      "sort",
-	Remark:    "remark",
-	Status:    "status",
-	CreatedAt: "created_at",
-	UpdatedAt: "updated_at",
+	Id:          "id",
+	GroupId:     "group_id",
+	Title:       "title",
+	Name:        "name",
+	Params:      "params",
+	Pattern:     "pattern",
+	Policy:      "policy",
+	Count:       "count",
+	ClusterMode: "cluster_mode",
+	NodeLabel:   "node_label",
+	Timeout:     "timeout",
+	Retry:       "retry",
+	Alert:       "alert",
+	AlertEmail:  "alert_email",
+	Sort:        "sort",
+	Remark:      "remark",
+	Status:      "status",
+	CreatedAt:   "created_at",
+	UpdatedAt:   "updated_at",
 }
 
 // NewSysCronDao creates and returns a new DAO object for table data access.
@@ -21,44 +21,46 @@ type SysServeLicenseDao struct {
 
 // SysServeLicenseColumns defines and stores column names for the table hg_sys_serve_license.
 type SysServeLicenseColumns struct {
-	Id           string // 许可ID
-	Group        string // 分组
-	Name         string // 许可名称
-	Appid        string // 应用ID
-	SecretKey    string // 应用秘钥
-	RemoteAddr   string // 最后连接地址
-	OnlineLimit  string // 在线限制
-	LoginTimes   string // 登录次数
-	LastLoginAt  string // 最后登录时间
-	LastActiveAt string // 最后心跳
-	Routes       string // 路由表，空使用默认分组路由
-	AllowedIps   string // IP白名单
-	EndAt        string // 授权有效期
-	Remark       string // 备注
-	Status       string // 状态
-	CreatedAt    string // 创建时间
-	UpdatedAt    string // 修改时间
+	Id              string // 许可ID
+	Group           string // 分组
+	Name            string // 许可名称
+	Appid           string // 应用ID
+	SecretKey       string // 应用秘钥
+	RemoteAddr      string // 最后连接地址
+	OnlineLimit     string // 在线限制
+	LoginTimes      string // 登录次数
+	LastLoginAt     string // 最后登录时间
+	LastActiveAt    string // 最后心跳
+	Routes          string // 路由表，空使用默认分组路由
+	AllowedIps      string // IP白名单
+	CertFingerprint string // 绑定的客户端证书SHA256指纹，多个用,隔开，为空不限制
+	EndAt           string // 授权有效期
+	Remark          string // 备注
+	Status          string // 状态
+	CreatedAt       string // 创建时间
+	UpdatedAt       string // 修改时间
 }
 
 // sysServeLicenseColumns holds the columns for the table hg_sys_serve_license.
 var sysServeLicenseColumns = SysServeLicenseColumns{
-	Id:           "id",
-	Group:        "group",
-	Name:         "name",
-	Appid:        "appid",
-	SecretKey:    "secret_key",
-	RemoteAddr:   "remote_addr",
-	OnlineLimit:  "online_limit",
-	LoginTimes:   "login_times",
-	LastLoginAt:  "last_login_at",
-	LastActiveAt: "last_active_at",
-	Routes:       "routes",
-	AllowedIps:   "allowed_ips",
-	EndAt:        "end_at",
-	Remark:       "remark",
-	Status:       "status",
-	CreatedAt:    "created_at",
-	UpdatedAt:    "updated_at",
+	Id:              "id",
+	Group:           "group",
+	Name:            "name",
+	Appid:           "appid",
+	SecretKey:       "secret_key",
+	RemoteAddr:      "remote_addr",
+	OnlineLimit:     "online_limit",
+	LoginTimes:      "login_times",
+	LastLoginAt:     "last_login_at",
+	LastActiveAt:    "last_active_at",
+	Routes:          "routes",
+	AllowedIps:      "allowed_ips",
+	CertFingerprint: "cert_fingerprint",
+	EndAt:           "end_at",
+	Remark:          "remark",
+	Status:          "status",
+	CreatedAt:       "created_at",
+	UpdatedAt:       "updated_at",
 }
 
 // NewSysServeLicenseDao creates and returns a new DAO object for table data access.
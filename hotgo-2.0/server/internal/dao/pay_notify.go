@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"hotgo/internal/dao/internal"
+)
+
+// payNotifyDao is the data access object for the table hg_pay_notify.
+// You can define custom methods on it to extend its functionality as needed.
+type payNotifyDao struct {
+	*internal.PayNotifyDao
+}
+
+var (
+	// PayNotify is a globally accessible object for table hg_pay_notify operations.
+	PayNotify = payNotifyDao{internal.NewPayNotifyDao()}
+)
+
+// Add your custom methods and functionality below.
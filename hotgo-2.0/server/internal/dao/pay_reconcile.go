@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"hotgo/internal/dao/internal"
+)
+
+// payReconcileDao is the data access object for the table hg_pay_reconcile.
+// You can define custom methods on it to extend its functionality as needed.
+type payReconcileDao struct {
+	*internal.PayReconcileDao
+}
+
+var (
+	// PayReconcile is a globally accessible object for table hg_pay_reconcile operations.
+	PayReconcile = payReconcileDao{internal.NewPayReconcileDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"hotgo/internal/dao/internal"
+)
+
+// sysQueueFailDao is the data access object for the table hg_sys_queue_fail.
+// You can define custom methods on it to extend its functionality as needed.
+type sysQueueFailDao struct {
+	*internal.SysQueueFailDao
+}
+
+var (
+	// SysQueueFail is a globally accessible object for table hg_sys_queue_fail operations.
+	SysQueueFail = sysQueueFailDao{internal.NewSysQueueFailDao()}
+)
+
+// Add your custom methods and functionality below.
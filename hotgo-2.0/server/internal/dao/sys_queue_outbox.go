@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"hotgo/internal/dao/internal"
+)
+
+// sysQueueOutboxDao is the data access object for the table hg_sys_queue_outbox.
+// You can define custom methods on it to extend its functionality as needed.
+type sysQueueOutboxDao struct {
+	*internal.SysQueueOutboxDao
+}
+
+var (
+	// SysQueueOutbox is a globally accessible object for table hg_sys_queue_outbox operations.
+	SysQueueOutbox = sysQueueOutboxDao{internal.NewSysQueueOutboxDao()}
+)
+
+// Add your custom methods and functionality below.
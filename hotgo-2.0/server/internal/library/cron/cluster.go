@@ -0,0 +1,164 @@
+// Package cron
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package cron
+
+import (
+	"context"
+	"fmt"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/consts"
+	"hotgo/internal/library/hgrds/lock"
+	"hotgo/internal/model/entity"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 同一次触发的执行标记保留时长，用于防止时钟偏差的节点在锁释放后重复执行
+const tickMarkTTL = 86400
+
+var (
+	nodeLabel string // 当前节点标签，用于匹配指定节点
+	nodeId    string // 当前节点标识，由主机名和进程号组成，同一主机上的多个进程也不会重复
+)
+
+func init() {
+	hostname, _ := os.Hostname()
+	nodeId = fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
+
+// ExecuteRecord 任务执行记录
+type ExecuteRecord struct {
+	Node     string      `json:"node"     dc:"执行节点"`
+	At       *gtime.Time `json:"at"       dc:"执行时间"`
+	EndAt    *gtime.Time `json:"endAt"    dc:"结束时间"`
+	Took     int64       `json:"took"     dc:"耗时(ms)"`
+	Status   int         `json:"status"   dc:"执行状态"`
+	Attempts int         `json:"attempts" dc:"执行次数"`
+	Err      string      `json:"err"      dc:"错误信息"`
+	Output   string      `json:"-"        dc:"执行输出"`
+}
+
+// SetNodeLabel 设置当前节点标签，用于指定节点模式下匹配执行节点，集群中每个节点应配置不同的标签
+func SetNodeLabel(label string) {
+	nodeLabel = label
+}
+
+// GetNodeLabel 获取当前节点标签，未设置时使用主机名
+func GetNodeLabel() string {
+	if nodeLabel != "" {
+		return nodeLabel
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// GetNode 获取当前节点，用于记录执行节点和抢占执行权，格式为：节点标签@主机名:进程号
+func GetNode() string {
+	return fmt.Sprintf("%s@%s", GetNodeLabel(), nodeId)
+}
+
+// matchNode 当前节点是否匹配指定标签
+func matchNode(labels string) bool {
+	current := GetNodeLabel()
+	for _, label := range strings.Split(labels, consts.CronSplitStr) {
+		if strings.TrimSpace(label) == current {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterGuard 按任务的集群模式包装执行函数
+func clusterGuard(sysCron *entity.SysCron, fun gcron.JobFunc) gcron.JobFunc {
+	var (
+		sn      = GenCronSn(sysCron)
+		mode    = sysCron.ClusterMode
+		labels  = sysCron.NodeLabel
+		pattern = sysCron.Pattern
+	)
+
+	return func(ctx context.Context) {
+		switch mode {
+		case consts.CronClusterPinned:
+			if !matchNode(labels) {
+				return
+			}
+		case consts.CronClusterSingle:
+			mutex, ok := acquireTick(ctx, sn, fireTime(pattern, time.Now()))
+			if !ok {
+				return
+			}
+			defer func() {
+				_ = mutex.Unlock(ctx)
+			}()
+		}
+		fun(ctx)
+	}
+}
+
+// fireTime 获取本次触发的计划执行时间
+// 各节点的定时器触发时刻存在毫秒到秒级的偏差，直接使用当前时间会让同一次触发落在不同的秒上，从而被多个节点重复执行
+// 表达式的秒位为固定值时，计划执行时间一定在该秒上，按分钟对齐，可以容忍30秒以内的偏差；其他表达式按秒对齐
+func fireTime(pattern string, now time.Time) int64 {
+	fields := strings.Fields(pattern)
+	if len(fields) == 5 {
+		return now.Round(time.Minute).Unix()
+	}
+
+	if len(fields) == 6 {
+		if second, err := strconv.Atoi(fields[0]); err == nil && second >= 0 && second < 60 {
+			offset := time.Duration(second) * time.Second
+			return now.Add(-offset).Round(time.Minute).Add(offset).Unix()
+		}
+	}
+	return now.Round(time.Second).Unix()
+}
+
+// acquireTick 抢占某次触发的执行权，锁按任务序列号和计划执行时间区分
+func acquireTick(ctx context.Context, sn string, fireAt int64) (mutex *lock.Lock, ok bool) {
+	mutex = lock.Mutex(fmt.Sprintf("cron:lock:%s:%d", sn, fireAt))
+	if err := mutex.TryLock(ctx); err != nil {
+		if !gerror.Is(err, lock.ErrLockFailed) {
+			Logger().Warningf(ctx, "cron %v acquire lock err:%+v", sn, err)
+		}
+		return nil, false
+	}
+
+	// 执行较快的任务会在其他节点触发前释放锁，通过执行标记避免同一次触发被重复执行
+	v, err := g.Redis().Do(ctx, "SET", fmt.Sprintf("cron:tick:%s:%d", sn, fireAt), GetNode(), "NX", "EX", tickMarkTTL)
+	if err != nil || v.IsEmpty() {
+		_ = mutex.Unlock(ctx)
+		return nil, false
+	}
+	return mutex, true
+}
+
+// lastRecordKey 最近一次执行记录key
+func lastRecordKey(sn string) string {
+	return fmt.Sprintf("cron:last:%s", sn)
+}
+
+// saveLastRecord 保存最近一次执行记录
+func saveLastRecord(ctx context.Context, sn string, record *ExecuteRecord) {
+	if _, err := g.Redis().Set(ctx, lastRecordKey(sn), record); err != nil {
+		Logger().Debugf(ctx, "cron %v save last record err:%+v", sn, err)
+	}
+}
+
+// GetLastRecord 获取任务最近一次执行记录
+func GetLastRecord(ctx context.Context, sysCron *entity.SysCron) (record *ExecuteRecord, err error) {
+	v, err := g.Redis().Get(ctx, lastRecordKey(GenCronSn(sysCron)))
+	if err != nil || v.IsEmpty() {
+		return
+	}
+	err = v.Scan(&record)
+	return
+}
@@ -40,6 +40,8 @@ type Cron interface {
 
 // Parser 任务执行参数
 type Parser struct {
+	Sn     string       // 任务序列号
+	Node   string       // 执行节点
 	Args   []string     // 任务参数
 	Logger *glog.Logger // 日志管理实例
 }
@@ -115,22 +117,23 @@ func StartALL(sysCron []*entity.SysCron) (err error) {
 			var (
 				t   *gcron.Entry
 				ctx = GenCronCtx(cron)
+				fun = clusterGuard(cron, f.Fun)
 			)
 			switch cron.Policy {
 			case consts.CronPolicySame:
-				t, err = gcron.Add(ctx, cron.Pattern, f.Fun, sn)
+				t, err = gcron.Add(ctx, cron.Pattern, fun, sn)
 
 			case consts.CronPolicySingle:
-				t, err = gcron.AddSingleton(ctx, cron.Pattern, f.Fun, sn)
+				t, err = gcron.AddSingleton(ctx, cron.Pattern, fun, sn)
 
 			case consts.CronPolicyOnce:
-				t, err = gcron.AddOnce(ctx, cron.Pattern, f.Fun, sn)
+				t, err = gcron.AddOnce(ctx, cron.Pattern, fun, sn)
 
 			case consts.CronPolicyTimes:
 				if f.Count <= 0 {
 					f.Count = 1
 				}
-				t, err = gcron.AddTimes(ctx, cron.Pattern, int(cron.Count), f.Fun, sn)
+				t, err = gcron.AddTimes(ctx, cron.Pattern, int(cron.Count), fun, sn)
 
 			default:
 				return gerror.Newf("使用无效的策略, cron.Policy=%v", cron.Policy)
@@ -200,7 +203,7 @@ func Once(ctx context.Context, sysCron *entity.SysCron) error {
 	for _, v := range crons.tasks {
 		if v.Name == sysCron.Name {
 			simple.SafeGo(ctx, func(ctx context.Context) {
-				v.Fun(GenCronCtx(sysCron))
+				clusterGuard(sysCron, v.Fun)(GenCronCtx(sysCron))
 			})
 			return nil
 		}
@@ -8,6 +8,7 @@ package cron
 import (
 	"context"
 	"fmt"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gctx"
 	"github.com/gogf/gf/v2/os/glog"
@@ -15,6 +16,7 @@ import (
 	"hotgo/internal/consts"
 	"hotgo/internal/model/entity"
 	"strings"
+	"time"
 )
 
 // GenCronSn 生成任务序列号
@@ -26,6 +28,7 @@ func GenCronSn(sysCron *entity.SysCron) string {
 func GenCronCtx(sysCron *entity.SysCron) (ctx context.Context) {
 	ctx = context.WithValue(gctx.New(), consts.ContextKeyCronArgs, strings.Split(sysCron.Params, consts.CronSplitStr))
 	ctx = context.WithValue(ctx, consts.ContextKeyCronSn, GenCronSn(sysCron))
+	ctx = context.WithValue(ctx, consts.ContextKeyCron, sysCron)
 	return ctx
 }
 
@@ -67,22 +70,91 @@ func GenExecuteFun(fun func(ctx context.Context, parser *Parser) (err error)) fu
 			Logger().Panic(ctx, "执行定时任务时，参数解析失败!")
 			return
 		}
-		parser := new(Parser)
-		parser.Args = args
-		parser.Logger = GenLoggerByCtx(ctx)
+		sysCron, _ := ctx.Value(consts.ContextKeyCron).(*entity.SysCron)
 
-		st := gtime.Now()
-		err := g.Try(ctx, func(ctx context.Context) {
+		var (
+			sn, _   = ctx.Value(consts.ContextKeyCronSn).(string)
+			logger  = GenLoggerByCtx(ctx)
+			timeout time.Duration
+			retry   int
+		)
+
+		if sysCron != nil {
+			timeout = time.Duration(sysCron.Timeout) * time.Second
+			retry = sysCron.Retry
+		}
+
+		record := &ExecuteRecord{
+			Node: GetNode(),
+			At:   gtime.Now(),
+		}
+
+		// 多次重试的输出合并记录
+		output := new(outputCapture)
+		for {
+			record.Attempts++
+
+			parser := new(Parser)
+			parser.Sn = sn
+			parser.Node = record.Node
+			parser.Args = args
+			parser.Logger = captureLogger(logger, output)
+
+			var err error
+			record.Status, err = executeAttempt(ctx, fun, parser, timeout)
+			if err == nil {
+				record.Err = ""
+				break
+			}
+
+			record.Err = err.Error()
+			// 超时的任务可能仍在运行，不再重试以免重复执行
+			if record.Status == consts.CronLogStatusTimeout || record.Attempts > retry {
+				break
+			}
+			logger.Warningf(ctx, "[%v] execute failed, retry %v/%v, err:%+v", record.Node, record.Attempts, retry, err)
+			time.Sleep(time.Duration(record.Attempts) * time.Second)
+		}
+
+		record.EndAt = gtime.Now()
+		record.Took = record.EndAt.Sub(record.At).Milliseconds() // 执行耗时
+		record.Output = output.String()
+
+		saveLastRecord(ctx, sn, record)
+		handleRecord(ctx, sysCron, record)
+
+		if record.Status != consts.CronLogStatusSuccess {
+			logger.Errorf(ctx, "[%v] execute failed, attempts %v, took %vms, err:%+v", record.Node, record.Attempts, record.Took, record.Err)
+			return
+		}
+		logger.Infof(ctx, "[%v] execute success, attempts %v, took %vms.", record.Node, record.Attempts, record.Took)
+	}
+}
+
+// executeAttempt 执行一次任务，设置了超时时间时通过上下文通知任务退出
+func executeAttempt(ctx context.Context, fun func(ctx context.Context, parser *Parser) (err error), parser *Parser, timeout time.Duration) (status int, err error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Try(ctx, func(ctx context.Context) {
 			if err := fun(ctx, parser); err != nil {
 				panic(err)
 			}
 		})
+	}()
 
-		milliseconds := gtime.Now().Sub(st).Milliseconds() // 执行耗时
+	select {
+	case err = <-done:
 		if err != nil {
-			parser.Logger.Errorf(ctx, "execute failed, took %vms, err:%+v", milliseconds, err)
-			return
+			return consts.CronLogStatusFailed, err
 		}
-		parser.Logger.Infof(ctx, "execute success, took %vms.", milliseconds)
+		return consts.CronLogStatusSuccess, nil
+	case <-ctx.Done():
+		return consts.CronLogStatusTimeout, gerror.Newf("执行超时，超过%v秒未完成", timeout.Seconds())
 	}
 }
@@ -0,0 +1,95 @@
+// Package cron
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package cron
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/os/glog"
+	"hotgo/internal/model/entity"
+	"strings"
+	"sync"
+)
+
+// OutputMaxLength 单次执行记录保留的输出长度
+const OutputMaxLength = 10000
+
+// RecordHandler 执行记录处理器，每次任务执行完毕后调用
+type RecordHandler func(ctx context.Context, sysCron *entity.SysCron, record *ExecuteRecord)
+
+var (
+	recordHandlers []RecordHandler
+	recordMu       sync.RWMutex
+)
+
+// RegisterRecordHandler 注册执行记录处理器
+func RegisterRecordHandler(h RecordHandler) {
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	recordHandlers = append(recordHandlers, h)
+}
+
+// handleRecord 将执行记录交给已注册的处理器
+func handleRecord(ctx context.Context, sysCron *entity.SysCron, record *ExecuteRecord) {
+	if sysCron == nil {
+		return
+	}
+
+	recordMu.RLock()
+	handlers := recordHandlers
+	recordMu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, sysCron, record)
+	}
+}
+
+// outputCapture 收集任务执行期间通过parser.Logger输出的日志
+type outputCapture struct {
+	sync.Mutex
+	buf       strings.Builder
+	truncated bool
+}
+
+// handler 日志处理器，记录输出后继续交给后续处理器
+func (c *outputCapture) handler(ctx context.Context, in *glog.HandlerInput) {
+	c.write(in.String())
+	in.Next(ctx)
+}
+
+func (c *outputCapture) write(s string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.truncated {
+		return
+	}
+
+	if remain := OutputMaxLength - c.buf.Len(); len(s) > remain {
+		c.buf.WriteString(s[:remain])
+		c.buf.WriteString("\n...(truncated)")
+		c.truncated = true
+		return
+	}
+	c.buf.WriteString(s)
+}
+
+func (c *outputCapture) String() string {
+	c.Lock()
+	defer c.Unlock()
+	return strings.ToValidUTF8(c.buf.String(), "")
+}
+
+// captureLogger 基于任务日志实例克隆出将输出写入c的日志实例
+func captureLogger(logger *glog.Logger, c *outputCapture) *glog.Logger {
+	handlers := logger.GetConfig().Handlers
+	if len(handlers) == 0 && glog.GetDefaultHandler() != nil {
+		handlers = []glog.Handler{glog.GetDefaultHandler()}
+	}
+
+	clone := logger.Clone()
+	clone.SetHandlers(append([]glog.Handler{c.handler}, handlers...)...)
+	return clone
+}
@@ -0,0 +1,235 @@
+// Package fsm
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// 有限状态机
+// 以声明的方式描述状态、允许的流转、守卫条件和副作用钩子，不在声明中的流转统一拒绝
+// 状态机本身不负责持久化，状态更新、流转记录和事件发布通过钩子实现，调用方应在同一个事务中触发流转
+
+// GuardFunc 守卫条件，返回错误时拒绝流转
+type GuardFunc func(ctx context.Context, c *Context) error
+
+// HookFunc 流转钩子，返回错误时中止流转
+type HookFunc func(ctx context.Context, c *Context) error
+
+// Transition 状态流转
+type Transition struct {
+	Event  string    // 事件
+	Name   string    // 事件名称
+	From   []int     // 允许触发的源状态
+	To     int       // 目标状态
+	Manual bool      // 是否允许在后台手动指定目标状态触发
+	Guard  GuardFunc // 守卫条件
+	Action HookFunc  // 副作用，如变更余额、发起退款
+}
+
+// Context 流转上下文
+type Context struct {
+	Event string      // 事件
+	Name  string      // 事件名称
+	From  int         // 源状态
+	To    int         // 目标状态
+	Data  interface{} // 调用方传入的业务数据
+}
+
+// IllegalError 非法流转
+type IllegalError struct {
+	Machine  string // 状态机名称
+	Event    string // 事件
+	Name     string // 事件名称
+	From     int    // 当前状态
+	FromName string // 当前状态名称
+}
+
+func (e *IllegalError) Error() string {
+	return fmt.Sprintf("%v当前状态为[%v]，不允许%v", e.Machine, e.FromName, e.Name)
+}
+
+// IsIllegal 是否为非法流转错误
+func IsIllegal(err error) bool {
+	var e *IllegalError
+	return errors.As(err, &e)
+}
+
+// Machine 状态机
+type Machine struct {
+	name        string
+	states      map[int]string
+	transitions map[string]*Transition
+	events      []string
+	before      []HookFunc
+	after       []HookFunc
+}
+
+// New 创建状态机，states为所有状态及名称
+func New(name string, states map[int]string) *Machine {
+	return &Machine{
+		name:        name,
+		states:      states,
+		transitions: make(map[string]*Transition),
+	}
+}
+
+// Add 声明流转，声明有误时直接panic，应在初始化阶段完成声明
+func (m *Machine) Add(ts ...*Transition) *Machine {
+	for _, t := range ts {
+		if t.Event == "" {
+			panic(fmt.Sprintf("fsm %v: transition event is empty", m.name))
+		}
+
+		if _, ok := m.transitions[t.Event]; ok {
+			panic(fmt.Sprintf("fsm %v: transition event %v is duplicated", m.name, t.Event))
+		}
+
+		if len(t.From) == 0 {
+			panic(fmt.Sprintf("fsm %v: transition event %v has no source state", m.name, t.Event))
+		}
+
+		for _, state := range append([]int{t.To}, t.From...) {
+			if _, ok := m.states[state]; !ok {
+				panic(fmt.Sprintf("fsm %v: transition event %v use undefined state %v", m.name, t.Event, state))
+			}
+		}
+
+		if t.Name == "" {
+			t.Name = t.Event
+		}
+
+		m.transitions[t.Event] = t
+		m.events = append(m.events, t.Event)
+	}
+	return m
+}
+
+// Before 注册前置钩子，所有流转在执行副作用前调用，通常用于持久化状态
+func (m *Machine) Before(hooks ...HookFunc) *Machine {
+	m.before = append(m.before, hooks...)
+	return m
+}
+
+// After 注册后置钩子，所有流转在执行副作用后调用，通常用于记录流转历史、发布事件
+func (m *Machine) After(hooks ...HookFunc) *Machine {
+	m.after = append(m.after, hooks...)
+	return m
+}
+
+// Name 状态机名称
+func (m *Machine) Name() string {
+	return m.name
+}
+
+// StateName 获取状态名称
+func (m *Machine) StateName(state int) string {
+	if name, ok := m.states[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("未知状态:%v", state)
+}
+
+// Transition 获取事件对应的流转声明
+func (m *Machine) Transition(event string) *Transition {
+	return m.transitions[event]
+}
+
+// Can 检查当前状态是否允许触发事件
+func (m *Machine) Can(event string, from int) (t *Transition, err error) {
+	t, ok := m.transitions[event]
+	if !ok {
+		err = gerror.Newf("%v状态机未声明事件：%v", m.name, event)
+		return
+	}
+
+	for _, state := range t.From {
+		if state == from {
+			return
+		}
+	}
+
+	err = &IllegalError{
+		Machine:  m.name,
+		Event:    event,
+		Name:     t.Name,
+		From:     from,
+		FromName: m.StateName(from),
+	}
+	return nil, err
+}
+
+// Events 获取当前状态允许触发的流转，按声明顺序返回
+func (m *Machine) Events(from int) (list []*Transition) {
+	for _, event := range m.events {
+		if _, err := m.Can(event, from); err == nil {
+			list = append(list, m.transitions[event])
+		}
+	}
+	return
+}
+
+// Find 查找从当前状态流转到目标状态的手动流转
+func (m *Machine) Find(from, to int) (t *Transition, err error) {
+	for _, v := range m.Events(from) {
+		if v.Manual && v.To == to {
+			return v, nil
+		}
+	}
+
+	err = &IllegalError{
+		Machine:  m.name,
+		Name:     fmt.Sprintf("变更为[%v]", m.StateName(to)),
+		From:     from,
+		FromName: m.StateName(from),
+	}
+	return
+}
+
+// Fire 触发事件，依次执行守卫条件、前置钩子、副作用和后置钩子，任一环节返回错误时中止
+func (m *Machine) Fire(ctx context.Context, event string, from int, data interface{}) (c *Context, err error) {
+	t, err := m.Can(event, from)
+	if err != nil {
+		return
+	}
+
+	c = &Context{
+		Event: t.Event,
+		Name:  t.Name,
+		From:  from,
+		To:    t.To,
+		Data:  data,
+	}
+
+	if t.Guard != nil {
+		if err = t.Guard(ctx, c); err != nil {
+			return
+		}
+	}
+
+	for _, hook := range m.before {
+		if err = hook(ctx, c); err != nil {
+			return
+		}
+	}
+
+	if t.Action != nil {
+		if err = t.Action(ctx, c); err != nil {
+			return
+		}
+	}
+
+	for _, hook := range m.after {
+		if err = hook(ctx, c); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,116 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+const (
+	stateWait = iota + 1
+	stateDone
+	stateClose
+)
+
+func newMachine(log *[]string) *Machine {
+	record := func(name string) HookFunc {
+		return func(ctx context.Context, c *Context) error {
+			*log = append(*log, name+":"+c.Event)
+			return nil
+		}
+	}
+
+	return New("订单", map[int]string{
+		stateWait:  "待付款",
+		stateDone:  "已完成",
+		stateClose: "已关闭",
+	}).Add(
+		&Transition{Event: "pay", Name: "支付", From: []int{stateWait}, To: stateDone, Action: record("action")},
+		&Transition{Event: "close", Name: "关闭订单", From: []int{stateWait}, To: stateClose, Manual: true},
+		&Transition{
+			Event: "reopen",
+			Name:  "重新打开",
+			From:  []int{stateClose},
+			To:    stateWait,
+			Guard: func(ctx context.Context, c *Context) error {
+				if c.Data == nil {
+					return errors.New("reopen reason is required")
+				}
+				return nil
+			},
+		},
+	).Before(record("before")).After(record("after"))
+}
+
+func TestFire(t *testing.T) {
+	var (
+		ctx = context.Background()
+		log []string
+		m   = newMachine(&log)
+	)
+
+	c, err := m.Fire(ctx, "pay", stateWait, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.From != stateWait || c.To != stateDone {
+		t.Fatalf("unexpected context: %+v", c)
+	}
+
+	if want := []string{"before:pay", "action:pay", "after:pay"}; len(log) != len(want) || log[0] != want[0] || log[1] != want[1] || log[2] != want[2] {
+		t.Fatalf("hooks = %v, want %v", log, want)
+	}
+
+	log = nil
+	if _, err = m.Fire(ctx, "pay", stateDone, nil); !IsIllegal(err) {
+		t.Fatalf("err = %v, want illegal transition", err)
+	}
+
+	if err.Error() != "订单当前状态为[已完成]，不允许支付" {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+
+	if _, err = m.Fire(ctx, "reopen", stateClose, nil); err == nil || IsIllegal(err) {
+		t.Fatalf("err = %v, want guard error", err)
+	}
+
+	if len(log) != 0 {
+		t.Fatalf("hooks should not run when rejected: %v", log)
+	}
+
+	if _, err = m.Fire(ctx, "unknown", stateWait, nil); err == nil {
+		t.Fatal("undeclared event should fail")
+	}
+}
+
+func TestFind(t *testing.T) {
+	var (
+		log []string
+		m   = newMachine(&log)
+	)
+
+	if events := m.Events(stateWait); len(events) != 2 || events[0].Event != "pay" || events[1].Event != "close" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+
+	tr, err := m.Find(stateWait, stateClose)
+	if err != nil || tr.Event != "close" {
+		t.Fatalf("find = %v, %v", tr, err)
+	}
+
+	// 支付不是手动流转，不能通过指定目标状态触发
+	if _, err = m.Find(stateWait, stateDone); !IsIllegal(err) {
+		t.Fatalf("err = %v, want illegal transition", err)
+	}
+}
+
+func TestAddPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("undefined state should panic")
+		}
+	}()
+
+	New("订单", map[int]string{stateWait: "待付款"}).Add(&Transition{Event: "pay", From: []int{stateWait}, To: stateDone})
+}
@@ -0,0 +1,197 @@
+// Package registry
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package registry
+
+import (
+	"context"
+	"fmt"
+	"github.com/gogf/gf/v2/container/gvar"
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"os"
+	"time"
+)
+
+// 集群节点注册表
+// 节点集合记录所有节点，节点存活标记带过期时间，节点成员哈希记录节点上的在线成员（如连接）
+// 节点定时上报心跳并全量同步成员，超过存活时间未上报心跳的节点及其成员会在查询时被移除
+
+// DefaultTTL 默认节点存活时间
+const DefaultTTL = 30 * time.Second
+
+// Registry 节点注册表
+type Registry struct {
+	prefix string
+	node   string
+	ttl    time.Duration
+}
+
+// DefaultNode 默认节点名称，使用主机名和进程ID
+func DefaultNode() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// New 创建节点注册表，prefix为redis键前缀，node为空时使用默认节点名称，ttl小于3秒时使用默认存活时间
+func New(prefix, node string, ttl time.Duration) *Registry {
+	if node == "" {
+		node = DefaultNode()
+	}
+
+	if ttl < 3*time.Second {
+		ttl = DefaultTTL
+	}
+	return &Registry{prefix: prefix, node: node, ttl: ttl}
+}
+
+// Node 当前节点名称
+func (r *Registry) Node() string {
+	return r.node
+}
+
+// TTL 节点存活时间
+func (r *Registry) TTL() time.Duration {
+	return r.ttl
+}
+
+// Key 生成注册表下的键，可用于存放节点的其他附属数据
+func (r *Registry) Key(name, node string) string {
+	return fmt.Sprintf("%s:%s:%s", r.prefix, name, node)
+}
+
+// nodesKey 节点集合
+func (r *Registry) nodesKey() string {
+	return fmt.Sprintf("%s:nodes", r.prefix)
+}
+
+// aliveKey 节点存活标记
+func (r *Registry) aliveKey(node string) string {
+	return r.Key("alive", node)
+}
+
+// membersKey 节点成员
+func (r *Registry) membersKey(node string) string {
+	return r.Key("members", node)
+}
+
+// Heartbeat 上报节点心跳，并将当前节点的成员全量同步到注册表，移除已下线但未能及时注销的成员
+func (r *Registry) Heartbeat(ctx context.Context, members map[string]any) (err error) {
+	seconds := int64(r.ttl.Seconds())
+	if _, err = g.Redis().Set(ctx, r.aliveKey(r.node), gtime.Timestamp(), gredis.SetOption{TTLOption: gredis.TTLOption{EX: &seconds}}); err != nil {
+		return
+	}
+
+	if _, err = g.Redis().SAdd(ctx, r.nodesKey(), r.node); err != nil {
+		return
+	}
+
+	keys, err := g.Redis().HKeys(ctx, r.membersKey(r.node))
+	if err != nil {
+		return
+	}
+
+	var stale []string
+	for _, key := range keys {
+		if _, ok := members[key]; !ok {
+			stale = append(stale, key)
+		}
+	}
+	if len(stale) > 0 {
+		if _, err = g.Redis().HDel(ctx, r.membersKey(r.node), stale...); err != nil {
+			return
+		}
+	}
+
+	if len(members) > 0 {
+		if _, err = g.Redis().HSet(ctx, r.membersKey(r.node), members); err != nil {
+			return
+		}
+	}
+	_, err = g.Redis().Expire(ctx, r.membersKey(r.node), seconds)
+	return
+}
+
+// Register 登记当前节点的成员
+func (r *Registry) Register(ctx context.Context, field string, value any) (err error) {
+	if _, err = g.Redis().HSet(ctx, r.membersKey(r.node), map[string]any{field: value}); err != nil {
+		return
+	}
+	_, err = g.Redis().Expire(ctx, r.membersKey(r.node), int64(r.ttl.Seconds()))
+	return
+}
+
+// Unregister 注销当前节点的成员
+func (r *Registry) Unregister(ctx context.Context, fields ...string) (err error) {
+	_, err = g.Redis().HDel(ctx, r.membersKey(r.node), fields...)
+	return
+}
+
+// Leave 离开集群，移除当前节点的注册信息，keys为需要一并删除的节点附属数据
+func (r *Registry) Leave(ctx context.Context, keys ...string) (err error) {
+	keys = append([]string{r.aliveKey(r.node), r.membersKey(r.node)}, keys...)
+	if _, err = g.Redis().Del(ctx, keys...); err != nil {
+		return
+	}
+	_, err = g.Redis().SRem(ctx, r.nodesKey(), r.node)
+	return
+}
+
+// Nodes 获取存活的其他节点，同时清理已过期的节点
+func (r *Registry) Nodes(ctx context.Context) (nodes []string, err error) {
+	members, err := g.Redis().SMembers(ctx, r.nodesKey())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		node := member.String()
+		if node == r.node {
+			continue
+		}
+
+		alive, err := g.Redis().Exists(ctx, r.aliveKey(node))
+		if err != nil {
+			return nil, err
+		}
+
+		if alive == 0 {
+			_, _ = g.Redis().SRem(ctx, r.nodesKey(), node)
+			_, _ = g.Redis().Del(ctx, r.membersKey(node))
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return
+}
+
+// Members 获取指定节点的成员
+func (r *Registry) Members(ctx context.Context, node string) (map[string]*gvar.Var, error) {
+	v, err := g.Redis().HGetAll(ctx, r.membersKey(node))
+	if err != nil {
+		return nil, err
+	}
+	return v.MapStrVar(), nil
+}
+
+// Lookup 查找持有指定成员的其他存活节点，未找到时返回空
+func (r *Registry) Lookup(ctx context.Context, field string) (node string, err error) {
+	nodes, err := r.Nodes(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, v := range nodes {
+		exists, err := g.Redis().HExists(ctx, r.membersKey(v), field)
+		if err != nil {
+			return "", err
+		}
+		if exists > 0 {
+			return v, nil
+		}
+	}
+	return
+}
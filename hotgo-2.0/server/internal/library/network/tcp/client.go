@@ -7,6 +7,7 @@ package tcp
 
 import (
 	"context"
+	"crypto/tls"
 	"github.com/gogf/gf/v2/container/gtype"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
@@ -26,6 +27,7 @@ type Client struct {
 	config    *ClientConfig   // 配置
 	msgParser *MsgParser      // 消息处理器
 	logger    *glog.Logger    // 日志处理器
+	tlsConfig *tls.Config     // 传输层加密配置
 	isLogin   *gtype.Bool     // 是否已登录
 	taskGo    *grpool.Pool    // 任务协程池
 	closeFlag *gtype.Bool     // 关闭标签，关闭以后可以重连
@@ -36,15 +38,20 @@ type Client struct {
 
 // ClientConfig 客户端配置
 type ClientConfig struct {
-	Addr            string        // 连接地址
-	Auth            *AuthMeta     // 认证元数据
-	Timeout         time.Duration // 连接超时时间
-	ConnectInterval time.Duration // 重连时间间隔
-	MaxConnectCount uint          // 最大重连次数，0不限次数
-	ConnectCount    uint          // 已重连次数
-	AutoReconnect   bool          // 是否开启自动重连
-	LoginEvent      CallbackEvent // 登录成功事件
-	CloseEvent      CallbackEvent // 连接关闭事件
+	Addr              string        // 连接地址
+	Auth              *AuthMeta     // 认证元数据
+	Timeout           time.Duration // 连接超时时间
+	ConnectInterval   time.Duration // 重连时间间隔
+	MaxConnectCount   uint          // 最大重连次数，0不限次数
+	ConnectCount      uint          // 已重连次数
+	AutoReconnect     bool          // 是否开启自动重连
+	TLS               *TLSConfig    // 传输层加密配置，为空时使用明文传输
+	Codec             string        // 期望使用的编码，为空且未开启压缩时不进行协商
+	CompressThreshold int           // 消息压缩阈值(字节)，协商成功后超过该大小的消息使用gzip压缩，0不压缩
+	MaxInflight       int           // rpc请求并发上限，超过时发起方等待、处理方直接拒绝，0不限制
+	Write             *WriteConfig  // 连接写队列配置，为空时使用默认配置
+	LoginEvent        CallbackEvent // 登录成功事件
+	CloseEvent        CallbackEvent // 连接关闭事件
 }
 
 // CallbackEvent 回调事件
@@ -81,6 +88,24 @@ func NewClient(config *ClientConfig) (client *Client) {
 		client.config.Timeout = config.Timeout
 	}
 
+	if config.Codec != "" {
+		if _, ok := GetCodec(config.Codec); !ok {
+			client.logger.Fatal(client.ctx, gerror.Wrapf(baseErr, "codec %v is not registered", config.Codec))
+			return
+		}
+	} else if config.CompressThreshold > 0 {
+		client.config.Codec = CodecJSON
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.clientConfig(config.Addr)
+		if err != nil {
+			client.logger.Fatal(client.ctx, gerror.Wrap(baseErr, err.Error()))
+			return
+		}
+		client.tlsConfig = tlsConfig
+	}
+
 	client.isLogin = gtype.NewBool(false)
 	client.closeFlag = gtype.NewBool(false)
 	client.stopFlag = gtype.NewBool(false)
@@ -142,6 +167,14 @@ func (client *Client) RegisterRPCRouter(routers ...interface{}) {
 	}
 }
 
+// RegisterStreamRouter 注册流式路由
+func (client *Client) RegisterStreamRouter(routers ...interface{}) {
+	err := client.msgParser.RegisterStreamRouter(routers...)
+	if err != nil {
+		client.logger.Fatal(client.ctx, err)
+	}
+}
+
 // RegisterInterceptor 注册拦截器
 func (client *Client) RegisterInterceptor(interceptors ...Interceptor) {
 	client.msgParser.RegisterInterceptor(interceptors...)
@@ -150,7 +183,15 @@ func (client *Client) RegisterInterceptor(interceptors ...Interceptor) {
 // dial
 func (client *Client) dial() *gtcp.Conn {
 	for {
-		conn, err := gtcp.NewConn(client.config.Addr, client.config.Timeout)
+		var (
+			conn *gtcp.Conn
+			err  error
+		)
+		if client.tlsConfig != nil {
+			conn, err = dialTLS(client.config.Addr, client.config.Timeout, client.tlsConfig)
+		} else {
+			conn, err = gtcp.NewConn(client.config.Addr, client.config.Timeout)
+		}
 		if err == nil || client.closeFlag.Val() {
 			return conn
 		}
@@ -190,7 +231,9 @@ reconnect:
 		return
 	}
 
-	client.conn = NewConn(conn, client.logger, client.msgParser)
+	client.conn = NewConn(conn, client.logger, client.msgParser, client.config.Write)
+	client.conn.outbound = true
+	client.conn.SetMaxInflight(client.config.MaxInflight)
 	client.config.ConnectCount = 0
 	client.read()
 	client.mutex.Unlock()
@@ -301,3 +344,11 @@ func (client *Client) Request(ctx context.Context, data interface{}) (interface{
 func (client *Client) RequestScan(ctx context.Context, data, response interface{}) error {
 	return client.conn.RequestScan(ctx, data, response)
 }
+
+// RequestStream 发起流式请求，响应按顺序写入返回的通道
+func (client *Client) RequestStream(ctx context.Context, data interface{}) (<-chan *StreamResponse, error) {
+	if client.conn == nil {
+		return nil, gerror.New("conn is nil")
+	}
+	return client.conn.RequestStream(ctx, data)
+}
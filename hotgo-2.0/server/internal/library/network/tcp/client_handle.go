@@ -41,6 +41,8 @@ func (client *Client) serverLogin() {
 		Group:     auth.Group,
 		AppId:     auth.AppId,
 		Timestamp: gtime.Timestamp(),
+		Codec:     client.config.Codec,
+		Protocol:  ProtocolVersion,
 	}
 
 	// 签名
@@ -62,6 +64,15 @@ func (client *Client) onResponseServerLogin(ctx context.Context, req *ServerLogi
 		return
 	}
 
+	// 服务器支持协商时切换编码，未返回时继续使用JSON编码
+	conn := ConnFromCtx(ctx)
+	if req.Codec != "" {
+		if codec, ok := GetCodec(req.Codec); ok {
+			conn.SetCodec(codec, client.config.CompressThreshold)
+		}
+	}
+	conn.setPeerProtocol(req.Protocol)
+
 	client.isLogin.Set(true)
 
 	if client.config.LoginEvent != nil {
@@ -0,0 +1,322 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/gogf/gf/v2/util/grand"
+	"hotgo/internal/library/hgrds/pubsub"
+	"hotgo/internal/library/hgrds/registry"
+	"sync"
+	"time"
+)
+
+// 集群转发的消息类型
+const (
+	clusterMsgSend      = iota + 1 // 发送消息
+	clusterMsgRequest              // 发起rpc请求
+	clusterMsgReply                // rpc响应
+	clusterMsgBroadcast            // 分组广播
+	clusterMsgClose                // 断开连接
+)
+
+// ClusterConfig 集群配置，为空时以单节点模式运行，不依赖redis
+type ClusterConfig struct {
+	Node string        // 节点名称，需集群内唯一，为空时使用主机名和进程ID
+	TTL  time.Duration // 节点存活时间，超过该时间未上报心跳的节点及其连接会被移除，默认30秒
+}
+
+// ClusterClient 集群中已认证的客户端连接
+type ClusterClient struct {
+	Node      string     `json:"node"`      // 所在节点
+	CID       int64      `json:"cid"`       // 连接ID，仅在所在节点内唯一
+	Addr      string     `json:"addr"`      // 客户端地址
+	LocalAddr string     `json:"localAddr"` // 服务端地址
+	Auth      *AuthMeta  `json:"auth"`      // 认证元数据，不含应用秘钥
+	FirstTime int64      `json:"firstTime"` // 首次连接时间
+	Heartbeat int64      `json:"heartbeat"` // 心跳时间，其他节点的连接为最近一次同步时的值
+	Write     WriteStats `json:"write"`     // 写队列统计，其他节点的连接为最近一次同步时的值
+}
+
+// clusterMsg 节点间转发的消息
+type clusterMsg struct {
+	Kind    int             `json:"kind"`              // 消息类型
+	Id      string          `json:"id,omitempty"`      // 请求标识，用于匹配rpc响应
+	From    string          `json:"from"`              // 来源节点
+	CID     int64           `json:"cid,omitempty"`     // 目标连接ID
+	Group   string          `json:"group,omitempty"`   // 广播分组
+	Router  string          `json:"router,omitempty"`  // 消息路由
+	TraceId string          `json:"traceId,omitempty"` // 链路ID
+	Data    json.RawMessage `json:"data,omitempty"`    // 消息数据，JSON格式
+	Error   string          `json:"error,omitempty"`   // rpc响应错误
+	Timeout int64           `json:"timeout,omitempty"` // rpc请求剩余超时时间(ms)
+}
+
+// cluster 集群注册表和节点间消息转发
+// 注册表以连接ID为成员记录节点上已认证的连接
+type cluster struct {
+	server   *Server
+	node     string
+	ttl      time.Duration
+	registry *registry.Registry
+	pending  map[string]chan *clusterMsg // 等待其他节点响应的rpc请求
+	mutex    sync.Mutex
+}
+
+// newCluster 初始化集群
+func newCluster(server *Server, config *ClusterConfig) *cluster {
+	r := registry.New(fmt.Sprintf("tcp:cluster:%s", server.name), config.Node, config.TTL)
+	return &cluster{
+		server:   server,
+		node:     r.Node(),
+		ttl:      r.TTL(),
+		registry: r,
+		pending:  make(map[string]chan *clusterMsg),
+	}
+}
+
+// channel 节点消息通道
+func (c *cluster) channel(node string) string {
+	return fmt.Sprintf("tcp.cluster.%s.%s", c.server.name, node)
+}
+
+// join 加入集群，订阅本节点的消息通道并上报心跳
+func (c *cluster) join(ctx context.Context) error {
+	if err := pubsub.Subscribe(c.channel(c.node), c.handleMessage); err != nil {
+		return err
+	}
+	c.heartbeat(ctx)
+	return nil
+}
+
+// leave 离开集群，移除本节点的注册信息
+func (c *cluster) leave(ctx context.Context) {
+	if err := c.registry.Leave(ctx); err != nil {
+		c.server.logger.Warningf(ctx, "cluster leave err:%+v", err)
+	}
+}
+
+// heartbeat 上报节点心跳，并将本节点已认证的连接全量同步到注册表
+func (c *cluster) heartbeat(ctx context.Context) {
+	fields := make(map[string]any)
+	for _, client := range c.server.localClients() {
+		fields[gconv.String(client.CID)] = client
+	}
+
+	if err := c.registry.Heartbeat(ctx, fields); err != nil {
+		c.server.logger.Warningf(ctx, "cluster heartbeat err:%+v", err)
+	}
+}
+
+// register 登记本节点已认证的连接
+func (c *cluster) register(ctx context.Context, client *ClusterClient) {
+	if err := c.registry.Register(ctx, gconv.String(client.CID), client); err != nil {
+		c.server.logger.Warningf(ctx, "cluster register client err:%+v", err)
+	}
+}
+
+// unregister 注销本节点的连接
+func (c *cluster) unregister(ctx context.Context, cid int64) {
+	if err := c.registry.Unregister(ctx, gconv.String(cid)); err != nil {
+		c.server.logger.Warningf(ctx, "cluster unregister client err:%+v", err)
+	}
+}
+
+// nodes 获取存活的其他节点，同时清理已过期的节点
+func (c *cluster) nodes(ctx context.Context) ([]string, error) {
+	return c.registry.Nodes(ctx)
+}
+
+// remoteClients 获取其他节点已认证的连接
+func (c *cluster) remoteClients(ctx context.Context) (list []*ClusterClient, err error) {
+	nodes, err := c.nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		members, err := c.registry.Members(ctx, node)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range members {
+			var client *ClusterClient
+			if err = item.Scan(&client); err != nil || client == nil {
+				continue
+			}
+			list = append(list, client)
+		}
+	}
+	return
+}
+
+// newMsg 将消息数据编码为转发消息
+func (c *cluster) newMsg(ctx context.Context, kind int, data interface{}) (*clusterMsg, error) {
+	message, err := c.server.msgParser.doDecoding(ctx, data, "")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterMsg{Kind: kind, From: c.node, Router: message.Router, TraceId: message.TraceId, Data: body}, nil
+}
+
+// publish 向指定节点推送消息
+func (c *cluster) publish(ctx context.Context, node string, msg *clusterMsg) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	receivers, err := pubsub.Publish(ctx, c.channel(node), string(b))
+	if err != nil {
+		return err
+	}
+
+	if receivers == 0 {
+		return gerror.Newf("cluster node %v is unreachable", node)
+	}
+	return nil
+}
+
+// request 向指定节点发起rpc请求并等待响应，ctx未设置截止时间时默认等待RPCTimeout秒
+func (c *cluster) request(ctx context.Context, node string, msg *clusterMsg) (json.RawMessage, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Second*RPCTimeout)
+		defer cancel()
+	}
+
+	deadline, _ := ctx.Deadline()
+	msg.Id = grand.S(16)
+	msg.Timeout = max(time.Until(deadline).Milliseconds(), 1)
+
+	resCh := make(chan *clusterMsg, 1)
+	c.mutex.Lock()
+	c.pending[msg.Id] = resCh
+	c.mutex.Unlock()
+
+	defer func() {
+		c.mutex.Lock()
+		delete(c.pending, msg.Id)
+		c.mutex.Unlock()
+	}()
+
+	if err := c.publish(ctx, node, msg); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctxError(ctx)
+	case res := <-resCh:
+		if res.Error != "" {
+			return nil, gerror.New(res.Error)
+		}
+		return res.Data, nil
+	}
+}
+
+// handleMessage 处理其他节点转发的消息
+func (c *cluster) handleMessage(ctx context.Context, message *gredis.Message) {
+	if c.server.IsClose() {
+		return
+	}
+
+	var msg *clusterMsg
+	if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil || msg == nil {
+		c.server.logger.Warningf(ctx, "cluster message invalid:%v, err:%+v", message.Payload, err)
+		return
+	}
+
+	if msg.TraceId != "" {
+		ctx, _ = SetCtxTraceID(gctx.New(), msg.TraceId)
+	}
+
+	switch msg.Kind {
+	case clusterMsgReply:
+		c.mutex.Lock()
+		resCh, ok := c.pending[msg.Id]
+		c.mutex.Unlock()
+		if ok {
+			select {
+			case resCh <- msg:
+			default:
+			}
+		}
+
+	case clusterMsgSend:
+		if conn := c.server.GetClientById(msg.CID); conn != nil {
+			if err := conn.sendRaw(ctx, msg.Router, msg.Data); err != nil {
+				c.server.logger.Warningf(ctx, "cluster send to client %v err:%+v", msg.CID, err)
+			}
+		}
+
+	case clusterMsgBroadcast:
+		for _, conn := range c.server.GetGroupClients(msg.Group) {
+			_ = conn.sendRaw(ctx, msg.Router, msg.Data)
+		}
+
+	case clusterMsgClose:
+		if conn := c.server.GetClientById(msg.CID); conn != nil {
+			conn.Close()
+		}
+
+	case clusterMsgRequest:
+		c.reply(ctx, msg)
+	}
+}
+
+// reply 代其他节点向本节点的连接发起rpc请求，并将结果返回给来源节点
+func (c *cluster) reply(ctx context.Context, msg *clusterMsg) {
+	res := &clusterMsg{Kind: clusterMsgReply, Id: msg.Id, From: c.node}
+
+	conn := c.server.GetClientById(msg.CID)
+	if conn == nil {
+		res.Error = "client is offline"
+	} else {
+		reqCtx, cancel := context.WithTimeout(ctx, time.Duration(msg.Timeout)*time.Millisecond)
+		defer cancel()
+
+		body, err := conn.requestRaw(reqCtx, msg.Router, msg.Data)
+		if err == nil {
+			res.Data, err = marshalData(body)
+		}
+		if err != nil {
+			res.Error = err.Error()
+		}
+	}
+
+	if err := c.publish(ctx, msg.From, res); err != nil {
+		c.server.logger.Warningf(ctx, "cluster reply to %v err:%+v", msg.From, err)
+	}
+}
+
+// marshalData 将rpc响应数据编码为JSON
+func marshalData(data interface{}) (json.RawMessage, error) {
+	if raw, ok := data.(*RawData); ok {
+		if !raw.Native {
+			return raw.Body, nil
+		}
+
+		value, err := raw.Interface()
+		if err != nil {
+			return nil, err
+		}
+		data = value
+	}
+	return json.Marshal(data)
+}
@@ -0,0 +1,76 @@
+// Package tcp_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp_test
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/test/gtest"
+	"hotgo/internal/library/network/tcp"
+	"testing"
+	"time"
+)
+
+type TestClusterPingReq struct {
+	Name string `json:"name"`
+}
+
+type TestClusterPingRes struct {
+	tcp.ServerRes
+	Name string `json:"name"`
+}
+
+func TestClusterSingleNode(t *testing.T) {
+	var (
+		ctx  = gctx.New()
+		serv = tcp.NewServer(&tcp.ServerConfig{Name: "hotgo", Addr: "127.0.0.1:8013"})
+	)
+
+	serv.RegisterRouter(func(ctx context.Context, req *tcp.ServerLoginReq) {
+		conn := tcp.ConnFromCtx(ctx)
+		serv.AuthClient(conn, &tcp.AuthMeta{Name: req.Name, Group: req.Group, AppId: req.AppId, SecretKey: "secret"})
+		_ = conn.Send(ctx, new(tcp.ServerLoginRes))
+	})
+
+	go func() {
+		_ = serv.Listen()
+	}()
+	defer serv.Close()
+
+	client := tcp.NewClient(&tcp.ClientConfig{
+		Addr:            "127.0.0.1:8013",
+		ConnectInterval: 100 * time.Millisecond,
+		Auth:            &tcp.AuthMeta{Name: "test", Group: "cron", AppId: "test"},
+	})
+	client.RegisterRPCRouter(func(ctx context.Context, req *TestClusterPingReq) (res *TestClusterPingRes, err error) {
+		return &TestClusterPingRes{Name: req.Name}, nil
+	})
+	defer client.Stop()
+
+	gtest.C(t, func(t *gtest.T) {
+		t.AssertNil(client.Start())
+		for i := 0; i < 50 && !client.IsLogin(); i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Assert(client.IsLogin(), true)
+
+		// 单节点模式不依赖redis，只返回本节点的连接，且不暴露应用秘钥
+		t.Assert(serv.IsCluster(), false)
+		clients, err := serv.ClusterGroupClients(ctx, "cron")
+		t.AssertNil(err)
+		t.Assert(len(clients), 1)
+		t.Assert(clients[0].Node, serv.Node())
+		t.Assert(clients[0].Auth.SecretKey, "")
+		t.Assert(serv.ClusterAppIdOnline(ctx, "test"), 1)
+
+		var res TestClusterPingRes
+		t.AssertNil(serv.RequestScanTo(ctx, clients[0], &TestClusterPingReq{Name: "hotgo"}, &res))
+		t.Assert(res.Name, "hotgo")
+
+		t.AssertNil(serv.CloseClient(ctx, clients[0]))
+		t.AssertNE(serv.CloseClient(ctx, &tcp.ClusterClient{Node: serv.Node(), CID: -1}), nil)
+	})
+}
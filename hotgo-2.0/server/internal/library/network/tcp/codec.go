@@ -0,0 +1,236 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/gogf/gf/v2/encoding/gcompress"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// 内置编码
+const (
+	CodecJSON     = "json"
+	CodecMsgpack  = "msgpack"
+	CodecProtobuf = "protobuf"
+)
+
+// 帧头格式：[编码标识][标志位][消息体]
+// 未协商编码的连接仍直接发送JSON消息体，接收时通过首字节'{'区分，以兼容旧版本
+const (
+	frameHeaderLen = 2
+	frameFlagGzip  = 1 << 0
+	frameJSONStart = '{'
+)
+
+// MaxUnzipSize 压缩帧解压后的最大长度，单个数据包最大为65535字节(gtcp默认包头)，按最高64倍压缩率计算，超出时视为非法数据包
+const MaxUnzipSize = 0xFFFF * 64
+
+// Codec 消息编解码器
+type Codec interface {
+	Id() byte                                // 编码标识，写入帧头，需全局唯一且不能为'{'
+	Name() string                            // 编码名称，登录时用于协商
+	Marshal(msg *Message) ([]byte, error)    // 编码消息
+	Unmarshal(data []byte) (*Message, error) // 解码消息
+}
+
+// dataCodec 支持按路由参数类型直接解析数据的编解码器
+type dataCodec interface {
+	scanData(body []byte, pointer interface{}) error
+	decodeData(body []byte) (interface{}, error)
+}
+
+// RawData 二进制编码下未解析的消息数据，由路由按参数类型直接解析，避免中间转换
+type RawData struct {
+	Body   []byte    // 消息数据
+	Native bool      // 是否为编码器原生格式，否则为JSON格式
+	codec  dataCodec // 原生格式的解析器
+}
+
+// Scan 将数据解析到pointer
+func (d *RawData) Scan(pointer interface{}) error {
+	if d.Native && d.codec != nil {
+		return d.codec.scanData(d.Body, pointer)
+	}
+	return gjson.New(d.Body).Scan(pointer)
+}
+
+// Interface 将数据解析为通用类型
+func (d *RawData) Interface() (interface{}, error) {
+	if d.Native && d.codec != nil {
+		return d.codec.decodeData(d.Body)
+	}
+	j, err := gjson.DecodeToJson(d.Body)
+	if err != nil {
+		return nil, err
+	}
+	return j.Interface(), nil
+}
+
+var (
+	codecs    = make(map[string]Codec)
+	codecIds  = make(map[byte]Codec)
+	codecsMu  sync.RWMutex
+	jsonCodec = new(JSONCodec)
+)
+
+func init() {
+	for _, codec := range []Codec{jsonCodec, new(MsgpackCodec), new(ProtobufCodec)} {
+		if err := RegisterCodec(codec); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterCodec 注册编解码器，通信双方需注册相同的编解码器
+func RegisterCodec(codec Codec) error {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	if codec.Id() == frameJSONStart {
+		return gerror.Newf("codec %v id conflicts with json frame", codec.Name())
+	}
+	if _, ok := codecs[codec.Name()]; ok {
+		return gerror.Newf("codec name duplicate registration:%v", codec.Name())
+	}
+	if _, ok := codecIds[codec.Id()]; ok {
+		return gerror.Newf("codec id duplicate registration:%v", codec.Id())
+	}
+	codecs[codec.Name()] = codec
+	codecIds[codec.Id()] = codec
+	return nil
+}
+
+// GetCodec 通过名称获取编解码器
+func GetCodec(name string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[name]
+	return codec, ok
+}
+
+// getCodecById 通过帧头标识获取编解码器
+func getCodecById(id byte) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecIds[id]
+	return codec, ok
+}
+
+// encoding 连接发送消息时使用的编码选项
+type encoding struct {
+	codec             Codec // 编解码器
+	compressThreshold int   // 压缩阈值，0不压缩
+	framed            bool  // 是否携带帧头，未协商时为false
+}
+
+// legacyEncoding 未协商编码的连接使用的默认选项
+var legacyEncoding = &encoding{codec: jsonCodec}
+
+// packFrame 编码消息并按需压缩
+func packFrame(enc *encoding, msg *Message) ([]byte, error) {
+	body, err := enc.codec.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enc.framed {
+		return body, nil
+	}
+
+	var flag byte
+	if enc.compressThreshold > 0 && len(body) > enc.compressThreshold {
+		if zipped, err := gcompress.Gzip(body); err == nil && len(zipped) < len(body) {
+			body = zipped
+			flag |= frameFlagGzip
+		}
+	}
+
+	frame := make([]byte, frameHeaderLen, frameHeaderLen+len(body))
+	frame[0] = enc.codec.Id()
+	frame[1] = flag
+	return append(frame, body...), nil
+}
+
+// unpackFrame 解析帧并解码消息，兼容未携带帧头的JSON消息
+func unpackFrame(data []byte) (msg *Message, err error) {
+	if len(data) == 0 {
+		return nil, gerror.New("empty package")
+	}
+
+	if data[0] == frameJSONStart {
+		msg, err = jsonCodec.Unmarshal(data)
+	} else {
+		msg, err = unpackHeaderFrame(data)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if msg.Router == "" {
+		return nil, gerror.Newf("message is not router: %+v", msg)
+	}
+	return msg, nil
+}
+
+// unpackHeaderFrame 解析携带帧头的消息
+func unpackHeaderFrame(data []byte) (*Message, error) {
+	if len(data) < frameHeaderLen {
+		return nil, gerror.Newf("invalid package header: %v", data)
+	}
+
+	codec, ok := getCodecById(data[0])
+	if !ok {
+		return nil, gerror.Newf("unsupported codec id: %v", data[0])
+	}
+
+	body := data[frameHeaderLen:]
+	if data[1]&frameFlagGzip != 0 {
+		unzipped, err := unGzipLimit(body, MaxUnzipSize)
+		if err != nil {
+			return nil, gerror.Wrap(err, "package ungzip failed")
+		}
+		body = unzipped
+	}
+	return codec.Unmarshal(body)
+}
+
+// isCompressedFrame 是否为压缩帧
+func isCompressedFrame(data []byte) bool {
+	return len(data) >= frameHeaderLen && data[0] != frameJSONStart && data[1]&frameFlagGzip != 0
+}
+
+// unGzipLimit 解压数据，解压后超出limit时返回错误，避免压缩炸弹耗尽内存
+func unGzipLimit(data []byte, limit int64) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	body, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, gerror.Newf("unzipped package exceeds %v bytes", limit)
+	}
+	return body, nil
+}
+
+// isNilPointer 是否为空指针
+func isNilPointer(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
@@ -0,0 +1,39 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"encoding/json"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// JSONCodec JSON编解码器，默认编码
+type JSONCodec struct{}
+
+// Id 编码标识
+func (c *JSONCodec) Id() byte {
+	return 1
+}
+
+// Name 编码名称
+func (c *JSONCodec) Name() string {
+	return CodecJSON
+}
+
+// Marshal 编码消息
+func (c *JSONCodec) Marshal(msg *Message) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+// Unmarshal 解码消息
+func (c *JSONCodec) Unmarshal(data []byte) (*Message, error) {
+	var msg Message
+	if err := gconv.Scan(data, &msg); err != nil {
+		return nil, gerror.Newf("invalid package struct: %s", err.Error())
+	}
+	return &msg, nil
+}
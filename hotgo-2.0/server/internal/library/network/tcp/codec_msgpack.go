@@ -0,0 +1,126 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// MsgpackCodec MessagePack编解码器
+// 消息数据实现了msgp.Marshaler/msgp.Unmarshaler(可通过msgp工具生成)时直接调用生成的方法，否则按json标签通过反射编码为MessagePack
+// 解码时兼容旧版本发送的json字段
+type MsgpackCodec struct{}
+
+// Id 编码标识
+func (c *MsgpackCodec) Id() byte {
+	return 2
+}
+
+// Name 编码名称
+func (c *MsgpackCodec) Name() string {
+	return CodecMsgpack
+}
+
+// Marshal 编码消息
+func (c *MsgpackCodec) Marshal(msg *Message) (b []byte, err error) {
+	b = msgp.AppendMapHeader(make([]byte, 0, 128), 7)
+	b = msgp.AppendString(b, "router")
+	b = msgp.AppendString(b, msg.Router)
+	b = msgp.AppendString(b, "traceId")
+	b = msgp.AppendString(b, msg.TraceId)
+	b = msgp.AppendString(b, "msgId")
+	b = msgp.AppendString(b, msg.MsgId)
+	b = msgp.AppendString(b, "error")
+	b = msgp.AppendString(b, msg.Error)
+	b = msgp.AppendString(b, "type")
+	b = msgp.AppendInt(b, msg.Type)
+	b = msgp.AppendString(b, "timeout")
+	b = msgp.AppendInt64(b, msg.Timeout)
+
+	b = msgp.AppendString(b, "data")
+	if v, ok := msg.Data.(msgp.Marshaler); ok && !isNilPointer(msg.Data) {
+		return v.MarshalMsg(b)
+	}
+
+	value, err := normalizeData(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	return msgp.AppendIntf(b, value)
+}
+
+// Unmarshal 解码消息
+func (c *MsgpackCodec) Unmarshal(data []byte) (*Message, error) {
+	sz, o, err := msgp.ReadMapHeaderBytes(data)
+	if err != nil {
+		return nil, gerror.Wrap(err, "invalid msgpack package")
+	}
+
+	var (
+		msg = new(Message)
+		key []byte
+	)
+	for i := uint32(0); i < sz; i++ {
+		if key, o, err = msgp.ReadMapKeyZC(o); err != nil {
+			return nil, gerror.Wrap(err, "invalid msgpack package")
+		}
+
+		switch string(key) {
+		case "router":
+			msg.Router, o, err = msgp.ReadStringBytes(o)
+		case "traceId":
+			msg.TraceId, o, err = msgp.ReadStringBytes(o)
+		case "msgId":
+			msg.MsgId, o, err = msgp.ReadStringBytes(o)
+		case "error":
+			msg.Error, o, err = msgp.ReadStringBytes(o)
+		case "type":
+			msg.Type, o, err = msgp.ReadIntBytes(o)
+		case "timeout":
+			msg.Timeout, o, err = msgp.ReadInt64Bytes(o)
+		case "data":
+			var rest []byte
+			if rest, err = msgp.Skip(o); err == nil {
+				msg.Data = &RawData{Body: o[:len(o)-len(rest)], Native: true, codec: c}
+				o = rest
+			}
+		case "json":
+			var body []byte
+			if body, o, err = msgp.ReadBytesBytes(o, nil); err == nil {
+				msg.Data = &RawData{Body: body}
+			}
+		default:
+			o, err = msgp.Skip(o)
+		}
+
+		if err != nil {
+			return nil, gerror.Wrapf(err, "invalid msgpack field:%s", key)
+		}
+	}
+	return msg, nil
+}
+
+// scanData 将原生格式的数据解析到pointer
+func (c *MsgpackCodec) scanData(body []byte, pointer interface{}) error {
+	if v, ok := pointer.(msgp.Unmarshaler); ok {
+		_, err := v.UnmarshalMsg(body)
+		return err
+	}
+
+	value, err := c.decodeData(body)
+	if err != nil {
+		return err
+	}
+	return gconv.Scan(value, pointer)
+}
+
+// decodeData 将原生格式的数据解析为通用类型
+func (c *MsgpackCodec) decodeData(body []byte) (interface{}, error) {
+	value, _, err := msgp.ReadIntfBytes(body)
+	return value, err
+}
@@ -0,0 +1,190 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/util/gconv"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// 消息信封字段编号
+const (
+	pbFieldRouter protowire.Number = iota + 1
+	pbFieldTraceId
+	pbFieldMsgId
+	pbFieldError
+	pbFieldData
+	pbFieldJSON
+	pbFieldType
+	pbFieldTimeout
+	pbFieldValue
+)
+
+// ProtobufCodec protobuf编解码器
+// 消息数据实现了proto.Message时直接编码，否则按json标签转换为google.protobuf.Value编码，解码时兼容旧版本发送的JSON字段
+type ProtobufCodec struct{}
+
+// pbValueCodec google.protobuf.Value格式的数据解析器
+type pbValueCodec struct{}
+
+// Id 编码标识
+func (c *ProtobufCodec) Id() byte {
+	return 3
+}
+
+// Name 编码名称
+func (c *ProtobufCodec) Name() string {
+	return CodecProtobuf
+}
+
+// Marshal 编码消息
+func (c *ProtobufCodec) Marshal(msg *Message) ([]byte, error) {
+	b := make([]byte, 0, 128)
+	b = appendProtoString(b, pbFieldRouter, msg.Router)
+	b = appendProtoString(b, pbFieldTraceId, msg.TraceId)
+	b = appendProtoString(b, pbFieldMsgId, msg.MsgId)
+	b = appendProtoString(b, pbFieldError, msg.Error)
+	b = appendProtoVarint(b, pbFieldType, uint64(msg.Type))
+	b = appendProtoVarint(b, pbFieldTimeout, uint64(msg.Timeout))
+
+	if v, ok := msg.Data.(proto.Message); ok && !isNilPointer(msg.Data) {
+		body, err := proto.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, pbFieldData, protowire.BytesType)
+		return protowire.AppendBytes(b, body), nil
+	}
+
+	value, err := normalizeData(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	pbValue, err := structpb.NewValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := proto.Marshal(pbValue)
+	if err != nil {
+		return nil, err
+	}
+	b = protowire.AppendTag(b, pbFieldValue, protowire.BytesType)
+	return protowire.AppendBytes(b, body), nil
+}
+
+// Unmarshal 解码消息
+func (c *ProtobufCodec) Unmarshal(data []byte) (*Message, error) {
+	msg := new(Message)
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, gerror.Wrap(protowire.ParseError(n), "invalid protobuf package")
+		}
+		data = data[n:]
+
+		if typ == protowire.VarintType && (num == pbFieldType || num == pbFieldTimeout) {
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, gerror.Wrapf(protowire.ParseError(n), "invalid protobuf field:%v", num)
+			}
+			data = data[n:]
+
+			if num == pbFieldType {
+				msg.Type = int(v)
+			} else {
+				msg.Timeout = int64(v)
+			}
+			continue
+		}
+
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, data); n < 0 {
+				return nil, gerror.Wrap(protowire.ParseError(n), "invalid protobuf package")
+			}
+			data = data[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return nil, gerror.Wrapf(protowire.ParseError(n), "invalid protobuf field:%v", num)
+		}
+		data = data[n:]
+
+		switch num {
+		case pbFieldRouter:
+			msg.Router = string(v)
+		case pbFieldTraceId:
+			msg.TraceId = string(v)
+		case pbFieldMsgId:
+			msg.MsgId = string(v)
+		case pbFieldError:
+			msg.Error = string(v)
+		case pbFieldData:
+			msg.Data = &RawData{Body: v, Native: true, codec: c}
+		case pbFieldValue:
+			msg.Data = &RawData{Body: v, Native: true, codec: pbValueCodec{}}
+		case pbFieldJSON:
+			msg.Data = &RawData{Body: v}
+		}
+	}
+	return msg, nil
+}
+
+// scanData 将原生格式的数据解析到pointer
+func (c *ProtobufCodec) scanData(body []byte, pointer interface{}) error {
+	v, ok := pointer.(proto.Message)
+	if !ok {
+		return gerror.Newf("protobuf data requires proto.Message, got %T", pointer)
+	}
+	return proto.Unmarshal(body, v)
+}
+
+// decodeData 原生格式缺少类型信息，无法解析为通用类型
+func (c *ProtobufCodec) decodeData(body []byte) (interface{}, error) {
+	return nil, gerror.New("protobuf data can only be scanned into proto.Message, use RequestScan instead")
+}
+
+// scanData 将google.protobuf.Value格式的数据解析到pointer
+func (c pbValueCodec) scanData(body []byte, pointer interface{}) error {
+	value, err := c.decodeData(body)
+	if err != nil {
+		return err
+	}
+	return gconv.Scan(value, pointer)
+}
+
+// decodeData 将google.protobuf.Value格式的数据解析为通用类型
+func (c pbValueCodec) decodeData(body []byte) (interface{}, error) {
+	var value structpb.Value
+	if err := proto.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+	return value.AsInterface(), nil
+}
+
+// appendProtoVarint 追加整数字段，零值省略
+func appendProtoVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// appendProtoString 追加字符串字段，空值省略
+func appendProtoString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
@@ -0,0 +1,176 @@
+// Package tcp_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp_test
+
+import (
+	"github.com/gogf/gf/v2/encoding/gcompress"
+	"github.com/gogf/gf/v2/net/gtcp"
+	"github.com/gogf/gf/v2/test/gtest"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"hotgo/internal/library/network/tcp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCodec(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		for _, name := range []string{tcp.CodecJSON, tcp.CodecMsgpack, tcp.CodecProtobuf} {
+			codec, ok := tcp.GetCodec(name)
+			t.Assert(ok, true)
+
+			b, err := codec.Marshal(&tcp.Message{Router: "TestMsgReq", MsgId: "1", Data: &TestMsgReq{Name: name}})
+			t.AssertNil(err)
+
+			msg, err := codec.Unmarshal(b)
+			t.AssertNil(err)
+			t.Assert(msg.Router, "TestMsgReq")
+			t.Assert(msg.MsgId, "1")
+
+			var req TestMsgReq
+			t.AssertNil(msg.Scan(&req))
+			t.Assert(req.Name, name)
+		}
+	})
+}
+
+func TestCodecProtobufNative(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		codec, _ := tcp.GetCodec(tcp.CodecProtobuf)
+		b, err := codec.Marshal(&tcp.Message{Router: "StringValue", Data: wrapperspb.String("hotgo")})
+		t.AssertNil(err)
+
+		msg, err := codec.Unmarshal(b)
+		t.AssertNil(err)
+
+		var res wrapperspb.StringValue
+		t.AssertNil(msg.Scan(&res))
+		t.Assert(res.GetValue(), "hotgo")
+	})
+}
+
+func TestCodecFrame(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		parser := tcp.NewMsgParser(nil)
+
+		// 未协商时兼容JSON消息
+		msg, err := parser.Encoding([]byte(`{"router":"TestMsgReq","data":{"name":"hotgo"}}`))
+		t.AssertNil(err)
+
+		var req TestMsgReq
+		t.AssertNil(msg.Scan(&req))
+		t.Assert(req.Name, "hotgo")
+
+		// 携带帧头的压缩消息
+		codec, _ := tcp.GetCodec(tcp.CodecMsgpack)
+		body, err := codec.Marshal(&tcp.Message{Router: "TestMsgReq", Data: &TestMsgReq{Name: strings.Repeat("a", 1024)}})
+		t.AssertNil(err)
+
+		zipped, err := gcompress.Gzip(body)
+		t.AssertNil(err)
+
+		msg, err = parser.Encoding(append([]byte{codec.Id(), 1}, zipped...))
+		t.AssertNil(err)
+		t.AssertNil(msg.Scan(&req))
+		t.Assert(len(req.Name), 1024)
+	})
+}
+
+type testCodecItem struct {
+	Id    int64  `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+type testCodecData struct {
+	Name   string            `json:"name"`
+	Count  int               `json:"count"`
+	Ratio  float64           `json:"ratio"`
+	Items  []*testCodecItem  `json:"items"`
+	Extra  map[string]string `json:"extra"`
+	Ignore string            `json:"-"`
+}
+
+func TestCodecGenericData(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		data := &testCodecData{
+			Name:   "hotgo",
+			Count:  3,
+			Ratio:  0.5,
+			Items:  []*testCodecItem{{Id: 1, Title: "a"}, {Id: 2}},
+			Extra:  map[string]string{"k": "v"},
+			Ignore: "ignore",
+		}
+
+		for _, name := range []string{tcp.CodecMsgpack, tcp.CodecProtobuf} {
+			codec, _ := tcp.GetCodec(name)
+			b, err := codec.Marshal(&tcp.Message{Router: "TestCodecData", Data: data})
+			t.AssertNil(err)
+
+			// 二进制编码不再回退为JSON
+			t.Assert(strings.Contains(string(b), `"name"`), false)
+
+			msg, err := codec.Unmarshal(b)
+			t.AssertNil(err)
+
+			var res testCodecData
+			t.AssertNil(msg.Scan(&res))
+			t.Assert(res.Name, "hotgo")
+			t.Assert(res.Count, 3)
+			t.Assert(res.Ratio, 0.5)
+			t.Assert(len(res.Items), 2)
+			t.Assert(res.Items[0].Title, "a")
+			t.Assert(res.Items[1].Id, 2)
+			t.Assert(res.Extra["k"], "v")
+			t.Assert(res.Ignore, "")
+		}
+	})
+}
+
+func TestCodecFrameUnzipLimit(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		zipped, err := gcompress.Gzip([]byte(strings.Repeat("a", tcp.MaxUnzipSize+1)))
+		t.AssertNil(err)
+
+		codec, _ := tcp.GetCodec(tcp.CodecMsgpack)
+		_, err = tcp.NewMsgParser(nil).Encoding(append([]byte{codec.Id(), 1}, zipped...))
+		t.AssertNE(err, nil)
+	})
+}
+
+func TestCodecCompressedBeforeLogin(t *testing.T) {
+	serv := tcp.NewServer(&tcp.ServerConfig{Name: "hotgo", Addr: "127.0.0.1:8015"})
+	go func() {
+		_ = serv.Listen()
+	}()
+	defer serv.Close()
+
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			conn *gtcp.Conn
+			err  error
+		)
+		for i := 0; i < 20; i++ {
+			if conn, err = gtcp.NewConn("127.0.0.1:8015", time.Second); err == nil {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.AssertNil(err)
+		defer conn.Close()
+
+		codec, _ := tcp.GetCodec(tcp.CodecMsgpack)
+		body, err := codec.Marshal(&tcp.Message{Router: "TestMsgReq", Data: &TestMsgReq{Name: "hotgo"}})
+		t.AssertNil(err)
+
+		zipped, err := gcompress.Gzip(body)
+		t.AssertNil(err)
+
+		// 未登录时发送压缩帧，服务器直接断开连接
+		t.AssertNil(conn.SendPkg(append([]byte{codec.Id(), 1}, zipped...)))
+		_, err = conn.RecvPkg(gtcp.PkgOption{Retry: gtcp.Retry{Count: 0}})
+		t.AssertNE(err, nil)
+	})
+}
@@ -0,0 +1,173 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"reflect"
+	"strings"
+)
+
+// textMarshaler 同encoding.TextMarshaler，包内的encoding已用于编码选项
+type textMarshaler interface {
+	MarshalText() (text []byte, err error)
+}
+
+// normalizeData 将任意消息数据转换为通用类型，用于二进制编码直接编码未生成原生编解码方法的数据
+// 转换结果只包含nil、bool、int64、uint64、float64、string、[]byte、[]interface{}和map[string]interface{}
+// 结构体按json标签命名字段，支持omitempty和忽略字段，实现了json.Marshaler或encoding.TextMarshaler的类型按其输出转换
+func normalizeData(data interface{}) (interface{}, error) {
+	return normalizeValue(reflect.ValueOf(data))
+}
+
+// normalizeValue 转换反射值
+func normalizeValue(rv reflect.Value) (interface{}, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return nil, nil
+	}
+
+	if rv.CanInterface() {
+		switch v := rv.Interface().(type) {
+		case json.Marshaler:
+			b, err := v.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			var value interface{}
+			if err = json.Unmarshal(b, &value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		case textMarshaler:
+			b, err := v.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return string(b), nil
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return normalizeValue(rv.Elem())
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return b, nil
+		}
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			v, err := normalizeValue(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			v, err := normalizeValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			m[mapKey(iter.Key())] = v
+		}
+		return m, nil
+	case reflect.Struct:
+		m := make(map[string]interface{}, rv.NumField())
+		if err := normalizeStruct(rv, m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	default:
+		return nil, gerror.Newf("unsupported data type:%v", rv.Type())
+	}
+}
+
+// normalizeStruct 按json标签将结构体字段写入m，未命名的嵌入结构体字段提升到外层
+func normalizeStruct(rv reflect.Value, m map[string]interface{}) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := rv.Field(i)
+
+		if field.Anonymous && name == "" {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Ptr {
+				continue
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := normalizeStruct(fv, m); err != nil {
+					return err
+				}
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+		}
+
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		v, err := normalizeValue(fv)
+		if err != nil {
+			return gerror.Wrapf(err, "field %v", field.Name)
+		}
+		m[name] = v
+	}
+	return nil
+}
+
+// mapKey 转换map的键
+func mapKey(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+	return fmt.Sprint(key.Interface())
+}
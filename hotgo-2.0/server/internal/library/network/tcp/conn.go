@@ -7,6 +7,8 @@ package tcp
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"github.com/gogf/gf/v2/container/gtype"
 	"github.com/gogf/gf/v2/container/gvar"
 	"github.com/gogf/gf/v2/errors/gcode"
@@ -18,7 +20,9 @@ import (
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/grand"
 	"net"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // AuthMeta 认证元数据
@@ -40,37 +44,50 @@ type Conn struct {
 	Heartbeat int64      // 心跳
 	FirstTime int64      // 首次连接时间
 
-	writeChan chan []byte  // 发数据
-	closeFlag *gtype.Bool  // 关闭标签
-	logger    *glog.Logger // 日志处理器
-	msgParser *MsgParser   // 消息处理器
+	writeChan   chan []byte              // 发数据
+	writeConfig *WriteConfig             // 写队列配置
+	queuedBytes atomic.Int64             // 写队列中等待发送的字节数
+	sentMsgs    atomic.Int64             // 已发送的消息数
+	sentBytes   atomic.Int64             // 已发送的字节数
+	droppedMsgs atomic.Int64             // 因队列溢出丢弃的消息数
+	closeFlag   *gtype.Bool              // 关闭标签
+	logger      *glog.Logger             // 日志处理器
+	msgParser   *MsgParser               // 消息处理器
+	encoding    atomic.Pointer[encoding] // 发送消息使用的编码
+
+	ctx          context.Context               // 连接上下文，连接关闭后取消
+	cancel       context.CancelFunc            // 取消连接上下文
+	calls        map[string]context.CancelFunc // 正在处理的rpc请求
+	callsMutex   sync.Mutex                    // rpc请求锁
+	inflight     chan struct{}                 // 发起中的rpc请求，用于限制并发数量
+	peerProtocol atomic.Int32                  // 对端协议版本
+	outbound     bool                          // 是否为客户端主动发起的连接，对端为服务器
 }
 
 var idCounter int64
 
-func NewConn(conn *gtcp.Conn, logger *glog.Logger, msgParser *MsgParser) *Conn {
+// NewConn 初始化连接，writeConfig为空时使用默认的写队列配置
+func NewConn(conn *gtcp.Conn, logger *glog.Logger, msgParser *MsgParser, writeConfig ...*WriteConfig) *Conn {
 	tcpConn := new(Conn)
 	tcpConn.CID = atomic.AddInt64(&idCounter, 1)
 	tcpConn.Conn = conn
 	tcpConn.Heartbeat = gtime.Timestamp()
 	tcpConn.FirstTime = gtime.Timestamp()
 
-	tcpConn.writeChan = make(chan []byte, 1000)
+	if len(writeConfig) > 0 {
+		tcpConn.writeConfig = normalizeWriteConfig(writeConfig[0])
+	} else {
+		tcpConn.writeConfig = normalizeWriteConfig(nil)
+	}
+	tcpConn.writeChan = make(chan []byte, tcpConn.writeConfig.BufferSize)
 	tcpConn.closeFlag = gtype.NewBool(false)
 	tcpConn.logger = logger
 	tcpConn.msgParser = msgParser
+	tcpConn.encoding.Store(legacyEncoding)
+	tcpConn.ctx, tcpConn.cancel = context.WithCancel(context.Background())
+	tcpConn.calls = make(map[string]context.CancelFunc)
 
-	go func() {
-		for b := range tcpConn.writeChan {
-			if b == nil {
-				break
-			}
-			if err := conn.SendPkg(b); err != nil {
-				logger.Errorf(gctx.New(), "SendPkg err:%+v", err)
-				break
-			}
-		}
-	}()
+	go tcpConn.writeLoop()
 	return tcpConn
 }
 
@@ -85,11 +102,22 @@ func (c *Conn) Run() error {
 			return nil
 		}
 
+		// 服务器在客户端登录认证前不接受压缩帧，避免未认证的连接通过压缩数据消耗内存
+		if !c.outbound && c.Auth == nil && isCompressedFrame(data) {
+			return gerror.NewCode(gcode.CodeInvalidRequest, "compressed frame is not allowed before login, conn closed")
+		}
+
 		msg, err := c.msgParser.Encoding(data)
 		if err != nil {
 			return gerror.NewCodef(gcode.CodeInternalError, "message encoding err:%+v conn closed", err)
 		}
 
+		// 取消帧直接结束对应的rpc处理，不经过拦截器
+		if msg.Type == MsgTypeCancel {
+			c.cancelCall(msg.MsgId)
+			continue
+		}
+
 		ctx, err := c.bindContext(msg)
 		if err != nil {
 			return gerror.NewCodef(gcode.CodeInternalError, "bindContext err:%+v message: %+v", err, msg)
@@ -116,11 +144,38 @@ func (c *Conn) LocalAddr() net.Addr {
 	return c.Conn.LocalAddr()
 }
 
-// Write
-func (c *Conn) Write(b []byte) {
-	if !c.closeFlag.Val() {
-		c.writeChan <- b
+// IsTLS 是否为TLS加密连接
+func (c *Conn) IsTLS() bool {
+	_, ok := c.Conn.Conn.(*tls.Conn)
+	return ok
+}
+
+// PeerFingerprint 对端证书指纹，非TLS连接或对端未提供证书时为空
+func (c *Conn) PeerFingerprint() string {
+	tlsConn, ok := c.Conn.Conn.(*tls.Conn)
+	if !ok {
+		return ""
 	}
+
+	state := tlsConn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return ""
+	}
+	return CertFingerprint(state.PeerCertificates[0])
+}
+
+// SetCodec 设置发送消息使用的编解码器和压缩阈值，仅在登录协商成功后调用，对端需支持帧头解析
+func (c *Conn) SetCodec(codec Codec, compressThreshold int) {
+	c.encoding.Store(&encoding{
+		codec:             codec,
+		compressThreshold: compressThreshold,
+		framed:            true,
+	})
+}
+
+// Codec 获取发送消息使用的编解码器
+func (c *Conn) Codec() Codec {
+	return c.encoding.Load().codec
 }
 
 // Send 发送消息
@@ -128,12 +183,28 @@ func (c *Conn) Send(ctx context.Context, data interface{}) error {
 	if c.closeFlag.Val() {
 		return gerror.New("conn is closed")
 	}
-	b, err := c.msgParser.Decoding(ctx, data, "")
+	message, err := c.msgParser.doDecoding(ctx, data, "")
 	if err != nil {
 		return err
 	}
-	c.Write(b)
-	return nil
+	return c.writeMessage(message)
+}
+
+// sendRaw 发送已编码为JSON的消息数据，用于集群转发
+func (c *Conn) sendRaw(ctx context.Context, router string, data json.RawMessage) error {
+	if c.closeFlag.Val() {
+		return gerror.New("conn is closed")
+	}
+	return c.writeMessage(&Message{Router: router, TraceId: gctx.CtxId(ctx), Data: data})
+}
+
+// writeMessage 按当前编码发送消息
+func (c *Conn) writeMessage(message *Message) error {
+	b, err := packFrame(c.encoding.Load(), message)
+	if err != nil {
+		return err
+	}
+	return c.Write(b)
 }
 
 func (c *Conn) Close() {
@@ -141,34 +212,221 @@ func (c *Conn) Close() {
 		return
 	}
 	c.closeFlag.Set(true)
-	close(c.writeChan)
+	c.cancel()
 	c.Conn.Close()
 }
 
+// SetMaxInflight 设置rpc请求的并发上限，对发起和处理的请求分别生效，0不限制，需在连接开始收发消息前设置
+func (c *Conn) SetMaxInflight(n int) {
+	if n > 0 {
+		c.inflight = make(chan struct{}, n)
+	}
+}
+
+// PeerProtocol 对端协议版本，登录完成前或对端为旧版本时为0
+func (c *Conn) PeerProtocol() int {
+	return int(c.peerProtocol.Load())
+}
+
+// setPeerProtocol 设置对端协议版本
+func (c *Conn) setPeerProtocol(version int) {
+	c.peerProtocol.Store(int32(version))
+}
+
 // Request 发送消息并等待响应结果
 func (c *Conn) Request(ctx context.Context, data interface{}) (interface{}, error) {
-	if c.closeFlag.Val() {
-		return nil, gerror.New("conn is closed")
-	}
-	msgId := grand.S(16)
-	b, err := c.msgParser.Decoding(ctx, data, msgId)
-	if err != nil {
-		return nil, err
+	body, err := c.request(ctx, data)
+	if raw, ok := body.(*RawData); ok {
+		value, rawErr := raw.Interface()
+		if err == nil {
+			err = rawErr
+		}
+		return value, err
 	}
-	return c.msgParser.rpc.Request(ctx, msgId, func() {
-		c.Write(b)
-	})
+	return body, err
 }
 
 // RequestScan 发送消息并等待响应结果，将结果保存在response中
 func (c *Conn) RequestScan(ctx context.Context, data, response interface{}) error {
-	body, err := c.Request(ctx, data)
+	body, err := c.request(ctx, data)
 	if err != nil {
 		return err
 	}
+	if raw, ok := body.(*RawData); ok {
+		return raw.Scan(response)
+	}
 	return gvar.New(body).Scan(response)
 }
 
+// request 发送消息并等待响应结果，二进制编码时返回未解析的*RawData
+func (c *Conn) request(ctx context.Context, data interface{}) (interface{}, error) {
+	message, err := c.msgParser.doDecoding(ctx, data, grand.S(16))
+	if err != nil {
+		return nil, err
+	}
+	return c.doRequest(ctx, message)
+}
+
+// requestRaw 发送已编码为JSON的rpc请求并等待响应结果，用于集群转发
+func (c *Conn) requestRaw(ctx context.Context, router string, data json.RawMessage) (interface{}, error) {
+	return c.doRequest(ctx, &Message{Router: router, TraceId: gctx.CtxId(ctx), MsgId: grand.S(16), Data: data})
+}
+
+// doRequest 发送rpc请求并等待响应结果
+func (c *Conn) doRequest(ctx context.Context, message *Message) (interface{}, error) {
+	ctx, release, err := c.beginRequest(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	b, err := c.packRequest(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.msgParser.rpc.Request(ctx, message.MsgId, func() {
+		if err := c.Write(b); err != nil {
+			c.msgParser.rpc.fail(message.MsgId, err)
+		}
+	})
+
+	if ctx.Err() != nil {
+		if c.ctx.Err() != nil {
+			return nil, gerror.New("conn is closed")
+		}
+		c.cancelRemote(message)
+	}
+	return res, err
+}
+
+// RequestStream 发起流式RPC请求，响应按顺序写入返回的通道，流结束、出错或ctx取消后通道关闭
+// ctx未设置截止时间时不会超时，不再需要时请取消ctx，对端需注册对应的流式路由
+func (c *Conn) RequestStream(ctx context.Context, data interface{}) (<-chan *StreamResponse, error) {
+	if c.PeerProtocol() < ProtocolVersion {
+		return nil, gerror.New("peer does not support stream rpc")
+	}
+
+	message, err := c.msgParser.doDecoding(ctx, data, grand.S(16))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, release, err := c.beginRequest(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := c.packRequest(ctx, message)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	ch, done := c.msgParser.rpc.Stream(ctx, message.MsgId, func() {
+		if err := c.Write(b); err != nil {
+			c.msgParser.rpc.fail(message.MsgId, err)
+		}
+	})
+
+	go func() {
+		<-done
+		if ctx.Err() != nil && c.ctx.Err() == nil {
+			c.cancelRemote(message)
+		}
+		release()
+	}()
+	return ch, nil
+}
+
+// beginRequest 占用并发名额并绑定请求上下文，连接关闭时请求随之取消
+func (c *Conn) beginRequest(ctx context.Context, withTimeout bool) (context.Context, func(), error) {
+	if c.closeFlag.Val() {
+		return nil, nil, gerror.New("conn is closed")
+	}
+
+	if c.inflight != nil {
+		select {
+		case c.inflight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, nil, ctxError(ctx)
+		case <-c.ctx.Done():
+			return nil, nil, gerror.New("conn is closed")
+		}
+	}
+
+	var cancel context.CancelFunc
+	if _, ok := ctx.Deadline(); !ok && withTimeout {
+		ctx, cancel = context.WithTimeout(ctx, time.Second*RPCTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	stop := context.AfterFunc(c.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		if c.inflight != nil {
+			<-c.inflight
+		}
+	}, nil
+}
+
+// packRequest 编码rpc请求，携带剩余超时时间以便对端同步结束处理
+func (c *Conn) packRequest(ctx context.Context, message *Message) ([]byte, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		message.Timeout = max(time.Until(deadline).Milliseconds(), 1)
+	}
+
+	return packFrame(c.encoding.Load(), message)
+}
+
+// cancelRemote 通知对端取消rpc请求，对端为旧版本时不发送
+func (c *Conn) cancelRemote(message *Message) {
+	if c.PeerProtocol() < ProtocolVersion || c.closeFlag.Val() {
+		return
+	}
+	_ = c.writeMessage(&Message{Router: message.Router, TraceId: message.TraceId, MsgId: message.MsgId, Type: MsgTypeCancel})
+}
+
+// startCall 登记正在处理的rpc请求，返回可被调用方取消的上下文
+func (c *Conn) startCall(ctx context.Context, msg *Message) (context.Context, func(), error) {
+	c.callsMutex.Lock()
+	defer c.callsMutex.Unlock()
+
+	if c.inflight != nil && len(c.calls) >= cap(c.inflight) {
+		return ctx, nil, gerror.New("too many in-flight rpc requests, please try again later")
+	}
+
+	var cancel context.CancelFunc
+	if msg.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(msg.Timeout)*time.Millisecond)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	stop := context.AfterFunc(c.ctx, cancel)
+	c.calls[msg.MsgId] = cancel
+	return ctx, func() {
+		stop()
+		cancel()
+		c.callsMutex.Lock()
+		delete(c.calls, msg.MsgId)
+		c.callsMutex.Unlock()
+	}, nil
+}
+
+// cancelCall 取消正在处理的rpc请求
+func (c *Conn) cancelCall(msgId string) {
+	c.callsMutex.Lock()
+	cancel, ok := c.calls[msgId]
+	c.callsMutex.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
 // bindContext 将用户身份绑定到上下文
 func (c *Conn) bindContext(msg *Message) (ctx context.Context, err error) {
 	ctx = initCtx(gctx.New(), &Context{
@@ -0,0 +1,165 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gctx"
+	"time"
+)
+
+// 写队列溢出策略
+const (
+	OverflowBlock      = "block"      // 阻塞等待，超过等待时间后丢弃当前消息并返回错误
+	OverflowDropOldest = "dropOldest" // 丢弃队列中最早的消息
+	OverflowDisconnect = "disconnect" // 断开连接
+)
+
+const (
+	defaultWriteBufferSize   = 1000
+	defaultWriteBlockTimeout = 5 * time.Second
+)
+
+// WriteConfig 连接写队列配置
+type WriteConfig struct {
+	BufferSize   int           // 写队列长度，默认1000
+	Overflow     string        // 写队列满时的处理策略，默认block
+	BlockTimeout time.Duration // block策略的最长等待时间，默认5秒
+	WriteTimeout time.Duration // 单条消息的写超时时间，超时后断开连接，0不限制
+}
+
+// WriteStats 连接写队列统计
+type WriteStats struct {
+	QueuedMsgs  int   `json:"queuedMsgs"`  // 等待发送的消息数
+	QueuedBytes int64 `json:"queuedBytes"` // 等待发送的字节数
+	SentMsgs    int64 `json:"sentMsgs"`    // 已发送的消息数
+	SentBytes   int64 `json:"sentBytes"`   // 已发送的字节数
+	DroppedMsgs int64 `json:"droppedMsgs"` // 因队列溢出丢弃的消息数
+}
+
+// normalizeWriteConfig 补全写队列配置的默认值
+func normalizeWriteConfig(config *WriteConfig) *WriteConfig {
+	c := WriteConfig{}
+	if config != nil {
+		c = *config
+	}
+
+	if c.BufferSize <= 0 {
+		c.BufferSize = defaultWriteBufferSize
+	}
+
+	switch c.Overflow {
+	case OverflowBlock, OverflowDropOldest, OverflowDisconnect:
+	default:
+		c.Overflow = OverflowBlock
+	}
+
+	if c.BlockTimeout <= 0 {
+		c.BlockTimeout = defaultWriteBlockTimeout
+	}
+	return &c
+}
+
+// Write 将数据放入写队列，队列已满时按溢出策略处理
+func (c *Conn) Write(b []byte) error {
+	if c.closeFlag.Val() {
+		return gerror.New("conn is closed")
+	}
+
+	size := int64(len(b))
+	c.queuedBytes.Add(size)
+
+	select {
+	case c.writeChan <- b:
+		return nil
+	default:
+	}
+
+	switch c.writeConfig.Overflow {
+	case OverflowDropOldest:
+		for {
+			select {
+			case old := <-c.writeChan:
+				c.queuedBytes.Add(-int64(len(old)))
+				c.droppedMsgs.Add(1)
+			default:
+			}
+
+			select {
+			case c.writeChan <- b:
+				return nil
+			case <-c.ctx.Done():
+				c.queuedBytes.Add(-size)
+				return gerror.New("conn is closed")
+			default:
+			}
+		}
+
+	case OverflowDisconnect:
+		c.queuedBytes.Add(-size)
+		c.droppedMsgs.Add(1)
+		c.logger.Warningf(gctx.New(), "conn %v write queue overflow, close conn", c.RemoteAddr())
+		c.Close()
+		return gerror.New("write queue overflow, conn closed")
+
+	default:
+		timer := time.NewTimer(c.writeConfig.BlockTimeout)
+		defer timer.Stop()
+
+		select {
+		case c.writeChan <- b:
+			return nil
+		case <-timer.C:
+			c.queuedBytes.Add(-size)
+			c.droppedMsgs.Add(1)
+			return gerror.New("write queue is full, wait timeout")
+		case <-c.ctx.Done():
+			c.queuedBytes.Add(-size)
+			return gerror.New("conn is closed")
+		}
+	}
+}
+
+// writeLoop 按顺序发送写队列中的数据，发送失败时关闭连接，由读取协程结束后完成清理
+func (c *Conn) writeLoop() {
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case b := <-c.writeChan:
+			c.queuedBytes.Add(-int64(len(b)))
+
+			var err error
+			if c.writeConfig.WriteTimeout > 0 {
+				err = c.Conn.SendPkgWithTimeout(b, c.writeConfig.WriteTimeout)
+			} else {
+				err = c.Conn.SendPkg(b)
+			}
+
+			if err != nil {
+				if !c.closeFlag.Val() {
+					c.logger.Warningf(gctx.New(), "conn %v SendPkg err:%+v, close conn", c.RemoteAddr(), err)
+				}
+				c.Close()
+				return
+			}
+
+			c.sentMsgs.Add(1)
+			c.sentBytes.Add(int64(len(b)))
+		}
+	}
+}
+
+// WriteStats 获取写队列统计
+func (c *Conn) WriteStats() WriteStats {
+	return WriteStats{
+		QueuedMsgs:  len(c.writeChan),
+		QueuedBytes: max(c.queuedBytes.Load(), 0),
+		SentMsgs:    c.sentMsgs.Load(),
+		SentBytes:   c.sentBytes.Load(),
+		DroppedMsgs: c.droppedMsgs.Load(),
+	}
+}
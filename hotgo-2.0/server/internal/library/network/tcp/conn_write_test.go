@@ -0,0 +1,91 @@
+// Package tcp_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp_test
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/gtcp"
+	"github.com/gogf/gf/v2/test/gtest"
+	"hotgo/internal/library/network/tcp"
+	"net"
+	"testing"
+	"time"
+)
+
+// newBlockedConn 创建一个对端不读取数据的连接
+func newBlockedConn(config *tcp.WriteConfig) (*tcp.Conn, net.Conn) {
+	local, remote := net.Pipe()
+	return tcp.NewConn(gtcp.NewConnByNetConn(local), g.Log(), tcp.NewMsgParser(nil), config), remote
+}
+
+func TestConnWriteOverflow(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		conn, remote := newBlockedConn(&tcp.WriteConfig{BufferSize: 2, Overflow: tcp.OverflowDropOldest})
+		defer remote.Close()
+		defer conn.Close()
+
+		// 第一条消息由写协程取出后阻塞在发送中
+		t.AssertNil(conn.Write([]byte("hotgo")))
+		time.Sleep(50 * time.Millisecond)
+
+		for i := 0; i < 5; i++ {
+			t.AssertNil(conn.Write([]byte("hotgo")))
+		}
+
+		stats := conn.WriteStats()
+		t.Assert(stats.QueuedMsgs, 2)
+		t.Assert(stats.QueuedBytes, 10)
+		t.Assert(stats.DroppedMsgs, 3)
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		conn, remote := newBlockedConn(&tcp.WriteConfig{BufferSize: 1, Overflow: tcp.OverflowBlock, BlockTimeout: 50 * time.Millisecond})
+		defer remote.Close()
+		defer conn.Close()
+
+		var err error
+		for i := 0; i < 3 && err == nil; i++ {
+			err = conn.Write([]byte("hotgo"))
+		}
+		t.AssertNE(err, nil)
+		t.Assert(conn.WriteStats().DroppedMsgs, 1)
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		conn, remote := newBlockedConn(&tcp.WriteConfig{BufferSize: 1, Overflow: tcp.OverflowDisconnect})
+		defer remote.Close()
+
+		var err error
+		for i := 0; i < 3 && err == nil; i++ {
+			err = conn.Write([]byte("hotgo"))
+		}
+		t.AssertNE(err, nil)
+		t.AssertNE(conn.Write([]byte("hotgo")), nil)
+	})
+}
+
+func TestConnWriteTimeout(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		conn, remote := newBlockedConn(&tcp.WriteConfig{WriteTimeout: 50 * time.Millisecond})
+		defer remote.Close()
+
+		t.AssertNil(conn.Write([]byte("hotgo")))
+
+		// 写超时后连接被关闭，读取方随之结束
+		done := make(chan error, 1)
+		go func() {
+			done <- conn.Run()
+		}()
+
+		select {
+		case err := <-done:
+			t.AssertNE(err, nil)
+		case <-time.After(time.Second):
+			t.Error("conn was not closed after write timeout")
+		}
+		t.AssertNE(conn.Write([]byte("hotgo")), nil)
+	})
+}
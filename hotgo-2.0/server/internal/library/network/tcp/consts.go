@@ -7,14 +7,28 @@ package tcp
 
 // 定时任务
 const (
-	CronHeartbeatVerify = "tcpHeartbeatVerify"
-	CronHeartbeat       = "tcpHeartbeat"
-	CronAuthVerify      = "tcpAuthVerify"
+	CronHeartbeatVerify  = "tcpHeartbeatVerify"
+	CronHeartbeat        = "tcpHeartbeat"
+	CronAuthVerify       = "tcpAuthVerify"
+	CronClusterHeartbeat = "tcpClusterHeartbeat"
 )
 
 const (
 	HeartbeatTimeout = 300 // tcp心跳超时，默认300s
-	RPCTimeout       = 10  // rpc通讯超时时间， 默认10s
+	RPCTimeout       = 10  // rpc通讯超时时间，ctx未设置截止时间时使用， 默认10s
+	StreamBufferSize = 64  // 流式响应的接收缓冲数量
+)
+
+// ProtocolVersion 协议版本，登录时交换，对端版本>=1时才会发送取消帧和流式响应
+const ProtocolVersion = 1
+
+// 消息类型
+const (
+	MsgTypeDefault   = iota // 普通消息或rpc请求
+	MsgTypeReply            // rpc响应
+	MsgTypeCancel           // 取消rpc请求
+	MsgTypeStream           // 流式响应数据
+	MsgTypeStreamEnd        // 流式响应结束
 )
 
 const (
@@ -22,6 +36,7 @@ const (
 	ParseRouterRPCErrInvalidParams   = "register RPC router [%v] method must have two response params"
 	ParseRouterErrInvalidFirstParam  = "the first params of the processing method that registers the router[%v] must be of type context.Context"
 	ParseRouterErrInvalidSecondParam = "the second params of the processing method that registers the router[%v] must be of type pointer to a struct"
+	ParseStreamErrInvalidParams      = "register stream router[%v] method must be func(ctx context.Context, req *XxxReq, stream *tcp.Stream) error"
 )
 
 type CtxKey string
@@ -65,11 +65,15 @@ type ServerLoginReq struct {
 	AppId     string `json:"appID"            description:"应用ID"`
 	Timestamp int64  `json:"timestamp"        description:"服务器时间戳"`
 	Sign      string `json:"sign"             description:"签名"`
+	Codec     string `json:"codec,omitempty"  description:"期望使用的编码"` // 为空时不协商，始终使用JSON编码
+	Protocol  int    `json:"protocol,omitempty" description:"协议版本"`
 }
 
 // ServerLoginRes 响应服务登录
 type ServerLoginRes struct {
 	ServerRes
+	Codec    string `json:"codec,omitempty" description:"协商后的编码"` // 为空时服务器不支持协商，继续使用JSON编码
+	Protocol int    `json:"protocol,omitempty" description:"服务器协议版本"`
 }
 
 // ServerHeartbeatReq 心跳
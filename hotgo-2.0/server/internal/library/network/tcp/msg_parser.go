@@ -7,12 +7,10 @@ package tcp
 
 import (
 	"context"
-	"encoding/json"
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/errors/gcode"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/os/gctx"
-	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/gogf/gf/v2/util/gutil"
 	"reflect"
 	"sync"
@@ -35,11 +33,21 @@ type Interceptor func(ctx context.Context, msg *Message) (err error)
 
 // Message 标准消息
 type Message struct {
-	Router  string      `json:"router"`          // 路由
-	TraceId string      `json:"traceId"`         // 链路ID
-	Data    interface{} `json:"data"`            // 数据
-	MsgId   string      `json:"msgId,omitempty"` // 消息ID，rpc用
-	Error   string      `json:"error,omitempty"` // 消息错误，rpc用
+	Router  string      `json:"router"`            // 路由
+	TraceId string      `json:"traceId"`           // 链路ID
+	Data    interface{} `json:"data"`              // 数据
+	MsgId   string      `json:"msgId,omitempty"`   // 消息ID，rpc用
+	Error   string      `json:"error,omitempty"`   // 消息错误，rpc用
+	Type    int         `json:"type,omitempty"`    // 消息类型，rpc用
+	Timeout int64       `json:"timeout,omitempty"` // 请求剩余超时时间(毫秒)，rpc用
+}
+
+// Scan 将消息数据解析到pointer，二进制编码时Data为*RawData
+func (msg *Message) Scan(pointer interface{}) error {
+	if raw, ok := msg.Data.(*RawData); ok {
+		return raw.Scan(pointer)
+	}
+	return gjson.New(msg.Data).Scan(pointer)
 }
 
 // NewMsgParser 初始化消息处理器
@@ -87,30 +95,41 @@ func (m *MsgParser) RegisterRPCRouter(routers ...interface{}) (err error) {
 	return
 }
 
+// RegisterStreamRouter 注册流式路由
+func (m *MsgParser) RegisterStreamRouter(routers ...interface{}) (err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, router := range routers {
+		info, err := ParseStreamHandler(router)
+		if err != nil {
+			return err
+		}
+		if _, ok := m.routers[info.Id]; ok {
+			return gerror.Newf("server stream router duplicate registration:%v", info.Id)
+		}
+		m.routers[info.Id] = info
+	}
+	return
+}
+
 // RegisterInterceptor 注册拦截器
 func (m *MsgParser) RegisterInterceptor(interceptors ...Interceptor) {
 	m.interceptors = append(m.interceptors, interceptors...)
 }
 
-// Encoding 消息编码
+// Encoding 消息编码，根据帧头自动识别消息使用的编解码器
 func (m *MsgParser) Encoding(data []byte) (*Message, error) {
-	var msg Message
-	if err := gconv.Scan(data, &msg); err != nil {
-		return nil, gerror.Newf("invalid package struct: %s", err.Error())
-	}
-	if msg.Router == "" {
-		return nil, gerror.Newf("message is not router: %+v", msg)
-	}
-	return &msg, nil
+	return unpackFrame(data)
 }
 
-// Decoding 消息解码
+// Decoding 消息解码，使用默认的JSON编码
 func (m *MsgParser) Decoding(ctx context.Context, data interface{}, msgId string) ([]byte, error) {
 	message, err := m.doDecoding(ctx, data, msgId)
 	if err != nil {
 		return nil, err
 	}
-	return json.Marshal(message)
+	return packFrame(legacyEncoding, message)
 }
 
 // Decoding 消息解码
@@ -141,43 +160,65 @@ func (m *MsgParser) handleRouterMsg(ctx context.Context, msg *Message) error {
 // doHandleRouterMsg 处理路由消息
 func (m *MsgParser) doHandleRouterMsg(ctx context.Context, handler *RouteHandler, msg *Message) (err error) {
 	var input = gutil.Copy(handler.Input.Interface())
-	if err = gjson.New(msg.Data).Scan(input); err != nil {
+	if err = msg.Scan(input); err != nil {
 		return gerror.NewCodef(
 			gcode.CodeInvalidParameter,
 			"router scan failed:%v to parse message:%v",
 			err, handler.Id)
 	}
 
+	if handler.IsStream && msg.MsgId == "" {
+		return gerror.NewCodef(gcode.CodeInvalidParameter, "stream router %v must be requested by RequestStream", handler.Id)
+	}
+
+	// rpc请求绑定可被调用方取消的上下文
+	var (
+		conn    = ConnFromCtx(ctx)
+		release = func() {}
+	)
+	if (handler.IsRPC || handler.IsStream) && msg.MsgId != "" {
+		var callErr error
+		if ctx, release, callErr = conn.startCall(ctx, msg); callErr != nil {
+			return m.replyError(ctx, handler, msg, callErr)
+		}
+	}
+
 	args := []reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(input)}
 
 	m.task(ctx, func() {
+		defer release()
+
+		if handler.IsStream {
+			stream := &Stream{ctx: ctx, conn: conn, msgId: msg.MsgId}
+			streamErr := gerror.New("stream handler exited unexpectedly")
+			defer func() {
+				_ = stream.end(handler.Id, streamErr)
+			}()
+
+			results := handler.Func.Call(append(args, reflect.ValueOf(stream)))
+			streamErr = resultError(results[0])
+			return
+		}
+
 		results := handler.Func.Call(args)
 		if handler.IsRPC {
 			switch len(results) {
 			case 2:
-				var responseErr error
-				if !results[1].IsNil() {
-					if err, ok := results[1].Interface().(error); ok {
-						responseErr = err
-					}
-				}
-
+				responseErr := resultError(results[1])
 				responseMsg, deErr := m.doDecoding(ctx, results[0].Interface(), msg.MsgId)
-				if deErr != nil && responseErr == nil {
-					responseErr = deErr
+				if deErr != nil {
+					if responseErr == nil {
+						responseErr = deErr
+					}
+					responseMsg = &Message{Router: handler.Id, TraceId: gctx.CtxId(ctx), MsgId: msg.MsgId}
 				}
 
 				if responseErr != nil {
 					responseMsg.Error = responseErr.Error()
 				}
 
-				b, err := json.Marshal(responseMsg)
-				if err != nil {
-					return
-				}
-
-				user := GetCtx(ctx)
-				user.Conn.Write(b)
+				responseMsg.Type = MsgTypeReply
+				_ = conn.writeMessage(responseMsg)
 			}
 			return
 		}
@@ -201,20 +242,39 @@ func (m *MsgParser) handleInterceptor(ctx context.Context, msg *Message) (interc
 	if !ok {
 		return
 	}
+	return m.replyError(ctx, handler, msg, interceptErr)
+}
 
-	if handler.IsRPC {
-		var output = gutil.Copy(handler.Output.Interface())
-		response, doerr := m.doDecoding(ctx, output, msg.MsgId)
-		if doerr != nil {
-			return doerr
+// replyError 向rpc调用方响应错误
+func (m *MsgParser) replyError(ctx context.Context, handler *RouteHandler, msg *Message, replyErr error) error {
+	conn := ConnFromCtx(ctx)
+	if handler.IsStream {
+		if msg.MsgId == "" {
+			return nil
 		}
+		return (&Stream{ctx: ctx, conn: conn, msgId: msg.MsgId}).end(handler.Id, replyErr)
+	}
 
-		response.Error = interceptErr.Error()
-		b, err := json.Marshal(response)
-		if err != nil {
-			return err
-		}
-		ConnFromCtx(ctx).Write(b)
+	if !handler.IsRPC {
+		return nil
 	}
-	return
+
+	var output = gutil.Copy(handler.Output.Interface())
+	response, err := m.doDecoding(ctx, output, msg.MsgId)
+	if err != nil {
+		return err
+	}
+
+	response.Type = MsgTypeReply
+	response.Error = replyErr.Error()
+	return conn.writeMessage(response)
+}
+
+// resultError 获取处理方法返回的错误
+func resultError(v reflect.Value) error {
+	if v.IsNil() {
+		return nil
+	}
+	err, _ := v.Interface().(error)
+	return err
 }
@@ -16,11 +16,12 @@ import (
 
 // RouteHandler 路由处理器
 type RouteHandler struct {
-	Id     string        // 路由ID
-	IsRPC  bool          // 是否支持rpc协议
-	Func   reflect.Value // 路由处理方法
-	Input  reflect.Value // 输入参数
-	Output reflect.Value // 输出参数
+	Id       string        // 路由ID
+	IsRPC    bool          // 是否支持rpc协议
+	IsStream bool          // 是否为流式响应
+	Func     reflect.Value // 路由处理方法
+	Input    reflect.Value // 输入参数
+	Output   reflect.Value // 输出参数
 }
 
 // ParseRouteHandler 解析路由
@@ -7,7 +7,7 @@ package tcp
 
 import (
 	"context"
-	"github.com/gogf/gf/v2/container/gtype"
+	"errors"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"sync"
 	"time"
@@ -17,6 +17,7 @@ import (
 type RPC struct {
 	mutex     sync.Mutex
 	callbacks map[string]RPCResponseFunc
+	streams   map[string]*rpcStream
 	task      RoutineTask
 }
 
@@ -33,24 +34,27 @@ func NewRPC(task RoutineTask) *RPC {
 	return &RPC{
 		task:      task,
 		callbacks: make(map[string]RPCResponseFunc),
+		streams:   make(map[string]*rpcStream),
 	}
 }
 
-// Request 发起RPC请求
+// Request 发起RPC请求，ctx未设置截止时间时默认等待RPCTimeout秒
 func (r *RPC) Request(ctx context.Context, msgId string, send func()) (res interface{}, err error) {
+	// resCh不关闭，请求结束后由GC回收，避免与异步执行的回调产生竞争
 	resCh := make(chan RPCResponse, 1)
-	isClose := gtype.NewBool(false)
+	defer r.popCallback(msgId)
 
-	defer func() {
-		isClose.Set(true)
-		close(resCh)
-		r.popCallback(msgId)
-	}()
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Second*RPCTimeout)
+		defer cancel()
+	}
 
 	r.mutex.Lock()
 	r.callbacks[msgId] = func(res interface{}, err error) {
-		if !isClose.Val() {
-			resCh <- RPCResponse{res: res, err: err}
+		select {
+		case resCh <- RPCResponse{res: res, err: err}:
+		default:
 		}
 	}
 	r.mutex.Unlock()
@@ -58,36 +62,95 @@ func (r *RPC) Request(ctx context.Context, msgId string, send func()) (res inter
 	r.task(ctx, send)
 
 	select {
-	case <-time.After(time.Second * RPCTimeout):
-		err = gerror.New("RPC response timeout")
+	case <-ctx.Done():
+		err = ctxError(ctx)
 		return
 	case got := <-resCh:
 		return got.res, got.err
 	}
 }
 
+// Stream 发起流式RPC请求，响应按顺序写入返回的通道，结束、出错或ctx取消后通道关闭，同时关闭done
+// 调用方需及时消费通道，通道写满后会阻塞当前连接的消息读取
+func (r *RPC) Stream(ctx context.Context, msgId string, send func()) (ch <-chan *StreamResponse, done <-chan struct{}) {
+	s := &rpcStream{
+		ch:   make(chan *StreamResponse, StreamBufferSize),
+		done: make(chan struct{}),
+	}
+
+	r.mutex.Lock()
+	r.streams[msgId] = s
+	r.mutex.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			r.popStream(msgId)
+			s.finish(&StreamResponse{Error: ctxError(ctx)}, false)
+		case <-s.done:
+		}
+	}()
+
+	r.task(ctx, send)
+	return s.ch, s.done
+}
+
 // Response RPC消息响应
 func (r *RPC) Response(ctx context.Context, msg *Message) bool {
 	if len(msg.MsgId) == 0 {
 		return false
 	}
 
-	f, ok := r.popCallback(msg.MsgId)
-	if !ok {
-		return false
-	}
-
 	var msgError error
 	if len(msg.Error) > 0 {
 		msgError = gerror.New(msg.Error)
 	}
 
+	switch msg.Type {
+	case MsgTypeStream, MsgTypeStreamEnd:
+		// 流式响应在当前协程中按顺序投递，已取消的流直接丢弃
+		if msg.Type == MsgTypeStreamEnd {
+			s, ok := r.popStream(msg.MsgId)
+			if ok {
+				var last *StreamResponse
+				if msgError != nil {
+					last = &StreamResponse{Error: msgError}
+				}
+				s.finish(last, true)
+			}
+			return true
+		}
+
+		if s, ok := r.getStream(msg.MsgId); ok {
+			s.push(&StreamResponse{Data: msg.Data, Error: msgError})
+		}
+		return true
+	}
+
+	f, ok := r.popCallback(msg.MsgId)
+	if !ok {
+		// 请求已超时或取消，丢弃迟到的响应
+		return msg.Type == MsgTypeReply
+	}
+
 	r.task(ctx, func() {
 		f(msg.Data, msgError)
 	})
 	return true
 }
 
+// fail 请求发送失败时立即结束等待
+func (r *RPC) fail(msgId string, err error) {
+	if f, ok := r.popCallback(msgId); ok {
+		f(nil, err)
+		return
+	}
+
+	if s, ok := r.popStream(msgId); ok {
+		s.finish(&StreamResponse{Error: err}, false)
+	}
+}
+
 // popCallback 弹出回调
 func (r *RPC) popCallback(msgId string) (RPCResponseFunc, bool) {
 	r.mutex.Lock()
@@ -99,3 +162,87 @@ func (r *RPC) popCallback(msgId string) (RPCResponseFunc, bool) {
 	}
 	return call, ok
 }
+
+// getStream 获取流
+func (r *RPC) getStream(msgId string) (*rpcStream, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	s, ok := r.streams[msgId]
+	return s, ok
+}
+
+// popStream 弹出流
+func (r *RPC) popStream(msgId string) (*rpcStream, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	s, ok := r.streams[msgId]
+	if ok {
+		delete(r.streams, msgId)
+	}
+	return s, ok
+}
+
+// rpcStream 流式请求的接收状态
+type rpcStream struct {
+	mutex  sync.Mutex
+	ch     chan *StreamResponse
+	done   chan struct{}
+	once   sync.Once
+	closed bool
+}
+
+// push 投递一条响应，流结束后丢弃
+func (s *rpcStream) push(res *StreamResponse) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.ch <- res:
+	case <-s.done:
+	}
+}
+
+// finish 结束流，wait为true时等待最后一条响应写入通道
+func (s *rpcStream) finish(last *StreamResponse, wait bool) {
+	if !wait {
+		s.once.Do(func() { close(s.done) })
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+
+	if last != nil {
+		if wait {
+			select {
+			case s.ch <- last:
+			case <-s.done:
+			}
+		} else {
+			select {
+			case s.ch <- last:
+			default:
+			}
+		}
+	}
+	close(s.ch)
+	s.once.Do(func() { close(s.done) })
+}
+
+// ctxError 将上下文结束原因转换为RPC错误
+func ctxError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return gerror.New("RPC response timeout")
+	}
+	return gerror.Wrap(ctx.Err(), "RPC request canceled")
+}
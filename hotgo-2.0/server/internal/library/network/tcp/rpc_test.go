@@ -0,0 +1,137 @@
+// Package tcp_test
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/test/gtest"
+	"hotgo/internal/library/network/tcp"
+	"sync"
+	"testing"
+	"time"
+)
+
+type TestSlowRPCReq struct{}
+
+type TestSlowRPCRes struct {
+	tcp.ServerRes
+}
+
+type TestStreamReq struct {
+	Count int `json:"count"`
+}
+
+type TestStreamRes struct {
+	Index int `json:"index"`
+}
+
+func TestRPCDeadlineAndStream(t *testing.T) {
+	var (
+		ctx      = gctx.New()
+		canceled = make(chan struct{}, 1)
+		serv     = tcp.NewServer(&tcp.ServerConfig{Name: "hotgo", Addr: "127.0.0.1:8012"})
+	)
+
+	serv.RegisterRouter(func(ctx context.Context, req *tcp.ServerLoginReq) {
+		conn := tcp.ConnFromCtx(ctx)
+		res := new(tcp.ServerLoginRes)
+		res.Protocol = serv.NegotiateProtocol(conn, req.Protocol)
+		_ = conn.Send(ctx, res)
+	})
+
+	serv.RegisterRPCRouter(func(ctx context.Context, req *TestSlowRPCReq) (res *TestSlowRPCRes, err error) {
+		select {
+		case <-ctx.Done():
+			canceled <- struct{}{}
+		case <-time.After(5 * time.Second):
+		}
+		return
+	})
+
+	serv.RegisterStreamRouter(func(ctx context.Context, req *TestStreamReq, stream *tcp.Stream) error {
+		for i := 0; i < req.Count; i++ {
+			if err := stream.Send(&TestStreamRes{Index: i}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	go func() {
+		_ = serv.Listen()
+	}()
+	defer serv.Close()
+
+	client := tcp.NewClient(&tcp.ClientConfig{
+		Addr:            "127.0.0.1:8012",
+		ConnectInterval: 100 * time.Millisecond,
+		Auth:            &tcp.AuthMeta{Name: "test", Group: "test", AppId: "test"},
+	})
+	defer client.Stop()
+
+	gtest.C(t, func(t *gtest.T) {
+		t.AssertNil(client.Start())
+		for i := 0; i < 50 && !client.IsLogin(); i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Assert(client.IsLogin(), true)
+
+		// 取消后结束等待并通知服务器取消处理
+		reqCtx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(200*time.Millisecond, cancel)
+
+		start := time.Now()
+		err := client.RequestScan(reqCtx, &TestSlowRPCReq{}, &TestSlowRPCRes{})
+		t.AssertNE(err, nil)
+		t.AssertLT(time.Since(start), time.Second)
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Error("server handler was not canceled")
+		}
+
+		// 流式响应按顺序返回，结束后通道关闭
+		ch, err := client.RequestStream(ctx, &TestStreamReq{Count: 3})
+		t.AssertNil(err)
+
+		var indexes []int
+		for res := range ch {
+			var item TestStreamRes
+			t.AssertNil(res.Scan(&item))
+			indexes = append(indexes, item.Index)
+		}
+		t.Assert(indexes, []int{0, 1, 2})
+	})
+}
+
+func TestRPCReplyWhileCanceling(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		// 回调异步执行，响应与请求取消同时发生时不能向已结束的请求发送
+		rpc := tcp.NewRPC(func(ctx context.Context, task func()) {
+			go task()
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2000; i++ {
+			msgId := fmt.Sprintf("msg-%d", i)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			wg.Add(1)
+			_, _ = rpc.Request(ctx, msgId, func() {
+				go func() {
+					defer wg.Done()
+					rpc.Response(ctx, &tcp.Message{MsgId: msgId, Type: tcp.MsgTypeReply})
+				}()
+			})
+		}
+		wg.Wait()
+		time.Sleep(100 * time.Millisecond)
+	})
+}
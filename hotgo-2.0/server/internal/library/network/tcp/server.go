@@ -14,6 +14,7 @@ import (
 	"github.com/gogf/gf/v2/os/gctx"
 	"github.com/gogf/gf/v2/os/glog"
 	"github.com/gogf/gf/v2/os/grpool"
+	"github.com/gogf/gf/v2/text/gstr"
 	"hotgo/utility/simple"
 	"sync"
 )
@@ -31,12 +32,23 @@ type Server struct {
 	mutexConns sync.Mutex       // 连接锁，主要用于客户端上下线
 	taskGo     *grpool.Pool     // 任务协程池
 	msgParser  *MsgParser       // 消息处理器
+	codecs     []string         // 允许协商的编码
+	compress   int              // 消息压缩阈值
+	inflight   int              // 单个连接的rpc请求并发上限
+	cluster    *cluster         // 集群，单节点模式时为空
+	write      *WriteConfig     // 连接写队列配置
 }
 
 // ServerConfig tcp服务器配置
 type ServerConfig struct {
-	Name string // 服务名称
-	Addr string // 监听地址
+	Name              string         // 服务名称
+	Addr              string         // 监听地址
+	TLS               *TLSConfig     // 传输层加密配置，为空时使用明文传输
+	Codecs            []string       // 允许客户端协商使用的编码，为空时允许所有已注册的编码
+	CompressThreshold int            // 消息压缩阈值(字节)，协商成功后超过该大小的消息使用gzip压缩，0不压缩
+	MaxInflight       int            // 单个连接的rpc请求并发上限，超过时发起方等待、处理方直接拒绝，0不限制
+	Cluster           *ClusterConfig // 集群配置，为空时以单节点模式运行
+	Write             *WriteConfig   // 连接写队列配置，为空时使用默认配置
 }
 
 // NewServer 初始一个tcp服务器对象
@@ -62,11 +74,32 @@ func NewServer(config *ServerConfig) (server *Server) {
 
 	server.addr = config.Addr
 	server.name = config.Name
-	server.ln = gtcp.NewServer(server.addr, server.accept, config.Name)
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.serverConfig()
+		if err != nil {
+			server.logger.Fatal(server.ctx, gerror.Wrap(baseErr, err.Error()))
+			return
+		}
+		server.ln = gtcp.NewServerTLS(server.addr, tlsConfig, server.accept, config.Name)
+	} else {
+		server.ln = gtcp.NewServer(server.addr, server.accept, config.Name)
+	}
 	server.closeFlag = gtype.NewBool(false)
 	server.clients = make(map[string]*Conn)
 	server.taskGo = grpool.New(20)
 	server.msgParser = NewMsgParser(server.handleRoutineTask)
+	server.codecs = config.Codecs
+	server.compress = config.CompressThreshold
+	server.inflight = config.MaxInflight
+	server.write = config.Write
+
+	if config.Cluster != nil {
+		server.cluster = newCluster(server, config.Cluster)
+		if err := server.cluster.join(server.ctx); err != nil {
+			server.logger.Fatal(server.ctx, gerror.Wrap(baseErr, err.Error()))
+			return
+		}
+	}
 
 	server.startCron()
 	return
@@ -74,7 +107,14 @@ func NewServer(config *ServerConfig) (server *Server) {
 
 // accept
 func (server *Server) accept(conn *gtcp.Conn) {
-	tcpConn := NewConn(conn, server.logger, server.msgParser)
+	if err := handshake(conn); err != nil {
+		server.logger.Infof(server.ctx, "client %v rejected: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	tcpConn := NewConn(conn, server.logger, server.msgParser, server.write)
+	tcpConn.SetMaxInflight(server.inflight)
 	server.AddClient(tcpConn)
 	go func() {
 		if err := tcpConn.Run(); err != nil {
@@ -90,10 +130,15 @@ func (server *Server) accept(conn *gtcp.Conn) {
 // RemoveClient 移除客户端
 func (server *Server) RemoveClient(conn *Conn) {
 	label := server.ClientLabel(conn.Conn)
-	if _, ok := server.clients[label]; ok {
-		server.mutexConns.Lock()
+	server.mutexConns.Lock()
+	_, ok := server.clients[label]
+	if ok {
 		delete(server.clients, label)
-		server.mutexConns.Unlock()
+	}
+	server.mutexConns.Unlock()
+
+	if ok && conn.Auth != nil && server.cluster != nil {
+		server.cluster.unregister(server.ctx, conn.CID)
 	}
 }
 
@@ -113,6 +158,31 @@ func (server *Server) AuthClient(conn *Conn, auth *AuthMeta) {
 		return
 	}
 	client.Auth = auth
+
+	if server.cluster != nil {
+		server.cluster.register(server.ctx, server.clusterClient(client))
+	}
+}
+
+// NegotiateCodec 协商客户端连接使用的编码，返回协商结果，客户端未请求协商时返回空并继续使用JSON编码
+func (server *Server) NegotiateCodec(conn *Conn, name string) string {
+	if name == "" {
+		return ""
+	}
+
+	codec, ok := GetCodec(name)
+	if !ok || (len(server.codecs) > 0 && !gstr.InArray(server.codecs, name)) {
+		codec = jsonCodec
+	}
+
+	conn.SetCodec(codec, server.compress)
+	return codec.Name()
+}
+
+// NegotiateProtocol 记录客户端的协议版本，返回服务器的协议版本
+func (server *Server) NegotiateProtocol(conn *Conn, version int) int {
+	conn.setPeerProtocol(version)
+	return ProtocolVersion
 }
 
 // ClientLabel 客户端标识
@@ -213,6 +283,14 @@ func (server *Server) RegisterRPCRouter(routers ...interface{}) {
 	}
 }
 
+// RegisterStreamRouter 注册流式路由
+func (server *Server) RegisterStreamRouter(routers ...interface{}) {
+	err := server.msgParser.RegisterStreamRouter(routers...)
+	if err != nil {
+		server.logger.Fatal(server.ctx, err)
+	}
+}
+
 // RegisterInterceptor 注册拦截器
 func (server *Server) RegisterInterceptor(interceptors ...Interceptor) {
 	server.msgParser.RegisterInterceptor(interceptors...)
@@ -234,6 +312,10 @@ func (server *Server) Close() {
 	server.closeFlag.Set(true)
 	server.stopCron()
 
+	if server.cluster != nil {
+		server.cluster.leave(server.ctx)
+	}
+
 	server.mutexConns.Lock()
 	for _, client := range server.clients {
 		client.Conn.Close()
@@ -292,3 +374,8 @@ func (server *Server) Request(ctx context.Context, client *Conn, data interface{
 func (server *Server) RequestScan(ctx context.Context, client *Conn, data, response interface{}) error {
 	return client.RequestScan(ctx, data, response)
 }
+
+// RequestStream 向指定客户端发起流式请求，响应按顺序写入返回的通道
+func (server *Server) RequestStream(ctx context.Context, client *Conn, data interface{}) (<-chan *StreamResponse, error) {
+	return client.RequestStream(ctx, data)
+}
@@ -0,0 +1,234 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gctx"
+)
+
+// Node 当前节点名称，单节点模式时为服务名称
+func (server *Server) Node() string {
+	if server.cluster != nil {
+		return server.cluster.node
+	}
+	return server.name
+}
+
+// IsCluster 是否以集群模式运行
+func (server *Server) IsCluster() bool {
+	return server.cluster != nil
+}
+
+// IsLocalClient 客户端是否连接在当前节点
+func (server *Server) IsLocalClient(client *ClusterClient) bool {
+	return server.cluster == nil || client.Node == server.cluster.node
+}
+
+// clusterClient 将本节点的连接转换为集群客户端
+func (server *Server) clusterClient(conn *Conn) *ClusterClient {
+	client := &ClusterClient{
+		Node:      server.Node(),
+		CID:       conn.CID,
+		Addr:      conn.RemoteAddr().String(),
+		LocalAddr: conn.LocalAddr().String(),
+		FirstTime: conn.FirstTime,
+		Heartbeat: conn.Heartbeat,
+		Write:     conn.WriteStats(),
+	}
+
+	if conn.Auth != nil {
+		auth := *conn.Auth
+		auth.SecretKey = ""
+		client.Auth = &auth
+	}
+	return client
+}
+
+// localClients 本节点已认证的连接
+func (server *Server) localClients() (list []*ClusterClient) {
+	server.mutexConns.Lock()
+	defer server.mutexConns.Unlock()
+
+	for _, conn := range server.clients {
+		if conn.Auth != nil {
+			list = append(list, server.clusterClient(conn))
+		}
+	}
+	return
+}
+
+// ClusterClients 获取集群中所有已认证的连接，单节点模式时只包含本节点的连接
+func (server *Server) ClusterClients(ctx context.Context) ([]*ClusterClient, error) {
+	list := server.localClients()
+	if server.cluster == nil {
+		return list, nil
+	}
+
+	remote, err := server.cluster.remoteClients(ctx)
+	if err != nil {
+		return nil, gerror.Wrap(err, "get cluster clients failed")
+	}
+	return append(list, remote...), nil
+}
+
+// ClusterGroupClients 获取集群中指定分组的所有连接
+func (server *Server) ClusterGroupClients(ctx context.Context, group string) (list []*ClusterClient, err error) {
+	clients, err := server.ClusterClients(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, v := range clients {
+		if v.Auth != nil && v.Auth.Group == group {
+			list = append(list, v)
+		}
+	}
+	return
+}
+
+// ClusterAppIdClients 获取集群中指定appid的所有连接
+func (server *Server) ClusterAppIdClients(ctx context.Context, appid string) (list []*ClusterClient, err error) {
+	clients, err := server.ClusterClients(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, v := range clients {
+		if v.Auth != nil && v.Auth.AppId == appid {
+			list = append(list, v)
+		}
+	}
+	return
+}
+
+// ClusterAppIdOnline 获取集群中指定appid的在线数量，获取失败时返回本节点的在线数量
+func (server *Server) ClusterAppIdOnline(ctx context.Context, appid string) int {
+	list, err := server.ClusterAppIdClients(ctx, appid)
+	if err != nil {
+		server.logger.Warningf(ctx, "ClusterAppIdOnline err:%+v", err)
+		return server.GetAppIdOnline(appid)
+	}
+	return len(list)
+}
+
+// localConn 获取集群客户端在本节点的连接
+func (server *Server) localConn(client *ClusterClient) (*Conn, error) {
+	conn := server.GetClientById(client.CID)
+	if conn == nil {
+		return nil, gerror.New("client is offline")
+	}
+	return conn, nil
+}
+
+// SendTo 向集群中的客户端发送消息，客户端在其他节点时通过集群转发
+func (server *Server) SendTo(ctx context.Context, client *ClusterClient, data interface{}) error {
+	if server.IsLocalClient(client) {
+		conn, err := server.localConn(client)
+		if err != nil {
+			return err
+		}
+		return conn.Send(ctx, data)
+	}
+
+	msg, err := server.cluster.newMsg(ctx, clusterMsgSend, data)
+	if err != nil {
+		return err
+	}
+	msg.CID = client.CID
+	return server.cluster.publish(ctx, client.Node, msg)
+}
+
+// RequestTo 向集群中的客户端发送消息并等待响应结果
+func (server *Server) RequestTo(ctx context.Context, client *ClusterClient, data interface{}) (interface{}, error) {
+	if server.IsLocalClient(client) {
+		conn, err := server.localConn(client)
+		if err != nil {
+			return nil, err
+		}
+		return conn.Request(ctx, data)
+	}
+
+	body, err := server.requestRemote(ctx, client, data)
+	if err != nil {
+		return nil, err
+	}
+	return (&RawData{Body: body}).Interface()
+}
+
+// RequestScanTo 向集群中的客户端发送消息并等待响应结果，将结果保存在response中
+func (server *Server) RequestScanTo(ctx context.Context, client *ClusterClient, data, response interface{}) error {
+	if server.IsLocalClient(client) {
+		conn, err := server.localConn(client)
+		if err != nil {
+			return err
+		}
+		return conn.RequestScan(ctx, data, response)
+	}
+
+	body, err := server.requestRemote(ctx, client, data)
+	if err != nil {
+		return err
+	}
+	return (&RawData{Body: body}).Scan(response)
+}
+
+// requestRemote 通过集群向其他节点的客户端发起rpc请求
+func (server *Server) requestRemote(ctx context.Context, client *ClusterClient, data interface{}) ([]byte, error) {
+	msg, err := server.cluster.newMsg(ctx, clusterMsgRequest, data)
+	if err != nil {
+		return nil, err
+	}
+	msg.CID = client.CID
+	return server.cluster.request(ctx, client.Node, msg)
+}
+
+// BroadcastGroup 向集群中指定分组的所有连接发送消息，返回第一个发送失败的错误
+func (server *Server) BroadcastGroup(ctx context.Context, group string, data interface{}) (err error) {
+	for _, conn := range server.GetGroupClients(group) {
+		if sendErr := conn.Send(ctx, data); sendErr != nil && err == nil {
+			err = sendErr
+		}
+	}
+
+	if server.cluster == nil {
+		return
+	}
+
+	nodes, nodesErr := server.cluster.nodes(ctx)
+	if nodesErr != nil {
+		return gerror.Wrap(nodesErr, "get cluster nodes failed")
+	}
+
+	msg, msgErr := server.cluster.newMsg(ctx, clusterMsgBroadcast, data)
+	if msgErr != nil {
+		return msgErr
+	}
+	msg.Group = group
+
+	for _, node := range nodes {
+		if pubErr := server.cluster.publish(ctx, node, msg); pubErr != nil && err == nil {
+			err = pubErr
+		}
+	}
+	return
+}
+
+// CloseClient 断开集群中的客户端连接
+func (server *Server) CloseClient(ctx context.Context, client *ClusterClient) error {
+	if server.IsLocalClient(client) {
+		conn, err := server.localConn(client)
+		if err != nil {
+			return err
+		}
+		conn.Close()
+		return nil
+	}
+
+	msg := &clusterMsg{Kind: clusterMsgClose, From: server.cluster.node, CID: client.CID, TraceId: gctx.CtxId(ctx)}
+	return server.cluster.publish(ctx, client.Node, msg)
+}
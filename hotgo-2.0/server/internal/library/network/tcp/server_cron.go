@@ -21,6 +21,7 @@ func (server *Server) getCronKey(s string) string {
 func (server *Server) stopCron() {
 	gcron.Remove(server.getCronKey(CronHeartbeatVerify))
 	gcron.Remove(server.getCronKey(CronAuthVerify))
+	gcron.Remove(server.getCronKey(CronClusterHeartbeat))
 }
 
 // startCron 启动定时任务
@@ -65,4 +66,12 @@ func (server *Server) startCron() {
 			}
 		}, server.getCronKey(CronAuthVerify))
 	}
+
+	// 集群心跳
+	if server.cluster != nil && gcron.Search(server.getCronKey(CronClusterHeartbeat)) == nil {
+		pattern := fmt.Sprintf("@every %ds", max(int(server.cluster.ttl.Seconds())/3, 1))
+		_, _ = gcron.AddSingleton(server.ctx, pattern, func(ctx context.Context) {
+			server.cluster.heartbeat(ctx)
+		}, server.getCronKey(CronClusterHeartbeat))
+	}
 }
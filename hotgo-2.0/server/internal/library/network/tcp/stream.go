@@ -0,0 +1,115 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/container/gvar"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/text/gstr"
+	"reflect"
+	"runtime"
+)
+
+// StreamResponse 流式响应
+type StreamResponse struct {
+	Data  interface{} // 响应数据，二进制编码时为*RawData
+	Error error       // 响应错误，流会在返回错误后结束
+}
+
+// Scan 将响应数据解析到pointer
+func (r *StreamResponse) Scan(pointer interface{}) error {
+	if r.Error != nil {
+		return r.Error
+	}
+	if raw, ok := r.Data.(*RawData); ok {
+		return raw.Scan(pointer)
+	}
+	return gvar.New(r.Data).Scan(pointer)
+}
+
+// Stream 流式响应推送器，由流式路由的处理方法使用
+type Stream struct {
+	ctx   context.Context
+	conn  *Conn
+	msgId string
+}
+
+// Context 流的上下文，调用方取消或超时后结束
+func (s *Stream) Context() context.Context {
+	return s.ctx
+}
+
+// Send 向调用方推送一条响应
+func (s *Stream) Send(data interface{}) error {
+	if err := s.ctx.Err(); err != nil {
+		return ctxError(s.ctx)
+	}
+
+	message, err := s.conn.msgParser.doDecoding(s.ctx, data, s.msgId)
+	if err != nil {
+		return err
+	}
+	message.Type = MsgTypeStream
+	return s.conn.writeMessage(message)
+}
+
+// end 结束流
+func (s *Stream) end(router string, err error) error {
+	message := &Message{Router: router, MsgId: s.msgId, Type: MsgTypeStreamEnd}
+	if err != nil {
+		message.Error = err.Error()
+	}
+	return s.conn.writeMessage(message)
+}
+
+var streamType = reflect.TypeOf((*Stream)(nil))
+
+// ParseStreamHandler 解析流式路由
+func ParseStreamHandler(router interface{}) (info *RouteHandler, err error) {
+	funcName := runtime.FuncForPC(reflect.ValueOf(router).Pointer()).Name()
+	funcType := reflect.ValueOf(router).Type()
+
+	if funcType.NumIn() != 3 || funcType.NumOut() != 1 {
+		err = gerror.Newf(ParseStreamErrInvalidParams, funcName)
+		return
+	}
+
+	if funcType.In(0) != reflect.TypeOf((*context.Context)(nil)).Elem() {
+		err = gerror.Newf(ParseRouterErrInvalidFirstParam, funcName)
+		return
+	}
+
+	inputType := funcType.In(1)
+	if !(inputType.Kind() == reflect.Ptr && inputType.Elem().Kind() == reflect.Struct) {
+		err = gerror.Newf(ParseRouterErrInvalidSecondParam, funcName)
+		return
+	}
+
+	if funcType.In(2) != streamType || !funcType.Out(0).Implements(reflect.TypeOf((*error)(nil)).Elem()) {
+		err = gerror.Newf(ParseStreamErrInvalidParams, funcName)
+		return
+	}
+
+	// The request struct should be named as `xxxReq`.
+	if !gstr.HasSuffix(inputType.String(), `Req`) {
+		err = gerror.NewCodef(
+			gcode.CodeInvalidParameter,
+			`invalid struct naming of the request: defined as "%s", but should be named with the "Req" suffix, such as "XxxReq"`,
+			inputType.String(),
+		)
+		return
+	}
+
+	info = &RouteHandler{
+		Id:       gstr.SubStrFromREx(inputType.String(), `.`),
+		IsStream: true,
+		Func:     reflect.ValueOf(router),
+		Input:    reflect.New(inputType.Elem()),
+	}
+	return
+}
@@ -0,0 +1,148 @@
+// Package tcp
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package tcp
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/net/gtcp"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// TLSConfig 传输层加密配置
+type TLSConfig struct {
+	CertFile           string // 证书文件
+	KeyFile            string // 私钥文件
+	CAFile             string // CA证书文件，服务端用于校验客户端证书，客户端用于校验服务端证书，为空时客户端使用系统根证书
+	ClientAuth         bool   // 服务端是否要求客户端提供证书并校验(双向认证)
+	ServerName         string // 客户端校验的服务端证书名称，为空时使用连接地址中的主机名
+	InsecureSkipVerify bool   // 客户端跳过服务端证书校验，仅用于本地测试
+}
+
+// handshakeTimeout TLS握手超时时间
+const handshakeTimeout = 10 * time.Second
+
+// serverConfig 生成服务端TLS配置
+func (c *TLSConfig) serverConfig() (*tls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, gerror.New("tls certFile and keyFile must be set")
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, gerror.Wrap(err, "tls load key pair failed")
+	}
+
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.ClientAuth {
+		if c.CAFile == "" {
+			return nil, gerror.New("tls caFile must be set when clientAuth is enabled")
+		}
+		if conf.ClientCAs, err = loadCertPool(c.CAFile); err != nil {
+			return nil, err
+		}
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return conf, nil
+}
+
+// clientConfig 生成客户端TLS配置
+func (c *TLSConfig) clientConfig(addr string) (*tls.Config, error) {
+	conf := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if conf.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, gerror.Wrapf(err, "tls parse address %v failed", addr)
+		}
+		conf.ServerName = host
+	}
+
+	// 双向认证时携带客户端证书
+	if c.CertFile != "" && c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, gerror.Wrap(err, "tls load key pair failed")
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.RootCAs = pool
+	}
+	return conf, nil
+}
+
+// loadCertPool 加载CA证书
+func loadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, gerror.Wrapf(err, "tls read ca file %v failed", file)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, gerror.Newf("tls ca file %v has no valid certificate", file)
+	}
+	return pool, nil
+}
+
+// dialTLS 建立TLS连接并完成握手
+func dialTLS(addr string, timeout time.Duration, conf *tls.Config) (*gtcp.Conn, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, conf)
+	if err != nil {
+		return nil, err
+	}
+	return gtcp.NewConnByNetConn(conn), nil
+}
+
+// handshake 服务端主动完成TLS握手，以便尽早拒绝无效的客户端证书
+func handshake(conn *gtcp.Conn) error {
+	tlsConn, ok := conn.Conn.(*tls.Conn)
+	if !ok {
+		return nil
+	}
+
+	if err := tlsConn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return err
+	}
+
+	if err := tlsConn.Handshake(); err != nil {
+		return gerror.Wrap(err, "tls handshake failed")
+	}
+	return tlsConn.SetDeadline(time.Time{})
+}
+
+// CertFingerprint 获取证书的SHA256指纹，小写十六进制格式
+func CertFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeFingerprint 统一指纹格式，兼容openssl输出的带冒号大写格式
+func NormalizeFingerprint(fingerprint string) string {
+	fingerprint = strings.TrimSpace(fingerprint)
+	fingerprint = strings.TrimPrefix(strings.ToLower(fingerprint), "sha256 fingerprint=")
+	return strings.ReplaceAll(fingerprint, ":", "")
+}
@@ -6,16 +6,26 @@
 package alipay
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"github.com/go-pay/gopay"
 	"github.com/go-pay/gopay/alipay"
+	"github.com/gogf/gf/v2/encoding/gcharset"
 	"github.com/gogf/gf/v2/errors/gerror"
-	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/gconv"
 	"hotgo/internal/consts"
+	"hotgo/internal/library/payment/bill"
 	"hotgo/internal/model"
 	"hotgo/internal/model/input/payin"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 func New(config *model.PayConfig) *aliPay {
@@ -55,7 +65,12 @@ func (h *aliPay) Refund(ctx context.Context, in payin.RefundInp) (res *payin.Ref
 
 // Notify 异步通知
 func (h *aliPay) Notify(ctx context.Context, in payin.NotifyInp) (res *payin.NotifyModel, err error) {
-	notifyReq, err := alipay.ParseNotifyToBodyMap(ghttp.RequestFromCtx(ctx).Request)
+	r, err := in.Request()
+	if err != nil {
+		return
+	}
+
+	notifyReq, err := alipay.ParseNotifyToBodyMap(r)
 	if err != nil {
 		return
 	}
@@ -98,6 +113,8 @@ func (h *aliPay) Notify(ctx context.Context, in payin.NotifyInp) (res *payin.Not
 	res.OutTradeNo = notify.OutTradeNo
 	res.PayAt = notify.GmtPayment
 	res.ActualAmount = gconv.Float64(notify.ReceiptAmount)
+	res.TotalAmount = gconv.Float64(notify.TotalAmount)
+	res.MchId = notify.AppId
 	return
 }
 
@@ -122,6 +139,187 @@ func (h *aliPay) CreateOrder(ctx context.Context, in payin.CreateOrderInp) (res
 	return
 }
 
+// QueryOrder 查询订单
+func (h *aliPay) QueryOrder(ctx context.Context, in payin.QueryOrderInp) (res *payin.QueryOrderModel, err error) {
+	client, err := GetClient(h.config)
+	if err != nil {
+		return
+	}
+
+	bm := make(gopay.BodyMap)
+	bm.Set("out_trade_no", in.Pay.OutTradeNo)
+
+	res = new(payin.QueryOrderModel)
+	res.OutTradeNo = in.Pay.OutTradeNo
+
+	query, err := client.TradeQuery(ctx, bm)
+	if err != nil {
+		// 用户未扫码时支付宝不会创建交易
+		if bizErr, ok := alipay.IsBizError(err); ok && bizErr.SubCode == "ACQ.TRADE_NOT_EXIST" {
+			res.TradeState = consts.TradeStateNotExist
+			return res, nil
+		}
+		return nil, err
+	}
+
+	res.TransactionId = query.Response.TradeNo
+	switch query.Response.TradeStatus {
+	case "TRADE_SUCCESS", "TRADE_FINISHED":
+		res.TradeState = consts.TradeStateSuccess
+		res.PayAt = gtime.NewFromStrLayout(query.Response.SendPayDate, time.DateTime)
+		res.ActualAmount = gconv.Float64(query.Response.ReceiptAmount)
+	case "TRADE_CLOSED":
+		res.TradeState = consts.TradeStateClosed
+	default:
+		res.TradeState = consts.TradeStateWait
+	}
+	return
+}
+
+// CloseOrder 关闭订单
+func (h *aliPay) CloseOrder(ctx context.Context, in payin.CloseOrderInp) (res *payin.CloseOrderModel, err error) {
+	client, err := GetClient(h.config)
+	if err != nil {
+		return
+	}
+
+	bm := make(gopay.BodyMap)
+	bm.Set("out_trade_no", in.Pay.OutTradeNo)
+
+	if _, err = client.TradeClose(ctx, bm); err != nil {
+		// 交易不存在时无需关闭
+		if bizErr, ok := alipay.IsBizError(err); ok && bizErr.SubCode == "ACQ.TRADE_NOT_EXIST" {
+			return new(payin.CloseOrderModel), nil
+		}
+		return nil, err
+	}
+	res = new(payin.CloseOrderModel)
+	return
+}
+
+// QueryRefund 查询退款
+func (h *aliPay) QueryRefund(ctx context.Context, in payin.QueryRefundInp) (res *payin.QueryRefundModel, err error) {
+	client, err := GetClient(h.config)
+	if err != nil {
+		return
+	}
+
+	bm := make(gopay.BodyMap)
+	bm.Set("out_trade_no", in.Pay.OutTradeNo).
+		Set("out_request_no", in.RefundSn).
+		Set("query_options", []string{"gmt_refund_pay"})
+
+	query, err := client.TradeFastPayRefundQuery(ctx, bm)
+	if err != nil {
+		return
+	}
+
+	res = new(payin.QueryRefundModel)
+	res.RefundSn = in.RefundSn
+	res.RefundId = query.Response.TradeNo
+	res.RefundMoney = gconv.Float64(query.Response.RefundAmount)
+
+	// 未返回退款状态时表示退款还未成功
+	if query.Response.RefundStatus == "REFUND_SUCCESS" {
+		res.RefundState = consts.RefundStateSuccess
+		res.RefundAt = gtime.NewFromStrLayout(query.Response.GmtRefundPay, time.DateTime)
+	} else {
+		res.RefundState = consts.RefundStateProcessing
+	}
+	return
+}
+
+// DownloadBill 下载交易账单
+func (h *aliPay) DownloadBill(ctx context.Context, in payin.DownloadBillInp) (res *payin.DownloadBillModel, err error) {
+	client, err := GetClient(h.config)
+	if err != nil {
+		return
+	}
+
+	bm := make(gopay.BodyMap)
+	bm.Set("bill_type", "trade").
+		Set("bill_date", in.BillDate.Format("Y-m-d"))
+
+	query, err := client.DataBillDownloadUrlQuery(ctx, bm)
+	if err != nil {
+		return
+	}
+
+	content, err := downloadBill(ctx, query.Response.BillDownloadUrl)
+	if err != nil {
+		return
+	}
+
+	rows, err := bill.Parse(content, "支付宝交易号")
+	if err != nil {
+		return
+	}
+
+	res = new(payin.DownloadBillModel)
+	for _, row := range rows {
+		item := &payin.BillItem{
+			OutTradeNo:    row.Get("商户订单号"),
+			TransactionId: row.Get("支付宝交易号"),
+			Amount:        math.Abs(row.Float64("订单金额（元）", "订单金额(元)")),
+			TradeAt:       gtime.NewFromStrLayout(row.Get("完成时间"), time.DateTime),
+		}
+
+		switch row.Get("业务类型") {
+		case "交易":
+			item.Kind = consts.BillKindPay
+		case "退款":
+			item.Kind = consts.BillKindRefund
+			item.RefundSn = row.Get("退款批次号/请求号")
+		default:
+			continue
+		}
+		res.List = append(res.List, item)
+	}
+	return
+}
+
+// downloadBill 下载账单压缩包，读取其中GBK编码的业务明细
+func downloadBill(ctx context.Context, url string) (content string, err error) {
+	resp, err := g.Client().Get(ctx, url)
+	if err != nil {
+		return
+	}
+	defer resp.Close()
+
+	data := resp.ReadAll()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		err = gerror.Wrap(err, "解压支付宝账单失败")
+		return
+	}
+
+	for _, file := range reader.File {
+		name := file.Name
+		if !utf8.ValidString(name) {
+			if name, err = gcharset.ToUTF8("GBK", name); err != nil {
+				return
+			}
+		}
+
+		if !strings.Contains(name, "业务明细") || strings.Contains(name, "汇总") {
+			continue
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		b, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			return "", err
+		}
+		return gcharset.ToUTF8("GBK", string(b))
+	}
+	err = gerror.New("支付宝账单中没有找到业务明细")
+	return
+}
+
 func GetClient(config *model.PayConfig) (client *alipay.Client, err error) {
 	client, err = alipay.NewClient(config.AliPayAppId, gfile.GetContents(config.AliPayPrivateKey), true)
 	if err != nil {
@@ -0,0 +1,97 @@
+// Package bill
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package bill
+
+import (
+	"encoding/csv"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// 第三方账单解析
+// 各渠道的账单都是CSV格式，但表头前后会夹带说明行和汇总行，这里统一按表头列名读取明细
+
+// Row 账单明细行
+type Row map[string]string
+
+// Get 获取列值，传入多个列名时返回第一个存在的列
+func (r Row) Get(names ...string) string {
+	for _, name := range names {
+		if v, ok := r[name]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// Float64 获取金额列
+func (r Row) Float64(names ...string) float64 {
+	return gconv.Float64(strings.ReplaceAll(r.Get(names...), ",", ""))
+}
+
+// Parse 解析账单明细，headerKey为表头中必须包含的列名，用于定位表头行
+// 表头之前的内容会被忽略，遇到以#开头的行、汇总行或列数不一致的行时结束
+func Parse(content, headerKey string) (rows []Row, err error) {
+	var header []string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+		if line == "" {
+			continue
+		}
+
+		if header != nil && strings.HasPrefix(line, "#") {
+			break
+		}
+
+		fields, err := splitLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if header == nil {
+			for _, field := range fields {
+				if field == headerKey {
+					header = fields
+					break
+				}
+			}
+			continue
+		}
+
+		if len(fields) != len(header) {
+			break
+		}
+
+		row := make(Row, len(header))
+		for i, name := range header {
+			row[name] = fields[i]
+		}
+		rows = append(rows, row)
+	}
+
+	if header == nil {
+		err = gerror.Newf("账单中没有找到表头：%v", headerKey)
+	}
+	return
+}
+
+// splitLine 拆分一行CSV，去掉微信账单在值前面追加的`符号
+func splitLine(line string) (fields []string, err error) {
+	r := csv.NewReader(strings.NewReader(line))
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+	if fields, err = r.Read(); err != nil {
+		err = gerror.Wrap(err, "解析账单失败")
+		return
+	}
+
+	for i, field := range fields {
+		fields[i] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(field), "`"))
+	}
+	return
+}
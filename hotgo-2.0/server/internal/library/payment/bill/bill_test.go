@@ -0,0 +1,38 @@
+package bill
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	content := "#支付宝业务明细查询\n" +
+		"#账号：[20880000000000000156]\n" +
+		"支付宝交易号,商户订单号,业务类型,订单金额（元）\n" +
+		"2023010122001,`202301010001,交易,\"1,000.50\"\n" +
+		"2023010122002,202301010002,退款,-20.00\n" +
+		"#-----------------------------------------业务明细列表结束------------------------------------\n" +
+		"总交易笔数,总金额\n"
+
+	rows, err := Parse(content, "支付宝交易号")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("want 2 rows, got %v", len(rows))
+	}
+
+	if got := rows[0].Get("商户订单号"); got != "202301010001" {
+		t.Fatalf("want 202301010001, got %v", got)
+	}
+
+	if got := rows[0].Float64("订单金额(元)", "订单金额（元）"); got != 1000.5 {
+		t.Fatalf("want 1000.5, got %v", got)
+	}
+
+	if got := rows[1].Float64("订单金额（元）"); got != -20 {
+		t.Fatalf("want -20, got %v", got)
+	}
+
+	if _, err = Parse(content, "微信订单号"); err == nil {
+		t.Fatal("want header not found error")
+	}
+}
@@ -5,7 +5,10 @@
 // @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
 package payment
 
-import "hotgo/internal/model"
+import (
+	"hotgo/internal/consts"
+	"hotgo/internal/model"
+)
 
 var config *model.PayConfig
 
@@ -16,3 +19,27 @@ func SetConfig(c *model.PayConfig) {
 func GetConfig() *model.PayConfig {
 	return config
 }
+
+// EnabledPayTypes 获取已配置商户号的支付方式
+func EnabledPayTypes() (payTypes []string) {
+	if config == nil {
+		return
+	}
+
+	if config.WxPayMchId != "" {
+		payTypes = append(payTypes, consts.PayTypeWxPay)
+	}
+
+	if config.AliPayAppId != "" {
+		payTypes = append(payTypes, consts.PayTypeAliPay)
+	}
+
+	if config.QQPayMchId != "" {
+		payTypes = append(payTypes, consts.PayTypeQQPay)
+	}
+
+	if config.MockPayEnabled {
+		payTypes = append(payTypes, consts.PayTypeMockPay)
+	}
+	return
+}
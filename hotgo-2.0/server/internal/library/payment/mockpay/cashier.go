@@ -0,0 +1,227 @@
+// Package mockpay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package mockpay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hotgo/internal/consts"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/grand"
+)
+
+// 本地收银台
+
+const (
+	ActionPay    = "pay"    // 支付成功
+	ActionFail   = "fail"   // 支付失败，订单仍可继续支付
+	ActionCancel = "cancel" // 取消支付，关闭订单
+	ActionNotify = "notify" // 重新发送支付成功通知
+)
+
+var cashierTpl = template.Must(template.New("cashier").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>模拟支付收银台</title>
+<style>
+body{font-family:-apple-system,"Microsoft YaHei",sans-serif;background:#f5f7f9;margin:0;padding:40px 12px}
+.box{max-width:420px;margin:0 auto;background:#fff;border-radius:6px;padding:24px;box-shadow:0 1px 4px rgba(0,0,0,.08)}
+h3{margin:0 0 16px}.amount{font-size:32px;color:#d03050;margin:12px 0}.row{color:#666;font-size:14px;margin:6px 0}
+.msg{padding:10px;border-radius:4px;margin:12px 0;background:#f0f9eb;color:#18a058}.msg.err{background:#fef0f0;color:#d03050}
+form{display:inline-block;margin:16px 8px 0 0}button{border:0;border-radius:4px;padding:8px 18px;color:#fff;cursor:pointer}
+.pay{background:#18a058}.fail{background:#f0a020}.cancel{background:#909399}a{color:#2080f0}
+</style>
+</head>
+<body>
+<div class="box">
+<h3>模拟支付收银台</h3>
+<div class="row">商品：{{.Subject}}</div>
+<div class="row">商户订单号：{{.OutTradeNo}}</div>
+{{if .TransactionId}}<div class="row">交易号：{{.TransactionId}}</div>{{end}}
+<div class="amount">￥{{.Amount}}</div>
+{{if .Message}}<div class="msg{{if .Error}} err{{end}}">{{.Message}}</div>{{end}}
+{{if eq .TradeState "wait"}}
+{{range .Actions}}<form method="post" action="{{$.SubmitURL}}"><input type="hidden" name="outTradeNo" value="{{$.OutTradeNo}}"><input type="hidden" name="action" value="{{.Action}}"><button class="{{.Action}}" type="submit">{{.Label}}</button></form>{{end}}
+{{else if and (eq .TradeState "success") .Error}}
+<form method="post" action="{{.SubmitURL}}"><input type="hidden" name="outTradeNo" value="{{.OutTradeNo}}"><input type="hidden" name="action" value="notify"><button class="pay" type="submit">重新发送通知</button></form>
+{{end}}
+{{if and .ReturnUrl (ne .TradeState "wait")}}<p><a href="{{.ReturnUrl}}">返回商户页面</a></p>{{end}}
+</div>
+</body>
+</html>`))
+
+type cashierAction struct {
+	Action string
+	Label  string
+}
+
+type cashierView struct {
+	Subject       string
+	OutTradeNo    string
+	TransactionId string
+	Amount        string
+	TradeState    string
+	ReturnUrl     string
+	SubmitURL     string
+	Message       string
+	Error         bool
+	Actions       []cashierAction
+}
+
+// Cashier 渲染收银台页面
+func (h *mockPay) Cashier(ctx context.Context, outTradeNo string) (html string, err error) {
+	if err = h.checkEnabled(); err != nil {
+		return
+	}
+
+	o := getOrder(outTradeNo)
+	if o == nil {
+		err = errOrderNotExist
+		return
+	}
+	return h.render(o, "", false)
+}
+
+// Submit 处理收银台操作，支付成功后签名并回调到订单的支付通知地址
+func (h *mockPay) Submit(ctx context.Context, outTradeNo, action string) (html string, err error) {
+	if err = h.checkEnabled(); err != nil {
+		return
+	}
+
+	var (
+		message string
+		failed  bool
+	)
+
+	switch action {
+	case ActionPay:
+		o, err := updateOrder(outTradeNo, func(o *order) error {
+			if o.TradeState != consts.TradeStateWait {
+				return gerror.New("模拟支付订单不是待支付状态")
+			}
+			o.TradeState = consts.TradeStateSuccess
+			o.TransactionId = genNo("MOCK")
+			o.PayAt = gtime.Now()
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+
+		message, failed = "支付成功，已通知商户", false
+		if err = h.sendNotify(ctx, o); err != nil {
+			g.Log().Warningf(ctx, "mockpay sendNotify outTradeNo:%v err:%+v", outTradeNo, err)
+			message, failed = fmt.Sprintf("支付成功，但通知商户失败：%v", err), true
+		}
+		return h.render(o, message, failed)
+	case ActionNotify:
+		o := getOrder(outTradeNo)
+		if o == nil {
+			return "", errOrderNotExist
+		}
+
+		if o.TradeState != consts.TradeStateSuccess {
+			return "", gerror.New("模拟支付订单未支付，无需通知")
+		}
+
+		message, failed = "已重新通知商户", false
+		if err = h.sendNotify(ctx, o); err != nil {
+			message, failed = fmt.Sprintf("通知商户失败：%v", err), true
+		}
+		return h.render(o, message, failed)
+	case ActionFail:
+		o := getOrder(outTradeNo)
+		if o == nil {
+			return "", errOrderNotExist
+		}
+
+		if o.TradeState != consts.TradeStateWait {
+			return "", gerror.New("模拟支付订单不是待支付状态")
+		}
+		return h.render(o, "支付失败，可以重新选择支付结果", true)
+	case ActionCancel:
+		o, err := updateOrder(outTradeNo, func(o *order) error {
+			if o.TradeState != consts.TradeStateWait {
+				return gerror.New("模拟支付订单不是待支付状态")
+			}
+			o.TradeState = consts.TradeStateClosed
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		return h.render(o, "已取消支付，订单已关闭", false)
+	default:
+		err = gerror.Newf("不支持的收银台操作：%v", action)
+	}
+	return
+}
+
+// sendNotify 向订单的支付通知地址发送签名后的支付成功通知
+func (h *mockPay) sendNotify(ctx context.Context, o *order) (err error) {
+	params := map[string]string{
+		"mch_id":         MchId,
+		"out_trade_no":   o.Pay.OutTradeNo,
+		"transaction_id": o.TransactionId,
+		"trade_state":    TradeSuccess,
+		"total_amount":   fmt.Sprintf("%.2f", o.Pay.PayAmount),
+		"pay_at":         o.PayAt.String(),
+		"nonce_str":      grand.Letters(32),
+	}
+	params["sign"] = Sign(params, h.secret())
+
+	data := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		data[k] = v
+	}
+
+	resp, err := g.Client().Timeout(10*time.Second).Post(ctx, o.Pay.NotifyUrl, data)
+	if err != nil {
+		return
+	}
+	defer resp.Close()
+
+	body := strings.TrimSpace(resp.ReadAllString())
+	if body != NotifySuccess {
+		err = gerror.Newf("通知地址响应异常，状态码：%v，响应内容：%v", resp.StatusCode, body)
+	}
+	return
+}
+
+func (h *mockPay) render(o *order, message string, failed bool) (html string, err error) {
+	view := cashierView{
+		Subject:       o.Pay.Subject,
+		OutTradeNo:    o.Pay.OutTradeNo,
+		TransactionId: o.TransactionId,
+		Amount:        fmt.Sprintf("%.2f", o.Pay.PayAmount),
+		TradeState:    o.TradeState,
+		ReturnUrl:     o.Pay.ReturnUrl,
+		SubmitURL:     strings.TrimSuffix(o.Pay.NotifyUrl, NotifyPath) + SubmitPath,
+		Message:       message,
+		Error:         failed,
+		Actions: []cashierAction{
+			{Action: ActionPay, Label: "支付成功"},
+			{Action: ActionFail, Label: "支付失败"},
+			{Action: ActionCancel, Label: "取消支付"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err = cashierTpl.Execute(&buf, view); err != nil {
+		return
+	}
+	html = buf.String()
+	return
+}
@@ -0,0 +1,307 @@
+// Package mockpay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package mockpay
+
+import (
+	"context"
+	"fmt"
+	"hotgo/internal/consts"
+	"hotgo/internal/model"
+	"hotgo/internal/model/input/payin"
+	"math"
+	"net/url"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/gogf/gf/v2/util/grand"
+)
+
+// 模拟支付
+// 不依赖任何第三方平台，订单在本地收银台完成支付后，按照真实渠道的方式签名并回调到支付通知地址
+// 仅用于本地开发和自动化测试，生产环境请不要开启
+
+const (
+	MchId         = "mockpay"             // 模拟商户号
+	NotifyPath    = "/pay/notify/mockpay" // 异步通知路由
+	CashierPath   = "/pay/mock/cashier"   // 收银台路由
+	SubmitPath    = "/pay/mock/submit"    // 收银台提交路由
+	TradeSuccess  = "SUCCESS"             // 通知中的支付成功状态
+	NotifySuccess = "success"             // 通知地址处理成功后的响应内容
+)
+
+var errOrderNotExist = gerror.New("模拟支付交易不存在")
+
+func New(config *model.PayConfig) *mockPay {
+	return &mockPay{
+		config: config,
+	}
+}
+
+type mockPay struct {
+	config *model.PayConfig
+}
+
+// Refund 订单退款，开启【模拟退款失败】后所有退款申请都会失败，失败的退款单号可以再次申请
+func (h *mockPay) Refund(ctx context.Context, in payin.RefundInp) (res *payin.RefundModel, err error) {
+	if err = h.checkEnabled(); err != nil {
+		return
+	}
+
+	_, err = updateOrder(in.Pay.OutTradeNo, func(o *order) error {
+		if o.TradeState != consts.TradeStateSuccess && o.TradeState != consts.TradeStateRefund {
+			return gerror.New("模拟支付订单未支付，无法退款")
+		}
+
+		item := o.findRefund(in.RefundSn)
+		if item != nil && item.RefundState != consts.RefundStateFail {
+			return gerror.Newf("退款单号[%v]已存在，请勿重复申请", in.RefundSn)
+		}
+
+		if toCent(o.Refunded)+toCent(in.RefundMoney) > toCent(o.Pay.PayAmount) {
+			return gerror.New("退款金额超过订单可退金额")
+		}
+
+		// 失败的退款重新申请时覆盖原退款单
+		if item == nil {
+			item = &refund{RefundSn: in.RefundSn}
+			o.Refunds = append(o.Refunds, item)
+		}
+		item.RefundId = genNo("MOCKR")
+		item.RefundMoney = in.RefundMoney
+		item.RefundAt = gtime.Now()
+
+		if h.config.MockPayRefundFail {
+			item.RefundState = consts.RefundStateFail
+			return nil
+		}
+
+		item.RefundState = consts.RefundStateSuccess
+		o.Refunded += in.RefundMoney
+		if toCent(o.Refunded) >= toCent(o.Pay.PayAmount) {
+			o.TradeState = consts.TradeStateRefund
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	if h.config.MockPayRefundFail {
+		err = gerror.New("模拟退款失败，如需退款成功请关闭支付配置中的【模拟退款失败】")
+		return
+	}
+
+	res = new(payin.RefundModel)
+	return
+}
+
+// Notify 异步通知
+func (h *mockPay) Notify(ctx context.Context, in payin.NotifyInp) (res *payin.NotifyModel, err error) {
+	if err = h.checkEnabled(); err != nil {
+		return
+	}
+
+	values, err := url.ParseQuery(string(in.Body))
+	if err != nil {
+		return
+	}
+
+	params := make(map[string]string, len(values))
+	for k := range values {
+		params[k] = values.Get(k)
+	}
+
+	if !VerifySign(params, h.secret()) {
+		err = gerror.New("模拟支付验签不通过！")
+		return
+	}
+
+	var notify *NotifyRequest
+	if err = gconv.Scan(params, &notify); err != nil {
+		return
+	}
+
+	if notify == nil {
+		err = gerror.New("解析订单参数失败！")
+		return
+	}
+
+	if notify.TradeState != TradeSuccess {
+		err = gerror.New("非交易支付成功状态，无需处理！")
+		return
+	}
+
+	if notify.OutTradeNo == "" {
+		err = gerror.New("订单中没有找到商户单号！")
+		return
+	}
+
+	res = new(payin.NotifyModel)
+	res.TransactionId = notify.TransactionId
+	res.OutTradeNo = notify.OutTradeNo
+	res.PayAt = gtime.NewFromStr(notify.PayAt)
+	res.ActualAmount = gconv.Float64(notify.TotalAmount)
+	res.TotalAmount = gconv.Float64(notify.TotalAmount)
+	res.MchId = notify.MchId
+	return
+}
+
+// CreateOrder 创建订单
+func (h *mockPay) CreateOrder(ctx context.Context, in payin.CreateOrderInp) (res *payin.CreateOrderModel, err error) {
+	if err = h.checkEnabled(); err != nil {
+		return
+	}
+
+	if in.Pay.TradeType != consts.TradeTypeMock {
+		err = gerror.Newf("暂未支持的交易方式：%v", in.Pay.TradeType)
+		return
+	}
+
+	if in.Pay.NotifyUrl == "" || !strings.HasSuffix(in.Pay.NotifyUrl, NotifyPath) {
+		err = gerror.Newf("模拟支付通知地址有误：%v", in.Pay.NotifyUrl)
+		return
+	}
+
+	pay := *in.Pay
+	saveOrder(&order{
+		Pay:        &pay,
+		TradeState: consts.TradeStateWait,
+	})
+
+	res = new(payin.CreateOrderModel)
+	res.TradeType = in.Pay.TradeType
+	res.PayURL = CashierURL(in.Pay.NotifyUrl, in.Pay.OutTradeNo)
+	res.OutTradeNo = in.Pay.OutTradeNo
+	return
+}
+
+// QueryOrder 查询订单
+func (h *mockPay) QueryOrder(ctx context.Context, in payin.QueryOrderInp) (res *payin.QueryOrderModel, err error) {
+	res = new(payin.QueryOrderModel)
+	res.OutTradeNo = in.Pay.OutTradeNo
+
+	o := getOrder(in.Pay.OutTradeNo)
+	if o == nil {
+		res.TradeState = consts.TradeStateNotExist
+		return
+	}
+
+	res.TradeState = o.TradeState
+	res.TransactionId = o.TransactionId
+	if o.TradeState == consts.TradeStateSuccess || o.TradeState == consts.TradeStateRefund {
+		res.PayAt = o.PayAt
+		res.ActualAmount = o.Pay.PayAmount
+	}
+	return
+}
+
+// CloseOrder 关闭订单
+func (h *mockPay) CloseOrder(ctx context.Context, in payin.CloseOrderInp) (res *payin.CloseOrderModel, err error) {
+	_, err = updateOrder(in.Pay.OutTradeNo, func(o *order) error {
+		if o.TradeState == consts.TradeStateSuccess || o.TradeState == consts.TradeStateRefund {
+			return gerror.New("模拟支付订单已支付，无法关闭")
+		}
+		o.TradeState = consts.TradeStateClosed
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	res = new(payin.CloseOrderModel)
+	return
+}
+
+// QueryRefund 查询退款
+func (h *mockPay) QueryRefund(ctx context.Context, in payin.QueryRefundInp) (res *payin.QueryRefundModel, err error) {
+	o := getOrder(in.Pay.OutTradeNo)
+	if o == nil {
+		err = errOrderNotExist
+		return
+	}
+
+	item := o.findRefund(in.RefundSn)
+	if item == nil {
+		err = gerror.Newf("模拟支付退款单[%v]不存在", in.RefundSn)
+		return
+	}
+
+	res = &payin.QueryRefundModel{
+		RefundSn:    item.RefundSn,
+		RefundId:    item.RefundId,
+		RefundState: item.RefundState,
+		RefundMoney: item.RefundMoney,
+		RefundAt:    item.RefundAt,
+	}
+	return
+}
+
+// DownloadBill 下载交易账单
+func (h *mockPay) DownloadBill(ctx context.Context, in payin.DownloadBillInp) (res *payin.DownloadBillModel, err error) {
+	var (
+		billDate = in.BillDate.Format("Y-m-d")
+		onDate   = func(t *gtime.Time) bool { return t != nil && t.Format("Y-m-d") == billDate }
+	)
+
+	res = new(payin.DownloadBillModel)
+	rangeOrders(func(o *order) {
+		if onDate(o.PayAt) {
+			res.List = append(res.List, &payin.BillItem{
+				Kind:          consts.BillKindPay,
+				OutTradeNo:    o.Pay.OutTradeNo,
+				TransactionId: o.TransactionId,
+				Amount:        o.Pay.PayAmount,
+				TradeAt:       o.PayAt,
+			})
+		}
+
+		for _, item := range o.Refunds {
+			if item.RefundState != consts.RefundStateSuccess || !onDate(item.RefundAt) {
+				continue
+			}
+			res.List = append(res.List, &payin.BillItem{
+				Kind:          consts.BillKindRefund,
+				OutTradeNo:    o.Pay.OutTradeNo,
+				TransactionId: o.TransactionId,
+				RefundSn:      item.RefundSn,
+				Amount:        item.RefundMoney,
+				TradeAt:       item.RefundAt,
+			})
+		}
+	})
+	return
+}
+
+// CashierURL 根据支付通知地址生成收银台地址
+func CashierURL(notifyURL, outTradeNo string) string {
+	return fmt.Sprintf("%s%s?outTradeNo=%s", strings.TrimSuffix(notifyURL, NotifyPath), CashierPath, url.QueryEscape(outTradeNo))
+}
+
+// checkEnabled 检查是否已开启模拟支付，未配置签名密钥时不允许签名和验签，避免使用公开的默认密钥伪造通知
+func (h *mockPay) checkEnabled() error {
+	if h.config == nil || !h.config.MockPayEnabled {
+		return gerror.New("模拟支付未开启，请到后台【系统设置】-【配置管理】-【支付配置】中开启")
+	}
+
+	if h.config.MockPaySecret == "" {
+		return gerror.New("模拟支付未配置签名密钥，请到后台【系统设置】-【配置管理】-【支付配置】中配置")
+	}
+	return nil
+}
+
+// secret 签名密钥，调用前需要通过checkEnabled检查
+func (h *mockPay) secret() string {
+	return h.config.MockPaySecret
+}
+
+func genNo(prefix string) string {
+	return fmt.Sprintf("%v%v%v", prefix, gtime.Now().Format("YmdHis"), grand.N(10000000, 99999999))
+}
+
+func toCent(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,221 @@
+package mockpay
+
+import (
+	"context"
+	"hotgo/internal/consts"
+	"hotgo/internal/model"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/payin"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+const testSecret = "mockpay-test-secret"
+
+func TestSign(t *testing.T) {
+	params := map[string]string{"out_trade_no": "1001", "trade_state": TradeSuccess, "empty": ""}
+	params["sign"] = Sign(params, testSecret)
+
+	if !VerifySign(params, testSecret) {
+		t.Fatal("verify sign failed")
+	}
+
+	if VerifySign(params, "other") {
+		t.Fatal("verify sign with wrong secret passed")
+	}
+
+	params["trade_state"] = "FAIL"
+	if VerifySign(params, testSecret) {
+		t.Fatal("verify tampered params passed")
+	}
+}
+
+func TestOrderFlow(t *testing.T) {
+	var notified map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		notified = make(map[string]string)
+		for k := range r.PostForm {
+			notified[k] = r.PostForm.Get(k)
+		}
+
+		if !VerifySign(notified, testSecret) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(NotifySuccess))
+	}))
+	defer srv.Close()
+
+	var (
+		ctx    = context.Background()
+		config = &model.PayConfig{MockPayEnabled: true, MockPaySecret: testSecret}
+		client = New(config)
+		pay    = &entity.PayLog{
+			OutTradeNo: "mock_test_1001",
+			Subject:    "测试订单",
+			PayAmount:  10,
+			TradeType:  consts.TradeTypeMock,
+			NotifyUrl:  srv.URL + "/api" + NotifyPath,
+		}
+	)
+
+	order, err := client.CreateOrder(ctx, payin.CreateOrderInp{Pay: pay})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := srv.URL + "/api" + CashierPath + "?outTradeNo=mock_test_1001"; order.PayURL != want {
+		t.Fatalf("payURL = %v, want %v", order.PayURL, want)
+	}
+
+	if _, err = client.Submit(ctx, pay.OutTradeNo, ActionPay); err != nil {
+		t.Fatal(err)
+	}
+
+	if notified["out_trade_no"] != pay.OutTradeNo || notified["total_amount"] != "10.00" {
+		t.Fatalf("unexpected notify params: %v", notified)
+	}
+
+	query, err := client.QueryOrder(ctx, payin.QueryOrderInp{Pay: pay})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query.TradeState != consts.TradeStateSuccess || query.TransactionId != notified["transaction_id"] {
+		t.Fatalf("unexpected query result: %+v", query)
+	}
+
+	if _, err = client.Refund(ctx, payin.RefundInp{Pay: pay, RefundSn: "r1", RefundMoney: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	config.MockPayRefundFail = true
+	if _, err = client.Refund(ctx, payin.RefundInp{Pay: pay, RefundSn: "r2", RefundMoney: 6}); err == nil {
+		t.Fatal("refund should fail")
+	}
+
+	refund, err := client.QueryRefund(ctx, payin.QueryRefundInp{Pay: pay, RefundSn: "r2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if refund.RefundState != consts.RefundStateFail {
+		t.Fatalf("refund state = %v, want %v", refund.RefundState, consts.RefundStateFail)
+	}
+
+	// 失败的退款可以使用同一退款单号重新申请
+	config.MockPayRefundFail = false
+	if _, err = client.Refund(ctx, payin.RefundInp{Pay: pay, RefundSn: "r2", RefundMoney: 6}); err != nil {
+		t.Fatal(err)
+	}
+
+	if refund, err = client.QueryRefund(ctx, payin.QueryRefundInp{Pay: pay, RefundSn: "r2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if refund.RefundState != consts.RefundStateSuccess {
+		t.Fatalf("refund state = %v, want %v", refund.RefundState, consts.RefundStateSuccess)
+	}
+
+	if _, err = client.Refund(ctx, payin.RefundInp{Pay: pay, RefundSn: "r2", RefundMoney: 6}); err == nil {
+		t.Fatal("duplicate refund should fail")
+	}
+
+	if _, err = client.Refund(ctx, payin.RefundInp{Pay: pay, RefundSn: "r3", RefundMoney: 1}); err == nil {
+		t.Fatal("refund exceeding paid amount should fail")
+	}
+
+	bill, err := client.DownloadBill(ctx, payin.DownloadBillInp{BillDate: gtime.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bill.List) != 3 {
+		t.Fatalf("bill items = %v, want 3", len(bill.List))
+	}
+}
+
+func TestCancel(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		client = New(&model.PayConfig{MockPayEnabled: true, MockPaySecret: testSecret})
+		pay    = &entity.PayLog{
+			OutTradeNo: "mock_test_1002",
+			PayAmount:  1,
+			TradeType:  consts.TradeTypeMock,
+			NotifyUrl:  "http://127.0.0.1" + NotifyPath,
+		}
+	)
+
+	if _, err := client.CreateOrder(ctx, payin.CreateOrderInp{Pay: pay}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Submit(ctx, pay.OutTradeNo, ActionCancel); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Submit(ctx, pay.OutTradeNo, ActionPay); err == nil {
+		t.Fatal("closed order should not be paid")
+	}
+
+	query, err := client.QueryOrder(ctx, payin.QueryOrderInp{Pay: pay})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query.TradeState != consts.TradeStateClosed {
+		t.Fatalf("trade state = %v, want %v", query.TradeState, consts.TradeStateClosed)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		client = New(&model.PayConfig{MockPayEnabled: true, MockPaySecret: testSecret})
+		params = map[string]string{
+			"mch_id":         MchId,
+			"out_trade_no":   "mock_test_1003",
+			"transaction_id": "MOCK1003",
+			"trade_state":    TradeSuccess,
+			"total_amount":   "12.50",
+			"nonce_str":      "abc",
+		}
+	)
+	params["sign"] = Sign(params, testSecret)
+
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+
+	res, err := client.Notify(ctx, payin.NotifyInp{Body: []byte(values.Encode())})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.OutTradeNo != "mock_test_1003" || res.TotalAmount != 12.5 || res.MchId != MchId {
+		t.Fatalf("unexpected notify result: %+v", res)
+	}
+
+	values.Set("total_amount", "0.01")
+	if _, err = client.Notify(ctx, payin.NotifyInp{Body: []byte(values.Encode())}); err == nil {
+		t.Fatal("tampered notify should fail")
+	}
+
+	// 未开启或未配置密钥时拒绝通知
+	values.Set("total_amount", "12.50")
+	for _, config := range []*model.PayConfig{
+		{MockPayEnabled: false, MockPaySecret: testSecret},
+		{MockPayEnabled: true},
+	} {
+		if _, err = New(config).Notify(ctx, payin.NotifyInp{Body: []byte(values.Encode())}); err == nil {
+			t.Fatalf("notify accepted with config: %+v", config)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+// Package mockpay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package mockpay
+
+// NotifyRequest 模拟支付异步通知参数
+type NotifyRequest struct {
+	MchId         string `json:"mch_id"`
+	OutTradeNo    string `json:"out_trade_no"`
+	TransactionId string `json:"transaction_id"`
+	TradeState    string `json:"trade_state"`
+	TotalAmount   string `json:"total_amount"`
+	PayAt         string `json:"pay_at"`
+	NonceStr      string `json:"nonce_str"`
+	Sign          string `json:"sign"`
+}
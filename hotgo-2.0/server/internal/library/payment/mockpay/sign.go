@@ -0,0 +1,49 @@
+// Package mockpay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package mockpay
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// Sign 生成签名，签名原文为除sign外的非空参数按键名升序以key=value&key=value拼接，使用HMAC-SHA256计算
+func Sign(params map[string]string, secret string) string {
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if k == "sign" || v == "" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte('&')
+		}
+		buf.WriteString(k)
+		buf.WriteByte('=')
+		buf.WriteString(params[k])
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(buf.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySign 验证签名
+func VerifySign(params map[string]string, secret string) bool {
+	sign := params["sign"]
+	if sign == "" {
+		return false
+	}
+	return hmac.Equal([]byte(sign), []byte(Sign(params, secret)))
+}
@@ -0,0 +1,97 @@
+// Package mockpay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package mockpay
+
+import (
+	"hotgo/internal/model/entity"
+	"sync"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 模拟渠道侧的交易数据，只保存在当前进程内存中，服务重启后丢失
+
+type order struct {
+	Pay           *entity.PayLog
+	TradeState    string
+	TransactionId string
+	PayAt         *gtime.Time
+	Refunded      float64
+	Refunds       []*refund
+}
+
+type refund struct {
+	RefundSn    string
+	RefundId    string
+	RefundState string
+	RefundMoney float64
+	RefundAt    *gtime.Time
+}
+
+var (
+	orders   = make(map[string]*order)
+	ordersMu sync.RWMutex
+)
+
+// saveOrder 保存订单，同一商户订单号重复下单时覆盖原订单
+func saveOrder(o *order) {
+	ordersMu.Lock()
+	defer ordersMu.Unlock()
+	orders[o.Pay.OutTradeNo] = o
+}
+
+// getOrder 获取订单快照，不存在时返回nil
+func getOrder(outTradeNo string) *order {
+	ordersMu.RLock()
+	defer ordersMu.RUnlock()
+	o, ok := orders[outTradeNo]
+	if !ok {
+		return nil
+	}
+	return o.clone()
+}
+
+// updateOrder 加锁修改订单，返回修改后的快照
+func updateOrder(outTradeNo string, f func(o *order) error) (*order, error) {
+	ordersMu.Lock()
+	defer ordersMu.Unlock()
+	o, ok := orders[outTradeNo]
+	if !ok {
+		return nil, errOrderNotExist
+	}
+	if err := f(o); err != nil {
+		return nil, err
+	}
+	return o.clone(), nil
+}
+
+// rangeOrders 遍历所有订单快照
+func rangeOrders(f func(o *order)) {
+	ordersMu.RLock()
+	defer ordersMu.RUnlock()
+	for _, o := range orders {
+		f(o.clone())
+	}
+}
+
+func (o *order) clone() *order {
+	c := *o
+	c.Refunds = make([]*refund, 0, len(o.Refunds))
+	for _, r := range o.Refunds {
+		rc := *r
+		c.Refunds = append(c.Refunds, &rc)
+	}
+	return &c
+}
+
+func (o *order) findRefund(refundSn string) *refund {
+	for _, r := range o.Refunds {
+		if r.RefundSn == refundSn {
+			return r
+		}
+	}
+	return nil
+}
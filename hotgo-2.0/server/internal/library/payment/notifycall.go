@@ -8,10 +8,9 @@ package payment
 import (
 	"context"
 	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
-	"hotgo/internal/library/contexts"
 	"hotgo/internal/model/input/payin"
-	"hotgo/utility/simple"
 	"sync"
 )
 
@@ -41,15 +40,19 @@ func RegisterNotifyCallMap(calls map[string]NotifyCallFunc) {
 	}
 }
 
-// NotifyCall 执行订单分组的异步回调
-func NotifyCall(ctx context.Context, in *payin.NotifyCallFuncInp) {
+// NotifyCall 执行订单分组的支付成功回调，订单分组未注册回调方法时直接返回
+// 回调由支付成功时写入发件箱的消息驱动，返回错误时消息会按队列的重试策略重新消费，回调方法需要保证幂等
+func NotifyCall(ctx context.Context, in *payin.NotifyCallFuncInp) (err error) {
+	ncLock.Lock()
 	f, ok := notifyCall[in.Pay.OrderGroup]
-	if ok {
-		ctx = contexts.Detach(ctx)
-		simple.SafeGo(ctx, func(ctx context.Context) {
-			if err := f(ctx, in); err != nil {
-				g.Log().Warningf(ctx, "payment.NotifyCall in:%+v exec err:%+v", gjson.New(in.Pay).String(), err)
-			}
-		})
+	ncLock.Unlock()
+	if !ok {
+		g.Log().Debugf(ctx, "payment.NotifyCall orderGroup:%v not registered", in.Pay.OrderGroup)
+		return
+	}
+
+	if err = f(ctx, in); err != nil {
+		err = gerror.Wrapf(err, "payment.NotifyCall in:%+v exec failed", gjson.New(in.Pay).String())
 	}
+	return
 }
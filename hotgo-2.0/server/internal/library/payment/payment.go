@@ -11,6 +11,7 @@ import (
 	"hotgo/internal/consts"
 	"hotgo/internal/dao"
 	"hotgo/internal/library/payment/alipay"
+	"hotgo/internal/library/payment/mockpay"
 	"hotgo/internal/library/payment/qqpay"
 	"hotgo/internal/library/payment/wxpay"
 	"hotgo/internal/model/input/payin"
@@ -28,6 +29,14 @@ type PayClient interface {
 	Notify(ctx context.Context, in payin.NotifyInp) (res *payin.NotifyModel, err error)
 	// Refund 订单退款
 	Refund(ctx context.Context, in payin.RefundInp) (res *payin.RefundModel, err error)
+	// QueryOrder 查询订单
+	QueryOrder(ctx context.Context, in payin.QueryOrderInp) (res *payin.QueryOrderModel, err error)
+	// CloseOrder 关闭订单
+	CloseOrder(ctx context.Context, in payin.CloseOrderInp) (res *payin.CloseOrderModel, err error)
+	// QueryRefund 查询退款
+	QueryRefund(ctx context.Context, in payin.QueryRefundInp) (res *payin.QueryRefundModel, err error)
+	// DownloadBill 下载交易账单
+	DownloadBill(ctx context.Context, in payin.DownloadBillInp) (res *payin.DownloadBillModel, err error)
 }
 
 func New(name ...string) PayClient {
@@ -47,6 +56,8 @@ func New(name ...string) PayClient {
 		client = wxpay.New(config)
 	case consts.PayTypeQQPay:
 		client = qqpay.New(config)
+	case consts.PayTypeMockPay:
+		client = mockpay.New(config)
 	default:
 		panic(fmt.Sprintf("暂不支持的支付方式:%v", payType))
 	}
@@ -119,6 +130,8 @@ func AutoTradeType(payType, userAgent string) (tradeType string) {
 			return consts.TradeTypeQQWap
 		}
 		return consts.TradeTypeQQWeb
+	case consts.PayTypeMockPay:
+		return consts.TradeTypeMock
 	default:
 	}
 	return
@@ -10,13 +10,14 @@ import (
 	"github.com/go-pay/gopay"
 	"github.com/go-pay/gopay/qq"
 	"github.com/gogf/gf/v2/errors/gerror"
-	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/gogf/gf/v2/util/grand"
 	"hotgo/internal/consts"
+	"hotgo/internal/library/payment/bill"
 	"hotgo/internal/model"
 	"hotgo/internal/model/input/payin"
+	"time"
 )
 
 func New(config *model.PayConfig) *qqPay {
@@ -37,7 +38,12 @@ func (h *qqPay) Refund(ctx context.Context, in payin.RefundInp) (res *payin.Refu
 
 // Notify 异步通知
 func (h *qqPay) Notify(ctx context.Context, in payin.NotifyInp) (res *payin.NotifyModel, err error) {
-	notifyReq, err := qq.ParseNotifyToBodyMap(ghttp.RequestFromCtx(ctx).Request)
+	r, err := in.Request()
+	if err != nil {
+		return
+	}
+
+	notifyReq, err := qq.ParseNotifyToBodyMap(r)
 	if err != nil {
 		return
 	}
@@ -80,6 +86,8 @@ func (h *qqPay) Notify(ctx context.Context, in payin.NotifyInp) (res *payin.Noti
 	res.OutTradeNo = notify.OutTradeNo
 	res.PayAt = gtime.New(notify.TimeEnd)
 	res.ActualAmount = gconv.Float64(notify.CouponFee) / 100 // 用户本次交易中，实际支付的金额 转为元，和系统内保持一至
+	res.TotalAmount = gconv.Float64(notify.TotalFee) / 100
+	res.MchId = notify.MchId
 	return
 }
 
@@ -126,6 +134,156 @@ func (h *qqPay) CreateOrder(ctx context.Context, in payin.CreateOrderInp) (res *
 	return
 }
 
+// QueryOrder 查询订单
+func (h *qqPay) QueryOrder(ctx context.Context, in payin.QueryOrderInp) (res *payin.QueryOrderModel, err error) {
+	bm := make(gopay.BodyMap)
+	bm.Set("mch_id", h.config.QQPayMchId).
+		Set("out_trade_no", in.Pay.OutTradeNo).
+		Set("nonce_str", grand.Letters(32))
+
+	qqRsp, err := GetClient(h.config).OrderQuery(ctx, bm)
+	if err != nil {
+		return
+	}
+
+	if qqRsp.ReturnCode != "SUCCESS" {
+		err = gerror.New(qqRsp.ReturnMsg)
+		return
+	}
+
+	res = new(payin.QueryOrderModel)
+	res.OutTradeNo = in.Pay.OutTradeNo
+
+	if qqRsp.ResultCode != "SUCCESS" {
+		if qqRsp.ErrCode == "ORDERNOTEXIST" {
+			res.TradeState = consts.TradeStateNotExist
+			return
+		}
+		err = gerror.New(qqRsp.ErrCodeDes)
+		return
+	}
+
+	res.TransactionId = qqRsp.TransactionId
+	switch qqRsp.TradeState {
+	case "SUCCESS":
+		res.TradeState = consts.TradeStateSuccess
+		res.PayAt = gtime.New(qqRsp.TimeEnd)
+		res.ActualAmount = gconv.Float64(qqRsp.CashFee) / 100
+	case "REFUND":
+		res.TradeState = consts.TradeStateRefund
+	case "CLOSED", "REVOKED", "PAYERROR":
+		res.TradeState = consts.TradeStateClosed
+	default:
+		res.TradeState = consts.TradeStateWait
+	}
+	return
+}
+
+// CloseOrder 关闭订单
+func (h *qqPay) CloseOrder(ctx context.Context, in payin.CloseOrderInp) (res *payin.CloseOrderModel, err error) {
+	bm := make(gopay.BodyMap)
+	bm.Set("mch_id", h.config.QQPayMchId).
+		Set("out_trade_no", in.Pay.OutTradeNo).
+		Set("nonce_str", grand.Letters(32))
+
+	qqRsp, err := GetClient(h.config).CloseOrder(ctx, bm)
+	if err != nil {
+		return
+	}
+
+	if qqRsp.ReturnCode != "SUCCESS" {
+		err = gerror.New(qqRsp.ReturnMsg)
+		return
+	}
+
+	if qqRsp.ResultCode != "SUCCESS" {
+		err = gerror.New(qqRsp.ErrCodeDes)
+		return
+	}
+	res = new(payin.CloseOrderModel)
+	return
+}
+
+// QueryRefund 查询退款
+func (h *qqPay) QueryRefund(ctx context.Context, in payin.QueryRefundInp) (res *payin.QueryRefundModel, err error) {
+	bm := make(gopay.BodyMap)
+	bm.Set("mch_id", h.config.QQPayMchId).
+		Set("out_refund_no", in.RefundSn).
+		Set("nonce_str", grand.Letters(32))
+
+	qqRsp, err := GetClient(h.config).RefundQuery(ctx, bm)
+	if err != nil {
+		return
+	}
+
+	if qqRsp.ReturnCode != "SUCCESS" {
+		err = gerror.New(qqRsp.ReturnMsg)
+		return
+	}
+
+	if qqRsp.ResultCode != "SUCCESS" {
+		err = gerror.New(qqRsp.ErrCodeDes)
+		return
+	}
+
+	res = new(payin.QueryRefundModel)
+	res.RefundSn = in.RefundSn
+	res.RefundId = qqRsp.RefundId0
+	res.RefundMoney = gconv.Float64(qqRsp.RefundFee0) / 100
+
+	switch qqRsp.RefundStatus0 {
+	case "SUCCESS":
+		res.RefundState = consts.RefundStateSuccess
+	case "FAIL", "CHANGE":
+		res.RefundState = consts.RefundStateFail
+	default:
+		res.RefundState = consts.RefundStateProcessing
+	}
+	return
+}
+
+// DownloadBill 下载交易账单
+func (h *qqPay) DownloadBill(ctx context.Context, in payin.DownloadBillInp) (res *payin.DownloadBillModel, err error) {
+	bm := make(gopay.BodyMap)
+	bm.Set("mch_id", h.config.QQPayMchId).
+		Set("bill_date", in.BillDate.Format("Ymd")).
+		Set("bill_type", "ALL").
+		Set("nonce_str", grand.Letters(32))
+
+	content, err := GetClient(h.config).StatementDown(ctx, bm)
+	if err != nil {
+		return
+	}
+
+	rows, err := bill.Parse(content, "商户订单号")
+	if err != nil {
+		return
+	}
+
+	res = new(payin.DownloadBillModel)
+	for _, row := range rows {
+		item := &payin.BillItem{
+			OutTradeNo:    row.Get("商户订单号"),
+			TransactionId: row.Get("QQ钱包订单号", "财付通订单号"),
+			TradeAt:       gtime.NewFromStrLayout(row.Get("交易时间"), time.DateTime),
+		}
+
+		switch row.Get("交易状态") {
+		case "SUCCESS":
+			item.Kind = consts.BillKindPay
+			item.Amount = row.Float64("订单金额", "总金额")
+		case "REFUND":
+			item.Kind = consts.BillKindRefund
+			item.RefundSn = row.Get("商户退款单号")
+			item.Amount = row.Float64("退款金额")
+		default:
+			continue
+		}
+		res.List = append(res.List, item)
+	}
+	return
+}
+
 func GetClient(config *model.PayConfig) (client *qq.Client) {
 	client = qq.NewClient(config.QQPayMchId, config.QQPayApiKey)
 
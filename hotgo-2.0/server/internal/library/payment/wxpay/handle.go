@@ -13,9 +13,9 @@ import (
 	"github.com/go-pay/gopay/wechat/v3"
 	"github.com/go-pay/smap"
 	"github.com/gogf/gf/v2/errors/gerror"
-	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gtime"
 	"hotgo/internal/consts"
+	"hotgo/internal/library/payment/bill"
 	weOpen "hotgo/internal/library/wechat"
 	"hotgo/internal/model"
 	"hotgo/internal/model/input/payin"
@@ -68,7 +68,12 @@ func (h *wxPay) Refund(ctx context.Context, in payin.RefundInp) (res *payin.Refu
 
 // Notify 异步通知
 func (h *wxPay) Notify(ctx context.Context, in payin.NotifyInp) (res *payin.NotifyModel, err error) {
-	notifyReq, err := wechat.V3ParseNotify(ghttp.RequestFromCtx(ctx).Request)
+	r, err := in.Request()
+	if err != nil {
+		return
+	}
+
+	notifyReq, err := wechat.V3ParseNotify(r)
 	if err != nil {
 		return
 	}
@@ -110,7 +115,9 @@ func (h *wxPay) Notify(ctx context.Context, in payin.NotifyInp) (res *payin.Noti
 	res.TransactionId = notify.TransactionId
 	res.OutTradeNo = notify.OutTradeNo
 	res.PayAt = gtime.New(notify.SuccessTime)
-	res.ActualAmount = float64(notify.Amount.PayerTotal / 100) // 转为元，和系统内保持一至
+	res.ActualAmount = float64(notify.Amount.PayerTotal) / 100 // 转为元，和系统内保持一至
+	res.TotalAmount = float64(notify.Amount.Total) / 100
+	res.MchId = notify.Mchid
 	return
 }
 
@@ -129,6 +136,159 @@ func (h *wxPay) CreateOrder(ctx context.Context, in payin.CreateOrderInp) (res *
 	return
 }
 
+// QueryOrder 查询订单
+func (h *wxPay) QueryOrder(ctx context.Context, in payin.QueryOrderInp) (res *payin.QueryOrderModel, err error) {
+	client, err := GetClient(h.config)
+	if err != nil {
+		return
+	}
+
+	wxRsp, err := client.V3TransactionQueryOrder(ctx, wechat.OutTradeNo, in.Pay.OutTradeNo)
+	if err != nil {
+		return
+	}
+
+	res = new(payin.QueryOrderModel)
+	res.OutTradeNo = in.Pay.OutTradeNo
+
+	if wxRsp.Code != 0 {
+		if wxRsp.ErrResponse.Code == "ORDER_NOT_EXIST" {
+			res.TradeState = consts.TradeStateNotExist
+			return
+		}
+		err = gerror.New(wxRsp.Error)
+		return
+	}
+
+	res.TransactionId = wxRsp.Response.TransactionId
+	switch wxRsp.Response.TradeState {
+	case "SUCCESS":
+		res.TradeState = consts.TradeStateSuccess
+		res.PayAt = gtime.New(wxRsp.Response.SuccessTime)
+		if wxRsp.Response.Amount != nil {
+			res.ActualAmount = float64(wxRsp.Response.Amount.PayerTotal) / 100
+		}
+	case "REFUND":
+		res.TradeState = consts.TradeStateRefund
+	case "CLOSED", "REVOKED", "PAYERROR":
+		res.TradeState = consts.TradeStateClosed
+	default:
+		res.TradeState = consts.TradeStateWait
+	}
+	return
+}
+
+// CloseOrder 关闭订单
+func (h *wxPay) CloseOrder(ctx context.Context, in payin.CloseOrderInp) (res *payin.CloseOrderModel, err error) {
+	client, err := GetClient(h.config)
+	if err != nil {
+		return
+	}
+
+	wxRsp, err := client.V3TransactionCloseOrder(ctx, in.Pay.OutTradeNo)
+	if err != nil {
+		return
+	}
+
+	if wxRsp.Code != 0 {
+		err = gerror.New(wxRsp.Error)
+		return
+	}
+	res = new(payin.CloseOrderModel)
+	return
+}
+
+// QueryRefund 查询退款
+func (h *wxPay) QueryRefund(ctx context.Context, in payin.QueryRefundInp) (res *payin.QueryRefundModel, err error) {
+	client, err := GetClient(h.config)
+	if err != nil {
+		return
+	}
+
+	wxRsp, err := client.V3RefundQuery(ctx, in.RefundSn, nil)
+	if err != nil {
+		return
+	}
+
+	if wxRsp.Code != 0 {
+		err = gerror.New(wxRsp.Error)
+		return
+	}
+
+	res = new(payin.QueryRefundModel)
+	res.RefundSn = in.RefundSn
+	res.RefundId = wxRsp.Response.RefundId
+	if wxRsp.Response.Amount != nil {
+		res.RefundMoney = float64(wxRsp.Response.Amount.Refund) / 100
+	}
+
+	switch wxRsp.Response.Status {
+	case "SUCCESS":
+		res.RefundState = consts.RefundStateSuccess
+		res.RefundAt = gtime.New(wxRsp.Response.SuccessTime)
+	case "PROCESSING":
+		res.RefundState = consts.RefundStateProcessing
+	default:
+		res.RefundState = consts.RefundStateFail
+	}
+	return
+}
+
+// DownloadBill 下载交易账单
+func (h *wxPay) DownloadBill(ctx context.Context, in payin.DownloadBillInp) (res *payin.DownloadBillModel, err error) {
+	client, err := GetClient(h.config)
+	if err != nil {
+		return
+	}
+
+	bm := make(gopay.BodyMap)
+	bm.Set("bill_date", in.BillDate.Format("Y-m-d")).
+		Set("bill_type", "ALL")
+
+	wxRsp, err := client.V3BillTradeBill(ctx, bm)
+	if err != nil {
+		return
+	}
+
+	if wxRsp.Code != 0 {
+		err = gerror.New(wxRsp.Error)
+		return
+	}
+
+	content, err := client.V3BillDownLoadBill(ctx, wxRsp.Response.DownloadUrl)
+	if err != nil {
+		return
+	}
+
+	rows, err := bill.Parse(string(content), "微信订单号")
+	if err != nil {
+		return
+	}
+
+	res = new(payin.DownloadBillModel)
+	for _, row := range rows {
+		item := &payin.BillItem{
+			OutTradeNo:    row.Get("商户订单号"),
+			TransactionId: row.Get("微信订单号"),
+			TradeAt:       gtime.NewFromStrLayout(row.Get("交易时间"), time.DateTime),
+		}
+
+		switch row.Get("交易状态") {
+		case "SUCCESS":
+			item.Kind = consts.BillKindPay
+			item.Amount = row.Float64("订单金额", "应结订单金额")
+		case "REFUND":
+			item.Kind = consts.BillKindRefund
+			item.RefundSn = row.Get("商户退款单号")
+			item.Amount = row.Float64("申请退款金额", "退款金额")
+		default:
+			continue
+		}
+		res.List = append(res.List, item)
+	}
+	return
+}
+
 func GetClient(config *model.PayConfig) (client *wechat.ClientV3, err error) {
 	client, err = wechat.NewClientV3(config.WxPayMchId, config.WxPaySerialNo, config.WxPayAPIv3Key, config.WxPayPrivateKey)
 	if err != nil {
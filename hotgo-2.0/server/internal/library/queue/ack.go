@@ -0,0 +1,69 @@
+// Package queue
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package queue
+
+import (
+	"sync"
+)
+
+// DoneFunc 消息处理结束回调，handled为false表示消费者正在关闭，消息未被处理，驱动不应确认该消息
+type DoneFunc func(handled bool)
+
+// OnDone 设置消息处理结束回调，驱动可以在回调中确认消息或提交消费位点
+// 并发消费时回调在工作协程中执行，回调需要保证并发安全
+func (m MqMsg) OnDone(f DoneFunc) MqMsg {
+	m.done = f
+	return m
+}
+
+// finish 消息处理结束
+func (m MqMsg) finish(handled bool) {
+	if m.done != nil {
+		m.done(handled)
+	}
+}
+
+// ackTracker 按接收顺序确认消息
+// 并发消费时消息的处理完成顺序与接收顺序不一致，只有之前接收的消息都处理完成后才会执行确认，避免提前提交尚未处理完成的位点
+type ackTracker struct {
+	sync.Mutex
+	seq     int64
+	pending []int64          // 已接收未确认的序号，按接收顺序排列
+	commits map[int64]func() // 已处理完成等待确认的序号
+}
+
+// newAckTracker 创建消息确认跟踪器
+func newAckTracker() *ackTracker {
+	return &ackTracker{commits: make(map[int64]func())}
+}
+
+// track 登记一条已接收的消息，返回的回调在消息处理结束后调用，未被处理的消息及其之后的消息都不会被确认
+func (t *ackTracker) track(commit func()) DoneFunc {
+	t.Lock()
+	t.seq++
+	seq := t.seq
+	t.pending = append(t.pending, seq)
+	t.Unlock()
+
+	return func(handled bool) {
+		if !handled {
+			return
+		}
+
+		t.Lock()
+		defer t.Unlock()
+		t.commits[seq] = commit
+		for len(t.pending) > 0 {
+			f, ok := t.commits[t.pending[0]]
+			if !ok {
+				return
+			}
+			delete(t.commits, t.pending[0])
+			t.pending = t.pending[1:]
+			f()
+		}
+	}
+}
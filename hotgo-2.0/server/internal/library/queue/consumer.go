@@ -7,7 +7,9 @@ package queue
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 )
 
 // Consumer 消费者接口，实现该接口即可加入到消费队列中
@@ -16,10 +18,20 @@ type Consumer interface {
 	Handle(ctx context.Context, mqMsg MqMsg) (err error) // 处理消息的方法
 }
 
+// RetryConsumer 支持失败重试的消费者，重试次数用尽后消息将被标记为死信
+type RetryConsumer interface {
+	Consumer
+	MaxRetry() int // 最大重试次数
+}
+
+// FailHandler 消费失败处理器，dead为true表示重试次数已用尽
+type FailHandler func(ctx context.Context, topic string, mqMsg MqMsg, err error, dead bool)
+
 // consumerManager 消费者管理
 type consumerManager struct {
 	sync.Mutex
-	list map[string]Consumer // 维护的消费者列表
+	list         map[string]Consumer // 维护的消费者列表
+	failHandlers []FailHandler       // 消费失败处理器
 }
 
 var consumers = &consumerManager{
@@ -38,6 +50,32 @@ func RegisterConsumer(cs Consumer) {
 	consumers.list[topic] = cs
 }
 
+// RegisterFailHandler 注册消费失败处理器
+func RegisterFailHandler(h FailHandler) {
+	consumers.Lock()
+	defer consumers.Unlock()
+	consumers.failHandlers = append(consumers.failHandlers, h)
+}
+
+// GetConsumer 获取指定主题的消费者
+func GetConsumer(topic string) Consumer {
+	consumers.Lock()
+	defer consumers.Unlock()
+	return consumers.list[topic]
+}
+
+// GetTopics 获取已注册消费者的主题列表
+func GetTopics() []string {
+	consumers.Lock()
+	defer consumers.Unlock()
+	topics := make([]string, 0, len(consumers.list))
+	for topic := range consumers.list {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
 // StartConsumersListener 启动所有已注册的消费者监听
 func StartConsumersListener(ctx context.Context) {
 	for _, c := range consumers.list {
@@ -59,17 +97,63 @@ func consumerListen(ctx context.Context, job Consumer) {
 		return
 	}
 
-	if listenErr := c.ListenReceiveMsgDo(topic, func(mqMsg MqMsg) {
-		err = job.Handle(ctx, mqMsg)
+	// 消息处理结束后才通知驱动确认消息或提交消费位点，处理失败的消息已由重试和失败处理器兜底，同样视为处理结束
+	handle := func(mqMsg MqMsg) {
+		defer inflight.Done()
+		defer mqMsg.finish(true)
 
-		// if err != nil {
-		//	// 遇到错误，重新加入到队列
-		//	//queue.Push(topic, mqMsg.Body)
-		// }
+		// 相同幂等键的消息只消费一次
+		if !acquireIdempotent(ctx, mqMsg) {
+			Logger().Debugf(ctx, "queue topic:%v key:%v duplicate message skipped.", topic, mqMsg.Key)
+			return
+		}
+
+		err := consumerHandle(ctx, job, mqMsg)
+		finishIdempotent(ctx, mqMsg, err)
 
 		// 记录消费队列日志
 		ConsumerLog(ctx, topic, mqMsg, err)
+	}
+
+	d := newDispatcher(ctx, job, handle)
+	if listenErr := c.ListenReceiveMsgDo(topic, func(mqMsg MqMsg) {
+		// 服务关闭中不再处理新消息，驱动不会确认该消息，由消息队列稍后重新投递
+		if !acceptMsg() {
+			mqMsg.finish(false)
+			return
+		}
+		if d == nil {
+			handle(mqMsg)
+			return
+		}
+		d.dispatch(mqMsg)
 	}); listenErr != nil {
 		Logger().Fatalf(ctx, "消费队列：%s 监听失败, err:%+v", topic, listenErr)
 	}
 }
+
+// consumerHandle 处理消息，失败时按消费者声明的次数进行重试
+func consumerHandle(ctx context.Context, job Consumer, mqMsg MqMsg) (err error) {
+	maxRetry := 0
+	if rc, ok := job.(RetryConsumer); ok {
+		maxRetry = rc.MaxRetry()
+	}
+
+	for attempt := 0; attempt <= maxRetry; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		if err = job.Handle(ctx, mqMsg); err == nil {
+			return
+		}
+	}
+
+	consumers.Lock()
+	handlers := consumers.failHandlers
+	consumers.Unlock()
+
+	for _, h := range handlers {
+		h(ctx, job.GetTopic(), mqMsg, err, maxRetry > 0)
+	}
+	return
+}
@@ -40,12 +40,13 @@ func (q *DiskConsumerMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg M
 	}
 
 	var (
-		queue = NewDiskQueue(topic, q.config)
-		sleep = time.Second
+		queue   = NewDiskQueue(topic, q.config)
+		sleep   = time.Second
+		tracker = newAckTracker()
 	)
 
 	go func() {
-		for {
+		for !isClosing() {
 			if index, offset, data, err := queue.Read(); err == nil {
 				var mqMsg MqMsg
 				if err = json.Unmarshal(data, &mqMsg); err != nil {
@@ -53,8 +54,10 @@ func (q *DiskConsumerMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg M
 					continue
 				}
 				if mqMsg.MsgId != "" {
-					receiveDo(mqMsg)
-					queue.Commit(index, offset)
+					// 消息处理完成后按读取顺序提交读取位置
+					receiveDo(mqMsg.OnDone(tracker.track(func() {
+						queue.Commit(index, offset)
+					})))
 					sleep = time.Millisecond * 10
 				}
 			} else {
@@ -82,6 +85,11 @@ func (d *DiskProducerMq) SendMsg(topic string, body string) (mqMsg MqMsg, err er
 
 // SendByteMsg 生产数据
 func (d *DiskProducerMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, err error) {
+	return d.SendKeyMsg(topic, "", body)
+}
+
+// SendKeyMsg 生产携带幂等键的数据
+func (d *DiskProducerMq) SendKeyMsg(topic string, key string, body []byte) (mqMsg MqMsg, err error) {
 	if topic == "" {
 		return mqMsg, gerror.New("DiskMq topic is empty")
 	}
@@ -90,6 +98,7 @@ func (d *DiskProducerMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, er
 		RunType:   SendMsg,
 		Topic:     topic,
 		MsgId:     getRandMsgId(),
+		Key:       key,
 		Body:      body,
 		Timestamp: time.Now(),
 	}
@@ -125,7 +134,7 @@ func (d *DiskProducerMq) getProducer(topic string) *disk.Queue {
 
 func NewDiskQueue(topic string, config *disk.Config) *disk.Queue {
 	conf := &disk.Config{
-		Path:         fmt.Sprintf(config.Path + "/" + config.GroupName + "/" + topic),
+		Path:         fmt.Sprintf("%s/%s/%s", config.Path, config.GroupName, topic),
 		BatchSize:    config.BatchSize,
 		BatchTime:    config.BatchTime * time.Second,
 		SegmentSize:  config.SegmentSize,
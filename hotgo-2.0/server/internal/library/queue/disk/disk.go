@@ -88,6 +88,10 @@ func (q *Queue) Commit(index int64, offset int64) {
 		return
 	}
 
+	// 消息可能在工作协程中处理完成后提交，需要与读取互斥
+	q.Lock()
+	defer q.Unlock()
+
 	ck := &q.reader.checkpoint
 	ck.Index, ck.Offset = index, offset
 	q.reader.sync()
@@ -0,0 +1,137 @@
+// Package queue
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package queue
+
+import (
+	"context"
+	"hash/crc32"
+	"sync"
+	"time"
+)
+
+// ConcurrentConsumer 支持并发消费的消费者
+type ConcurrentConsumer interface {
+	Consumer
+	Concurrency() int // 并发处理消息的协程数量，小于等于1时按驱动投递顺序同步处理
+}
+
+// OrderedConsumer 支持顺序消费的消费者
+// 顺序键相同的消息由同一个协程按到达顺序依次处理，不同顺序键的消息并行处理
+type OrderedConsumer interface {
+	ConcurrentConsumer
+	OrderingKey(mqMsg MqMsg) string // 获取消息的顺序键，为空表示该消息不要求顺序
+}
+
+// DefaultDrainTimeout 服务关闭时等待处理中消息完成的默认时长
+const DefaultDrainTimeout = 10 * time.Second
+
+var (
+	closing   bool           // 是否正在关闭，关闭后驱动停止拉取新消息
+	closingMu sync.RWMutex   // 保护closing，保证关闭后不会再登记新的消息
+	inflight  sync.WaitGroup // 已接收但尚未处理完成的消息
+)
+
+// isClosing 消费者是否正在关闭
+func isClosing() bool {
+	closingMu.RLock()
+	defer closingMu.RUnlock()
+	return closing
+}
+
+// acceptMsg 登记一条待处理的消息，消费者正在关闭时返回false
+// 与StopConsumersListener使用同一把锁，开始等待处理中的消息后不会再有新的消息被登记
+func acceptMsg() bool {
+	closingMu.RLock()
+	defer closingMu.RUnlock()
+	if closing {
+		return false
+	}
+	inflight.Add(1)
+	return true
+}
+
+// StopConsumersListener 停止所有消费者监听
+// 驱动将停止拉取新消息，并在超时时间内等待已接收的消息处理完成，之后驱动在服务关闭事件中提交消费位点并断开连接
+func StopConsumersListener(ctx context.Context) {
+	closingMu.Lock()
+	if closing {
+		closingMu.Unlock()
+		return
+	}
+	closing = true
+	closingMu.Unlock()
+
+	timeout := DefaultDrainTimeout
+	if config.DrainTimeout > 0 {
+		timeout = time.Duration(config.DrainTimeout) * time.Second
+	}
+
+	done := make(chan struct{})
+	go func() {
+		inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		Logger().Debug(ctx, "queue consumers drained..")
+	case <-time.After(timeout):
+		Logger().Warningf(ctx, "queue consumers drain timeout after %v, in-flight messages may be redelivered", timeout)
+	}
+}
+
+// dispatcher 消息分发器，按消费者声明的并发数和顺序键将消息分发到工作协程
+type dispatcher struct {
+	ordered OrderedConsumer
+	shared  chan MqMsg   // 无顺序要求的消息，由空闲的工作协程处理
+	workers []chan MqMsg // 有顺序要求的消息，按顺序键固定分配到工作协程
+	handle  func(mqMsg MqMsg)
+}
+
+// newDispatcher 创建消息分发器，消费者未声明并发数时返回nil，由接收协程同步处理
+func newDispatcher(ctx context.Context, job Consumer, handle func(mqMsg MqMsg)) *dispatcher {
+	cc, ok := job.(ConcurrentConsumer)
+	if !ok || cc.Concurrency() <= 1 {
+		return nil
+	}
+
+	d := &dispatcher{
+		shared:  make(chan MqMsg),
+		workers: make([]chan MqMsg, cc.Concurrency()),
+		handle:  handle,
+	}
+	d.ordered, _ = job.(OrderedConsumer)
+
+	for i := range d.workers {
+		d.workers[i] = make(chan MqMsg)
+		go d.work(d.workers[i])
+	}
+	Logger().Debugf(ctx, "queue topic:%v start %v workers.", job.GetTopic(), len(d.workers))
+	return d
+}
+
+// dispatch 分发消息，所有工作协程繁忙时阻塞，从而对驱动形成背压
+func (d *dispatcher) dispatch(mqMsg MqMsg) {
+	if d.ordered != nil {
+		if key := d.ordered.OrderingKey(mqMsg); key != "" {
+			d.workers[crc32.ChecksumIEEE([]byte(key))%uint32(len(d.workers))] <- mqMsg
+			return
+		}
+	}
+	d.shared <- mqMsg
+}
+
+// work 工作协程
+func (d *dispatcher) work(own chan MqMsg) {
+	for {
+		select {
+		case mqMsg := <-own:
+			d.handle(mqMsg)
+		case mqMsg := <-d.shared:
+			d.handle(mqMsg)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Package queue
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package queue
+
+import (
+	"context"
+	"fmt"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+const (
+	// IdempotentTTL 幂等键消费完成记录的保留时长(秒)，超出该时长后重复投递的消息将被再次消费
+	IdempotentTTL = 86400
+	// IdempotentProcessingTTL 幂等键消费中标记的保留时长(秒)，消费进程崩溃后超出该时长即可重新消费
+	IdempotentProcessingTTL = 300
+)
+
+// acquireScript 已有消费完成记录时返回0，否则尝试写入消费中标记
+var acquireScript = `
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+if redis.call("SET", KEYS[2], ARGV[1], "NX", "EX", ARGV[2]) then
+	return 1
+end
+return 0
+`
+
+// idempotentKey 幂等键消费完成记录key
+func idempotentKey(topic, key string) string {
+	return fmt.Sprintf("queue:idempotent:%s:%s:%s", config.GroupName, topic, key)
+}
+
+// idempotentProcessingKey 幂等键消费中标记key
+func idempotentProcessingKey(topic, key string) string {
+	return fmt.Sprintf("queue:idempotent:processing:%s:%s:%s", config.GroupName, topic, key)
+}
+
+// acquireIdempotent 占用幂等键，返回false表示该消息已被消费或正在被其他节点消费
+// 占用时只写入短时效的消费中标记，消费成功后才写入消费完成记录，未携带幂等键的消息始终返回true
+func acquireIdempotent(ctx context.Context, mqMsg MqMsg) bool {
+	if mqMsg.Key == "" {
+		return true
+	}
+
+	keys := []string{idempotentKey(mqMsg.Topic, mqMsg.Key), idempotentProcessingKey(mqMsg.Topic, mqMsg.Key)}
+	v, err := g.Redis().GroupScript().Eval(ctx, acquireScript, 2, keys, []interface{}{mqMsg.MsgId, IdempotentProcessingTTL})
+	if err != nil {
+		// redis不可用时宁可重复消费，也不丢弃消息
+		Logger().Warningf(ctx, "queue acquireIdempotent topic:%v key:%v err:%+v", mqMsg.Topic, mqMsg.Key, err)
+		return true
+	}
+	return v.Int() == 1
+}
+
+// finishIdempotent 消费结束后处理幂等键
+// 消费成功时写入消费完成记录，消费失败时只清除消费中标记，以便消息重放或重新投递后可以再次消费
+func finishIdempotent(ctx context.Context, mqMsg MqMsg, err error) {
+	if mqMsg.Key == "" {
+		return
+	}
+
+	if err == nil {
+		if _, doErr := g.Redis().Do(ctx, "SET", idempotentKey(mqMsg.Topic, mqMsg.Key), mqMsg.MsgId, "EX", IdempotentTTL); doErr != nil {
+			Logger().Warningf(ctx, "queue finishIdempotent topic:%v key:%v err:%+v", mqMsg.Topic, mqMsg.Key, doErr)
+		}
+	}
+
+	if _, doErr := g.Redis().Do(ctx, "DEL", idempotentProcessingKey(mqMsg.Topic, mqMsg.Key)); doErr != nil {
+		Logger().Warningf(ctx, "queue finishIdempotent topic:%v key:%v err:%+v", mqMsg.Topic, mqMsg.Key, doErr)
+	}
+}
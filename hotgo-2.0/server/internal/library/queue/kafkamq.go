@@ -7,7 +7,6 @@ package queue
 
 import (
 	"context"
-	"fmt"
 	"github.com/IBM/sarama"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
@@ -40,12 +39,21 @@ func (r *KafkaMq) SendMsg(topic string, body string) (mqMsg MqMsg, err error) {
 
 // SendByteMsg 生产数据
 func (r *KafkaMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, err error) {
+	return r.SendKeyMsg(topic, "", body)
+}
+
+// SendKeyMsg 生产携带幂等键的数据
+func (r *KafkaMq) SendKeyMsg(topic string, key string, body []byte) (mqMsg MqMsg, err error) {
 	msg := &sarama.ProducerMessage{
 		Topic:     topic,
 		Value:     sarama.ByteEncoder(body),
 		Timestamp: time.Now(),
 	}
 
+	if key != "" {
+		msg.Key = sarama.StringEncoder(key)
+	}
+
 	if r.producerIns == nil {
 		err = gerror.New("queue kafka producerIns is nil")
 		return
@@ -60,6 +68,7 @@ func (r *KafkaMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, err error
 		return MqMsg{
 			RunType:   SendMsg,
 			Topic:     info.Topic,
+			Key:       key,
 			Offset:    info.Offset,
 			Partition: info.Partition,
 			Timestamp: info.Timestamp,
@@ -98,7 +107,13 @@ func (r *KafkaMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg))
 			}
 
 			if consumerCtx.Err() != nil {
-				Logger().Debugf(ctx, fmt.Sprintf("kafka consoumer stop : %v", consumerCtx.Err()))
+				Logger().Debugf(ctx, "kafka consoumer stop : %v", consumerCtx.Err())
+				return
+			}
+
+			// 服务关闭中，等待关闭事件提交位点并断开连接
+			if isClosing() {
+				<-consumerCtx.Done()
 				return
 			}
 			consumer.ready = make(chan bool)
@@ -243,16 +258,26 @@ func (consumer *KaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, cl
 	// The `ConsumeClaim` itself is called within a goroutine, see:
 	// https://github.com/Shopify/sarama/blob/master/consumer_group.go#L27-L29
 	// `ConsumeClaim` 方法已经是 goroutine 调用 不要在该方法内进行 goroutine
+	// 消息处理完成后按分区内的顺序标记位点，未标记的消息会在下次启动或重新平衡后重新投递
+	tracker := newAckTracker()
 	for message := range claim.Messages() {
+		// 服务关闭中不再处理新消息
+		if isClosing() {
+			return nil
+		}
+
+		message := message
 		consumer.receiveDoFun(MqMsg{
 			RunType:   ReceiveMsg,
 			Topic:     message.Topic,
+			Key:       string(message.Key),
 			Body:      message.Value,
 			Offset:    message.Offset,
 			Timestamp: message.Timestamp,
 			Partition: message.Partition,
-		})
-		session.MarkMessage(message, "")
+		}.OnDone(tracker.track(func() {
+			session.MarkMessage(message, "")
+		})))
 	}
 	return nil
 }
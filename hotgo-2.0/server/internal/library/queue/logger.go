@@ -23,13 +23,18 @@ func Logger() *glog.Logger {
 // ConsumerLog 消费日志
 func ConsumerLog(ctx context.Context, topic string, mqMsg MqMsg, err error) {
 	if err != nil {
+		incrStat(ctx, topic, StatFailed)
 		Logger().Errorf(ctx, ConsumerLogErrFormat, topic, string(mqMsg.Body), err)
+		return
 	}
+	incrStat(ctx, topic, StatConsumed)
 }
 
 // ProducerLog 生产日志
 func ProducerLog(ctx context.Context, topic string, mqMsg MqMsg, err error) {
 	if err != nil {
 		Logger().Infof(ctx, ProducerLogErrFormat, topic, string(mqMsg.Body), err)
+		return
 	}
+	incrStat(ctx, topic, StatProduced)
 }
@@ -34,3 +34,21 @@ func DelayPush(topic string, data interface{}, delay int64) (err error) {
 	ProducerLog(ctx, topic, mqMsg, err)
 	return
 }
+
+// PushWithKey 推送携带幂等键的队列，消费端会对相同幂等键的消息去重
+// 驱动不支持幂等键时退化为普通推送
+func PushWithKey(topic string, key string, data interface{}) (err error) {
+	q, err := InstanceProducer()
+	if err != nil {
+		return
+	}
+
+	var mqMsg MqMsg
+	if kq, ok := q.(MqKeyProducer); ok {
+		mqMsg, err = kq.SendKeyMsg(topic, key, []byte(gconv.String(data)))
+	} else {
+		mqMsg, err = q.SendMsg(topic, gconv.String(data))
+	}
+	ProducerLog(ctx, topic, mqMsg, err)
+	return
+}
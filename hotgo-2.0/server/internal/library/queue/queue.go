@@ -21,6 +21,11 @@ type MqProducer interface {
 	SendDelayMsg(topic string, body string, delay int64) (mqMsg MqMsg, err error)
 }
 
+// MqKeyProducer 支持携带幂等键的生产者，消费端可根据幂等键对重复投递的消息去重
+type MqKeyProducer interface {
+	SendKeyMsg(topic string, key string, body []byte) (mqMsg MqMsg, err error)
+}
+
 type MqConsumer interface {
 	ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg)) (err error)
 }
@@ -32,13 +37,14 @@ const (
 )
 
 type Config struct {
-	Switch    bool   `json:"switch"`
-	Driver    string `json:"driver"`
-	GroupName string `json:"groupName"`
-	Redis     RedisConf
-	Rocketmq  RocketmqConf
-	Kafka     KafkaConf
-	Disk      *disk.Config
+	Switch       bool   `json:"switch"`
+	Driver       string `json:"driver"`
+	GroupName    string `json:"groupName"`
+	DrainTimeout int64  `json:"drainTimeout"`
+	Redis        RedisConf
+	Rocketmq     RocketmqConf
+	Kafka        KafkaConf
+	Disk         *disk.Config
 }
 
 type RedisConf struct {
@@ -65,10 +71,12 @@ type MqMsg struct {
 	RunType   int       `json:"run_type"`
 	Topic     string    `json:"topic"`
 	MsgId     string    `json:"msg_id"`
+	Key       string    `json:"key"` // 幂等键，同一业务消息重复投递时保持不变
 	Offset    int64     `json:"offset"`
 	Partition int32     `json:"partition"`
 	Timestamp time.Time `json:"timestamp"`
 	Body      []byte    `json:"body"`
+	done      DoneFunc  // 消息处理结束回调，由驱动设置
 }
 
 var (
@@ -87,6 +95,11 @@ func init() {
 	}
 }
 
+// GetConfig 获取队列配置
+func GetConfig() Config {
+	return config
+}
+
 // InstanceConsumer 实例化消费者
 func InstanceConsumer() (mqClient MqConsumer, err error) {
 	return NewConsumer(config.GroupName)
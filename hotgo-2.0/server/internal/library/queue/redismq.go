@@ -35,6 +35,11 @@ func (r *RedisMq) SendMsg(topic string, body string) (mqMsg MqMsg, err error) {
 
 // SendByteMsg 生产数据
 func (r *RedisMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, err error) {
+	return r.SendKeyMsg(topic, "", body)
+}
+
+// SendKeyMsg 生产携带幂等键的数据
+func (r *RedisMq) SendKeyMsg(topic string, key string, body []byte) (mqMsg MqMsg, err error) {
 	if r.poolName == "" {
 		return mqMsg, gerror.New("RedisMq producer not register")
 	}
@@ -47,6 +52,7 @@ func (r *RedisMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, err error
 		RunType:   SendMsg,
 		Topic:     topic,
 		MsgId:     getRandMsgId(),
+		Key:       key,
 		Body:      body,
 		Timestamp: time.Now(),
 	}
@@ -56,13 +62,13 @@ func (r *RedisMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, err error
 		return
 	}
 
-	key := r.genKey(r.groupName, topic)
-	if _, err = g.Redis().Do(ctx, "LPUSH", key, data); err != nil {
+	queueKey := r.genKey(r.groupName, topic)
+	if _, err = g.Redis().Do(ctx, "LPUSH", queueKey, data); err != nil {
 		return
 	}
 
 	if r.timeout > 0 {
-		if _, err = g.Redis().Do(ctx, "EXPIRE", key, r.timeout); err != nil {
+		if _, err = g.Redis().Do(ctx, "EXPIRE", queueKey, r.timeout); err != nil {
 			return
 		}
 	}
@@ -137,6 +143,9 @@ func (r *RedisMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg))
 
 	go func() {
 		for range time.Tick(300 * time.Millisecond) {
+			if isClosing() {
+				return
+			}
 			mqMsgList := r.loopReadQueue(key)
 			for _, mqMsg := range mqMsgList {
 				receiveDo(mqMsg)
@@ -159,6 +168,15 @@ func (r *RedisMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg))
 	select {}
 }
 
+// Lag 消费堆积量，即队列中尚未被取出的消息数量，不含延迟消息
+func (r *RedisMq) Lag(topic string) (lag int64, err error) {
+	v, err := g.Redis().Do(ctx, "LLEN", r.genKey(r.groupName, topic))
+	if err != nil {
+		return
+	}
+	return v.Int64(), nil
+}
+
 // 生成队列key
 func (r *RedisMq) genKey(groupName string, topic string) string {
 	return fmt.Sprintf("queue:%s_%s", groupName, topic)
@@ -166,7 +184,7 @@ func (r *RedisMq) genKey(groupName string, topic string) string {
 
 func (r *RedisMq) loopReadQueue(key string) (mqMsgList []MqMsg) {
 	conn := g.Redis()
-	for {
+	for !isClosing() {
 		data, err := conn.Do(ctx, "RPOP", key)
 		if err != nil {
 			Logger().Warningf(ctx, "loopReadQueue redis RPOP err:%+v", err)
@@ -224,7 +242,7 @@ func (r *RedisMq) loopReadDelayQueue(key string) (resCh chan MqMsg, errCh chan e
 		defer close(errCh)
 
 		conn := g.Redis()
-		for {
+		for !isClosing() {
 			now := time.Now().Unix()
 			do, err := conn.Do(ctx, "zrangebyscore", key, "0", strconv.FormatInt(now, 10), "limit", 0, 1)
 			if err != nil {
@@ -11,7 +11,7 @@ import (
 	"hotgo/utility/simple"
 	"hotgo/utility/validate"
 	"sync"
-	"time"
+	"sync/atomic"
 
 	"github.com/apache/rocketmq-client-go/v2"
 	"github.com/apache/rocketmq-client-go/v2/admin"
@@ -21,7 +21,6 @@ import (
 	"github.com/apache/rocketmq-client-go/v2/rlog"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
-	"github.com/gogf/gf/v2/os/grpool"
 )
 
 type RocketMq struct {
@@ -34,7 +33,6 @@ type RocketManager struct {
 	Consumer *RocketMq
 	pMutex   sync.Mutex
 	cMutex   sync.Mutex
-	goPool   *grpool.Pool
 }
 
 var rocketManager = &RocketManager{}
@@ -66,11 +64,6 @@ func setRocketCloseEvent() {
 			}
 			Logger().Debug(ctx, "rocketmq consumer close...")
 		}
-
-		for rocketManager.goPool != nil && rocketManager.goPool.Size() != 0 {
-			g.Log().Debugf(ctx, "waiting for eocketmq consumer to complete execution[%v][%v]...", rocketManager.goPool.Size(), rocketManager.goPool.Jobs())
-			time.Sleep(time.Second)
-		}
 	})
 }
 
@@ -127,14 +120,21 @@ func (r *RocketMq) SendMsg(topic string, body string) (mqMsg MqMsg, err error) {
 
 // SendByteMsg 生产数据
 func (r *RocketMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, err error) {
+	return r.SendKeyMsg(topic, "", body)
+}
+
+// SendKeyMsg 生产携带幂等键的数据
+func (r *RocketMq) SendKeyMsg(topic string, key string, body []byte) (mqMsg MqMsg, err error) {
 	if r.producerIns == nil {
 		return mqMsg, gerror.New("rocketMq producer not register")
 	}
 
-	result, err := r.producerIns.SendSync(ctx, &primitive.Message{
-		Topic: topic,
-		Body:  body,
-	})
+	msg := primitive.NewMessage(topic, body)
+	if key != "" {
+		msg.WithKeys([]string{key})
+	}
+
+	result, err := r.producerIns.SendSync(ctx, msg)
 
 	if err != nil {
 		return
@@ -147,6 +147,7 @@ func (r *RocketMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, err erro
 		RunType: SendMsg,
 		Topic:   topic,
 		MsgId:   result.MsgID,
+		Key:     key,
 		Body:    body,
 	}
 	return mqMsg, nil
@@ -191,15 +192,38 @@ func (r *RocketMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg))
 	}
 
 	err = r.consumerIns.Subscribe(topic, consumer.MessageSelector{}, func(ctx context.Context, msgs ...*primitive.MessageExt) (consumer.ConsumeResult, error) {
+		// 服务关闭中不再处理新消息，交由broker稍后重新投递
+		if isClosing() {
+			return consumer.ConsumeRetryLater, nil
+		}
+
+		// 按接收顺序同步交给消费调度，由调度按排序键保证顺序并提供并发
+		// 等待本批消息全部处理结束后再确认，有未处理的消息时整批交由broker稍后重新投递
+		var (
+			wg        sync.WaitGroup
+			unhandled atomic.Bool
+		)
 		for _, item := range msgs {
-			_ = rocketManager.goPool.Add(ctx, func(ctx context.Context) {
-				receiveDo(MqMsg{
-					RunType: ReceiveMsg,
-					Topic:   item.Topic,
-					MsgId:   item.MsgId,
-					Body:    item.Body,
-				})
-			})
+			wg.Add(1)
+			done := func(handled bool) {
+				if !handled {
+					unhandled.Store(true)
+				}
+				wg.Done()
+			}
+
+			receiveDo(MqMsg{
+				RunType: ReceiveMsg,
+				Topic:   item.Topic,
+				MsgId:   item.MsgId,
+				Key:     item.GetKeys(),
+				Body:    item.Body,
+			}.OnDone(done))
+		}
+		wg.Wait()
+
+		if unhandled.Load() {
+			return consumer.ConsumeRetryLater, nil
 		}
 		return consumer.ConsumeSuccess, nil
 	})
@@ -296,9 +320,6 @@ func RegisterRocketMqConsumer() (mqIns *RocketMq, err error) {
 		return nil, err
 	}
 
-	// 开多携程处理消费任务，可以根据业务实际情况调整该配置
-	rocketManager.goPool = grpool.New(5)
-
 	SetRLogLevel()
 	rocketManager.Consumer = mqIns
 	return rocketManager.Consumer, nil
@@ -0,0 +1,120 @@
+// Package queue
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package queue
+
+import (
+	"context"
+	"fmt"
+	"github.com/gogf/gf/v2/frame/g"
+	"sort"
+)
+
+// 主题统计字段
+const (
+	StatProduced = "produced" // 生产数量
+	StatConsumed = "consumed" // 消费成功数量
+	StatFailed   = "failed"   // 消费失败数量
+)
+
+// MqLagger 支持查询消费堆积量的驱动
+type MqLagger interface {
+	Lag(topic string) (lag int64, err error)
+}
+
+// TopicStats 主题统计
+type TopicStats struct {
+	Topic        string `json:"topic"        dc:"主题"`
+	Registered   bool   `json:"registered"   dc:"是否已注册消费者"`
+	Produced     int64  `json:"produced"     dc:"生产数量"`
+	Consumed     int64  `json:"consumed"     dc:"消费成功数量"`
+	Failed       int64  `json:"failed"       dc:"消费失败数量"`
+	Lag          int64  `json:"lag"          dc:"消费堆积量"`
+	LagSupported bool   `json:"lagSupported" dc:"驱动是否支持堆积量查询"`
+}
+
+// statsKey 主题统计key，所有节点共用
+func statsKey(topic string) string {
+	return fmt.Sprintf("queue:stats:%s:%s", config.GroupName, topic)
+}
+
+// statsTopicsKey 出现过的主题集合
+func statsTopicsKey() string {
+	return fmt.Sprintf("queue:stats:%s:topics", config.GroupName)
+}
+
+// incrStat 累加主题统计
+func incrStat(ctx context.Context, topic, field string) {
+	if topic == "" {
+		return
+	}
+	if _, err := g.Redis().Do(ctx, "HINCRBY", statsKey(topic), field, 1); err != nil {
+		Logger().Debugf(ctx, "queue incrStat topic:%v field:%v err:%+v", topic, field, err)
+		return
+	}
+	_, _ = g.Redis().Do(ctx, "SADD", statsTopicsKey(), topic)
+}
+
+// GetTopicStats 获取所有主题的统计信息
+func GetTopicStats(ctx context.Context) (list []*TopicStats, err error) {
+	topics := make(map[string]bool)
+	for _, topic := range GetTopics() {
+		topics[topic] = true
+	}
+
+	members, err := g.Redis().Do(ctx, "SMEMBERS", statsTopicsKey())
+	if err != nil {
+		return
+	}
+	for _, topic := range members.Strings() {
+		if _, ok := topics[topic]; !ok {
+			topics[topic] = false
+		}
+	}
+
+	lagger, _ := lagDriver()
+	for topic, registered := range topics {
+		stats := &TopicStats{Topic: topic, Registered: registered}
+		values, err := g.Redis().Do(ctx, "HGETALL", statsKey(topic))
+		if err != nil {
+			return nil, err
+		}
+
+		m := values.MapStrVar()
+		stats.Produced = m[StatProduced].Int64()
+		stats.Consumed = m[StatConsumed].Int64()
+		stats.Failed = m[StatFailed].Int64()
+
+		if lagger != nil {
+			if stats.Lag, err = lagger.Lag(topic); err != nil {
+				Logger().Debugf(ctx, "queue lag topic:%v err:%+v", topic, err)
+			} else {
+				stats.LagSupported = true
+			}
+		}
+		list = append(list, stats)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Topic < list[j].Topic
+	})
+	return
+}
+
+// ResetTopicStats 重置主题统计
+func ResetTopicStats(ctx context.Context, topic string) (err error) {
+	_, err = g.Redis().Do(ctx, "DEL", statsKey(topic))
+	return
+}
+
+// lagDriver 获取当前驱动的堆积量查询实现
+func lagDriver() (lagger MqLagger, ok bool) {
+	producer, err := InstanceProducer()
+	if err != nil {
+		return
+	}
+	lagger, ok = producer.(MqLagger)
+	return
+}
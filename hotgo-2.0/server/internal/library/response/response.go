@@ -113,3 +113,13 @@ func RText(r *ghttp.Request, message string) {
 	// 写入响应
 	r.Response.Write(message)
 }
+
+// RHtml 返回HTML页面
+func RHtml(r *ghttp.Request, content string) {
+	// 清空响应
+	r.Response.ClearBuffer()
+
+	// 写入响应
+	r.Response.Header().Set("Content-Type", "text/html; charset=utf-8")
+	r.Response.Write(content)
+}
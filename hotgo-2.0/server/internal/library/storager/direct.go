@@ -0,0 +1,278 @@
+// Package storager
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package storager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/guid"
+	"hotgo/internal/consts"
+	"hotgo/internal/library/cache"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/model/entity"
+	"strings"
+	"time"
+)
+
+const (
+	directUploadExpire = time.Hour              // 直传签名有效期
+	directUploadTTL    = 24 * time.Hour         // 直传事件保留时长，需要覆盖大文件的上传时间
+	directMaxSize      = 5 * 1024 * 1024 * 1024 // 单次直传的最大文件大小，超出时使用分片上传
+)
+
+// directUpload 直传事件
+type directUpload struct {
+	UploadId string    `json:"uploadId"` // 直传事件ID
+	Drive    string    `json:"drive"`    // 上传驱动
+	FullPath string    `json:"fullPath"` // 存储路径
+	Meta     *FileMeta `json:"meta"`     // 文件元数据
+	MemberId int64     `json:"memberId"` // 上传用户
+	AppId    string    `json:"appId"`    // 上传应用
+}
+
+// directPending 待完成的直传文件，超过保留时长仍未完成时由回收任务删除
+type directPending struct {
+	Drive    string `json:"drive"`    // 上传驱动
+	FullPath string `json:"fullPath"` // 存储路径
+}
+
+// basePath 获取存储驱动配置的存储路径
+func basePath(drive string) string {
+	switch drive {
+	case consts.UploadDriveLocal:
+		return config.LocalPath
+	case consts.UploadDriveUCloud:
+		return config.UCloudPath
+	case consts.UploadDriveCos:
+		return config.CosPath
+	case consts.UploadDriveOss:
+		return config.OssPath
+	case consts.UploadDriveQiNiu:
+		return config.QiNiuPath
+	case consts.UploadDriveMinio:
+		return config.MinioPath
+	default:
+		return ""
+	}
+}
+
+// PresignUpload 申请客户端直传，校验文件类型和大小后生成当前驱动的直传签名
+func PresignUpload(ctx context.Context, in *PresignUploadParams) (res *PresignUploadModel, err error) {
+	res = new(PresignUploadModel)
+
+	meta := NewFileMeta(in.FileName, in.Size, in.Md5)
+	if err = ValidateFileMeta(in.UploadType, meta); err != nil {
+		return
+	}
+
+	if res.Attachment, err = reusableFile(ctx, in.Md5); err != nil || res.Attachment != nil {
+		return
+	}
+
+	uploadId := guid.S()
+	if err = ReserveQuota(ctx, uploadId, meta.Size, directUploadTTL); err != nil {
+		return
+	}
+
+	// 未能发起直传时释放预占，客户端改用其他方式上传时会重新预占
+	defer func() {
+		if !res.Direct {
+			ReleaseQuota(ctx, uploadId)
+		}
+	}()
+
+	if meta.Size > directMaxSize {
+		return
+	}
+
+	path := basePath(config.Drive)
+	if path == "" {
+		err = gerror.Newf("存储驱动[%v]必须配置存储路径!", config.Drive)
+		return
+	}
+
+	fullPath := GenFullPath(path, gfile.Ext(meta.Filename))
+	upload, err := New(config.Drive).PresignUpload(ctx, fullPath, meta, directUploadExpire)
+	if err != nil || upload == nil {
+		return
+	}
+
+	item := &directUpload{
+		UploadId: uploadId,
+		Drive:    config.Drive,
+		FullPath: fullPath,
+		Meta:     meta,
+		MemberId: contexts.GetUserId(ctx),
+		AppId:    contexts.GetModule(ctx),
+	}
+	if err = cache.Instance().Set(ctx, directUploadKey(item.UploadId), item, directUploadTTL); err != nil {
+		return
+	}
+
+	if err = addDirectPending(ctx, item); err != nil {
+		return
+	}
+
+	res.Direct = true
+	res.UploadId = item.UploadId
+	res.Upload = upload
+	res.ExpireAt = gtime.Now().Add(directUploadExpire).Unix()
+	return
+}
+
+// CompleteUpload 客户端直传完成，检查存储中的文件后写入附件记录
+func CompleteUpload(ctx context.Context, in *CompleteUploadParams) (res *entity.SysAttachment, err error) {
+	key := directUploadKey(in.UploadId)
+	v, err := cache.Instance().Get(ctx, key)
+	if err != nil {
+		return
+	}
+
+	var item *directUpload
+	if err = v.Scan(&item); err != nil {
+		return
+	}
+
+	if item == nil || item.MemberId != contexts.GetUserId(ctx) || item.AppId != contexts.GetModule(ctx) {
+		err = gerror.New("直传事件不存在或已过期，请重新上传")
+		return
+	}
+
+	if item.Drive != config.Drive {
+		_, _ = cache.Instance().Remove(ctx, key)
+		ReleaseQuota(ctx, item.UploadId)
+		err = gerror.New("存储驱动已变更，请重新上传")
+		return
+	}
+
+	drive := New(item.Drive)
+	stat, err := drive.Stat(ctx, item.FullPath)
+	if err != nil {
+		err = gerror.Wrap(err, "没有找到上传的文件，请确认上传成功后重试")
+		return
+	}
+
+	// 上传的文件与申请时不一致，删除文件防止绕过大小限制
+	if stat.Size != item.Meta.Size {
+		discardDirectUpload(ctx, key, item)
+		err = gerror.Newf("上传的文件大小与申请时不一致，申请:%v，实际:%v", item.Meta.Size, stat.Size)
+		return
+	}
+
+	// 存储平台提供了文件内容md5时校验客户端提交的md5，否则客户端提交的md5不可信，不记录到附件中，避免被其他上传复用
+	meta := *item.Meta
+	if stat.Md5 != "" && !strings.EqualFold(stat.Md5, item.Meta.Md5) {
+		discardDirectUpload(ctx, key, item)
+		err = gerror.New("上传的文件与申请时的md5不一致，请重新上传")
+		return
+	}
+
+	if stat.Md5 == "" {
+		g.Log().Infof(ctx, "direct upload md5 is unverifiable, path:%v, drive:%v", item.FullPath, item.Drive)
+		meta.Md5 = ""
+	}
+
+	if _, err = cache.Instance().Remove(ctx, key); err != nil {
+		return
+	}
+	removeDirectPending(ctx, item)
+	defer ReleaseQuota(ctx, item.UploadId)
+	return write(ctx, &meta, item.FullPath)
+}
+
+// discardDirectUpload 删除与申请时不一致的直传文件
+func discardDirectUpload(ctx context.Context, key string, item *directUpload) {
+	_, _ = cache.Instance().Remove(ctx, key)
+	ReleaseQuota(ctx, item.UploadId)
+	if err := New(item.Drive).Delete(ctx, item.FullPath); err != nil {
+		g.Log().Warningf(ctx, "delete mismatched direct upload failed, path:%v, err:%+v", item.FullPath, err)
+		return
+	}
+	removeDirectPending(ctx, item)
+}
+
+// addDirectPending 记录待完成的直传文件，按直传事件的过期时间排序
+func addDirectPending(ctx context.Context, item *directUpload) error {
+	member, err := json.Marshal(&directPending{Drive: item.Drive, FullPath: item.FullPath})
+	if err != nil {
+		return err
+	}
+
+	_, err = g.Redis().Do(ctx, "ZADD", directPendingKey(), time.Now().Add(directUploadTTL).Unix(), string(member))
+	return err
+}
+
+// removeDirectPending 直传完成后移除待完成记录
+func removeDirectPending(ctx context.Context, item *directUpload) {
+	member, err := json.Marshal(&directPending{Drive: item.Drive, FullPath: item.FullPath})
+	if err != nil {
+		return
+	}
+
+	if _, err = g.Redis().Do(ctx, "ZREM", directPendingKey(), string(member)); err != nil {
+		g.Log().Warningf(ctx, "remove direct upload pending failed, path:%v, err:%+v", item.FullPath, err)
+	}
+}
+
+// CleanDirectUploads 删除申请直传后超过保留时长仍未完成的文件，返回删除数量
+func CleanDirectUploads(ctx context.Context, limit int) (count int, err error) {
+	v, err := g.Redis().Do(ctx, "ZRANGEBYSCORE", directPendingKey(), "-inf", time.Now().Unix(), "LIMIT", 0, limit)
+	if err != nil {
+		return
+	}
+
+	for _, member := range v.Strings() {
+		// 多个节点同时清理时只由移除成功的节点删除文件
+		removed, err := g.Redis().Do(ctx, "ZREM", directPendingKey(), member)
+		if err != nil {
+			return count, err
+		}
+
+		if removed.Int() == 0 {
+			continue
+		}
+
+		var item *directPending
+		if err = json.Unmarshal([]byte(member), &item); err != nil || item == nil {
+			continue
+		}
+
+		if err = New(item.Drive).Delete(ctx, item.FullPath); err != nil {
+			g.Log().Warningf(ctx, "delete expired direct upload failed, path:%v, err:%+v", item.FullPath, err)
+			continue
+		}
+		count++
+	}
+	return
+}
+
+// directHeaders 直传时需要携带的请求头
+func directHeaders(meta *FileMeta) map[string]string {
+	return map[string]string{"Content-Type": contentType(meta)}
+}
+
+// contentType 获取文件的Content-Type
+func contentType(meta *FileMeta) string {
+	if meta.MimeType == "" {
+		return "application/octet-stream"
+	}
+	return meta.MimeType
+}
+
+// directUploadKey 直传事件缓存key
+func directUploadKey(uploadId string) string {
+	return fmt.Sprintf("%v:%v", consts.CacheDirectUpload, uploadId)
+}
+
+// directPendingKey 待完成的直传文件key
+func directPendingKey() string {
+	return fmt.Sprintf("%v:pending", consts.CacheDirectUpload)
+}
@@ -0,0 +1,29 @@
+package storager
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHeaderMd5(t *testing.T) {
+	header := http.Header{}
+	header.Set("ETag", `"9E107D9D372BB6826BD81D3542A419D6"`)
+	if v := headerMd5(header); v != "9e107d9d372bb6826bd81d3542a419d6" {
+		t.Fatalf("md5 from etag: %v", v)
+	}
+
+	// 分片上传生成的ETag不是文件内容md5
+	header.Set("ETag", `"9e107d9d372bb6826bd81d3542a419d6-3"`)
+	if v := headerMd5(header); v != "" {
+		t.Fatalf("multipart etag used as md5: %v", v)
+	}
+
+	header.Set("Content-MD5", "nhB9nTcrtoJr2B01QqQZ1g==")
+	if v := headerMd5(header); v != "9e107d9d372bb6826bd81d3542a419d6" {
+		t.Fatalf("md5 from content-md5: %v", v)
+	}
+
+	if v := etagMd5("Fh8xVqod2MQ1mocfI4S4KpRL6D98"); v != "" {
+		t.Fatalf("qiniu hash used as md5: %v", v)
+	}
+}
@@ -0,0 +1,636 @@
+// Package storager
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package storager
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"image"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/disintegration/imaging"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gmlock"
+	"github.com/gogf/gf/v2/os/grpool"
+	_ "golang.org/x/image/webp"
+)
+
+// 图片版本访问参数
+const (
+	ImageSizeQuery   = "x-size"   // 尺寸，如：200x200
+	ImageFormatQuery = "x-format" // 格式，目前仅支持webp
+)
+
+// 图片版本生成方式
+const (
+	ImageVariantEager = 1 // 上传时生成
+	ImageVariantLazy  = 2 // 首次访问时生成
+)
+
+// 图片格式
+const (
+	imageFormatJPEG = "jpeg"
+	imageFormatPNG  = "png"
+	imageFormatWebp = "webp"
+)
+
+const (
+	imageMaxProcessSize = 32 * 1024 * 1024 // 参与处理的图片大小上限
+	imageMaxPixels      = 50_000_000       // 参与处理的图片像素上限，防止解压炸弹
+	imageMaxSide        = 4096             // 预设尺寸边长上限
+	imageJPEGQuality    = 85               // JPEG编码质量
+	imageWorkers        = 4                // 同时在后台处理的图片数量
+)
+
+// imagePool 上传图片的后台处理协程池
+var imagePool = grpool.New(imageWorkers)
+
+// 可处理的图片格式，gif可能是动图不做处理
+var imageFormats = map[string]string{
+	"jpg":  imageFormatJPEG,
+	"jpeg": imageFormatJPEG,
+	"png":  imageFormatPNG,
+	"webp": imageFormatWebp,
+}
+
+// ImageSize 图片尺寸，宽或高为0时按比例缩放
+type ImageSize struct {
+	Width  int
+	Height int
+}
+
+// IsZero 是否为原尺寸
+func (s ImageSize) IsZero() bool {
+	return s.Width == 0 && s.Height == 0
+}
+
+func (s ImageSize) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// ParseImageSize 解析图片尺寸，格式：宽x高
+func ParseImageSize(s string) (size ImageSize, err error) {
+	w, h, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "x")
+	if !ok {
+		err = gerror.Newf("图片尺寸格式不正确:%v", s)
+		return
+	}
+
+	if size.Width, err = strconv.Atoi(w); err != nil {
+		err = gerror.Newf("图片尺寸格式不正确:%v", s)
+		return
+	}
+
+	if size.Height, err = strconv.Atoi(h); err != nil {
+		err = gerror.Newf("图片尺寸格式不正确:%v", s)
+		return
+	}
+
+	if size.Width < 0 || size.Height < 0 || size.Width > imageMaxSide || size.Height > imageMaxSide || size.IsZero() {
+		err = gerror.Newf("图片尺寸超出范围:%v", s)
+		return
+	}
+	return
+}
+
+// ImageSizes 获取配置的预设尺寸，第一个尺寸作为缩略图
+func ImageSizes() (sizes []ImageSize) {
+	if config == nil {
+		return
+	}
+
+	for _, s := range strings.Split(config.ImageSizes, ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		size, err := ParseImageSize(s)
+		if err != nil {
+			continue
+		}
+		sizes = append(sizes, size)
+	}
+	return
+}
+
+// ImageVariantPath 获取图片版本的存储路径，如：attachment/2023-01-01/abc@200x200.webp
+func ImageVariantPath(fullPath string, size ImageSize, format string) string {
+	ext := path.Ext(fullPath)
+	key := "orig"
+	if !size.IsZero() {
+		key = size.String()
+	}
+
+	if imageFormats[Ext(fullPath)] != format {
+		return strings.TrimSuffix(fullPath, ext) + "@" + key + "." + format
+	}
+	return strings.TrimSuffix(fullPath, ext) + "@" + key + ext
+}
+
+// ImageVariant 获取图片指定尺寸和格式的版本路径，版本不存在时生成
+// size仅允许预设尺寸，为空时保持原尺寸；format为空时保持原格式
+func ImageVariant(ctx context.Context, drive, fullPath, size, format string) (variantPath string, err error) {
+	srcFormat, ok := imageFormats[Ext(fullPath)]
+	if !ok {
+		err = gerror.New("该图片格式不支持处理")
+		return
+	}
+
+	var imageSize ImageSize
+	if size != "" {
+		if imageSize, err = presetImageSize(size); err != nil {
+			return
+		}
+	}
+
+	switch format {
+	case "", srcFormat:
+		format = srcFormat
+	case imageFormatWebp:
+		if config.ImageWebp != 1 {
+			err = gerror.New("未开启WebP转换")
+			return
+		}
+	default:
+		err = gerror.Newf("不支持转换的图片格式:%v", format)
+		return
+	}
+
+	if imageSize.IsZero() && format == srcFormat {
+		return fullPath, nil
+	}
+
+	variantPath = ImageVariantPath(fullPath, imageSize, format)
+
+	// 同一版本并发请求时只生成一次
+	gmlock.Lock(variantPath)
+	defer gmlock.Unlock(variantPath)
+
+	drv := New(drive)
+	if _, err = drv.Stat(ctx, variantPath); err == nil {
+		return
+	}
+
+	data, err := readImage(ctx, drv, fullPath)
+	if err != nil {
+		return
+	}
+
+	img, err := decodeImage(data)
+	if err != nil {
+		return
+	}
+
+	err = putImageVariant(ctx, drv, variantPath, img, imageSize, format)
+	return
+}
+
+// ImageVariantURL 获取图片指定尺寸和格式版本的访问地址
+func ImageVariantURL(ctx context.Context, drive, fullPath, size, format string) (string, error) {
+	variantPath, err := ImageVariant(ctx, drive, fullPath, size, format)
+	if err != nil {
+		return "", err
+	}
+	return LastUrl(ctx, variantPath, drive), nil
+}
+
+// presetImageSize 解析尺寸并检查是否为预设尺寸，避免任意尺寸请求耗尽存储和计算资源
+func presetImageSize(s string) (size ImageSize, err error) {
+	if size, err = ParseImageSize(s); err != nil {
+		return
+	}
+
+	for _, v := range ImageSizes() {
+		if v == size {
+			return
+		}
+	}
+	err = gerror.Newf("不支持的图片尺寸:%v", s)
+	return
+}
+
+// processImageAsync 在后台处理上传的图片，完成后更新附件记录
+// 处理需要从存储中重新读取图片并生成版本，云存储时耗时较长，不在上传请求中执行
+func processImageAsync(ctx context.Context, models *entity.SysAttachment) {
+	var (
+		orig = *models
+		item = *models
+	)
+
+	err := imagePool.AddWithRecover(gctx.NeverDone(ctx), func(ctx context.Context) {
+		if err := processImage(ctx, &item); err != nil {
+			g.Log().Warningf(ctx, "process image %v err:%+v", item.Path, err)
+		}
+
+		var (
+			cols = dao.SysAttachment.Columns()
+			data = g.Map{}
+		)
+
+		if item.Width != orig.Width || item.Height != orig.Height {
+			data[cols.Width] = item.Width
+			data[cols.Height] = item.Height
+		}
+
+		if item.Size != orig.Size || item.Md5 != orig.Md5 {
+			data[cols.Size] = item.Size
+			data[cols.Md5] = item.Md5
+		}
+
+		if len(data) == 0 {
+			return
+		}
+
+		if _, err := GetModel(ctx).WherePri(item.Id).Data(data).Update(); err != nil {
+			g.Log().Warningf(ctx, "update processed image %v err:%+v", item.Path, err)
+		}
+	}, func(ctx context.Context, exception error) {
+		g.Log().Warningf(ctx, "process image %v panic:%+v", item.Path, exception)
+	})
+
+	if err != nil {
+		g.Log().Warningf(ctx, "add process image %v err:%+v", item.Path, err)
+	}
+}
+
+// processImage 处理上传的图片，去除EXIF等元数据并按配置纠正方向，记录宽高，按配置生成预设尺寸的版本
+// 去除元数据或纠正方向后会覆盖原文件，附件的大小和md5同步更新为实际写入的内容
+func processImage(ctx context.Context, models *entity.SysAttachment) (err error) {
+	format, ok := imageFormats[models.Ext]
+	if !ok || models.Size > imageMaxProcessSize {
+		return
+	}
+
+	drv := New(models.Drive)
+	data, err := readImage(ctx, drv, models.Path)
+	if err != nil {
+		return
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	models.Width, models.Height = cfg.Width, cfg.Height
+
+	var (
+		img       image.Image
+		processed []byte
+	)
+
+	// 纠正方向需要重新编码，重新编码后元数据不会保留，其他情况直接移除元数据段，不影响画质
+	if format == imageFormatJPEG && config.ImageAutoOrient == 1 && hasExif(data) && cfg.Width*cfg.Height <= imageMaxPixels {
+		if img, err = decodeImage(data); err != nil {
+			return
+		}
+
+		buf := new(bytes.Buffer)
+		if err = encodeImage(buf, img, format); err != nil {
+			return
+		}
+		processed = buf.Bytes()
+		models.Width, models.Height = img.Bounds().Dx(), img.Bounds().Dy()
+	} else if stripped, ok := stripExif(data, format); ok {
+		processed = stripped
+	}
+
+	if processed != nil {
+		size := int64(len(processed))
+		sum := md5.Sum(processed)
+		if err = drv.Put(ctx, models.Path, bytes.NewReader(processed), size, models.MimeType); err != nil {
+			return
+		}
+		models.Size = size
+		models.Md5 = hex.EncodeToString(sum[:])
+		data = processed
+	}
+
+	if cfg.Width*cfg.Height > imageMaxPixels {
+		return
+	}
+
+	if config.ImageVariantMode != ImageVariantEager {
+		return
+	}
+
+	if img == nil {
+		if img, err = decodeImage(data); err != nil {
+			return
+		}
+	}
+
+	formats := []string{format}
+	if config.ImageWebp == 1 && format != imageFormatWebp {
+		formats = append(formats, imageFormatWebp)
+	}
+
+	for _, size := range ImageSizes() {
+		for _, f := range formats {
+			if err = putImageVariant(ctx, drv, ImageVariantPath(models.Path, size, f), img, size, f); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// deleteImageVariants 删除按当前预设尺寸生成的图片版本
+func deleteImageVariants(ctx context.Context, drv UploadDrive, fullPath string) (err error) {
+	for _, variantPath := range imageVariantPaths(fullPath) {
+		if err = drv.Delete(ctx, variantPath); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// imageVariantPaths 获取按当前预设尺寸可能生成的图片版本路径
+func imageVariantPaths(fullPath string) (paths []string) {
+	format, ok := imageFormats[Ext(fullPath)]
+	if !ok {
+		return
+	}
+
+	formats := []string{format}
+	if format != imageFormatWebp {
+		formats = append(formats, imageFormatWebp)
+	}
+
+	for _, size := range append([]ImageSize{{}}, ImageSizes()...) {
+		for _, f := range formats {
+			if size.IsZero() && f == format {
+				continue
+			}
+			paths = append(paths, ImageVariantPath(fullPath, size, f))
+		}
+	}
+	return
+}
+
+// readImage 读取图片内容
+func readImage(ctx context.Context, drv UploadDrive, fullPath string) (data []byte, err error) {
+	reader, err := drv.Open(ctx, fullPath)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+
+	if data, err = io.ReadAll(io.LimitReader(reader, imageMaxProcessSize+1)); err != nil {
+		return
+	}
+
+	if len(data) > imageMaxProcessSize {
+		err = gerror.New("图片过大，无法处理")
+	}
+	return
+}
+
+// decodeImage 解码图片，按EXIF方向信息旋转
+func decodeImage(data []byte) (img image.Image, err error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+
+	if cfg.Width*cfg.Height > imageMaxPixels {
+		err = gerror.New("图片像素过大，无法处理")
+		return
+	}
+
+	return imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(config.ImageAutoOrient == 1))
+}
+
+// encodeImage 按格式编码图片
+func encodeImage(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case imageFormatWebp:
+		return nativewebp.Encode(w, img, nil)
+	case imageFormatPNG:
+		return imaging.Encode(w, img, imaging.PNG)
+	default:
+		return imaging.Encode(w, img, imaging.JPEG, imaging.JPEGQuality(imageJPEGQuality))
+	}
+}
+
+// resizeImage 调整图片尺寸，宽高都指定时居中裁剪，否则按比例缩放且不放大
+func resizeImage(img image.Image, size ImageSize) image.Image {
+	bounds := img.Bounds()
+	switch {
+	case size.IsZero():
+		return img
+	case size.Width == 0:
+		if bounds.Dy() <= size.Height {
+			return img
+		}
+	case size.Height == 0:
+		if bounds.Dx() <= size.Width {
+			return img
+		}
+	default:
+		return imaging.Fill(img, size.Width, size.Height, imaging.Center, imaging.Lanczos)
+	}
+	return imaging.Resize(img, size.Width, size.Height, imaging.Lanczos)
+}
+
+// putImageVariant 生成图片版本并写入存储
+func putImageVariant(ctx context.Context, drv UploadDrive, variantPath string, img image.Image, size ImageSize, format string) (err error) {
+	buf := new(bytes.Buffer)
+	if err = encodeImage(buf, resizeImage(img, size), format); err != nil {
+		return
+	}
+
+	return drv.Put(ctx, variantPath, buf, int64(buf.Len()), GetFileMimeType(Ext(variantPath)))
+}
+
+// hasExif 检查JPEG是否包含EXIF信息
+func hasExif(data []byte) bool {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return false
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return false
+		}
+
+		marker := data[i+1]
+		// 已到图像数据，后面不会再有EXIF段
+		if marker == 0xDA || marker == 0xD9 {
+			return false
+		}
+
+		length := int(data[i+2])<<8 | int(data[i+3])
+		if marker == 0xE1 && i+10 <= len(data) && string(data[i+4:i+10]) == "Exif\x00\x00" {
+			return true
+		}
+		i += 2 + length
+	}
+	return false
+}
+
+// stripExif 移除图片中的EXIF和XMP元数据段，如拍摄设备、GPS位置等信息，返回是否有移除
+// 只调整文件结构，不重新编码图像数据，无法解析的文件原样返回
+func stripExif(data []byte, format string) ([]byte, bool) {
+	switch format {
+	case imageFormatJPEG:
+		return stripJPEGExif(data)
+	case imageFormatPNG:
+		return stripPNGExif(data)
+	case imageFormatWebp:
+		return stripWebpExif(data)
+	}
+	return data, false
+}
+
+// stripJPEGExif 移除JPEG的APP1 EXIF和XMP段
+func stripJPEGExif(data []byte) ([]byte, bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return data, false
+	}
+
+	var (
+		out      = append(make([]byte, 0, len(data)), data[:2]...)
+		stripped bool
+	)
+
+	for i := 2; i < len(data); {
+		if i+2 > len(data) || data[i] != 0xFF {
+			return data, false
+		}
+
+		marker := data[i+1]
+		// 已到图像数据，后面不会再有元数据段
+		if marker == 0xDA || marker == 0xD9 {
+			out = append(out, data[i:]...)
+			break
+		}
+
+		// 不带长度的标记
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out = append(out, data[i:i+2]...)
+			i += 2
+			continue
+		}
+
+		if i+4 > len(data) {
+			return data, false
+		}
+
+		length := int(data[i+2])<<8 | int(data[i+3])
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return data, false
+		}
+
+		segment := data[i:end]
+		if marker == 0xE1 && (bytes.HasPrefix(segment[4:], []byte("Exif\x00\x00")) || bytes.HasPrefix(segment[4:], []byte("http://ns.adobe.com/xap/1.0/\x00"))) {
+			stripped = true
+		} else {
+			out = append(out, segment...)
+		}
+		i = end
+	}
+
+	if !stripped {
+		return data, false
+	}
+	return out, true
+}
+
+// stripPNGExif 移除PNG的eXIf块
+func stripPNGExif(data []byte) ([]byte, bool) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if len(data) < len(signature) || string(data[:len(signature)]) != signature {
+		return data, false
+	}
+
+	var (
+		out      = append(make([]byte, 0, len(data)), data[:len(signature)]...)
+		stripped bool
+	)
+
+	for i := len(signature); i < len(data); {
+		if i+8 > len(data) {
+			return data, false
+		}
+
+		// 长度、类型、数据、CRC
+		end := i + 12 + int(binary.BigEndian.Uint32(data[i:i+4]))
+		if end > len(data) || end < i {
+			return data, false
+		}
+
+		if string(data[i+4:i+8]) == "eXIf" {
+			stripped = true
+		} else {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+
+	if !stripped {
+		return data, false
+	}
+	return out, true
+}
+
+// stripWebpExif 移除WebP的EXIF和XMP块，并清除VP8X块中对应的标记位
+func stripWebpExif(data []byte) ([]byte, bool) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return data, false
+	}
+
+	var (
+		out      = append(make([]byte, 0, len(data)), data[:12]...)
+		vp8x     = -1
+		stripped bool
+	)
+
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return data, false
+		}
+
+		// 块数据长度为奇数时补齐一个字节
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		end := i + 8 + size + size%2
+		if end > len(data) || end < i {
+			return data, false
+		}
+
+		switch string(data[i : i+4]) {
+		case "EXIF", "XMP ":
+			stripped = true
+		case "VP8X":
+			vp8x = len(out)
+			out = append(out, data[i:end]...)
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+
+	if !stripped {
+		return data, false
+	}
+
+	if vp8x >= 0 && vp8x+8 < len(out) {
+		out[vp8x+8] &^= 0x08 | 0x04
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out, true
+}
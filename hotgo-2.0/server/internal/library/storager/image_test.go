@@ -0,0 +1,140 @@
+package storager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestParseImageSize(t *testing.T) {
+	size, err := ParseImageSize(" 200X100 ")
+	if err != nil || size.Width != 200 || size.Height != 100 {
+		t.Fatalf("unexpected size: %v, %v", size, err)
+	}
+
+	for _, s := range []string{"", "200", "0x0", "-1x10", "axb", "5000x10"} {
+		if _, err = ParseImageSize(s); err == nil {
+			t.Fatalf("invalid size accepted: %q", s)
+		}
+	}
+}
+
+func TestImageVariantPath(t *testing.T) {
+	cases := []struct {
+		size   ImageSize
+		format string
+		want   string
+	}{
+		{ImageSize{200, 200}, imageFormatJPEG, "attachment/2023-01-01/abc@200x200.jpg"},
+		{ImageSize{800, 0}, imageFormatWebp, "attachment/2023-01-01/abc@800x0.webp"},
+		{ImageSize{}, imageFormatWebp, "attachment/2023-01-01/abc@orig.webp"},
+	}
+
+	for _, c := range cases {
+		if got := ImageVariantPath("attachment/2023-01-01/abc.jpg", c.size, c.format); got != c.want {
+			t.Fatalf("want %v, got %v", c.want, got)
+		}
+	}
+}
+
+func TestHasExif(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := buf.Bytes()
+	if hasExif(plain) {
+		t.Fatal("plain jpeg reported exif")
+	}
+
+	// 在SOI之后插入APP1 EXIF段
+	app1 := []byte{0xFF, 0xE1, 0x00, 0x08, 'E', 'x', 'i', 'f', 0x00, 0x00}
+	withExif := append(append(append([]byte{}, plain[:2]...), app1...), plain[2:]...)
+	if !hasExif(withExif) {
+		t.Fatal("exif segment not detected")
+	}
+
+	if hasExif([]byte("not a jpeg")) {
+		t.Fatal("non jpeg reported exif")
+	}
+}
+
+func TestStripExif(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	// JPEG：移除APP1 EXIF和XMP段，保留其他段
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := buf.Bytes()
+	if _, ok := stripExif(plain, imageFormatJPEG); ok {
+		t.Fatal("plain jpeg reported stripped")
+	}
+
+	exif := []byte{0xFF, 0xE1, 0x00, 0x0A, 'E', 'x', 'i', 'f', 0x00, 0x00, 'M', 'M'}
+	xmp := append([]byte{0xFF, 0xE1, 0x00, 0x1F}, "http://ns.adobe.com/xap/1.0/\x00"...)
+	withExif := append(append(append(append([]byte{}, plain[:2]...), exif...), xmp...), plain[2:]...)
+	out, ok := stripExif(withExif, imageFormatJPEG)
+	if !ok || !bytes.Equal(out, plain) {
+		t.Fatal("jpeg metadata not stripped")
+	}
+
+	// PNG：移除eXIf块
+	buf.Reset()
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	plain = buf.Bytes()
+	if _, ok = stripExif(plain, imageFormatPNG); ok {
+		t.Fatal("plain png reported stripped")
+	}
+
+	// 插入到IHDR块之后，签名8字节，IHDR块25字节
+	chunk := append([]byte{0, 0, 0, 4}, "eXIfMM\x00*\x00\x00\x00\x00"...)
+	withExif = append(append(append([]byte{}, plain[:33]...), chunk...), plain[33:]...)
+	if out, ok = stripExif(withExif, imageFormatPNG); !ok || !bytes.Equal(out, plain) {
+		t.Fatal("png metadata not stripped")
+	}
+
+	if _, err := png.Decode(bytes.NewReader(out)); err != nil {
+		t.Fatal(err)
+	}
+
+	// WebP：移除EXIF块，清除VP8X中的EXIF标记位并修正RIFF长度
+	webpChunk := func(fourcc string, payload []byte) []byte {
+		b := append([]byte(fourcc), 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(b[4:], uint32(len(payload)))
+		b = append(b, payload...)
+		if len(payload)%2 == 1 {
+			b = append(b, 0)
+		}
+		return b
+	}
+
+	body := append([]byte("WEBP"), webpChunk("VP8X", []byte{0x08, 0, 0, 0, 3, 0, 0, 3, 0, 0})...)
+	body = append(body, webpChunk("VP8L", []byte{0x2F, 0, 0, 0})...)
+	body = append(body, webpChunk("EXIF", []byte("MM*"))...)
+	riff := append([]byte("RIFF"), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(riff[4:], uint32(len(body)))
+	withExif = append(riff, body...)
+
+	out, ok = stripExif(withExif, imageFormatWebp)
+	if !ok || len(out) != len(withExif)-12 {
+		t.Fatalf("webp metadata not stripped: %v", len(out))
+	}
+
+	if bytes.Contains(out, []byte("EXIF")) || out[20] != 0 || int(binary.LittleEndian.Uint32(out[4:8])) != len(out)-8 {
+		t.Fatal("webp container not updated")
+	}
+
+	if _, ok = stripExif([]byte("not an image"), imageFormatJPEG); ok {
+		t.Fatal("invalid data reported stripped")
+	}
+}
@@ -0,0 +1,413 @@
+// Package storager
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package storager
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/cache"
+	"hotgo/internal/model/entity"
+	"hotgo/utility/validate"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gmlock"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+const (
+	migrateBatchSize          = 100              // 每批读取的附件数量
+	migrateDefaultConcurrency = 4                // 默认并发数
+	migrateMaxConcurrency     = 32               // 最大并发数
+	migrateNotifyInterval     = time.Second      // 进度通知间隔
+	migrateRunningTTL         = 10 * time.Minute // 执行中进度的缓存时间，进程异常退出后超时可重新执行
+	migrateFinishedTTL        = 24 * time.Hour   // 执行结束后进度的缓存时间
+)
+
+// migrating 当前进程是否有迁移任务正在执行
+var migrating atomic.Bool
+
+// MigrateParams 存储迁移参数
+type MigrateParams struct {
+	From         string `json:"from"         dc:"源驱动"`
+	To           string `json:"to"           dc:"目标驱动"`
+	Concurrency  int    `json:"concurrency"  dc:"并发数"`
+	DeleteSource bool   `json:"deleteSource" dc:"迁移成功后删除源文件"`
+}
+
+// MigrateProgress 存储迁移进度
+type MigrateProgress struct {
+	From       string      `json:"from"       dc:"源驱动"`
+	To         string      `json:"to"         dc:"目标驱动"`
+	Total      int64       `json:"total"      dc:"待迁移数量"`
+	Done       int64       `json:"done"       dc:"已处理数量"`
+	Success    int64       `json:"success"    dc:"迁移成功数量"`
+	Skipped    int64       `json:"skipped"    dc:"跳过数量"`
+	Failed     int64       `json:"failed"     dc:"迁移失败数量"`
+	Size       int64       `json:"size"       dc:"已迁移大小，单位：字节"`
+	LastError  string      `json:"lastError"  dc:"最后一次失败原因"`
+	Running    bool        `json:"running"    dc:"是否执行中"`
+	StartedAt  *gtime.Time `json:"startedAt"  dc:"开始时间"`
+	FinishedAt *gtime.Time `json:"finishedAt" dc:"结束时间"`
+}
+
+// MigrateNotify 迁移进度通知
+type MigrateNotify func(progress *MigrateProgress)
+
+// Migrate 将源驱动中的附件迁移到目标驱动，迁移成功的附件记录会更新驱动和路径
+// 迁移按附件记录逐个进行，中断后再次执行时只处理仍在源驱动中的附件，失败的附件也会重新尝试
+func Migrate(ctx context.Context, in *MigrateParams, notify MigrateNotify) (res *MigrateProgress, err error) {
+	if err = CheckMigrate(ctx, in); err != nil {
+		return
+	}
+
+	if !migrating.CompareAndSwap(false, true) {
+		err = gerror.New("已有存储迁移任务正在执行，请稍后再试")
+		return
+	}
+	defer migrating.Store(false)
+
+	cols := dao.SysAttachment.Columns()
+	total, err := GetModel(ctx).Where(cols.Drive, in.From).Count()
+	if err != nil {
+		err = gerror.Wrap(err, "统计待迁移附件失败")
+		return
+	}
+
+	res = &MigrateProgress{
+		From:      in.From,
+		To:        in.To,
+		Total:     int64(total),
+		Running:   true,
+		StartedAt: gtime.Now(),
+	}
+
+	var (
+		mu         sync.Mutex
+		lastNotify time.Time
+	)
+
+	// report 保存并通知进度，force为false时按间隔节流
+	report := func(force bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !force && time.Since(lastNotify) < migrateNotifyInterval {
+			return
+		}
+		lastNotify = time.Now()
+
+		progress := *res
+		ttl := migrateRunningTTL
+		if !progress.Running {
+			ttl = migrateFinishedTTL
+		}
+
+		if err := cache.Instance().Set(ctx, consts.CacheAttachmentMigrate, &progress, ttl); err != nil {
+			g.Log().Warningf(ctx, "storager migrate save progress err:%+v", err)
+		}
+
+		if notify != nil {
+			notify(&progress)
+		}
+	}
+	report(true)
+
+	var (
+		lastId int64
+		jobs   = make(chan *entity.SysAttachment)
+		wg     sync.WaitGroup
+	)
+
+	// 单个大文件的复制时间可能超过执行中进度的缓存时间，定时刷新进度，避免其他进程误以为迁移已中断
+	stopRefresh := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(migrateRunningTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopRefresh:
+				return
+			case <-ticker.C:
+				report(true)
+			}
+		}
+	}()
+
+	for i := 0; i < in.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for models := range jobs {
+				size, skipped, err := migrateFile(ctx, in, models)
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					res.Failed++
+					res.LastError = fmt.Sprintf("附件[%v]:%v", models.Id, err)
+					g.Log().Warningf(ctx, "storager migrate attachment %v err:%+v", models.Id, err)
+				case skipped:
+					res.Skipped++
+				default:
+					res.Success++
+					res.Size += size
+				}
+				res.Done++
+				mu.Unlock()
+
+				report(false)
+			}
+		}()
+	}
+
+	// 按ID顺序分批读取，迁移失败的附件仍留在源驱动中，依靠游标避免本次重复处理
+	for {
+		var list []*entity.SysAttachment
+		err = GetModel(ctx).
+			Where(cols.Drive, in.From).
+			WhereGT(cols.Id, lastId).
+			OrderAsc(cols.Id).
+			Limit(migrateBatchSize).
+			Scan(&list)
+		if err != nil {
+			err = gerror.Wrap(err, "读取待迁移附件失败")
+			break
+		}
+
+		if len(list) == 0 {
+			break
+		}
+
+		for _, models := range list {
+			jobs <- models
+		}
+		lastId = list[len(list)-1].Id
+
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+	close(stopRefresh)
+
+	mu.Lock()
+	res.Running = false
+	res.FinishedAt = gtime.Now()
+	if err != nil {
+		res.LastError = err.Error()
+	}
+	mu.Unlock()
+
+	report(true)
+	return
+}
+
+// GetMigrateProgress 获取最近一次存储迁移的进度
+func GetMigrateProgress(ctx context.Context) (res *MigrateProgress, err error) {
+	v, err := cache.Instance().Get(ctx, consts.CacheAttachmentMigrate)
+	if err != nil || v.IsNil() {
+		return
+	}
+	err = v.Scan(&res)
+	return
+}
+
+// CheckMigrate 检查迁移参数和是否有迁移任务正在执行
+func CheckMigrate(ctx context.Context, in *MigrateParams) (err error) {
+	if in.From == in.To {
+		return gerror.New("源驱动和目标驱动不能相同")
+	}
+
+	if basePath(in.From) == "" {
+		return gerror.Newf("源驱动[%v]无效或未配置存储路径", in.From)
+	}
+
+	if basePath(in.To) == "" {
+		return gerror.Newf("目标驱动[%v]无效或未配置存储路径", in.To)
+	}
+
+	if in.Concurrency < 1 {
+		in.Concurrency = migrateDefaultConcurrency
+	}
+
+	if in.Concurrency > migrateMaxConcurrency {
+		in.Concurrency = migrateMaxConcurrency
+	}
+
+	if migrating.Load() {
+		return gerror.New("已有存储迁移任务正在执行，请稍后再试")
+	}
+
+	// 其他进程中的迁移任务
+	progress, err := GetMigrateProgress(ctx)
+	if err != nil {
+		return
+	}
+
+	if progress != nil && progress.Running {
+		return gerror.New("已有存储迁移任务正在执行，请稍后再试")
+	}
+	return
+}
+
+// migrateFile 迁移单个附件，同一文件被多条附件记录引用时一起更新
+func migrateFile(ctx context.Context, in *MigrateParams, models *entity.SysAttachment) (size int64, skipped bool, err error) {
+	// 外链附件没有存储文件，只更新驱动
+	if models.Path == "" || validate.IsURL(models.Path) {
+		return 0, false, updateMigrated(ctx, in, models, models.Path)
+	}
+
+	lockKey := "storager_migrate_" + models.Path
+	gmlock.Lock(lockKey)
+	defer gmlock.Unlock(lockKey)
+
+	// 同一文件的其他附件记录已迁移
+	count, err := GetModel(ctx).WherePri(models.Id).Where(dao.SysAttachment.Columns().Drive, in.From).Count()
+	if err != nil || count == 0 {
+		return 0, err == nil, err
+	}
+
+	src, dst := New(in.From), New(in.To)
+	newPath := migratePath(models.Path, in.From, in.To)
+
+	if size, err = copyFile(ctx, src, dst, models.Path, newPath, models.MimeType, models.Md5); err != nil {
+		return
+	}
+
+	// 图片版本可以按需重新生成，复制失败不影响迁移结果
+	if models.Kind == KindImg {
+		for _, variantPath := range imageVariantPaths(models.Path) {
+			if _, err := src.Stat(ctx, variantPath); err != nil {
+				continue
+			}
+			if _, err := copyFile(ctx, src, dst, variantPath, migratePath(variantPath, in.From, in.To), GetFileMimeType(Ext(variantPath)), ""); err != nil {
+				g.Log().Warningf(ctx, "storager migrate image variant %v err:%+v", variantPath, err)
+			}
+		}
+	}
+
+	if err = updateMigrated(ctx, in, models, newPath); err != nil {
+		return
+	}
+
+	if in.DeleteSource {
+		if err := src.Delete(ctx, models.Path); err != nil {
+			g.Log().Warningf(ctx, "storager migrate delete source %v err:%+v", models.Path, err)
+		}
+		if models.Kind == KindImg {
+			if err := deleteImageVariants(ctx, src, models.Path); err != nil {
+				g.Log().Warningf(ctx, "storager migrate delete source variants %v err:%+v", models.Path, err)
+			}
+		}
+	}
+	return
+}
+
+// updateMigrated 更新引用同一文件的附件记录的驱动和路径
+func updateMigrated(ctx context.Context, in *MigrateParams, models *entity.SysAttachment, newPath string) (err error) {
+	cols := dao.SysAttachment.Columns()
+	mod := GetModel(ctx).Where(cols.Drive, in.From).Where(cols.Path, models.Path)
+	if models.Path == "" {
+		mod = mod.WherePri(models.Id)
+	}
+
+	_, err = mod.Data(g.Map{
+		cols.Drive:   in.To,
+		cols.Path:    newPath,
+		cols.FileUrl: newPath,
+	}).Update()
+	if err != nil {
+		err = gerror.Wrap(err, "更新附件记录失败")
+	}
+	return
+}
+
+// copyFile 从源驱动流式复制文件到目标驱动，以复制时源文件流的md5校验目标文件，校验失败时删除目标文件
+// 附件记录中的md5与源文件不一致时只记录警告，如图片纠正方向后的旧记录
+func copyFile(ctx context.Context, src, dst UploadDrive, srcPath, dstPath, mimeType, md5Sum string) (size int64, err error) {
+	stat, err := src.Stat(ctx, srcPath)
+	if err != nil {
+		err = gerror.Wrapf(err, "获取源文件信息失败:%v", srcPath)
+		return
+	}
+
+	reader, err := src.Open(ctx, srcPath)
+	if err != nil {
+		err = gerror.Wrapf(err, "读取源文件失败:%v", srcPath)
+		return
+	}
+	defer reader.Close()
+
+	// 写入失败或校验不一致时目标文件可能已部分写入
+	defer func() {
+		if err == nil {
+			return
+		}
+		if delErr := dst.Delete(ctx, dstPath); delErr != nil {
+			g.Log().Warningf(ctx, "storager migrate delete invalid target %v err:%+v", dstPath, delErr)
+		}
+	}()
+
+	h := md5.New()
+	if err = dst.Put(ctx, dstPath, io.TeeReader(reader, h), stat.Size, mimeType); err != nil {
+		err = gerror.Wrapf(err, "写入目标文件失败:%v", dstPath)
+		return
+	}
+	srcSum := fmt.Sprintf("%x", h.Sum(nil))
+
+	if md5Sum != "" && !strings.EqualFold(srcSum, md5Sum) {
+		g.Log().Warningf(ctx, "storager migrate source md5 %v differs from attachment md5 %v:%v", srcSum, md5Sum, srcPath)
+	}
+
+	// 重新读取目标文件校验
+	sum, size, err := fileMd5(ctx, dst, dstPath)
+	if err != nil {
+		err = gerror.Wrapf(err, "校验目标文件失败:%v", dstPath)
+		return
+	}
+
+	if size != stat.Size || sum != srcSum {
+		err = gerror.Newf("目标文件校验不一致:%v", dstPath)
+	}
+	return
+}
+
+// fileMd5 计算存储中文件的md5和大小
+func fileMd5(ctx context.Context, drv UploadDrive, fullPath string) (sum string, size int64, err error) {
+	reader, err := drv.Open(ctx, fullPath)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+
+	h := md5.New()
+	if size, err = io.Copy(h, reader); err != nil {
+		return
+	}
+	sum = fmt.Sprintf("%x", h.Sum(nil))
+	return
+}
+
+// migratePath 将源驱动存储路径替换为目标驱动存储路径，不在源驱动存储路径下时保持不变
+func migratePath(fullPath, from, to string) string {
+	fromPath, toPath := basePath(from), basePath(to)
+	if !strings.HasPrefix(fullPath, fromPath) {
+		return fullPath
+	}
+	return toPath + strings.TrimPrefix(fullPath, fromPath)
+}
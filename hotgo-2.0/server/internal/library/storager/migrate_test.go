@@ -0,0 +1,122 @@
+package storager
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"hotgo/internal/consts"
+	"hotgo/internal/model"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// memDrive 内存存储驱动，只实现迁移用到的方法
+type memDrive struct {
+	UploadDrive
+	mu      sync.Mutex
+	files   map[string][]byte
+	corrupt bool // 写入时篡改内容
+}
+
+func newMemDrive() *memDrive {
+	return &memDrive{files: make(map[string][]byte)}
+}
+
+func (d *memDrive) Put(ctx context.Context, fullPath string, reader io.Reader, size int64, mimeType string) error {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	if d.corrupt && len(b) > 0 {
+		b[0] ^= 0xFF
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.files[fullPath] = b
+	return nil
+}
+
+func (d *memDrive) Delete(ctx context.Context, fullPath string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.files, fullPath)
+	return nil
+}
+
+func (d *memDrive) Stat(ctx context.Context, fullPath string) (*FileStat, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, ok := d.files[fullPath]
+	if !ok {
+		return nil, gerror.New("文件不存在")
+	}
+	return &FileStat{Path: fullPath, Size: int64(len(b))}, nil
+}
+
+func (d *memDrive) Open(ctx context.Context, fullPath string) (io.ReadCloser, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, ok := d.files[fullPath]
+	if !ok {
+		return nil, gerror.New("文件不存在")
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func TestCopyFile(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		data = []byte("hotgo migrate")
+		sum  = fmt.Sprintf("%x", md5.Sum(data))
+	)
+
+	src, dst := newMemDrive(), newMemDrive()
+	_ = src.Put(ctx, "a.txt", bytes.NewReader(data), int64(len(data)), "")
+
+	size, err := copyFile(ctx, src, dst, "a.txt", "b.txt", "", sum)
+	if err != nil || size != int64(len(data)) {
+		t.Fatalf("copy failed, size:%v, err:%v", size, err)
+	}
+
+	// 附件记录的md5过期时以源文件为准，只记录警告
+	if _, err = copyFile(ctx, src, dst, "a.txt", "c.txt", "", "stale"); err != nil {
+		t.Fatalf("stale attachment md5 rejected: %v", err)
+	}
+
+	// 目标文件与源文件不一致时校验失败并删除目标文件
+	dst.corrupt = true
+	if _, err = copyFile(ctx, src, dst, "a.txt", "d.txt", "", sum); err == nil {
+		t.Fatal("corrupted target accepted")
+	}
+
+	if _, err = dst.Stat(ctx, "d.txt"); err == nil {
+		t.Fatal("corrupted target not deleted")
+	}
+}
+
+func TestMigratePath(t *testing.T) {
+	old := GetConfig()
+	defer SetConfig(old)
+
+	SetConfig(&model.UploadConfig{LocalPath: "attachment/", MinioPath: "hotgo/attachment/"})
+
+	cases := []struct {
+		path, from, to, want string
+	}{
+		{"attachment/2023-01-01/abc.jpg", consts.UploadDriveLocal, consts.UploadDriveMinio, "hotgo/attachment/2023-01-01/abc.jpg"},
+		{"hotgo/attachment/2023-01-01/abc.jpg", consts.UploadDriveMinio, consts.UploadDriveLocal, "attachment/2023-01-01/abc.jpg"},
+		{"other/abc.jpg", consts.UploadDriveLocal, consts.UploadDriveMinio, "other/abc.jpg"},
+	}
+
+	for _, c := range cases {
+		if got := migratePath(c.path, c.from, c.to); got != c.want {
+			t.Fatalf("want %v, got %v", c.want, got)
+		}
+	}
+}
@@ -22,14 +22,63 @@ type FileMeta struct {
 	Md5       string `json:"md5"`       // 文件hash
 }
 
+// FileStat 存储对象信息
+type FileStat struct {
+	Path     string      `json:"path"`     // 文件路径
+	Size     int64       `json:"size"`     // 文件大小
+	MimeType string      `json:"mimeType"` // 文件类型
+	ETag     string      `json:"etag"`     // 文件标识，由存储驱动生成
+	Md5      string      `json:"md5"`      // 文件内容md5，存储平台未提供时为空
+	ModTime  *gtime.Time `json:"modTime"`  // 最后修改时间
+}
+
+// PresignedUpload 客户端直传签名
+type PresignedUpload struct {
+	Method   string            `json:"method"   dc:"请求方式，PUT时请求体为文件内容，POST时为multipart表单"`
+	URL      string            `json:"url"      dc:"上传地址"`
+	Headers  map[string]string `json:"headers"  dc:"PUT时需要携带的请求头"`
+	FormData map[string]string `json:"formData" dc:"POST时需要携带的表单字段"`
+	FileKey  string            `json:"fileKey"  dc:"POST时文件的表单字段名"`
+}
+
+// PresignUploadParams 申请直传
+type PresignUploadParams struct {
+	UploadType string `json:"uploadType"  dc:"文件类型"`
+	FileName   string `json:"fileName"    v:"required#文件名称不能为空" dc:"文件名称"`
+	Size       int64  `json:"size"        v:"min:1#文件大小不能为空" dc:"文件大小"`
+	Md5        string `json:"md5"         v:"required#文件md5值不能为空" dc:"文件md5值"`
+}
+
+type PresignUploadModel struct {
+	Direct     bool                  `json:"direct"     dc:"是否支持直传，不支持时客户端应使用普通上传或分片上传"`
+	UploadId   string                `json:"uploadId"   dc:"直传事件ID，上传完成后回调使用"`
+	Attachment *entity.SysAttachment `json:"attachment" dc:"文件已存在时直接返回附件"`
+	Upload     *PresignedUpload      `json:"upload"     dc:"直传签名"`
+	ExpireAt   int64                 `json:"expireAt"   dc:"签名过期时间戳"`
+}
+
+// CompleteUploadParams 直传完成
+type CompleteUploadParams struct {
+	UploadId string `json:"uploadId" v:"required#直传事件ID不能为空" dc:"直传事件ID"`
+}
+
 // MultipartProgress 分片进度
 type MultipartProgress struct {
-	UploadId      string      `json:"uploadId"`      // 上传事件ID
-	ThirdUploadId string      `json:"thirdUploadId"` // 第三方上传事件ID
-	Meta          *FileMeta   `json:"meta"`          // 文件元数据
-	ShardCount    int         `json:"shardCount"`    // 分片数量
-	UploadedIndex []int       `json:"uploadedIndex"` // 已上传的分片索引
-	CreatedAt     *gtime.Time `json:"createdAt"`     // 创建时间
+	UploadId      string           `json:"uploadId"`      // 上传事件ID
+	ThirdUploadId string           `json:"thirdUploadId"` // 第三方上传事件ID
+	FullPath      string           `json:"fullPath"`      // 第三方存储路径，创建上传事件时确定
+	Meta          *FileMeta        `json:"meta"`          // 文件元数据
+	ChunkSize     int64            `json:"chunkSize"`     // 分片大小
+	ShardCount    int              `json:"shardCount"`    // 分片数量
+	UploadedIndex []int            `json:"uploadedIndex"` // 已上传的分片索引
+	Parts         []*MultipartPart `json:"parts"`         // 第三方已上传的分片
+	CreatedAt     *gtime.Time      `json:"createdAt"`     // 创建时间
+}
+
+// MultipartPart 第三方已上传的分片
+type MultipartPart struct {
+	Index int    `json:"index"` // 分片索引，从1开始
+	ETag  string `json:"etag"`  // 分片标识，合并分片时使用
 }
 
 // CheckMultipartParams 检查文件分片
@@ -38,13 +87,16 @@ type CheckMultipartParams struct {
 	FileName   string `json:"fileName"    dc:"文件名称"`
 	Size       int64  `json:"size"        dc:"文件大小"`
 	Md5        string `json:"md5"         dc:"文件md5值"`
-	ShardCount int    `json:"shardCount"  dc:"分片数量"`
 	meta       *FileMeta
+	chunkSize  int64 // 分片大小，由存储驱动决定
+	shardCount int   // 分片数量，根据文件大小和分片大小计算
 }
 
 type CheckMultipartModel struct {
 	UploadId        string                `json:"uploadId"        dc:"上传事件ID"`
 	Attachment      *entity.SysAttachment `json:"attachment"      dc:"附件"`
+	ChunkSize       int64                 `json:"chunkSize"       dc:"分片大小，客户端需要按此大小切分文件"`
+	ShardCount      int                   `json:"shardCount"      dc:"分片数量"`
 	WaitUploadIndex []int                 `json:"waitUploadIndex" dc:"等待上传的分片索引"`
 	Progress        float64               `json:"progress"        dc:"上传进度"`
 	SizeFormat      string                `json:"sizeFormat"      dc:"文件大小"`
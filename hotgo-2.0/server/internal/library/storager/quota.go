@@ -0,0 +1,254 @@
+// Package storager
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package storager
+
+import (
+	"context"
+	"fmt"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/library/hgorm"
+	"hotgo/utility/format"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// QuotaUsage 存储容量使用情况
+type QuotaUsage struct {
+	Used        int64  `json:"used"        dc:"已用，单位：字节"`
+	Quota       int64  `json:"quota"       dc:"容量，单位：字节，0为不限制"`
+	UsedFormat  string `json:"usedFormat"  dc:"已用"`
+	QuotaFormat string `json:"quotaFormat" dc:"容量"`
+}
+
+// StorageUsage 存储用量
+type StorageUsage struct {
+	Member *QuotaUsage `json:"member" dc:"用户"`
+	App    *QuotaUsage `json:"app"    dc:"应用"`
+	Tenant *QuotaUsage `json:"tenant" dc:"租户，不属于租户时为空"`
+}
+
+// CheckQuota 检查上传后是否超出当前用户、应用和租户的存储容量，只检查不预占，并发上传时应使用ReserveQuota
+func CheckQuota(ctx context.Context, size int64) (err error) {
+	usage, err := GetUsage(ctx, contexts.GetUserId(ctx), contexts.GetModule(ctx))
+	if err != nil {
+		return
+	}
+
+	check := func(u *QuotaUsage, name string) error {
+		if u == nil || u.Quota <= 0 || u.Used+size <= u.Quota {
+			return nil
+		}
+		return gerror.Newf("%v存储容量不足，已用%v，总容量%v", name, u.UsedFormat, u.QuotaFormat)
+	}
+
+	if err = check(usage.Member, "用户"); err != nil {
+		return
+	}
+
+	if err = check(usage.Tenant, "租户"); err != nil {
+		return
+	}
+	return check(usage.App, "应用")
+}
+
+const (
+	quotaUploadTTL    = time.Hour        // 普通上传预占容量的最长保留时长
+	quotaReleaseDelay = 10 * time.Second // 上传完成后预占容量的保留时长，覆盖并发请求统计用量和预占之间的时间差
+)
+
+// reserveScript 清理过期的预占后，检查各维度的已用容量、预占容量和本次大小之和是否超出容量，全部未超出时写入预占
+// KEYS按维度成对传入：预占集合(成员为预占ID，分值为过期时间)、预占大小哈希，两者的过期时间跟随最晚过期的预占
+// ARGV：当前时间、过期时间、预占ID、本次大小，之后按维度成对传入已用容量和总容量
+// 返回0表示预占成功，否则返回超出容量的维度序号
+var reserveScript = `
+local now, expireAt, id, size = tonumber(ARGV[1]), tonumber(ARGV[2]), ARGV[3], tonumber(ARGV[4])
+for i = 1, #KEYS, 2 do
+	local expired = redis.call("ZRANGEBYSCORE", KEYS[i], "-inf", now)
+	for _, v in ipairs(expired) do
+		redis.call("ZREM", KEYS[i], v)
+		redis.call("HDEL", KEYS[i + 1], v)
+	end
+
+	local reserved = 0
+	for _, v in ipairs(redis.call("HVALS", KEYS[i + 1])) do
+		reserved = reserved + tonumber(v)
+	end
+	reserved = reserved - (tonumber(redis.call("HGET", KEYS[i + 1], id)) or 0)
+
+	local used, quota = tonumber(ARGV[4 + i]), tonumber(ARGV[5 + i])
+	if used + reserved + size > quota then
+		return (i + 1) / 2
+	end
+end
+for i = 1, #KEYS, 2 do
+	redis.call("ZADD", KEYS[i], expireAt, id)
+	redis.call("HSET", KEYS[i + 1], id, size)
+	for _, key in ipairs({KEYS[i], KEYS[i + 1]}) do
+		if redis.call("TTL", key) < expireAt - now then
+			redis.call("EXPIRE", key, expireAt - now)
+		end
+	end
+end
+return 0
+`
+
+// quotaDimension 存储容量的统计维度
+type quotaDimension struct {
+	name  string
+	key   string
+	usage *QuotaUsage
+}
+
+// quotaDimensions 获取设置了容量的统计维度
+func quotaDimensions(usage *StorageUsage, memberId int64, appId string, tenantId int64) (list []*quotaDimension) {
+	for _, v := range []*quotaDimension{
+		{name: "用户", key: quotaReserveKey("member", memberId), usage: usage.Member},
+		{name: "租户", key: quotaReserveKey("tenant", tenantId), usage: usage.Tenant},
+		{name: "应用", key: quotaReserveKey("app", appId), usage: usage.App},
+	} {
+		if v.usage != nil && v.usage.Quota > 0 {
+			list = append(list, v)
+		}
+	}
+	return
+}
+
+// quotaReserveKey 预占容量集合key
+func quotaReserveKey(dimension string, value any) string {
+	return fmt.Sprintf("%v:%v:%v", consts.CacheQuotaReserve, dimension, value)
+}
+
+// ReserveQuota 检查并预占当前用户、应用和租户的存储容量，检查和预占在redis中原子完成，避免并发上传同时通过检查后超出容量
+// id为上传事件ID，同一ID重复预占时只保留最后一次；预占在上传完成后由ReleaseQuota释放，未完成的上传在ttl后自动释放
+func ReserveQuota(ctx context.Context, id string, size int64, ttl time.Duration) (err error) {
+	var (
+		memberId = contexts.GetUserId(ctx)
+		appId    = contexts.GetModule(ctx)
+	)
+
+	usage, err := GetUsage(ctx, memberId, appId)
+	if err != nil {
+		return
+	}
+
+	dimensions := quotaDimensions(usage, memberId, appId, TenantId(ctx, memberId))
+	if len(dimensions) == 0 {
+		return
+	}
+
+	var (
+		now  = time.Now()
+		keys = make([]string, 0, len(dimensions)*2)
+		args = []interface{}{now.Unix(), now.Add(ttl).Unix(), id, size}
+	)
+
+	for _, v := range dimensions {
+		keys = append(keys, v.key, v.key+":size")
+		args = append(args, v.usage.Used, v.usage.Quota)
+	}
+
+	v, err := g.Redis().GroupScript().Eval(ctx, reserveScript, int64(len(keys)), keys, args)
+	if err != nil {
+		err = gerror.Wrap(err, "预占存储容量失败")
+		return
+	}
+
+	if i := v.Int(); i > 0 && i <= len(dimensions) {
+		u := dimensions[i-1].usage
+		err = gerror.Newf("%v存储容量不足，已用%v，总容量%v", dimensions[i-1].name, u.UsedFormat, u.QuotaFormat)
+	}
+	return
+}
+
+// ReleaseQuota 上传完成或放弃后释放预占的存储容量
+// 附件记录已写入时用量统计中已包含该文件，预占会再保留一小段时间，避免并发的预占在统计用量后、预占前漏算该文件
+func ReleaseQuota(ctx context.Context, id string) {
+	var (
+		memberId = contexts.GetUserId(ctx)
+		expireAt = time.Now().Add(quotaReleaseDelay).Unix()
+	)
+
+	for _, key := range []string{
+		quotaReserveKey("member", memberId),
+		quotaReserveKey("tenant", TenantId(ctx, memberId)),
+		quotaReserveKey("app", contexts.GetModule(ctx)),
+	} {
+		if _, err := g.Redis().Do(ctx, "ZADD", key, "XX", expireAt, id); err != nil {
+			g.Log().Warningf(ctx, "release quota %v err:%+v", id, err)
+		}
+	}
+}
+
+// GetUsage 获取用户、应用和用户所属租户的存储用量
+func GetUsage(ctx context.Context, memberId int64, appId string) (res *StorageUsage, err error) {
+	cols := dao.SysAttachment.Columns()
+	res = new(StorageUsage)
+
+	if res.Member, err = sumUsage(ctx, cols.MemberId, memberId, config.QuotaMember*1024*1024); err != nil {
+		return
+	}
+
+	if res.App, err = sumUsage(ctx, cols.AppId, appId, AppQuota(appId)); err != nil {
+		return
+	}
+
+	if tenantId := TenantId(ctx, memberId); tenantId > 0 {
+		res.Tenant, err = sumUsage(ctx, cols.TenantId, tenantId, config.QuotaTenant*1024*1024)
+	}
+	return
+}
+
+// AppQuota 获取应用的存储容量，单位：字节，0为不限制
+func AppQuota(appId string) int64 {
+	for _, v := range strings.Split(config.QuotaApp, ",") {
+		app, quota, ok := strings.Cut(strings.TrimSpace(v), ":")
+		if ok && strings.TrimSpace(app) == appId {
+			return gconv.Int64(strings.TrimSpace(quota)) * 1024 * 1024
+		}
+	}
+	return 0
+}
+
+// TenantId 获取用户所属的租户ID，不属于租户或关系无效时返回0
+func TenantId(ctx context.Context, memberId int64) int64 {
+	if memberId < 1 {
+		return 0
+	}
+
+	tr, err := hgorm.GetTenantRelation(ctx, memberId)
+	if err != nil {
+		return 0
+	}
+	return tr.TenantId
+}
+
+// sumUsage 统计指定维度的存储用量
+func sumUsage(ctx context.Context, column string, value any, quota int64) (res *QuotaUsage, err error) {
+	used, err := GetModel(ctx).Where(column, value).Sum(dao.SysAttachment.Columns().Size)
+	if err != nil {
+		err = gerror.Wrap(err, "统计存储用量失败")
+		return
+	}
+
+	res = &QuotaUsage{
+		Used:       int64(used),
+		Quota:      quota,
+		UsedFormat: format.FileSize(int64(used)),
+	}
+
+	if quota > 0 {
+		res.QuotaFormat = format.FileSize(quota)
+	} else {
+		res.QuotaFormat = "不限制"
+	}
+	return
+}
@@ -0,0 +1,86 @@
+// Package storager
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package storager
+
+import (
+	"context"
+	"hotgo/internal/model/entity"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// 附件引用方式
+const (
+	RefTypeId  = "id"  // 字段保存附件ID
+	RefTypeUrl = "url" // 字段保存附件地址或路径，支持多个地址拼接、JSON数组和富文本
+)
+
+// Ref 业务表中引用附件的字段
+type Ref struct {
+	Table  string // 表名
+	Column string // 字段名
+	Type   string // 引用方式
+}
+
+var (
+	refs   []*Ref
+	refsMu sync.RWMutex
+)
+
+// RegisterRef 声明业务表中引用附件的字段，被引用的附件不会被回收
+// 一般在业务逻辑的init中声明，如：storager.RegisterRef(dao.AdminMember.Table(), dao.AdminMember.Columns().Avatar, storager.RefTypeUrl)
+func RegisterRef(table, column, typ string) {
+	refsMu.Lock()
+	defer refsMu.Unlock()
+
+	for _, v := range refs {
+		if v.Table == table && v.Column == column {
+			v.Type = typ
+			return
+		}
+	}
+	refs = append(refs, &Ref{Table: table, Column: column, Type: typ})
+}
+
+// Refs 获取已声明的附件引用
+func Refs() []*Ref {
+	refsMu.RLock()
+	defer refsMu.RUnlock()
+
+	list := make([]*Ref, len(refs))
+	copy(list, refs)
+	return list
+}
+
+// IsReferenced 检查附件是否被业务数据引用，包括已软删除的数据
+func IsReferenced(ctx context.Context, models *entity.SysAttachment) (bool, error) {
+	for _, ref := range Refs() {
+		mod := g.Model(ref.Table).Ctx(ctx).Unscoped()
+		switch ref.Type {
+		case RefTypeId:
+			mod = mod.Where(ref.Column, models.Id)
+		case RefTypeUrl:
+			if models.Path == "" {
+				continue
+			}
+			mod = mod.WhereLike(ref.Column, "%"+models.Path+"%")
+		default:
+			return false, gerror.Newf("附件引用方式无效:%v.%v, %v", ref.Table, ref.Column, ref.Type)
+		}
+
+		count, err := mod.Count()
+		if err != nil {
+			return false, gerror.Wrapf(err, "检查附件引用失败:%v.%v", ref.Table, ref.Column)
+		}
+
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
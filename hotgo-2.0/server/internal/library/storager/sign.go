@@ -0,0 +1,61 @@
+// Package storager
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package storager
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/consts"
+	"hotgo/utility/simple"
+	"hotgo/utility/url"
+	netUrl "net/url"
+	"strconv"
+	"time"
+)
+
+// signSecret 本地文件签名密钥，复用令牌加密秘钥
+func signSecret(ctx context.Context) []byte {
+	return g.Cfg().MustGet(ctx, "token.secretKey").Bytes()
+}
+
+// signPath 生成文件路径签名
+func signPath(secret []byte, fullPath string, expires int64) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(fullPath + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyPath 验证文件路径签名
+func verifyPath(secret []byte, fullPath string, expires int64, sign string, now time.Time) error {
+	if expires < now.Unix() {
+		return gerror.New("访问地址已过期")
+	}
+
+	if !hmac.Equal([]byte(signPath(secret, fullPath, expires)), []byte(sign)) {
+		return gerror.New("访问地址签名无效")
+	}
+	return nil
+}
+
+// LocalSignedURL 生成本地文件有时效的访问地址
+func LocalSignedURL(ctx context.Context, fullPath string, expire time.Duration) string {
+	expires := gtime.Now().Add(expire).Unix()
+	query := netUrl.Values{}
+	query.Set("path", fullPath)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("sign", signPath(signSecret(ctx), fullPath, expires))
+	return url.GetAddr(ctx) + simple.RouterPrefix(ctx, consts.AppApi) + "/storage/download?" + query.Encode()
+}
+
+// VerifyLocalSignedURL 验证本地文件访问地址的签名
+func VerifyLocalSignedURL(ctx context.Context, fullPath string, expires int64, sign string) error {
+	return verifyPath(signSecret(ctx), fullPath, expires, sign, time.Now())
+}
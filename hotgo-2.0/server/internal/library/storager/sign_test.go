@@ -0,0 +1,36 @@
+package storager
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyPath(t *testing.T) {
+	var (
+		secret   = []byte("hotgo")
+		fullPath = "attachment/2023-01-01/test.png"
+		now      = time.Now()
+		expires  = now.Add(time.Minute).Unix()
+		sign     = signPath(secret, fullPath, expires)
+	)
+
+	if err := verifyPath(secret, fullPath, expires, sign, now); err != nil {
+		t.Fatalf("valid sign rejected: %v", err)
+	}
+
+	if err := verifyPath(secret, fullPath+"x", expires, sign, now); err == nil {
+		t.Fatal("tampered path accepted")
+	}
+
+	if err := verifyPath(secret, fullPath, expires+1, sign, now); err == nil {
+		t.Fatal("tampered expires accepted")
+	}
+
+	if err := verifyPath([]byte("other"), fullPath, expires, sign, now); err == nil {
+		t.Fatal("sign with other secret accepted")
+	}
+
+	if err := verifyPath(secret, fullPath, expires, sign, now.Add(2*time.Minute)); err == nil {
+		t.Fatal("expired sign accepted")
+	}
+}
@@ -7,6 +7,9 @@ package storager
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"hotgo/internal/consts"
 	"hotgo/internal/dao"
@@ -17,6 +20,9 @@ import (
 	"hotgo/utility/format"
 	"hotgo/utility/url"
 	"hotgo/utility/validate"
+	"io"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,17 +31,40 @@ import (
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/gogf/gf/v2/util/grand"
+	"github.com/gogf/gf/v2/util/guid"
 )
 
+// 分片大小
+const (
+	localChunkSize  = 2 * 1024 * 1024 // 本地存储
+	cloudChunkSize  = 5 * 1024 * 1024 // 云存储，满足各平台分片不小于5MB的要求(最后一片除外)
+	ucloudChunkSize = 4 * 1024 * 1024 // UCloud，分片大小必须与服务端返回的BlkSize一致
+)
+
+const multipartProgressTTL = 7 * 24 * time.Hour // 分片上传进度保留时长
+
 // UploadDrive 存储驱动
 type UploadDrive interface {
 	// Upload 上传
 	Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath string, err error)
+	// Put 写入指定路径，文件已存在时覆盖
+	Put(ctx context.Context, fullPath string, reader io.Reader, size int64, mimeType string) (err error)
 	// CreateMultipart 创建分片事件
 	CreateMultipart(ctx context.Context, in *CheckMultipartParams) (res *MultipartProgress, err error)
 	// UploadPart 上传分片
 	UploadPart(ctx context.Context, in *UploadPartParams) (res *UploadPartModel, err error)
+	// Delete 删除文件，文件不存在时不返回错误
+	Delete(ctx context.Context, fullPath string) (err error)
+	// Stat 获取文件信息
+	Stat(ctx context.Context, fullPath string) (res *FileStat, err error)
+	// Open 打开文件读取流，使用完毕后需要关闭
+	Open(ctx context.Context, fullPath string) (reader io.ReadCloser, err error)
+	// SignedURL 生成有时效的文件访问地址
+	SignedURL(ctx context.Context, fullPath string, expire time.Duration) (signedURL string, err error)
+	// PresignUpload 生成客户端直传签名，不支持直传的驱动返回nil
+	PresignUpload(ctx context.Context, fullPath string, meta *FileMeta, expire time.Duration) (res *PresignedUpload, err error)
 }
 
 // New 初始化存储驱动
@@ -94,6 +123,12 @@ func DoUpload(ctx context.Context, typ string, file *ghttp.UploadFile) (result *
 		return
 	}
 
+	uploadId := guid.S()
+	if err = ReserveQuota(ctx, uploadId, meta.Size, quotaUploadTTL); err != nil {
+		return
+	}
+	defer ReleaseQuota(ctx, uploadId)
+
 	// 上传到驱动
 	fullPath, err := New(config.Drive).Upload(ctx, file)
 	if err != nil {
@@ -215,6 +250,7 @@ func write(ctx context.Context, meta *FileMeta, fullPath string) (models *entity
 		Id:        0,
 		AppId:     contexts.GetModule(ctx),
 		MemberId:  contexts.GetUserId(ctx),
+		TenantId:  TenantId(ctx, contexts.GetUserId(ctx)),
 		Drive:     config.Drive,
 		Size:      meta.Size,
 		Path:      fullPath,
@@ -233,11 +269,21 @@ func write(ctx context.Context, meta *FileMeta, fullPath string) (models *entity
 		return
 	}
 	models.Id = id
+
+	// 图片在后台处理，处理失败不影响上传结果
+	if models.Kind == KindImg {
+		processImageAsync(ctx, models)
+	}
 	return
 }
 
 // HasFile 检查附件是否存在
 func HasFile(ctx context.Context, md5 string) (res *entity.SysAttachment, err error) {
+	// 未经校验的直传文件不记录md5，不能用于复用
+	if md5 == "" {
+		return
+	}
+
 	if err = GetModel(ctx).Where(dao.SysAttachment.Columns().Md5, md5).Scan(&res); err != nil {
 		err = gerror.Wrap(err, "检查文件hash时出现错误")
 		return
@@ -258,50 +304,100 @@ func HasFile(ctx context.Context, md5 string) (res *entity.SysAttachment, err er
 	return
 }
 
-// CheckMultipart 检查文件分片
-func CheckMultipart(ctx context.Context, in *CheckMultipartParams) (res *CheckMultipartModel, err error) {
-	res = new(CheckMultipartModel)
+// ReleaseFile 附件记录删除后，如果没有其他附件引用同一文件则删除存储中的文件
+func ReleaseFile(ctx context.Context, models *entity.SysAttachment) (err error) {
+	if models == nil || models.Path == "" || validate.IsURL(models.Path) {
+		return
+	}
+
+	cols := dao.SysAttachment.Columns()
+	count, err := GetModel(ctx).
+		Where(cols.Md5, models.Md5).
+		Where(cols.Drive, models.Drive).
+		Where(cols.Path, models.Path).
+		Count()
+	if err != nil {
+		err = gerror.Wrap(err, "检查附件引用时出现错误")
+		return
+	}
+
+	if count > 0 {
+		return
+	}
+
+	drv := New(models.Drive)
+	if err = drv.Delete(ctx, models.Path); err != nil {
+		return
+	}
+
+	if models.Kind == KindImg {
+		err = deleteImageVariants(ctx, drv, models.Path)
+	}
+	return
+}
 
+// NewFileMeta 根据客户端提交的文件信息生成文件元数据
+func NewFileMeta(fileName string, size int64, md5 string) *FileMeta {
 	meta := new(FileMeta)
-	meta.Filename = in.FileName
-	meta.Size = in.Size
-	meta.Ext = Ext(in.FileName)
+	meta.Filename = fileName
+	meta.Size = size
+	meta.Ext = Ext(fileName)
 	meta.Kind = GetFileKind(meta.Ext)
 	meta.MimeType = GetFileMimeType(meta.Ext)
 
 	// 兼容naiveUI
 	naiveType := "text/plain"
-	if IsImgType(Ext(in.FileName)) {
+	if IsImgType(meta.Ext) {
 		naiveType = ""
 	}
 	meta.NaiveType = naiveType
-	meta.Md5 = in.Md5
-
-	if err = ValidateFileMeta(in.UploadType, meta); err != nil {
-		return
-	}
+	meta.Md5 = md5
+	return meta
+}
 
-	result, err := HasFile(ctx, in.Md5)
+// reusableFile 获取可以复用的已上传文件，相同存储相同身份才复用
+func reusableFile(ctx context.Context, md5 string) (res *entity.SysAttachment, err error) {
+	result, err := HasFile(ctx, md5)
 	if err != nil {
 		return nil, err
 	}
 
-	// 文件已存在，直接返回。相同存储相同身份才复用
 	if result != nil && result.Drive == config.Drive && result.MemberId == contexts.GetUserId(ctx) && result.AppId == contexts.GetModule(ctx) {
-		res.Attachment = result
+		return result, nil
+	}
+	return nil, nil
+}
+
+// CheckMultipart 检查文件分片
+func CheckMultipart(ctx context.Context, in *CheckMultipartParams) (res *CheckMultipartModel, err error) {
+	res = new(CheckMultipartModel)
+
+	meta := NewFileMeta(in.FileName, in.Size, in.Md5)
+	if err = ValidateFileMeta(in.UploadType, meta); err != nil {
 		return
 	}
 
-	for i := 0; i < in.ShardCount; i++ {
-		res.WaitUploadIndex = append(res.WaitUploadIndex, i+1)
+	if res.Attachment, err = reusableFile(ctx, in.Md5); err != nil || res.Attachment != nil {
+		return
+	}
+
+	// 同一文件续传时使用相同的上传事件ID，重复预占只保留一份
+	if err = ReserveQuota(ctx, GenUploadId(ctx, in.Md5), meta.Size, multipartProgressTTL); err != nil {
+		return
 	}
 
 	in.meta = meta
+	in.chunkSize = MultipartChunkSize(config.Drive)
+	in.shardCount = max(int((in.Size+in.chunkSize-1)/in.chunkSize), 1)
 	progress, err := GetOrCreateMultipartProgress(ctx, in)
 	if err != nil {
 		return nil, err
 	}
 
+	for i := 0; i < progress.ShardCount; i++ {
+		res.WaitUploadIndex = append(res.WaitUploadIndex, i+1)
+	}
+
 	if len(progress.UploadedIndex) > 0 {
 		res.WaitUploadIndex = convert.DifferenceSlice(progress.UploadedIndex, res.WaitUploadIndex)
 	}
@@ -310,11 +406,85 @@ func CheckMultipart(ctx context.Context, in *CheckMultipartParams) (res *CheckMu
 		res.WaitUploadIndex = make([]int, 0)
 	}
 	res.UploadId = progress.UploadId
+	res.ChunkSize = progress.ChunkSize
+	res.ShardCount = progress.ShardCount
 	res.Progress = CalcUploadProgress(progress.UploadedIndex, progress.ShardCount)
 	res.SizeFormat = format.FileSize(progress.Meta.Size)
 	return
 }
 
+// MultipartChunkSize 获取存储驱动的分片大小
+func MultipartChunkSize(drive string) int64 {
+	switch drive {
+	case consts.UploadDriveLocal:
+		return localChunkSize
+	case consts.UploadDriveUCloud:
+		return ucloudChunkSize
+	default:
+		return cloudChunkSize
+	}
+}
+
+// newMultipartProgress 生成分片上传事件进度
+func newMultipartProgress(ctx context.Context, in *CheckMultipartParams) *MultipartProgress {
+	return &MultipartProgress{
+		UploadId:      GenUploadId(ctx, in.Md5),
+		Meta:          in.meta,
+		ChunkSize:     in.chunkSize,
+		ShardCount:    in.shardCount,
+		UploadedIndex: make([]int, 0),
+		Parts:         make([]*MultipartPart, 0),
+		CreatedAt:     gtime.Now(),
+	}
+}
+
+// savePart 记录第三方已上传的分片，未全部上传完毕时更新进度
+func savePart(ctx context.Context, mp *MultipartProgress, index int, etag string) (finish bool, err error) {
+	mp.UploadedIndex = append(mp.UploadedIndex, index)
+	mp.Parts = append(mp.Parts, &MultipartPart{Index: index, ETag: etag})
+
+	if len(mp.UploadedIndex) == mp.ShardCount {
+		return true, nil
+	}
+	return false, UpdateMultipartProgress(ctx, mp)
+}
+
+// sortedParts 按分片索引排序的已上传分片
+func (mp *MultipartProgress) sortedParts() []*MultipartPart {
+	parts := make([]*MultipartPart, len(mp.Parts))
+	copy(parts, mp.Parts)
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Index < parts[j].Index
+	})
+	return parts
+}
+
+// finishMultipart 第三方分片合并完成后删除进度并写入附件记录
+func finishMultipart(ctx context.Context, mp *MultipartProgress) (res *UploadPartModel, err error) {
+	defer ReleaseQuota(ctx, mp.UploadId)
+
+	if err = DelMultipartProgress(ctx, mp); err != nil {
+		return nil, err
+	}
+
+	attachment, err := write(ctx, mp.Meta, mp.FullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &UploadPartModel{
+		Attachment: attachment,
+		Progress:   100,
+		Finish:     true,
+	}
+	return
+}
+
+// partProgress 分片上传未完成时的进度
+func partProgress(mp *MultipartProgress) *UploadPartModel {
+	return &UploadPartModel{Progress: CalcUploadProgress(mp.UploadedIndex, mp.ShardCount)}
+}
+
 // CalcUploadProgress 计算上传进度
 func CalcUploadProgress(uploadedIndex []int, shardCount int) float64 {
 	return format.Round2Float64(float64(len(uploadedIndex)) / float64(shardCount) * 100)
@@ -333,6 +503,10 @@ func GetOrCreateMultipartProgress(ctx context.Context, in *CheckMultipartParams)
 		return nil, err
 	}
 	if res != nil {
+		// 兼容旧版本创建的上传事件，旧版本只有本地存储支持分片上传
+		if res.ChunkSize == 0 {
+			res.ChunkSize = localChunkSize
+		}
 		return res, nil
 	}
 	return New(config.Drive).CreateMultipart(ctx, in)
@@ -352,13 +526,13 @@ func GetMultipartProgress(ctx context.Context, uploadId string) (res *MultipartP
 // CreateMultipartProgress 创建分片上传事件进度
 func CreateMultipartProgress(ctx context.Context, in *MultipartProgress) (err error) {
 	key := fmt.Sprintf("%v:%v", consts.CacheMultipartUpload, in.UploadId)
-	return cache.Instance().Set(ctx, key, in, time.Hour*24*7)
+	return cache.Instance().Set(ctx, key, in, multipartProgressTTL)
 }
 
 // UpdateMultipartProgress 更新分片上传事件进度
 func UpdateMultipartProgress(ctx context.Context, in *MultipartProgress) (err error) {
 	key := fmt.Sprintf("%v:%v", consts.CacheMultipartUpload, in.UploadId)
-	return cache.Instance().Set(ctx, key, in, time.Hour*24*7)
+	return cache.Instance().Set(ctx, key, in, multipartProgressTTL)
 }
 
 // DelMultipartProgress 删除分片上传事件进度
@@ -390,3 +564,61 @@ func UploadPart(ctx context.Context, in *UploadPartParams) (res *UploadPartModel
 	}
 	return
 }
+
+// statFromHeader 根据http响应头生成文件信息
+func statFromHeader(fullPath string, header http.Header) *FileStat {
+	stat := &FileStat{
+		Path:     fullPath,
+		Size:     gconv.Int64(header.Get("Content-Length")),
+		MimeType: header.Get("Content-Type"),
+		ETag:     strings.Trim(header.Get("ETag"), `"`),
+		Md5:      headerMd5(header),
+	}
+
+	if t, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		stat.ModTime = gtime.New(t)
+	}
+	return stat
+}
+
+// headerMd5 从http响应头获取文件内容md5，优先使用Content-MD5，其次使用ETag
+func headerMd5(header http.Header) string {
+	if v := header.Get("Content-MD5"); v != "" {
+		if b, err := base64.StdEncoding.DecodeString(v); err == nil && len(b) == md5.Size {
+			return hex.EncodeToString(b)
+		}
+	}
+	return etagMd5(header.Get("ETag"))
+}
+
+// etagMd5 简单上传生成的ETag即为文件内容md5，分片上传等方式生成的ETag格式不同，无法作为md5时返回空
+func etagMd5(etag string) string {
+	etag = strings.ToLower(strings.Trim(etag, `"`))
+	if len(etag) != md5.Size*2 {
+		return ""
+	}
+
+	if _, err := hex.DecodeString(etag); err != nil {
+		return ""
+	}
+	return etag
+}
+
+// openURL 以流的方式读取远程文件
+func openURL(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, gerror.Newf("读取文件失败，状态码:%v", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
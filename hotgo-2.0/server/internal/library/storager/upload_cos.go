@@ -11,14 +11,27 @@ import (
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/tencentyun/cos-go-sdk-v5"
+	"io"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // CosDrive 腾讯云cos驱动
 type CosDrive struct {
 }
 
+// client 获取cos客户端
+func (d *CosDrive) client() *cos.Client {
+	URL, _ := url.Parse(config.CosBucketURL)
+	return cos.NewClient(&cos.BaseURL{BucketURL: URL}, &http.Client{
+		Transport: &cos.AuthorizationTransport{
+			SecretID:  config.CosSecretId,
+			SecretKey: config.CosSecretKey,
+		},
+	})
+}
+
 // Upload 上传到腾讯云cos对象存储
 func (d *CosDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath string, err error) {
 	if config.CosPath == "" {
@@ -33,27 +46,120 @@ func (d *CosDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath
 		return
 	}
 
-	URL, _ := url.Parse(config.CosBucketURL)
-	client := cos.NewClient(&cos.BaseURL{BucketURL: URL}, &http.Client{
-		Transport: &cos.AuthorizationTransport{
-			SecretID:  config.CosSecretId,
-			SecretKey: config.CosSecretKey,
-		},
-	})
-
 	fullPath = GenFullPath(config.CosPath, gfile.Ext(file.Filename))
-	_, err = client.Object.Put(ctx, fullPath, f2, nil)
+	_, err = d.client().Object.Put(ctx, fullPath, f2, nil)
+	return
+}
+
+// Put 写入文件，文件已存在时覆盖
+func (d *CosDrive) Put(ctx context.Context, fullPath string, reader io.Reader, size int64, mimeType string) (err error) {
+	opt := &cos.ObjectPutOptions{
+		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{
+			ContentType:   mimeType,
+			ContentLength: size,
+		},
+	}
+	_, err = d.client().Object.Put(ctx, fullPath, reader, opt)
 	return
 }
 
 // CreateMultipart 创建分片事件
 func (d *CosDrive) CreateMultipart(ctx context.Context, in *CheckMultipartParams) (res *MultipartProgress, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	if config.CosPath == "" {
+		err = gerror.New("COS存储驱动必须配置存储路径!")
+		return
+	}
+
+	res = newMultipartProgress(ctx, in)
+	res.FullPath = GenFullPath(config.CosPath, gfile.Ext(in.meta.Filename))
+	result, _, err := d.client().Object.InitiateMultipartUpload(ctx, res.FullPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res.ThirdUploadId = result.UploadID
+	if err = CreateMultipartProgress(ctx, res); err != nil {
+		return nil, err
+	}
 	return
 }
 
 // UploadPart 上传分片
 func (d *CosDrive) UploadPart(ctx context.Context, in *UploadPartParams) (res *UploadPartModel, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	f, err := in.File.Open()
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	client := d.client()
+	resp, err := client.Object.UploadPart(ctx, in.mp.FullPath, in.mp.ThirdUploadId, in.Index, f, &cos.ObjectUploadPartOptions{
+		ContentLength: in.File.Size,
+	})
+	if err != nil {
+		return
+	}
+
+	finish, err := savePart(ctx, in.mp, in.Index, resp.Header.Get("ETag"))
+	if err != nil || !finish {
+		return partProgress(in.mp), err
+	}
+
+	opt := &cos.CompleteMultipartUploadOptions{}
+	for _, v := range in.mp.sortedParts() {
+		opt.Parts = append(opt.Parts, cos.Object{PartNumber: v.Index, ETag: v.ETag})
+	}
+
+	if _, _, err = client.Object.CompleteMultipartUpload(ctx, in.mp.FullPath, in.mp.ThirdUploadId, opt); err != nil {
+		return
+	}
+	return finishMultipart(ctx, in.mp)
+}
+
+// Delete 删除文件
+func (d *CosDrive) Delete(ctx context.Context, fullPath string) (err error) {
+	_, err = d.client().Object.Delete(ctx, fullPath)
+	return
+}
+
+// Stat 获取文件信息
+func (d *CosDrive) Stat(ctx context.Context, fullPath string) (res *FileStat, err error) {
+	resp, err := d.client().Object.Head(ctx, fullPath, nil)
+	if err != nil {
+		return
+	}
+	return statFromHeader(fullPath, resp.Header), nil
+}
+
+// Open 打开文件读取流
+func (d *CosDrive) Open(ctx context.Context, fullPath string) (reader io.ReadCloser, err error) {
+	resp, err := d.client().Object.Get(ctx, fullPath, nil)
+	if err != nil {
+		return
+	}
+	return resp.Body, nil
+}
+
+// SignedURL 生成有时效的文件访问地址
+func (d *CosDrive) SignedURL(ctx context.Context, fullPath string, expire time.Duration) (signedURL string, err error) {
+	u, err := d.client().Object.GetPresignedURL(ctx, http.MethodGet, fullPath, config.CosSecretId, config.CosSecretKey, expire, nil)
+	if err != nil {
+		return
+	}
+	return u.String(), nil
+}
+
+// PresignUpload 生成客户端直传签名
+func (d *CosDrive) PresignUpload(ctx context.Context, fullPath string, meta *FileMeta, expire time.Duration) (res *PresignedUpload, err error) {
+	u, err := d.client().Object.GetPresignedURL(ctx, http.MethodPut, fullPath, config.CosSecretId, config.CosSecretKey, expire, nil)
+	if err != nil {
+		return
+	}
+
+	res = &PresignedUpload{
+		Method:  http.MethodPut,
+		URL:     u.String(),
+		Headers: directHeaders(meta),
+	}
 	return
 }
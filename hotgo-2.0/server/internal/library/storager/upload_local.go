@@ -13,11 +13,14 @@ import (
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/gconv"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // LocalDrive 本地驱动
@@ -52,14 +55,110 @@ func (d *LocalDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPa
 	return
 }
 
+// Put 写入本地文件，文件已存在时覆盖
+func (d *LocalDrive) Put(ctx context.Context, fullPath string, reader io.Reader, size int64, mimeType string) (err error) {
+	filePath, err := LocalFilePath(ctx, fullPath)
+	if err != nil {
+		return
+	}
+
+	if err = gfile.Mkdir(filepath.Dir(filePath)); err != nil {
+		return
+	}
+
+	f, err := gfile.Create(filePath)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, reader)
+	return
+}
+
+// Delete 删除本地文件
+func (d *LocalDrive) Delete(ctx context.Context, fullPath string) (err error) {
+	filePath, err := LocalFilePath(ctx, fullPath)
+	if err != nil {
+		return
+	}
+
+	if !gfile.Exists(filePath) {
+		return
+	}
+	return gfile.Remove(filePath)
+}
+
+// Stat 获取本地文件信息
+func (d *LocalDrive) Stat(ctx context.Context, fullPath string) (res *FileStat, err error) {
+	filePath, err := LocalFilePath(ctx, fullPath)
+	if err != nil {
+		return
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return
+	}
+
+	if info.IsDir() {
+		err = gerror.New("文件不存在")
+		return
+	}
+
+	res = &FileStat{
+		Path:     fullPath,
+		Size:     info.Size(),
+		MimeType: GetFileMimeType(Ext(fullPath)),
+		ETag:     strconv.FormatInt(info.ModTime().UnixNano(), 16) + "-" + strconv.FormatInt(info.Size(), 16),
+		ModTime:  gtime.New(info.ModTime()),
+	}
+	return
+}
+
+// Open 打开本地文件读取流
+func (d *LocalDrive) Open(ctx context.Context, fullPath string) (reader io.ReadCloser, err error) {
+	filePath, err := LocalFilePath(ctx, fullPath)
+	if err != nil {
+		return
+	}
+	return os.Open(filePath)
+}
+
+// SignedURL 生成有时效的本地文件访问地址，通过api应用的下载路由验证签名后访问
+func (d *LocalDrive) SignedURL(ctx context.Context, fullPath string, expire time.Duration) (signedURL string, err error) {
+	if _, err = LocalFilePath(ctx, fullPath); err != nil {
+		return
+	}
+	return LocalSignedURL(ctx, fullPath, expire), nil
+}
+
+// LocalFilePath 获取本地文件在磁盘上的路径，文件必须位于本地存储路径下
+func LocalFilePath(ctx context.Context, fullPath string) (filePath string, err error) {
+	sp := g.Cfg().MustGet(ctx, "server.serverRoot")
+	if sp.IsEmpty() {
+		err = gerror.New("本地上传驱动必须配置静态路径!")
+		return
+	}
+
+	if config.LocalPath == "" {
+		err = gerror.New("本地上传驱动必须配置本地存储路径!")
+		return
+	}
+
+	cleaned := path.Clean("/" + fullPath)[1:]
+	if cleaned != fullPath || !strings.HasPrefix(cleaned, strings.Trim(config.LocalPath, "/")+"/") {
+		err = gerror.New("文件路径无效")
+		return
+	}
+
+	filePath = strings.Trim(sp.String(), "/") + "/" + cleaned
+	return
+}
+
 // CreateMultipart 创建分片事件
 func (d *LocalDrive) CreateMultipart(ctx context.Context, in *CheckMultipartParams) (mp *MultipartProgress, err error) {
-	mp = new(MultipartProgress)
-	mp.UploadId = GenUploadId(ctx, in.Md5)
-	mp.Meta = in.meta
-	mp.ShardCount = in.ShardCount
-	mp.UploadedIndex = make([]int, 0)
-	mp.CreatedAt = gtime.Now()
+	mp = newMultipartProgress(ctx, in)
 	if err = CreateMultipartProgress(ctx, mp); err != nil {
 		return nil, err
 	}
@@ -100,6 +199,8 @@ func (d *LocalDrive) UploadPart(ctx context.Context, in *UploadPartParams) (res
 
 	// 已全部上传完毕
 	if len(in.mp.UploadedIndex) == in.mp.ShardCount {
+		defer ReleaseQuota(ctx, in.mp.UploadId)
+
 		// 删除进度统计
 		if err = DelMultipartProgress(ctx, in.mp); err != nil {
 			return nil, err
@@ -154,3 +255,8 @@ func MergePartFile(srcPath, dstPath string) (err error) {
 	}
 	return
 }
+
+// PresignUpload 本地存储不支持直传
+func (d *LocalDrive) PresignUpload(ctx context.Context, fullPath string, meta *FileMeta, expire time.Duration) (res *PresignedUpload, err error) {
+	return nil, nil
+}
@@ -10,17 +10,29 @@ import (
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/minio-go/v7/pkg/s3utils"
+	"io"
 	"mime"
+	"net/http"
 	"path/filepath"
+	"time"
 )
 
 // MinioDrive minio对象存储驱动
 type MinioDrive struct {
 }
 
+// client 获取minio客户端
+func (d *MinioDrive) client() (*minio.Client, error) {
+	return minio.New(config.MinioEndpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.MinioAccessKey, config.MinioSecretKey, ""),
+		Secure: config.MinioUseSSL == 1,
+	})
+}
+
 // Upload 上传到minio对象存储
 func (d *MinioDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath string, err error) {
 	if config.MinioPath == "" {
@@ -28,11 +40,7 @@ func (d *MinioDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPa
 		return
 	}
 
-	client, err := minio.New(config.MinioEndpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(config.MinioAccessKey, config.MinioSecretKey, ""),
-		Secure: config.MinioUseSSL == 1,
-	})
-
+	client, err := d.client()
 	if err != nil {
 		return "", err
 	}
@@ -63,14 +71,172 @@ func (d *MinioDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPa
 	return
 }
 
+// Put 写入文件，文件已存在时覆盖
+func (d *MinioDrive) Put(ctx context.Context, fullPath string, reader io.Reader, size int64, mimeType string) (err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	_, err = client.PutObject(ctx, config.MinioBucket, fullPath, reader, size, minio.PutObjectOptions{ContentType: mimeType})
+	return
+}
+
 // CreateMultipart 创建分片事件
 func (d *MinioDrive) CreateMultipart(ctx context.Context, in *CheckMultipartParams) (res *MultipartProgress, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	if config.MinioPath == "" {
+		err = gerror.New("minio存储驱动必须配置存储路径!")
+		return
+	}
+
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	res = newMultipartProgress(ctx, in)
+	res.FullPath = GenFullPath(config.MinioPath, gfile.Ext(in.meta.Filename))
+	if err = s3utils.CheckValidObjectName(res.FullPath); err != nil {
+		return nil, err
+	}
+
+	opts := minio.PutObjectOptions{ContentType: in.meta.MimeType}
+	if opts.ContentType == "" {
+		opts.ContentType = "application/octet-stream"
+	}
+
+	res.ThirdUploadId, err = minio.Core{Client: client}.NewMultipartUpload(ctx, config.MinioBucket, res.FullPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = CreateMultipartProgress(ctx, res); err != nil {
+		return nil, err
+	}
 	return
 }
 
 // UploadPart 上传分片
 func (d *MinioDrive) UploadPart(ctx context.Context, in *UploadPartParams) (res *UploadPartModel, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	f, err := in.File.Open()
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	core := minio.Core{Client: client}
+	part, err := core.PutObjectPart(ctx, config.MinioBucket, in.mp.FullPath, in.mp.ThirdUploadId, in.Index, f, in.File.Size, minio.PutObjectPartOptions{})
+	if err != nil {
+		return
+	}
+
+	finish, err := savePart(ctx, in.mp, in.Index, part.ETag)
+	if err != nil || !finish {
+		return partProgress(in.mp), err
+	}
+
+	parts := make([]minio.CompletePart, 0, len(in.mp.Parts))
+	for _, v := range in.mp.sortedParts() {
+		parts = append(parts, minio.CompletePart{PartNumber: v.Index, ETag: v.ETag})
+	}
+
+	if _, err = core.CompleteMultipartUpload(ctx, config.MinioBucket, in.mp.FullPath, in.mp.ThirdUploadId, parts, minio.PutObjectOptions{}); err != nil {
+		return
+	}
+	return finishMultipart(ctx, in.mp)
+}
+
+// Delete 删除文件
+func (d *MinioDrive) Delete(ctx context.Context, fullPath string) (err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+	return client.RemoveObject(ctx, config.MinioBucket, fullPath, minio.RemoveObjectOptions{})
+}
+
+// Stat 获取文件信息
+func (d *MinioDrive) Stat(ctx context.Context, fullPath string) (res *FileStat, err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	info, err := client.StatObject(ctx, config.MinioBucket, fullPath, minio.StatObjectOptions{})
+	if err != nil {
+		return
+	}
+
+	res = &FileStat{
+		Path:     fullPath,
+		Size:     info.Size,
+		MimeType: info.ContentType,
+		ETag:     info.ETag,
+		Md5:      etagMd5(info.ETag),
+		ModTime:  gtime.New(info.LastModified),
+	}
+	return
+}
+
+// Open 打开文件读取流
+func (d *MinioDrive) Open(ctx context.Context, fullPath string) (reader io.ReadCloser, err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	object, err := client.GetObject(ctx, config.MinioBucket, fullPath, minio.GetObjectOptions{})
+	if err != nil {
+		return
+	}
+
+	// GetObject不会请求服务端，提前检查文件是否存在
+	if _, err = object.Stat(); err != nil {
+		_ = object.Close()
+		return nil, err
+	}
+	return object, nil
+}
+
+// SignedURL 生成有时效的文件访问地址
+func (d *MinioDrive) SignedURL(ctx context.Context, fullPath string, expire time.Duration) (signedURL string, err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	u, err := client.PresignedGetObject(ctx, config.MinioBucket, fullPath, expire, nil)
+	if err != nil {
+		return
+	}
+	return u.String(), nil
+}
+
+// PresignUpload 生成客户端直传签名
+func (d *MinioDrive) PresignUpload(ctx context.Context, fullPath string, meta *FileMeta, expire time.Duration) (res *PresignedUpload, err error) {
+	if err = s3utils.CheckValidObjectName(fullPath); err != nil {
+		return
+	}
+
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	u, err := client.PresignedPutObject(ctx, config.MinioBucket, fullPath, expire)
+	if err != nil {
+		return
+	}
+
+	res = &PresignedUpload{
+		Method:  http.MethodPut,
+		URL:     u.String(),
+		Headers: directHeaders(meta),
+	}
 	return
 }
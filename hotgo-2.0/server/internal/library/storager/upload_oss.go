@@ -11,12 +11,24 @@ import (
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gfile"
+	"io"
+	"net/http"
+	"time"
 )
 
 // OssDrive 阿里云oss驱动
 type OssDrive struct {
 }
 
+// bucket 获取存储空间
+func (d *OssDrive) bucket() (*oss.Bucket, error) {
+	client, err := oss.New(config.OssEndpoint, config.OssSecretId, config.OssSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	return client.Bucket(config.OssBucket)
+}
+
 // Upload 上传到阿里云oss
 func (d *OssDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath string, err error) {
 	if config.OssPath == "" {
@@ -31,12 +43,7 @@ func (d *OssDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath
 		return
 	}
 
-	client, err := oss.New(config.OssEndpoint, config.OssSecretId, config.OssSecretKey)
-	if err != nil {
-		return
-	}
-
-	bucket, err := client.Bucket(config.OssBucket)
+	bucket, err := d.bucket()
 	if err != nil {
 		return
 	}
@@ -46,14 +53,137 @@ func (d *OssDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath
 	return
 }
 
+// Put 写入文件，文件已存在时覆盖
+func (d *OssDrive) Put(ctx context.Context, fullPath string, reader io.Reader, size int64, mimeType string) (err error) {
+	bucket, err := d.bucket()
+	if err != nil {
+		return
+	}
+	return bucket.PutObject(fullPath, reader, oss.ContentType(mimeType))
+}
+
 // CreateMultipart 创建分片事件
 func (d *OssDrive) CreateMultipart(ctx context.Context, in *CheckMultipartParams) (res *MultipartProgress, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	if config.OssPath == "" {
+		err = gerror.New("OSS存储驱动必须配置存储路径!")
+		return
+	}
+
+	bucket, err := d.bucket()
+	if err != nil {
+		return
+	}
+
+	res = newMultipartProgress(ctx, in)
+	res.FullPath = GenFullPath(config.OssPath, gfile.Ext(in.meta.Filename))
+	imur, err := bucket.InitiateMultipartUpload(res.FullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	res.ThirdUploadId = imur.UploadID
+	if err = CreateMultipartProgress(ctx, res); err != nil {
+		return nil, err
+	}
 	return
 }
 
 // UploadPart 上传分片
 func (d *OssDrive) UploadPart(ctx context.Context, in *UploadPartParams) (res *UploadPartModel, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	bucket, err := d.bucket()
+	if err != nil {
+		return
+	}
+
+	f, err := in.File.Open()
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	imur := oss.InitiateMultipartUploadResult{
+		Bucket:   config.OssBucket,
+		Key:      in.mp.FullPath,
+		UploadID: in.mp.ThirdUploadId,
+	}
+	part, err := bucket.UploadPart(imur, f, in.File.Size, in.Index)
+	if err != nil {
+		return
+	}
+
+	finish, err := savePart(ctx, in.mp, in.Index, part.ETag)
+	if err != nil || !finish {
+		return partProgress(in.mp), err
+	}
+
+	parts := make([]oss.UploadPart, 0, len(in.mp.Parts))
+	for _, v := range in.mp.sortedParts() {
+		parts = append(parts, oss.UploadPart{PartNumber: v.Index, ETag: v.ETag})
+	}
+
+	if _, err = bucket.CompleteMultipartUpload(imur, parts); err != nil {
+		return
+	}
+	return finishMultipart(ctx, in.mp)
+}
+
+// Delete 删除文件
+func (d *OssDrive) Delete(ctx context.Context, fullPath string) (err error) {
+	bucket, err := d.bucket()
+	if err != nil {
+		return
+	}
+	return bucket.DeleteObject(fullPath)
+}
+
+// Stat 获取文件信息
+func (d *OssDrive) Stat(ctx context.Context, fullPath string) (res *FileStat, err error) {
+	bucket, err := d.bucket()
+	if err != nil {
+		return
+	}
+
+	header, err := bucket.GetObjectDetailedMeta(fullPath)
+	if err != nil {
+		return
+	}
+	return statFromHeader(fullPath, header), nil
+}
+
+// Open 打开文件读取流
+func (d *OssDrive) Open(ctx context.Context, fullPath string) (reader io.ReadCloser, err error) {
+	bucket, err := d.bucket()
+	if err != nil {
+		return
+	}
+	return bucket.GetObject(fullPath)
+}
+
+// SignedURL 生成有时效的文件访问地址
+func (d *OssDrive) SignedURL(ctx context.Context, fullPath string, expire time.Duration) (signedURL string, err error) {
+	bucket, err := d.bucket()
+	if err != nil {
+		return
+	}
+	return bucket.SignURL(fullPath, oss.HTTPGet, int64(expire.Seconds()))
+}
+
+// PresignUpload 生成客户端直传签名
+func (d *OssDrive) PresignUpload(ctx context.Context, fullPath string, meta *FileMeta, expire time.Duration) (res *PresignedUpload, err error) {
+	bucket, err := d.bucket()
+	if err != nil {
+		return
+	}
+
+	signedURL, err := bucket.SignURL(fullPath, oss.HTTPPut, int64(expire.Seconds()), oss.ContentType(contentType(meta)))
+	if err != nil {
+		return
+	}
+
+	res = &PresignedUpload{
+		Method:  http.MethodPut,
+		URL:     signedURL,
+		Headers: directHeaders(meta),
+	}
 	return
 }
@@ -10,14 +10,68 @@ import (
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/qiniu/go-sdk/v7/auth/qbox"
 	"github.com/qiniu/go-sdk/v7/storage"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 )
 
 // QiNiuDrive 七牛云对象存储驱动
 type QiNiuDrive struct {
 }
 
+// mac 获取鉴权凭证
+func (d *QiNiuDrive) mac() *qbox.Mac {
+	return qbox.NewMac(config.QiNiuAccessKey, config.QiNiuSecretKey)
+}
+
+// config 获取存储空间配置
+func (d *QiNiuDrive) config() (cfg *storage.Config, err error) {
+	cfg = &storage.Config{}
+
+	// 是否使用https域名
+	cfg.UseHTTPS = true
+
+	// 上传是否使用CDN上传加速
+	cfg.UseCdnDomains = false
+
+	// 空间对应的机房
+	cfg.Region, err = storage.GetRegion(config.QiNiuAccessKey, config.QiNiuBucket)
+	return
+}
+
+// uploadToken 生成上传凭证
+func (d *QiNiuDrive) uploadToken() string {
+	putPolicy := storage.PutPolicy{
+		Scope: config.QiNiuBucket,
+	}
+	return putPolicy.UploadToken(d.mac())
+}
+
+// resumeUploader 获取分片上传器和上传域名
+func (d *QiNiuDrive) resumeUploader() (uploader *storage.ResumeUploaderV2, upHost string, err error) {
+	cfg, err := d.config()
+	if err != nil {
+		return
+	}
+
+	uploader = storage.NewResumeUploaderV2(cfg)
+	upHost, err = uploader.UpHost(config.QiNiuAccessKey, config.QiNiuBucket)
+	return
+}
+
+// bucketManager 获取空间管理器
+func (d *QiNiuDrive) bucketManager() (*storage.BucketManager, error) {
+	cfg, err := d.config()
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewBucketManager(d.mac(), cfg), nil
+}
+
 // Upload 上传到七牛云对象存储
 func (d *QiNiuDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath string, err error) {
 	if config.QiNiuPath == "" {
@@ -32,38 +86,174 @@ func (d *QiNiuDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPa
 		return
 	}
 
-	putPolicy := storage.PutPolicy{
-		Scope: config.QiNiuBucket,
-	}
-	token := putPolicy.UploadToken(qbox.NewMac(config.QiNiuAccessKey, config.QiNiuSecretKey))
-
-	cfg := storage.Config{}
+	token := d.uploadToken()
 
-	// 是否使用https域名
-	cfg.UseHTTPS = true
-
-	// 上传是否使用CDN上传加速
-	cfg.UseCdnDomains = false
-
-	// 空间对应的机房
-	cfg.Region, err = storage.GetRegion(config.QiNiuAccessKey, config.QiNiuBucket)
+	cfg, err := d.config()
 	if err != nil {
 		return
 	}
 
 	fullPath = GenFullPath(config.QiNiuPath, gfile.Ext(file.Filename))
-	err = storage.NewFormUploader(&cfg).Put(ctx, &storage.PutRet{}, token, fullPath, f2, file.Size, &storage.PutExtra{})
+	err = storage.NewFormUploader(cfg).Put(ctx, &storage.PutRet{}, token, fullPath, f2, file.Size, &storage.PutExtra{})
 	return
 }
 
+// Put 写入文件，文件已存在时覆盖
+func (d *QiNiuDrive) Put(ctx context.Context, fullPath string, reader io.Reader, size int64, mimeType string) (err error) {
+	cfg, err := d.config()
+	if err != nil {
+		return
+	}
+
+	// 指定文件名的凭证才允许覆盖上传
+	putPolicy := storage.PutPolicy{
+		Scope: config.QiNiuBucket + ":" + fullPath,
+	}
+	token := putPolicy.UploadToken(d.mac())
+	return storage.NewFormUploader(cfg).Put(ctx, &storage.PutRet{}, token, fullPath, reader, size, &storage.PutExtra{MimeType: mimeType})
+}
+
 // CreateMultipart 创建分片事件
 func (d *QiNiuDrive) CreateMultipart(ctx context.Context, in *CheckMultipartParams) (res *MultipartProgress, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	if config.QiNiuPath == "" {
+		err = gerror.New("七牛云存储驱动必须配置存储路径!")
+		return
+	}
+
+	uploader, upHost, err := d.resumeUploader()
+	if err != nil {
+		return
+	}
+
+	res = newMultipartProgress(ctx, in)
+	res.FullPath = GenFullPath(config.QiNiuPath, gfile.Ext(in.meta.Filename))
+
+	var ret storage.InitPartsRet
+	if err = uploader.InitParts(ctx, d.uploadToken(), upHost, config.QiNiuBucket, res.FullPath, true, &ret); err != nil {
+		return nil, err
+	}
+
+	res.ThirdUploadId = ret.UploadID
+	if err = CreateMultipartProgress(ctx, res); err != nil {
+		return nil, err
+	}
 	return
 }
 
 // UploadPart 上传分片
 func (d *QiNiuDrive) UploadPart(ctx context.Context, in *UploadPartParams) (res *UploadPartModel, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	uploader, upHost, err := d.resumeUploader()
+	if err != nil {
+		return
+	}
+
+	f, err := in.File.Open()
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	var (
+		token = d.uploadToken()
+		ret   storage.UploadPartsRet
+	)
+	if err = uploader.UploadParts(ctx, token, upHost, config.QiNiuBucket, in.mp.FullPath, true, in.mp.ThirdUploadId, int64(in.Index), "", &ret, f, int(in.File.Size)); err != nil {
+		return
+	}
+
+	finish, err := savePart(ctx, in.mp, in.Index, ret.Etag)
+	if err != nil || !finish {
+		return partProgress(in.mp), err
+	}
+
+	extra := &storage.RputV2Extra{MimeType: in.mp.Meta.MimeType}
+	for _, v := range in.mp.sortedParts() {
+		extra.Progresses = append(extra.Progresses, storage.UploadPartInfo{Etag: v.ETag, PartNumber: int64(v.Index)})
+	}
+
+	if err = uploader.CompleteParts(ctx, token, upHost, &storage.PutRet{}, config.QiNiuBucket, in.mp.FullPath, true, in.mp.ThirdUploadId, extra); err != nil {
+		return
+	}
+	return finishMultipart(ctx, in.mp)
+}
+
+// Delete 删除文件
+func (d *QiNiuDrive) Delete(ctx context.Context, fullPath string) (err error) {
+	manager, err := d.bucketManager()
+	if err != nil {
+		return
+	}
+
+	err = manager.Delete(config.QiNiuBucket, fullPath)
+	// 612 文件不存在
+	if e, ok := err.(*storage.ErrorInfo); ok && e.Code == 612 {
+		err = nil
+	}
+	return
+}
+
+// Stat 获取文件信息
+func (d *QiNiuDrive) Stat(ctx context.Context, fullPath string) (res *FileStat, err error) {
+	manager, err := d.bucketManager()
+	if err != nil {
+		return
+	}
+
+	info, err := manager.Stat(config.QiNiuBucket, fullPath)
+	if err != nil {
+		return
+	}
+
+	res = &FileStat{
+		Path:     fullPath,
+		Size:     info.Fsize,
+		MimeType: info.MimeType,
+		ETag:     info.Hash,
+		Md5:      strings.ToLower(info.Md5),
+		ModTime:  gtime.New(time.Unix(0, info.PutTime*100)),
+	}
+	return
+}
+
+// Open 打开文件读取流
+func (d *QiNiuDrive) Open(ctx context.Context, fullPath string) (reader io.ReadCloser, err error) {
+	signedURL, err := d.SignedURL(ctx, fullPath, time.Hour)
+	if err != nil {
+		return
+	}
+	return openURL(ctx, signedURL)
+}
+
+// SignedURL 生成有时效的文件访问地址
+func (d *QiNiuDrive) SignedURL(ctx context.Context, fullPath string, expire time.Duration) (signedURL string, err error) {
+	if config.QiNiuDomain == "" {
+		err = gerror.New("七牛云存储驱动必须配置访问域名!")
+		return
+	}
+	return storage.MakePrivateURLv2(d.mac(), config.QiNiuDomain, fullPath, time.Now().Add(expire).Unix()), nil
+}
+
+// PresignUpload 生成客户端直传的表单上传凭证，凭证只允许上传到指定路径且限制文件大小
+func (d *QiNiuDrive) PresignUpload(ctx context.Context, fullPath string, meta *FileMeta, expire time.Duration) (res *PresignedUpload, err error) {
+	_, upHost, err := d.resumeUploader()
+	if err != nil {
+		return
+	}
+
+	putPolicy := storage.PutPolicy{
+		Scope:      config.QiNiuBucket + ":" + fullPath,
+		Expires:    uint64(expire.Seconds()),
+		FsizeLimit: meta.Size,
+	}
+
+	res = &PresignedUpload{
+		Method: http.MethodPost,
+		URL:    upHost,
+		FormData: map[string]string{
+			"token": putPolicy.UploadToken(d.mac()),
+			"key":   fullPath,
+		},
+		FileKey: "file",
+	}
 	return
 }
@@ -11,20 +11,21 @@ import (
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gfile"
 	upload "github.com/ufilesdk-dev/ufile-gosdk"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // UCloudDrive UCloud对象存储驱动
 type UCloudDrive struct {
 }
 
-// Upload 上传到UCloud对象存储
-func (d *UCloudDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath string, err error) {
-	if config.UCloudPath == "" {
-		err = gerror.New("UCloud存储驱动必须配置存储路径!")
-		return
-	}
-
-	client, err := upload.NewFileRequest(&upload.Config{
+// client 获取UCloud客户端
+func (d *UCloudDrive) client() (*upload.UFileRequest, error) {
+	return upload.NewFileRequest(&upload.Config{
 		PublicKey:       config.UCloudPublicKey,
 		PrivateKey:      config.UCloudPrivateKey,
 		BucketHost:      config.UCloudBucketHost,
@@ -33,6 +34,16 @@ func (d *UCloudDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullP
 		Endpoint:        config.UCloudEndpoint,
 		VerifyUploadMD5: false,
 	}, nil)
+}
+
+// Upload 上传到UCloud对象存储
+func (d *UCloudDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullPath string, err error) {
+	if config.UCloudPath == "" {
+		err = gerror.New("UCloud存储驱动必须配置存储路径!")
+		return
+	}
+
+	client, err := d.client()
 	if err != nil {
 		return
 	}
@@ -49,14 +60,210 @@ func (d *UCloudDrive) Upload(ctx context.Context, file *ghttp.UploadFile) (fullP
 	return
 }
 
+// Put 写入文件，文件已存在时覆盖
+func (d *UCloudDrive) Put(ctx context.Context, fullPath string, reader io.Reader, size int64, mimeType string) (err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+	return client.IOPut(reader, fullPath, mimeType)
+}
+
 // CreateMultipart 创建分片事件
 func (d *UCloudDrive) CreateMultipart(ctx context.Context, in *CheckMultipartParams) (res *MultipartProgress, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	if config.UCloudPath == "" {
+		err = gerror.New("UCloud存储驱动必须配置存储路径!")
+		return
+	}
+
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	res = newMultipartProgress(ctx, in)
+	res.FullPath = GenFullPath(config.UCloudPath, gfile.Ext(in.meta.Filename))
+	state, err := client.InitiateMultipartUpload(res.FullPath, in.meta.MimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分片大小以服务端返回的为准
+	if state.BlkSize > 0 && int64(state.BlkSize) != res.ChunkSize {
+		res.ChunkSize = int64(state.BlkSize)
+		res.ShardCount = max(int((in.Size+res.ChunkSize-1)/res.ChunkSize), 1)
+	}
+
+	res.ThirdUploadId = state.UploadID
+	if err = CreateMultipartProgress(ctx, res); err != nil {
+		return nil, err
+	}
 	return
 }
 
 // UploadPart 上传分片
+// SDK的分片状态无法跨请求恢复，这里直接按UCloud分片接口发起请求
 func (d *UCloudDrive) UploadPart(ctx context.Context, in *UploadPartParams) (res *UploadPartModel, err error) {
-	err = gerror.New("当前驱动暂不支持分片上传！")
+	f, err := in.File.Open()
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	// UCloud的分片编号从0开始
+	query := url.Values{}
+	query.Set("uploadId", in.mp.ThirdUploadId)
+	query.Set("partNumber", strconv.Itoa(in.Index-1))
+
+	header, err := d.request(ctx, http.MethodPut, in.mp.FullPath, query, in.mp.Meta.MimeType, f, in.File.Size)
+	if err != nil {
+		return
+	}
+
+	finish, err := savePart(ctx, in.mp, in.Index, strings.Trim(header.Get("ETag"), `"`))
+	if err != nil || !finish {
+		return partProgress(in.mp), err
+	}
+
+	etags := make([]string, 0, len(in.mp.Parts))
+	for _, v := range in.mp.sortedParts() {
+		etags = append(etags, v.ETag)
+	}
+
+	body := strings.Join(etags, ",")
+	query = url.Values{}
+	query.Set("uploadId", in.mp.ThirdUploadId)
+	if _, err = d.request(ctx, http.MethodPost, in.mp.FullPath, query, in.mp.Meta.MimeType, strings.NewReader(body), int64(len(body))); err != nil {
+		return
+	}
+	return finishMultipart(ctx, in.mp)
+}
+
+// fileURL 生成文件地址，与SDK的生成规则一致
+func (d *UCloudDrive) fileURL(key string) (*url.URL, error) {
+	u := &url.URL{Scheme: "http", Host: config.UCloudBucketName + "." + config.UCloudFileHost}
+	if config.UCloudEndpoint != "" {
+		endpoint, err := url.Parse(config.UCloudEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		u.Scheme, u.Host = endpoint.Scheme, endpoint.Host
+	}
+	u.Path = "/" + key
+	return u, nil
+}
+
+// request 发起签名请求
+func (d *UCloudDrive) request(ctx context.Context, method, key string, query url.Values, mimeType string, body io.Reader, size int64) (header http.Header, err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	u, err := d.fileURL(key)
+	if err != nil {
+		return
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", mimeType)
+	req.Header.Set("Authorization", client.Auth.Authorization(method, config.UCloudBucketName, key, req.Header))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		err = gerror.Newf("UCloud请求失败，状态码:%v，响应:%s", resp.StatusCode, b)
+		return
+	}
+	return resp.Header, nil
+}
+
+// Delete 删除文件
+func (d *UCloudDrive) Delete(ctx context.Context, fullPath string) (err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	if err = client.DeleteFile(fullPath); err != nil && client.LastResponseStatus == http.StatusNotFound {
+		err = nil
+	}
+	return
+}
+
+// Stat 获取文件信息
+func (d *UCloudDrive) Stat(ctx context.Context, fullPath string) (res *FileStat, err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	if err = client.HeadFile(fullPath); err != nil {
+		return
+	}
+	return statFromHeader(fullPath, client.LastResponseHeader), nil
+}
+
+// Open 打开文件读取流
+func (d *UCloudDrive) Open(ctx context.Context, fullPath string) (reader io.ReadCloser, err error) {
+	signedURL, err := d.SignedURL(ctx, fullPath, time.Hour)
+	if err != nil {
+		return
+	}
+	return openURL(ctx, signedURL)
+}
+
+// SignedURL 生成有时效的文件访问地址
+func (d *UCloudDrive) SignedURL(ctx context.Context, fullPath string, expire time.Duration) (signedURL string, err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+	return client.GetPrivateURL(fullPath, expire), nil
+}
+
+// PresignUpload 生成客户端直传签名
+func (d *UCloudDrive) PresignUpload(ctx context.Context, fullPath string, meta *FileMeta, expire time.Duration) (res *PresignedUpload, err error) {
+	client, err := d.client()
+	if err != nil {
+		return
+	}
+
+	u, err := d.fileURL(fullPath)
+	if err != nil {
+		return
+	}
+
+	var (
+		headers = directHeaders(meta)
+		header  = http.Header{}
+		expires = strconv.FormatInt(time.Now().Add(expire).Unix(), 10)
+	)
+	for k, v := range headers {
+		header.Set(k, v)
+	}
+
+	signature, publicKey := client.Auth.AuthorizationPrivateURL(http.MethodPut, config.UCloudBucketName, fullPath, expires, header)
+	query := url.Values{}
+	query.Set("UCloudPublicKey", publicKey)
+	query.Set("Signature", signature)
+	query.Set("Expires", expires)
+	u.RawQuery = query.Encode()
+
+	res = &PresignedUpload{
+		Method:  http.MethodPut,
+		URL:     u.String(),
+		Headers: headers,
+	}
 	return
 }
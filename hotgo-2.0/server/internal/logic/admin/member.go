@@ -25,6 +25,7 @@ import (
 	"hotgo/internal/library/hgorm"
 	"hotgo/internal/library/hgorm/handler"
 	"hotgo/internal/library/hgorm/hook"
+	"hotgo/internal/library/storager"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/adminin"
 	"hotgo/internal/model/input/sysin"
@@ -54,6 +55,8 @@ func NewAdminMember() *sAdminMember {
 
 func init() {
 	service.RegisterAdminMember(NewAdminMember())
+	storager.RegisterRef(dao.AdminMember.Table(), dao.AdminMember.Columns().Avatar, storager.RefTypeUrl)
+	storager.RegisterRef(dao.AdminMember.Table(), dao.AdminMember.Columns().Cash, storager.RefTypeUrl)
 }
 
 // AddBalance 增加余额
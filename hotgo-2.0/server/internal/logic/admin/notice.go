@@ -12,6 +12,7 @@ import (
 	"hotgo/internal/dao"
 	"hotgo/internal/library/contexts"
 	"hotgo/internal/library/hgorm/handler"
+	"hotgo/internal/library/storager"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/adminin"
 	"hotgo/internal/model/input/form"
@@ -34,6 +35,7 @@ func NewAdminNotice() *sAdminNotice {
 
 func init() {
 	service.RegisterAdminNotice(NewAdminNotice())
+	storager.RegisterRef(dao.AdminNotice.Table(), dao.AdminNotice.Columns().Content, storager.RefTypeUrl)
 }
 
 // Model Orm模型
@@ -108,7 +110,7 @@ func (s *sAdminNotice) Edit(ctx context.Context, in *adminin.NoticeEditInp) (err
 	simple.SafeGo(ctx, func(ctx context.Context) {
 		if in.Type == consts.NoticeTypeLetter {
 			for _, receiverId := range in.Receiver {
-				websocket.SendToUser(receiverId, response)
+				websocket.SendToUser(receiverId, websocket.WithAck(response))
 			}
 		} else {
 			websocket.SendToAll(response)
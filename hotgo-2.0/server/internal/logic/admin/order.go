@@ -24,7 +24,6 @@ import (
 	"hotgo/utility/convert"
 	"hotgo/utility/excel"
 	"hotgo/utility/simple"
-	"hotgo/utility/validate"
 
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -60,47 +59,26 @@ func (s *sAdminOrder) AcceptRefund(ctx context.Context, in *adminin.OrderAcceptR
 		return
 	}
 
-	if view.Status != consts.OrderStatusReturnRequest {
-		err = gerror.New("当前订单状态不是申请退款状态，无需受理")
-		return
-	}
-
-	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) (err error) {
-		// 同意退款
-		if in.Status == consts.OrderStatusReturned {
-			// 更新余额
-			_, err = service.AdminCreditsLog().SaveBalance(ctx, &adminin.CreditsLogSaveBalanceInp{
-				MemberId:    view.MemberId,
-				AppId:       contexts.GetModule(ctx),
-				AddonsName:  contexts.GetAddonName(ctx),
-				CreditGroup: consts.CreditGroupBalanceRefund,
-				Num:         -view.Money,
-				MapId:       view.Id,
-				Remark:      fmt.Sprintf("余额退款:%v", in.Remark),
-			})
-			if err != nil {
-				return err
-			}
-
-			_, err = service.PayRefund().Refund(ctx, &payin.PayRefundInp{
-				OrderSn:     view.OrderSn,
-				RefundMoney: view.Money,
-				Reason:      view.RefundReason,
-				Remark:      in.Remark,
-			})
-			if err != nil {
-				return err
-			}
-		}
-
-		update := g.Map{
-			dao.AdminOrder.Columns().Status:             in.Status,
-			dao.AdminOrder.Columns().RejectRefundReason: in.RejectRefundReason,
+	switch in.Status {
+	case consts.OrderStatusReturned:
+		err = s.transit(ctx, consts.OrderEventAcceptRefund, &orderTransit{
+			Order:  &view.AdminOrder,
+			Remark: in.Remark,
+		})
+	case consts.OrderStatusReturnReject:
+		remark := in.RejectRefundReason
+		if remark == "" {
+			remark = in.Remark
 		}
 
-		_, err = s.Model(ctx).Where(dao.AdminOrder.Columns().Id, in.Id).Data(update).Update()
-		return
-	})
+		err = s.transit(ctx, consts.OrderEventRejectRefund, &orderTransit{
+			Order:  &view.AdminOrder,
+			Update: g.Map{dao.AdminOrder.Columns().RejectRefundReason: in.RejectRefundReason},
+			Remark: remark,
+		})
+	default:
+		err = gerror.New("受理状态只能是同意退款或拒绝退款")
+	}
 	return
 }
 
@@ -116,29 +94,20 @@ func (s *sAdminOrder) ApplyRefund(ctx context.Context, in *adminin.OrderApplyRef
 		return
 	}
 
-	if view.Status == consts.OrderStatusReturnRequest {
-		err = gerror.New("当前订单退款正在申请处理，请勿重复提交！")
-		return
-	}
-
-	if view.Status != consts.OrderStatusDone {
-		err = gerror.New("当前订单状态不支持申请退款，如有疑问请联系管理员！")
-		return
-	}
-
-	update := g.Map{
-		dao.AdminOrder.Columns().Status:       consts.OrderStatusReturnRequest,
-		dao.AdminOrder.Columns().RefundReason: in.RefundReason,
-	}
-
-	_, err = s.Model(ctx).Where(dao.AdminOrder.Columns().Id, in.Id).Data(update).Update()
+	err = s.transit(ctx, consts.OrderEventApplyRefund, &orderTransit{
+		Order:  &view.AdminOrder,
+		Update: g.Map{dao.AdminOrder.Columns().RefundReason: in.RefundReason},
+		Remark: in.RefundReason,
+	})
 	return
 }
 
 // PayNotify 支付成功通知
+// 由支付成功回调业务的队列消息触发，消息可能重复投递，订单已离开待支付和已关闭状态时视为已处理
+// 订单超时关闭后才收到的支付成功同样入账，避免用户已付款而余额未增加
 func (s *sAdminOrder) PayNotify(ctx context.Context, in *payin.NotifyCallFuncInp) (err error) {
 	var models *entity.AdminOrder
-	if err = s.Model(ctx).Where(dao.AdminOrder.Columns().OrderSn, in.Pay.OrderSn).Scan(&models); err != nil {
+	if err = s.Model(ctx, &handler.Option{FilterAuth: false}).Where(dao.AdminOrder.Columns().OrderSn, in.Pay.OrderSn).Scan(&models); err != nil {
 		return
 	}
 
@@ -147,36 +116,16 @@ func (s *sAdminOrder) PayNotify(ctx context.Context, in *payin.NotifyCallFuncInp
 		return
 	}
 
-	if models.Status != consts.OrderStatusNotPay {
-		err = gerror.New("订单已被处理，无需重复操作")
+	if models.Status != consts.OrderStatusNotPay && models.Status != consts.OrderStatusClose {
+		g.Log().Infof(ctx, "order PayNotify orderSn:%v status:%v already paid, skipped", models.OrderSn, models.Status)
 		return
 	}
 
-	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) (err error) {
-		// 更新订单状态
-		_, err = s.Model(ctx).Where(dao.AdminOrder.Columns().Id, models.Id).Data(g.Map{
-			dao.AdminOrder.Columns().Status: consts.OrderStatusDone,
-		}).Update()
-		if err != nil {
-			return
-		}
-
-		// 更新余额
-		_, err = service.AdminCreditsLog().SaveBalance(ctx, &adminin.CreditsLogSaveBalanceInp{
-			MemberId:    models.MemberId,
-			AppId:       in.Pay.AppId,
-			AddonsName:  in.Pay.AddonsName,
-			CreditGroup: consts.CreditGroupBalanceRecharge,
-			Num:         models.Money,
-			MapId:       models.Id,
-			Remark:      in.Pay.Subject,
-		})
-		if err != nil {
-			return err
-		}
-		return
+	err = s.transit(ctx, consts.OrderEventPay, &orderTransit{
+		Order:  models,
+		Remark: in.Pay.Subject,
+		Pay:    in.Pay,
 	})
-
 	if err != nil {
 		return
 	}
@@ -188,11 +137,52 @@ func (s *sAdminOrder) PayNotify(ctx context.Context, in *payin.NotifyCallFuncInp
 	}
 
 	simple.SafeGo(ctx, func(ctx context.Context) {
-		websocket.SendToUser(in.Pay.MemberId, response)
+		websocket.SendToUser(in.Pay.MemberId, websocket.WithAck(response))
+		websocket.SendToChannel(fmt.Sprintf("order:%v", models.Id), response)
+	})
+	return
+}
+
+// Close 关闭未支付的充值订单
+func (s *sAdminOrder) Close(ctx context.Context, in *adminin.OrderCloseInp) (err error) {
+	var models *entity.AdminOrder
+	if err = s.Model(ctx).Where(dao.AdminOrder.Columns().OrderSn, in.OrderSn).Scan(&models); err != nil {
+		return
+	}
+
+	if models == nil {
+		err = gerror.New("订单不存在")
+		return
+	}
+
+	err = s.transit(ctx, consts.OrderEventClose, &orderTransit{
+		Order:  models,
+		Remark: in.Remark,
 	})
 	return
 }
 
+// History 获取充值订单流转记录
+func (s *sAdminOrder) History(ctx context.Context, in *adminin.OrderHistoryInp) (list []*adminin.OrderHistoryModel, err error) {
+	view, err := s.View(ctx, &adminin.OrderViewInp{Id: in.Id})
+	if err != nil {
+		return
+	}
+
+	if view == nil {
+		err = gerror.New("订单不存在")
+		return
+	}
+
+	// 已经校验过订单的数据权限，流转记录中的操作人可能是其他管理员
+	err = dao.AdminOrderHistory.Ctx(ctx).
+		Where(dao.AdminOrderHistory.Columns().OrderId, view.Id).
+		Hook(hook.MemberSummary).
+		OrderAsc(dao.AdminOrderHistory.Columns().Id).
+		Scan(&list)
+	return
+}
+
 // Create 创建充值订单
 func (s *sAdminOrder) Create(ctx context.Context, in *adminin.OrderCreateInp) (res *adminin.OrderCreateModel, err error) {
 	var (
@@ -346,13 +336,14 @@ func (s *sAdminOrder) Export(ctx context.Context, in *adminin.OrderListInp) (err
 	return
 }
 
-// Edit 修改/新增充值订单
+// Edit 修改/新增充值订单，修改时订单状态只能通过状态机流转
 func (s *sAdminOrder) Edit(ctx context.Context, in *adminin.OrderEditInp) (err error) {
 	// 修改
 	if in.Id > 0 {
 		_, err = s.Model(ctx).
 			FieldsEx(
 				dao.AdminOrder.Columns().Id,
+				dao.AdminOrder.Columns().Status,
 				dao.AdminOrder.Columns().CreatedAt,
 			).
 			Where(dao.AdminOrder.Columns().Id, in.Id).Data(in).Update()
@@ -383,7 +374,7 @@ func (s *sAdminOrder) View(ctx context.Context, in *adminin.OrderViewInp) (res *
 	return
 }
 
-// Status 更新充值订单状态
+// Status 更新充值订单状态，只能变更为状态机中允许手动触发的目标状态
 func (s *sAdminOrder) Status(ctx context.Context, in *adminin.OrderStatusInp) (err error) {
 	if in.Id <= 0 {
 		err = gerror.New("ID不能为空")
@@ -395,13 +386,24 @@ func (s *sAdminOrder) Status(ctx context.Context, in *adminin.OrderStatusInp) (e
 		return
 	}
 
-	if !validate.InSlice(consts.StatusSlice, in.Status) {
-		err = gerror.New("状态不正确")
+	view, err := s.View(ctx, &adminin.OrderViewInp{Id: in.Id})
+	if err != nil {
 		return
 	}
 
-	_, err = s.Model(ctx).Where(dao.AdminOrder.Columns().Id, in.Id).Data(g.Map{
-		dao.AdminOrder.Columns().Status: in.Status,
-	}).Update()
+	if view == nil {
+		err = gerror.New("订单不存在")
+		return
+	}
+
+	t, err := orderMachine.Find(view.Status, in.Status)
+	if err != nil {
+		return
+	}
+
+	err = s.transit(ctx, t.Event, &orderTransit{
+		Order:  &view.AdminOrder,
+		Remark: "后台变更状态",
+	})
 	return
 }
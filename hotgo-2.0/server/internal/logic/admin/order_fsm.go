@@ -0,0 +1,215 @@
+// Package admin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package admin
+
+import (
+	"context"
+	"fmt"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/library/fsm"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/adminin"
+	"hotgo/internal/model/input/payin"
+	"hotgo/internal/service"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// 充值订单状态机
+// 订单状态只能通过这里声明的事件流转，每次流转在同一个事务中更新订单状态、执行副作用、写入流转记录并发布流转事件
+
+// orderTransit 订单流转的业务数据
+type orderTransit struct {
+	Order     *entity.AdminOrder // 流转前的订单
+	Update    g.Map              // 随状态一起更新的订单字段
+	Remark    string             // 流转备注
+	Pay       *entity.PayLog     // 支付成功时的支付记录
+	HistoryId int64              // 写入的流转记录ID
+}
+
+var orderMachine = newOrderMachine()
+
+func newOrderMachine() *fsm.Machine {
+	states := make(map[int]string)
+	for _, v := range consts.OrderStatusOptions {
+		if key := gconv.Int(v.Key); key > 0 {
+			states[key] = v.Label
+		}
+	}
+
+	return fsm.New("订单", states).
+		Add(
+			&fsm.Transition{
+				Event:  consts.OrderEventPay,
+				Name:   "支付成功",
+				From:   []int{consts.OrderStatusNotPay, consts.OrderStatusClose},
+				To:     consts.OrderStatusDone,
+				Guard:  orderPaid,
+				Action: orderRecharge,
+			},
+			&fsm.Transition{
+				Event:  consts.OrderEventClose,
+				Name:   "关闭订单",
+				From:   []int{consts.OrderStatusNotPay},
+				To:     consts.OrderStatusClose,
+				Manual: true,
+			},
+			&fsm.Transition{
+				Event: consts.OrderEventApplyRefund,
+				Name:  "申请退款",
+				From:  []int{consts.OrderStatusDone},
+				To:    consts.OrderStatusReturnRequest,
+			},
+			&fsm.Transition{
+				Event:  consts.OrderEventAcceptRefund,
+				Name:   "同意退款",
+				From:   []int{consts.OrderStatusReturnRequest},
+				To:     consts.OrderStatusReturned,
+				Action: orderRefund,
+			},
+			&fsm.Transition{
+				Event: consts.OrderEventRejectRefund,
+				Name:  "拒绝退款",
+				From:  []int{consts.OrderStatusReturnRequest},
+				To:    consts.OrderStatusReturnReject,
+			},
+		).
+		Before(orderSaveStatus).
+		After(orderSaveHistory, orderPublishEvent)
+}
+
+// orderSaveStatus 更新订单状态，只有订单仍处于流转前的状态时才会更新成功，避免并发流转重复执行副作用
+func orderSaveStatus(ctx context.Context, c *fsm.Context) (err error) {
+	var (
+		t      = c.Data.(*orderTransit)
+		cols   = dao.AdminOrder.Columns()
+		update = g.Map{cols.Status: c.To}
+	)
+
+	for k, v := range t.Update {
+		update[k] = v
+	}
+
+	result, err := dao.AdminOrder.Ctx(ctx).
+		Where(cols.Id, t.Order.Id).
+		Where(cols.Status, c.From).
+		Data(update).
+		Update()
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affected == 0 {
+		err = gerror.New("订单状态已发生变化，请刷新后重试")
+		return
+	}
+
+	t.Order.Status = c.To
+	return
+}
+
+// orderPaid 支付成功必须有该订单已支付的支付记录
+// 订单超时关闭后仍可能收到支付成功通知，此时用户已实际付款，凭已支付的支付记录将已关闭的订单流转为已完成
+func orderPaid(ctx context.Context, c *fsm.Context) (err error) {
+	t := c.Data.(*orderTransit)
+	if t.Pay == nil || t.Pay.PayStatus != consts.PayStatusOk || t.Pay.OrderSn != t.Order.OrderSn {
+		err = gerror.Newf("订单[%v]没有已支付的支付记录，不允许%v", t.Order.OrderSn, c.Name)
+	}
+	return
+}
+
+// orderRecharge 支付成功后增加余额
+func orderRecharge(ctx context.Context, c *fsm.Context) (err error) {
+	t := c.Data.(*orderTransit)
+	_, err = service.AdminCreditsLog().SaveBalance(ctx, &adminin.CreditsLogSaveBalanceInp{
+		MemberId:    t.Order.MemberId,
+		AppId:       t.Pay.AppId,
+		AddonsName:  t.Pay.AddonsName,
+		CreditGroup: consts.CreditGroupBalanceRecharge,
+		Num:         t.Order.Money,
+		MapId:       t.Order.Id,
+		Remark:      t.Pay.Subject,
+	})
+	return
+}
+
+// orderRefund 同意退款后扣除余额并发起原路退款
+func orderRefund(ctx context.Context, c *fsm.Context) (err error) {
+	t := c.Data.(*orderTransit)
+	_, err = service.AdminCreditsLog().SaveBalance(ctx, &adminin.CreditsLogSaveBalanceInp{
+		MemberId:    t.Order.MemberId,
+		AppId:       contexts.GetModule(ctx),
+		AddonsName:  contexts.GetAddonName(ctx),
+		CreditGroup: consts.CreditGroupBalanceRefund,
+		Num:         -t.Order.Money,
+		MapId:       t.Order.Id,
+		Remark:      fmt.Sprintf("余额退款:%v", t.Remark),
+	})
+	if err != nil {
+		return
+	}
+
+	_, err = service.PayRefund().Refund(ctx, &payin.PayRefundInp{
+		OrderSn:     t.Order.OrderSn,
+		RefundMoney: t.Order.Money,
+		Reason:      t.Order.RefundReason,
+		Remark:      t.Remark,
+	})
+	return
+}
+
+// orderSaveHistory 写入流转记录，系统触发的流转操作人为0
+func orderSaveHistory(ctx context.Context, c *fsm.Context) (err error) {
+	t := c.Data.(*orderTransit)
+	t.HistoryId, err = dao.AdminOrderHistory.Ctx(ctx).Data(entity.AdminOrderHistory{
+		OrderId:    t.Order.Id,
+		OrderSn:    t.Order.OrderSn,
+		Event:      c.Event,
+		EventName:  c.Name,
+		FromStatus: c.From,
+		ToStatus:   c.To,
+		CreatedBy:  contexts.GetUserId(ctx),
+		Remark:     t.Remark,
+		TraceId:    gctx.CtxId(ctx),
+	}).OmitEmptyData().InsertAndGetId()
+	return
+}
+
+// orderPublishEvent 通过发件箱发布流转事件，事务提交后才会投递
+func orderPublishEvent(ctx context.Context, c *fsm.Context) (err error) {
+	t := c.Data.(*orderTransit)
+	return service.SysQueue().Outbox(ctx, consts.QueueOrderTopic, fmt.Sprintf("order:%v:%v", t.Order.Id, t.HistoryId), &adminin.OrderTransitEvent{
+		HistoryId:  t.HistoryId,
+		OrderId:    t.Order.Id,
+		OrderSn:    t.Order.OrderSn,
+		MemberId:   t.Order.MemberId,
+		Event:      c.Event,
+		EventName:  c.Name,
+		FromStatus: c.From,
+		ToStatus:   c.To,
+		CreatedAt:  gtime.Now(),
+	})
+}
+
+// transit 在事务中触发订单流转
+func (s *sAdminOrder) transit(ctx context.Context, event string, t *orderTransit) (err error) {
+	return g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) (err error) {
+		_, err = orderMachine.Fire(ctx, event, t.Order.Status, t)
+		return
+	})
+}
@@ -0,0 +1,62 @@
+// Package admin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package admin
+
+import (
+	"hotgo/internal/consts"
+	"hotgo/internal/library/fsm"
+	"hotgo/internal/model/entity"
+	"testing"
+
+	"github.com/gogf/gf/v2/os/gctx"
+)
+
+func TestOrderPayAfterClose(t *testing.T) {
+	var (
+		ctx   = gctx.New()
+		order = &entity.AdminOrder{Id: 1, OrderSn: "TEST001", Status: consts.OrderStatusClose}
+		paid  = &entity.PayLog{OrderSn: order.OrderSn, PayStatus: consts.PayStatusOk}
+	)
+
+	// 超时关闭后收到支付成功通知，仍允许流转为已完成
+	tr, err := orderMachine.Can(consts.OrderEventPay, consts.OrderStatusClose)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tr.To != consts.OrderStatusDone {
+		t.Fatalf("pay after close to %v, want %v", tr.To, consts.OrderStatusDone)
+	}
+
+	c := &fsm.Context{Event: tr.Event, Name: tr.Name, From: order.Status, To: tr.To}
+
+	c.Data = &orderTransit{Order: order, Pay: paid}
+	if err = tr.Guard(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有已支付的支付记录时拒绝
+	for _, pay := range []*entity.PayLog{
+		nil,
+		{OrderSn: order.OrderSn, PayStatus: consts.PayStatusWait},
+		{OrderSn: "TEST002", PayStatus: consts.PayStatusOk},
+	} {
+		c.Data = &orderTransit{Order: order, Pay: pay}
+		if err = tr.Guard(ctx, c); err == nil {
+			t.Fatalf("pay guard accepted pay log: %+v", pay)
+		}
+	}
+
+	// 已完成的订单不会重复支付
+	if _, err = orderMachine.Can(consts.OrderEventPay, consts.OrderStatusDone); !fsm.IsIllegal(err) {
+		t.Fatalf("pay from done err = %v, want illegal", err)
+	}
+
+	// 已关闭的订单仍不能手动变更为已完成
+	if _, err = orderMachine.Find(consts.OrderStatusClose, consts.OrderStatusDone); !fsm.IsIllegal(err) {
+		t.Fatalf("manual done from close err = %v, want illegal", err)
+	}
+}
@@ -68,6 +68,32 @@ func (s *sCommonUpload) UploadPart(ctx context.Context, in *sysin.UploadPartInp)
 	return
 }
 
+// PresignUpload 申请客户端直传
+func (s *sCommonUpload) PresignUpload(ctx context.Context, in *sysin.PresignUploadInp) (res *sysin.PresignUploadModel, err error) {
+	data, err := storager.PresignUpload(ctx, in.PresignUploadParams)
+	if err != nil {
+		return nil, err
+	}
+	res = new(sysin.PresignUploadModel)
+	res.PresignUploadModel = data
+	return
+}
+
+// CompleteUpload 客户端直传完成
+func (s *sCommonUpload) CompleteUpload(ctx context.Context, in *sysin.CompleteUploadInp) (res *sysin.AttachmentListModel, err error) {
+	attachment, err := storager.CompleteUpload(ctx, in.CompleteUploadParams)
+	if err != nil {
+		return
+	}
+
+	attachment.FileUrl = storager.LastUrl(ctx, attachment.FileUrl, attachment.Drive)
+	res = &sysin.AttachmentListModel{
+		SysAttachment: *attachment,
+		SizeFormat:    format.FileSize(attachment.Size),
+	}
+	return
+}
+
 // ImageTransferStorage 图片链接转存
 func (s *sCommonUpload) ImageTransferStorage(ctx context.Context, in *sysin.ImageTransferStorageInp) (res *sysin.ImageTransferStorageModel, err error) {
 	if !gstr.HasPrefix(in.Url, "http://") && !gstr.HasPrefix(in.Url, "https://") {
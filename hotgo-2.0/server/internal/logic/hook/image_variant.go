@@ -0,0 +1,57 @@
+// Package hook
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package hook
+
+import (
+	"hotgo/internal/consts"
+	"hotgo/internal/library/storager"
+	"strings"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// 图片版本的浏览器缓存时间，版本文件生成后内容不会再变化
+const imageVariantMaxAge = "public, max-age=2592000"
+
+// imageVariant 本地存储的图片按参数输出指定尺寸或格式的版本，如：/attachment/2023-01-01/abc.jpg?x-size=200x200&x-format=webp
+// 版本首次访问时生成并保存在原图旁边，参数无效或处理失败时输出原图
+func (s *sHook) imageVariant(r *ghttp.Request) {
+	if !r.IsFileRequest() || storager.GetConfig() == nil {
+		return
+	}
+
+	var (
+		query  = r.URL.Query()
+		size   = query.Get(storager.ImageSizeQuery)
+		format = query.Get(storager.ImageFormatQuery)
+	)
+
+	if size == "" && format == "" {
+		return
+	}
+
+	ctx := r.Context()
+	fullPath := strings.TrimPrefix(r.URL.Path, "/")
+	if _, err := storager.LocalFilePath(ctx, fullPath); err != nil {
+		return
+	}
+
+	variantPath, err := storager.ImageVariant(ctx, consts.UploadDriveLocal, fullPath, size, format)
+	if err != nil {
+		g.Log().Debugf(ctx, "hook imageVariant %v err:%+v", r.URL.String(), err)
+		return
+	}
+
+	filePath, err := storager.LocalFilePath(ctx, variantPath)
+	if err != nil {
+		return
+	}
+
+	r.Response.Header().Set("Cache-Control", imageVariantMaxAge)
+	r.Response.ServeFile(filePath)
+	r.ExitAll()
+}
@@ -22,7 +22,7 @@ func New() *sHook {
 }
 
 func (s *sHook) BeforeServe(r *ghttp.Request) {
-
+	s.imageVariant(r)
 }
 
 func (s *sHook) AfterOutput(r *ghttp.Request) {
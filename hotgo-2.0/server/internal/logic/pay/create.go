@@ -15,6 +15,7 @@ import (
 	"hotgo/internal/library/contexts"
 	"hotgo/internal/library/location"
 	"hotgo/internal/library/payment"
+	"hotgo/internal/library/payment/mockpay"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/payin"
 	"hotgo/internal/service"
@@ -66,6 +67,8 @@ func (s *sPay) Create(ctx context.Context, in payin.PayCreateInp) (res *payin.Pa
 		mchId = config.WxPayMchId
 	case consts.PayTypeQQPay:
 		mchId = config.QQPayMchId
+	case consts.PayTypeMockPay:
+		mchId = mockpay.MchId
 	}
 
 	data := &entity.PayLog{
@@ -133,6 +136,8 @@ func (s *sPay) GenNotifyURL(ctx context.Context, in payin.PayCreateInp) (notifyU
 		object = v1.NotifyWxPayReq{}
 	case consts.PayTypeQQPay:
 		object = v1.NotifyQQPayReq{}
+	case consts.PayTypeMockPay:
+		object = v1.NotifyMockPayReq{}
 	default:
 		err = gerror.Newf("未被支持的支付方式：%v", in.PayType)
 		return
@@ -9,6 +9,8 @@ package pay
 
 import (
 	"context"
+	"fmt"
+	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
@@ -16,11 +18,14 @@ import (
 	"github.com/gogf/gf/v2/os/gctx"
 	"hotgo/internal/consts"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/hgorm/handler"
 	"hotgo/internal/library/location"
 	"hotgo/internal/library/payment"
+	"hotgo/internal/library/queue"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/payin"
 	"hotgo/internal/service"
+	"math"
 )
 
 // RegisterNotifyCall 注册支付成功回调方法
@@ -30,28 +35,122 @@ func (s *sPay) RegisterNotifyCall() {
 	})
 }
 
+// CheckQueue 检查支付成功回调依赖的消息队列是否开启
+// 支付成功后只在发件箱写入消息，由发件箱中继推送到队列后在queue服务中执行回调，队列关闭时已支付的业务订单不会被处理
+func (s *sPay) CheckQueue(ctx context.Context) (err error) {
+	if queue.GetConfig().Switch {
+		return
+	}
+
+	conf, err := service.SysConfig().GetPay(ctx)
+	if err != nil {
+		return
+	}
+
+	if conf.AliPayAppId == "" && conf.WxPayAppId == "" && conf.QQPayAppId == "" && !conf.MockPayEnabled {
+		return
+	}
+	return gerror.New("已配置支付渠道，支付成功回调依赖消息队列，请开启队列配置queue.switch并启动queue服务")
+}
+
 // Notify 异步通知
+// 原始报文先存档再处理，处理失败时返回错误让第三方继续重试，也可以在后台使用存档报文重放
 func (s *sPay) Notify(ctx context.Context, in *payin.PayNotifyInp) (res *payin.PayNotifyModel, err error) {
-	data, err := payment.New(in.PayType).Notify(ctx, payin.NotifyInp{})
+	var (
+		r     = ghttp.RequestFromCtx(ctx)
+		raw   = payin.NotifyInp{Header: r.Header, Body: r.GetBody()}
+		payIp = location.GetClientIp(r)
+	)
+
+	id, err := archiveNotify(ctx, in.PayType, raw, payIp)
 	if err != nil {
 		return
 	}
 
-	var models *entity.PayLog
-	if err = s.Model(ctx).Where(dao.PayLog.Columns().OutTradeNo, data.OutTradeNo).Scan(&models); err != nil {
+	data, duplicate, err := s.process(ctx, in.PayType, raw, payIp)
+	if ferr := finishNotify(ctx, id, data, duplicate, err); ferr != nil {
+		g.Log().Warningf(ctx, "pay Notify finish id:%v err:%+v", id, ferr)
+	}
+
+	if err != nil {
 		return
 	}
 
-	if models == nil {
-		err = gerror.Newf("商户订单号[%v]不存在支付记录，请检查", data.OutTradeNo)
+	res = &payin.PayNotifyModel{PayType: in.PayType}
+	return
+}
+
+// process 验签并核对支付记录后更新订单，订单此前已由同一笔交易支付成功时视为重复通知，不返回错误
+func (s *sPay) process(ctx context.Context, payType string, raw payin.NotifyInp, payIp string) (data *payin.NotifyModel, duplicate bool, err error) {
+	if data, err = payment.New(payType).Notify(ctx, raw); err != nil {
+		return
+	}
+
+	models, err := s.findByOutTradeNo(ctx, data.OutTradeNo)
+	if err != nil {
+		return
+	}
+
+	if models.PayType != payType {
+		err = gerror.Newf("商户订单号[%v]的支付方式为[%v]，与通知渠道[%v]不一致", data.OutTradeNo, models.PayType, payType)
+		return
+	}
+
+	if data.MchId != "" && models.MchId != "" && data.MchId != models.MchId {
+		err = gerror.Newf("通知商户号[%v]与支付记录商户号[%v]不一致", data.MchId, models.MchId)
 		return
 	}
 
-	if models.PayStatus != consts.PayStatusWait {
-		err = gerror.Newf("商户订单号[%v]已被处理，请勿重复操作", data.OutTradeNo)
+	if data.TotalAmount > 0 && math.Abs(data.TotalAmount-models.PayAmount) >= 0.01 {
+		err = gerror.Newf("通知订单金额[%v]与支付记录金额[%v]不一致", data.TotalAmount, models.PayAmount)
 		return
 	}
 
+	for i := 0; i < 2; i++ {
+		switch models.PayStatus {
+		case consts.PayStatusOk:
+			if models.TransactionId != "" && models.TransactionId != data.TransactionId {
+				err = gerror.Newf("商户订单号[%v]已由交易号[%v]支付成功，本次交易号[%v]可能是重复支付，请人工核实", data.OutTradeNo, models.TransactionId, data.TransactionId)
+				return
+			}
+			duplicate = true
+			return
+		case consts.PayStatusClose:
+			err = gerror.Newf("商户订单号[%v]已关闭但收到支付成功通知，请人工核实", data.OutTradeNo)
+			return
+		}
+
+		ok, err := s.settle(ctx, models, data, payIp)
+		if err != nil || ok {
+			return data, false, err
+		}
+
+		// 没有更新成功说明并发的通知或补单已处理了该记录，重新读取后按已处理的状态核对
+		if models, err = s.findByOutTradeNo(ctx, data.OutTradeNo); err != nil {
+			return data, false, err
+		}
+	}
+
+	err = gerror.Newf("商户订单号[%v]状态更新失败，请稍后重试", data.OutTradeNo)
+	return
+}
+
+// findByOutTradeNo 根据商户订单号获取支付记录
+// 通知可能由后台管理员重放，这里不过滤数据权限
+func (s *sPay) findByOutTradeNo(ctx context.Context, outTradeNo string) (models *entity.PayLog, err error) {
+	if err = s.Model(ctx, &handler.Option{FilterAuth: false}).Where(dao.PayLog.Columns().OutTradeNo, outTradeNo).Scan(&models); err != nil {
+		return
+	}
+
+	if models == nil {
+		err = gerror.Newf("商户订单号[%v]不存在支付记录，请检查", outTradeNo)
+	}
+	return
+}
+
+// settle 将待支付记录更新为已支付，返回本次是否更新成功
+// 支付成功回调业务的消息和支付记录在同一个事务中写入发件箱，由队列消费者执行回调，回调失败时会重试，不会出现已支付而业务订单未更新的情况
+func (s *sPay) settle(ctx context.Context, models *entity.PayLog, data *payin.NotifyModel, payIp string) (ok bool, err error) {
 	var traceIds []string
 	if err = models.TraceIds.Scan(&traceIds); err != nil {
 		return
@@ -62,37 +161,66 @@ func (s *sPay) Notify(ctx context.Context, in *payin.PayNotifyInp) (res *payin.P
 	models.PayStatus = consts.PayStatusOk
 	models.PayAt = data.PayAt
 	models.ActualAmount = data.ActualAmount
-	models.PayIp = location.GetClientIp(ghttp.RequestFromCtx(ctx))
+	models.PayIp = payIp
 	models.TraceIds = gjson.New(traceIds)
 
-	result, err := s.Model(ctx).
-		Fields(
-			dao.PayLog.Columns().TransactionId,
-			dao.PayLog.Columns().PayStatus,
-			dao.PayLog.Columns().PayAt,
-			dao.PayLog.Columns().PayIp,
-			dao.PayLog.Columns().TraceIds,
-			dao.PayLog.Columns().ActualAmount,
-		).
-		Where(dao.PayLog.Columns().Id, models.Id).
-		Where(dao.PayLog.Columns().PayStatus, consts.PayStatusWait).
-		OmitEmpty().
-		Data(models).Update()
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) (err error) {
+		result, err := s.Model(ctx, &handler.Option{FilterAuth: false}).
+			Fields(
+				dao.PayLog.Columns().TransactionId,
+				dao.PayLog.Columns().PayStatus,
+				dao.PayLog.Columns().PayAt,
+				dao.PayLog.Columns().PayIp,
+				dao.PayLog.Columns().TraceIds,
+				dao.PayLog.Columns().ActualAmount,
+			).
+			Where(dao.PayLog.Columns().Id, models.Id).
+			Where(dao.PayLog.Columns().PayStatus, consts.PayStatusWait).
+			OmitEmpty().
+			Data(models).Update()
+		if err != nil {
+			return
+		}
+
+		ret, err := result.RowsAffected()
+		if err != nil {
+			return
+		}
+
+		if ret == 0 {
+			g.Log().Warningf(ctx, "没有被更新的数据行")
+			return
+		}
+
+		// 回调业务
+		ok = true
+		return service.SysQueue().Outbox(ctx, consts.QueuePayNotifyTopic, fmt.Sprintf("pay:%v", models.Id), &payin.NotifyCallEvent{
+			PayLogId:   models.Id,
+			OrderGroup: models.OrderGroup,
+			OrderSn:    models.OrderSn,
+		})
+	})
 	if err != nil {
-		return
+		ok = false
 	}
+	return
+}
 
-	ret, err := result.RowsAffected()
-	if err != nil {
+// NotifyCall 根据发件箱消息执行支付成功回调业务
+func (s *sPay) NotifyCall(ctx context.Context, in *payin.NotifyCallEvent) (err error) {
+	var models *entity.PayLog
+	if err = s.Model(ctx, &handler.Option{FilterAuth: false}).Where(dao.PayLog.Columns().Id, in.PayLogId).Scan(&models); err != nil {
 		return
 	}
 
-	if ret == 0 {
-		g.Log().Warningf(ctx, "没有被更新的数据行")
+	if models == nil {
+		err = gerror.Newf("支付记录[%v]不存在，无法回调业务订单[%v]", in.PayLogId, in.OrderSn)
 		return
 	}
 
-	// 回调业务
-	payment.NotifyCall(ctx, &payin.NotifyCallFuncInp{Pay: models})
-	return
+	if models.PayStatus != consts.PayStatusOk {
+		err = gerror.Newf("支付记录[%v]未支付成功，不能回调业务订单[%v]", in.PayLogId, in.OrderSn)
+		return
+	}
+	return payment.NotifyCall(ctx, &payin.NotifyCallFuncInp{Pay: models})
 }
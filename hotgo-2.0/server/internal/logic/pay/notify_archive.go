@@ -0,0 +1,203 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pay
+
+// 异步通知存档和重放
+
+import (
+	"context"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/hgorm/handler"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/payin"
+	"hotgo/internal/service"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+type sPayNotify struct{}
+
+func NewPayNotify() *sPayNotify {
+	return &sPayNotify{}
+}
+
+func init() {
+	service.RegisterPayNotify(NewPayNotify())
+}
+
+// Model 支付通知ORM模型
+func (s *sPayNotify) Model(ctx context.Context, option ...*handler.Option) *gdb.Model {
+	return handler.Model(dao.PayNotify.Ctx(ctx), option...)
+}
+
+// List 获取支付通知列表
+func (s *sPayNotify) List(ctx context.Context, in *payin.PayNotifyListInp) (list []*payin.PayNotifyListModel, totalCount int, err error) {
+	cols := dao.PayNotify.Columns()
+	mod := s.Model(ctx)
+
+	// 查询支付方式
+	if in.PayType != "" {
+		mod = mod.Where(cols.PayType, in.PayType)
+	}
+
+	// 查询商户订单号
+	if in.OutTradeNo != "" {
+		mod = mod.Where(cols.OutTradeNo, in.OutTradeNo)
+	}
+
+	// 查询处理状态
+	if in.Status > 0 {
+		mod = mod.Where(cols.Status, in.Status)
+	}
+
+	// 查询创建时间
+	if len(in.CreatedAt) == 2 {
+		mod = mod.WhereBetween(cols.CreatedAt, in.CreatedAt[0], in.CreatedAt[1])
+	}
+
+	totalCount, err = mod.Clone().Count()
+	if err != nil {
+		return
+	}
+
+	if totalCount == 0 {
+		return
+	}
+
+	err = mod.FieldsEx(cols.Header, cols.Body).Page(in.Page, in.PerPage).OrderDesc(cols.Id).Scan(&list)
+	return
+}
+
+// View 获取支付通知详情，包含原始报文
+func (s *sPayNotify) View(ctx context.Context, in *payin.PayNotifyViewInp) (res *payin.PayNotifyViewModel, err error) {
+	if err = s.Model(ctx).WherePri(in.Id).Scan(&res); err != nil {
+		err = gerror.Wrap(err, "获取支付通知详情失败，请稍后重试！")
+		return
+	}
+
+	if res == nil {
+		err = gerror.New("支付通知不存在")
+	}
+	return
+}
+
+// Replay 使用存档的原始报文重新验签和处理失败的支付通知
+func (s *sPayNotify) Replay(ctx context.Context, in *payin.PayNotifyReplayInp) (res *payin.PayNotifyReplayModel, err error) {
+	var (
+		models *entity.PayNotify
+		cols   = dao.PayNotify.Columns()
+	)
+
+	if err = s.Model(ctx).WherePri(in.Id).Scan(&models); err != nil {
+		return
+	}
+
+	if models == nil {
+		err = gerror.New("支付通知不存在")
+		return
+	}
+
+	if models.Status != consts.PayNotifyStatusFail {
+		err = gerror.New("只能重放处理失败的支付通知")
+		return
+	}
+
+	raw := payin.NotifyInp{Body: []byte(models.Body)}
+	if models.Header != "" {
+		if err = gjson.DecodeTo(models.Header, &raw.Header); err != nil {
+			err = gerror.Wrap(err, "解析存档的请求头失败")
+			return
+		}
+	}
+
+	// 先抢占记录，避免重复点击时同一条通知被并发处理
+	result, err := s.Model(ctx).WherePri(models.Id).Where(cols.Status, consts.PayNotifyStatusFail).Data(g.Map{
+		cols.Status:      consts.PayNotifyStatusWait,
+		cols.ReplayCount: gdb.Raw(cols.ReplayCount + "+1"),
+	}).Update()
+	if err != nil {
+		return
+	}
+
+	ret, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if ret == 0 {
+		err = gerror.New("该通知正在处理中，请稍后刷新")
+		return
+	}
+
+	data, duplicate, perr := NewPay().process(ctx, models.PayType, raw, models.ClientIp)
+	if err = finishNotify(ctx, models.Id, data, duplicate, perr); err != nil {
+		return
+	}
+
+	res = &payin.PayNotifyReplayModel{Status: notifyStatus(duplicate, perr)}
+	if perr != nil {
+		res.ErrMsg = perr.Error()
+	}
+	return
+}
+
+// archiveNotify 存档通知的原始报文，返回存档记录ID
+func archiveNotify(ctx context.Context, payType string, raw payin.NotifyInp, clientIp string) (id int64, err error) {
+	header, err := gjson.EncodeString(raw.Header)
+	if err != nil {
+		return
+	}
+
+	return dao.PayNotify.Ctx(ctx).Data(&entity.PayNotify{
+		PayType:  payType,
+		Header:   header,
+		Body:     string(raw.Body),
+		ClientIp: clientIp,
+		TraceId:  gctx.CtxId(ctx),
+		Status:   consts.PayNotifyStatusWait,
+	}).OmitEmptyData().InsertAndGetId()
+}
+
+// finishNotify 记录通知的处理结果
+func finishNotify(ctx context.Context, id int64, data *payin.NotifyModel, duplicate bool, perr error) (err error) {
+	cols := dao.PayNotify.Columns()
+	update := g.Map{
+		cols.Status:      notifyStatus(duplicate, perr),
+		cols.ErrMsg:      "",
+		cols.ProcessedAt: gtime.Now(),
+	}
+
+	if data != nil {
+		update[cols.OutTradeNo] = data.OutTradeNo
+		update[cols.TransactionId] = data.TransactionId
+	}
+
+	if perr != nil {
+		update[cols.ErrMsg] = gstr.StrLimitRune(perr.Error(), 990)
+	}
+
+	_, err = dao.PayNotify.Ctx(ctx).WherePri(id).Data(update).Update()
+	return
+}
+
+// notifyStatus 根据处理结果获取通知处理状态
+func notifyStatus(duplicate bool, perr error) int {
+	if perr != nil {
+		return consts.PayNotifyStatusFail
+	}
+
+	if duplicate {
+		return consts.PayNotifyStatusDuplicate
+	}
+	return consts.PayNotifyStatusSuccess
+}
@@ -0,0 +1,189 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pay
+
+// 订单查询、关闭和掉单补偿
+
+import (
+	"context"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/payment"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/payin"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// QueryOrder 主动查询第三方支付订单，已支付但本地未更新时进行补单
+func (s *sPay) QueryOrder(ctx context.Context, in *payin.PayQueryOrderInp) (res *payin.PayQueryOrderModel, err error) {
+	mod := s.Model(ctx)
+	if in.OutTradeNo != "" {
+		mod = mod.Where(dao.PayLog.Columns().OutTradeNo, in.OutTradeNo)
+	} else {
+		mod = mod.Where(dao.PayLog.Columns().OrderSn, in.OrderSn)
+	}
+
+	var models *entity.PayLog
+	if err = mod.OrderDesc(dao.PayLog.Columns().Id).Scan(&models); err != nil {
+		return
+	}
+
+	if models == nil {
+		err = gerror.New("支付记录不存在，请检查")
+		return
+	}
+
+	query, settled, err := s.sync(ctx, models)
+	if err != nil {
+		return
+	}
+
+	res = &payin.PayQueryOrderModel{
+		OutTradeNo:    models.OutTradeNo,
+		TradeState:    query.TradeState,
+		PayStatus:     models.PayStatus,
+		TransactionId: query.TransactionId,
+		ActualAmount:  query.ActualAmount,
+		Settled:       settled,
+	}
+	return
+}
+
+// CloseOrder 关闭业务订单下所有待支付的第三方订单
+func (s *sPay) CloseOrder(ctx context.Context, in *payin.PayCloseOrderInp) (err error) {
+	var list []*entity.PayLog
+	if err = s.Model(ctx).
+		Where(dao.PayLog.Columns().OrderSn, in.OrderSn).
+		Where(dao.PayLog.Columns().PayStatus, consts.PayStatusWait).
+		Scan(&list); err != nil {
+		return
+	}
+
+	for _, models := range list {
+		// 关闭前先查一次，避免用户已付款但通知还没到达时把订单关掉
+		query, settled, err := s.sync(ctx, models)
+		if err != nil {
+			return err
+		}
+
+		if settled || query.TradeState == consts.TradeStateSuccess || query.TradeState == consts.TradeStateRefund {
+			return gerror.Newf("商户订单号[%v]已支付，无法关闭", models.OutTradeNo)
+		}
+
+		if err = s.close(ctx, models, query.TradeState); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// Compensate 补偿查询超过指定时间仍未收到通知的待支付订单
+// 第三方已支付的进行补单，已关闭、不存在或超过过期时间的关闭本地支付记录
+func (s *sPay) Compensate(ctx context.Context, in *payin.PayCompensateInp) (res *payin.PayCompensateModel, err error) {
+	var (
+		now  = gtime.Now()
+		list []*entity.PayLog
+	)
+
+	if err = s.Model(ctx).
+		Where(dao.PayLog.Columns().PayStatus, consts.PayStatusWait).
+		WhereLTE(dao.PayLog.Columns().CreatedAt, now.Add(-time.Duration(in.Minutes)*time.Minute)).
+		OrderAsc(dao.PayLog.Columns().Id).
+		Limit(in.Limit).
+		Scan(&list); err != nil {
+		return
+	}
+
+	res = new(payin.PayCompensateModel)
+	res.Scanned = len(list)
+
+	expireAt := now.Add(-time.Duration(in.Expire) * time.Minute)
+	for _, models := range list {
+		query, settled, err := s.sync(ctx, models)
+		if err != nil {
+			res.Failed++
+			g.Log().Warningf(ctx, "pay Compensate query outTradeNo:%v err:%+v", models.OutTradeNo, err)
+			continue
+		}
+
+		if settled {
+			res.Settled++
+			continue
+		}
+
+		switch query.TradeState {
+		case consts.TradeStateClosed, consts.TradeStateNotExist:
+			// 未扫码的订单第三方可能一直不存在，等到过期后再关闭，防止用户正在支付
+			if query.TradeState == consts.TradeStateNotExist && models.CreatedAt.After(expireAt) {
+				continue
+			}
+		case consts.TradeStateWait:
+			if models.CreatedAt.After(expireAt) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if err = s.close(ctx, models, query.TradeState); err != nil {
+			res.Failed++
+			g.Log().Warningf(ctx, "pay Compensate close outTradeNo:%v err:%+v", models.OutTradeNo, err)
+			continue
+		}
+		res.Closed++
+	}
+	return
+}
+
+// sync 查询第三方订单状态，第三方已支付时同步到本地
+func (s *sPay) sync(ctx context.Context, models *entity.PayLog) (query *payin.QueryOrderModel, settled bool, err error) {
+	query, err = payment.New(models.PayType).QueryOrder(ctx, payin.QueryOrderInp{Pay: models})
+	if err != nil {
+		return
+	}
+
+	if models.PayStatus != consts.PayStatusWait || query.TradeState != consts.TradeStateSuccess {
+		return
+	}
+
+	if settled, err = s.settle(ctx, models, &payin.NotifyModel{
+		OutTradeNo:    query.OutTradeNo,
+		TransactionId: query.TransactionId,
+		PayAt:         query.PayAt,
+		ActualAmount:  query.ActualAmount,
+	}, ""); err != nil {
+		return
+	}
+
+	if settled {
+		g.Log().Infof(ctx, "pay sync settled outTradeNo:%v, transactionId:%v", models.OutTradeNo, query.TransactionId)
+	}
+	return
+}
+
+// close 关闭第三方订单并将本地待支付记录标记为已关闭
+func (s *sPay) close(ctx context.Context, models *entity.PayLog, tradeState string) (err error) {
+	if tradeState == consts.TradeStateWait {
+		if _, err = payment.New(models.PayType).CloseOrder(ctx, payin.CloseOrderInp{Pay: models}); err != nil {
+			return
+		}
+	}
+
+	_, err = s.Model(ctx).
+		Where(dao.PayLog.Columns().Id, models.Id).
+		Where(dao.PayLog.Columns().PayStatus, consts.PayStatusWait).
+		Data(g.Map{
+			dao.PayLog.Columns().PayStatus: consts.PayStatusClose,
+		}).Update()
+	if err == nil {
+		models.PayStatus = consts.PayStatusClose
+	}
+	return
+}
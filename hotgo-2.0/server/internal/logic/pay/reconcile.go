@@ -0,0 +1,390 @@
+// Package pay
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pay
+
+// 交易对账
+
+import (
+	"context"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/library/hgorm/handler"
+	"hotgo/internal/library/payment"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/payin"
+	"hotgo/internal/service"
+	"math"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+type sPayReconcile struct{}
+
+func NewPayReconcile() *sPayReconcile {
+	return &sPayReconcile{}
+}
+
+func init() {
+	service.RegisterPayReconcile(NewPayReconcile())
+}
+
+// reconcileKey 对账明细的唯一标识，支付按商户订单号，退款按退款单号
+type reconcileKey struct {
+	kind string
+	no   string
+}
+
+// diffKey 对账差异的唯一标识
+type diffKey struct {
+	reconcileKey
+	typ int
+}
+
+func newDiffKey(row *entity.PayReconcile) diffKey {
+	key := diffKey{reconcileKey: reconcileKey{kind: row.Kind, no: row.OutTradeNo}, typ: row.Type}
+	if row.Kind == consts.BillKindRefund {
+		key.no = row.RefundSn
+	}
+	return key
+}
+
+// Model 对账差异ORM模型
+func (s *sPayReconcile) Model(ctx context.Context, option ...*handler.Option) *gdb.Model {
+	return handler.Model(dao.PayReconcile.Ctx(ctx), option...)
+}
+
+// Reconcile 下载指定日期的第三方账单，与本地支付和退款记录逐笔核对
+// 重复执行时会清除该日期未处理的差异后重新生成，已处理的差异保持不变
+func (s *sPayReconcile) Reconcile(ctx context.Context, in *payin.PayReconcileInp) (res *payin.PayReconcileModel, err error) {
+	var (
+		billDate = in.BillDate.StartOfDay()
+		start    = billDate.Clone()
+		end      = billDate.EndOfDay()
+	)
+
+	bill, err := payment.New(in.PayType).DownloadBill(ctx, payin.DownloadBillInp{BillDate: billDate})
+	if err != nil {
+		return
+	}
+
+	pays, err := s.localPays(ctx, in.PayType, start, end, bill.List)
+	if err != nil {
+		return
+	}
+
+	refunds, err := s.localRefunds(ctx, in.PayType, start, end, bill.List)
+	if err != nil {
+		return
+	}
+
+	var (
+		local = make(map[reconcileKey]*entity.PayReconcile, len(pays)+len(refunds))
+		diffs []*entity.PayReconcile
+	)
+
+	for _, v := range pays {
+		local[reconcileKey{kind: consts.BillKindPay, no: v.OutTradeNo}] = v
+	}
+
+	for _, v := range refunds {
+		local[reconcileKey{kind: consts.BillKindRefund, no: v.RefundSn}] = v
+	}
+
+	res = new(payin.PayReconcileModel)
+	res.BillCount = len(bill.List)
+	res.LocalCount = len(local)
+
+	seen := make(map[reconcileKey]struct{}, len(bill.List))
+	for _, item := range bill.List {
+		key := reconcileKey{kind: item.Kind, no: item.OutTradeNo}
+		if item.Kind == consts.BillKindRefund {
+			key.no = item.RefundSn
+		}
+		seen[key] = struct{}{}
+
+		row, ok := local[key]
+		if !ok {
+			diffs = append(diffs, &entity.PayReconcile{
+				Kind:          item.Kind,
+				OutTradeNo:    item.OutTradeNo,
+				TransactionId: item.TransactionId,
+				RefundSn:      item.RefundSn,
+				Type:          consts.PayReconcileTypeLocalMiss,
+				BillAmount:    item.Amount,
+			})
+			continue
+		}
+
+		if math.Abs(row.LocalAmount-item.Amount) >= 0.01 {
+			row.Type = consts.PayReconcileTypeAmountDiff
+			row.BillAmount = item.Amount
+			row.TransactionId = item.TransactionId
+			diffs = append(diffs, row)
+			continue
+		}
+		res.Matched++
+	}
+
+	for key, row := range local {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		// 跨日的记录只核对账单中出现的部分，不在本日期内的本地记录不算渠道缺失
+		if row.CreatedAt == nil || row.CreatedAt.Before(start) || row.CreatedAt.After(end) {
+			continue
+		}
+		row.Type = consts.PayReconcileTypeBillMiss
+		diffs = append(diffs, row)
+	}
+
+	res.DiffCount = len(diffs)
+	err = s.save(ctx, in.PayType, billDate, diffs)
+	return
+}
+
+// localPays 获取本地已支付记录，包含当天支付的和账单中出现的
+func (s *sPayReconcile) localPays(ctx context.Context, payType string, start, end *gtime.Time, items []*payin.BillItem) (list []*entity.PayReconcile, err error) {
+	var outTradeNos []string
+	for _, item := range items {
+		if item.Kind == consts.BillKindPay && item.OutTradeNo != "" {
+			outTradeNos = append(outTradeNos, item.OutTradeNo)
+		}
+	}
+
+	cols := dao.PayLog.Columns()
+	mod := service.Pay().Model(ctx, &handler.Option{FilterAuth: false}).
+		Where(cols.PayType, payType).
+		Where(cols.PayStatus, consts.PayStatusOk)
+
+	if len(outTradeNos) > 0 {
+		mod = mod.Where(mod.Builder().
+			WhereBetween(cols.PayAt, start, end).
+			WhereOrIn(cols.OutTradeNo, outTradeNos))
+	} else {
+		mod = mod.WhereBetween(cols.PayAt, start, end)
+	}
+
+	var logs []*entity.PayLog
+	if err = mod.Scan(&logs); err != nil {
+		return
+	}
+
+	for _, v := range logs {
+		list = append(list, &entity.PayReconcile{
+			Kind:          consts.BillKindPay,
+			OutTradeNo:    v.OutTradeNo,
+			TransactionId: v.TransactionId,
+			OrderSn:       v.OrderSn,
+			LocalAmount:   v.PayAmount,
+			CreatedAt:     v.PayAt,
+		})
+	}
+	return
+}
+
+// localRefunds 获取本地已退款记录，包含当天退款的和账单中出现的
+func (s *sPayReconcile) localRefunds(ctx context.Context, payType string, start, end *gtime.Time, items []*payin.BillItem) (list []*entity.PayReconcile, err error) {
+	var refundSns []string
+	for _, item := range items {
+		if item.Kind == consts.BillKindRefund && item.RefundSn != "" {
+			refundSns = append(refundSns, item.RefundSn)
+		}
+	}
+
+	var refunds []*entity.PayRefund
+	if err = service.PayRefund().Model(ctx, &handler.Option{FilterAuth: false}).
+		Where(dao.PayRefund.Columns().Status, consts.RefundStatusAgree).
+		WhereBetween(dao.PayRefund.Columns().CreatedAt, start, end).
+		Scan(&refunds); err != nil {
+		return
+	}
+
+	refundMap := make(map[string]*entity.PayRefund, len(refunds))
+	orderSns := make([]string, 0, len(refunds))
+	for _, v := range refunds {
+		refundMap[v.OrderSn] = v
+		orderSns = append(orderSns, v.OrderSn)
+	}
+
+	if len(orderSns) == 0 && len(refundSns) == 0 {
+		return
+	}
+
+	cols := dao.PayLog.Columns()
+	mod := service.Pay().Model(ctx, &handler.Option{FilterAuth: false}).
+		Where(cols.PayType, payType).
+		WhereNot(cols.RefundSn, "")
+
+	builder := mod.Builder()
+	if len(orderSns) > 0 {
+		builder = builder.WhereOrIn(cols.OrderSn, orderSns)
+	}
+	if len(refundSns) > 0 {
+		builder = builder.WhereOrIn(cols.RefundSn, refundSns)
+	}
+
+	var logs []*entity.PayLog
+	if err = mod.Where(builder).Scan(&logs); err != nil {
+		return
+	}
+
+	for _, v := range logs {
+		row := &entity.PayReconcile{
+			Kind:          consts.BillKindRefund,
+			OutTradeNo:    v.OutTradeNo,
+			TransactionId: v.TransactionId,
+			RefundSn:      v.RefundSn,
+			OrderSn:       v.OrderSn,
+		}
+
+		refund, ok := refundMap[v.OrderSn]
+		if !ok {
+			if err = service.PayRefund().Model(ctx, &handler.Option{FilterAuth: false}).
+				Where(dao.PayRefund.Columns().OrderSn, v.OrderSn).
+				Where(dao.PayRefund.Columns().Status, consts.RefundStatusAgree).
+				OrderDesc(dao.PayRefund.Columns().Id).
+				Scan(&refund); err != nil {
+				return
+			}
+		}
+
+		// 本地没有成功的退款记录时按缺失处理
+		if refund == nil {
+			continue
+		}
+
+		row.LocalAmount = refund.RefundMoney
+		row.CreatedAt = refund.CreatedAt
+		list = append(list, row)
+	}
+	return
+}
+
+// save 保存对账差异
+func (s *sPayReconcile) save(ctx context.Context, payType string, billDate *gtime.Time, diffs []*entity.PayReconcile) (err error) {
+	cols := dao.PayReconcile.Columns()
+	return g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) (err error) {
+		if _, err = s.Model(ctx).
+			Where(cols.PayType, payType).
+			Where(cols.BillDate, billDate).
+			Where(cols.Status, consts.PayReconcileStatusWait).
+			Delete(); err != nil {
+			return
+		}
+
+		var processed []*entity.PayReconcile
+		if err = s.Model(ctx).
+			Fields(cols.Kind, cols.OutTradeNo, cols.RefundSn, cols.Type).
+			Where(cols.PayType, payType).
+			Where(cols.BillDate, billDate).
+			Scan(&processed); err != nil {
+			return
+		}
+
+		handled := make(map[diffKey]struct{}, len(processed))
+		for _, v := range processed {
+			handled[newDiffKey(v)] = struct{}{}
+		}
+
+		var data []*entity.PayReconcile
+		for _, v := range diffs {
+			if _, ok := handled[newDiffKey(v)]; ok {
+				continue
+			}
+			v.PayType = payType
+			v.BillDate = billDate
+			v.Status = consts.PayReconcileStatusWait
+			v.CreatedAt = nil
+			data = append(data, v)
+		}
+
+		if len(data) == 0 {
+			return
+		}
+		_, err = s.Model(ctx).Data(data).OmitEmptyData().Insert()
+		return
+	})
+}
+
+// List 获取对账差异列表
+func (s *sPayReconcile) List(ctx context.Context, in *payin.PayReconcileListInp) (list []*payin.PayReconcileListModel, totalCount int, err error) {
+	cols := dao.PayReconcile.Columns()
+	mod := s.Model(ctx)
+
+	// 查询支付方式
+	if in.PayType != "" {
+		mod = mod.Where(cols.PayType, in.PayType)
+	}
+
+	// 查询账单日期
+	if in.BillDate != nil {
+		mod = mod.Where(cols.BillDate, in.BillDate.StartOfDay())
+	}
+
+	// 查询商户订单号
+	if in.OutTradeNo != "" {
+		mod = mod.Where(cols.OutTradeNo, in.OutTradeNo)
+	}
+
+	// 查询差异类型
+	if in.Type > 0 {
+		mod = mod.Where(cols.Type, in.Type)
+	}
+
+	// 查询处理状态
+	if in.Status > 0 {
+		mod = mod.Where(cols.Status, in.Status)
+	}
+
+	// 查询创建时间
+	if len(in.CreatedAt) == 2 {
+		mod = mod.WhereBetween(cols.CreatedAt, in.CreatedAt[0], in.CreatedAt[1])
+	}
+
+	totalCount, err = mod.Clone().Count()
+	if err != nil {
+		return
+	}
+
+	if totalCount == 0 {
+		return
+	}
+
+	err = mod.Page(in.Page, in.PerPage).OrderDesc(cols.Id).Scan(&list)
+	return
+}
+
+// Handle 标记对账差异已处理
+func (s *sPayReconcile) Handle(ctx context.Context, in *payin.PayReconcileHandleInp) (err error) {
+	cols := dao.PayReconcile.Columns()
+	result, err := s.Model(ctx).
+		Where(cols.Id, in.Id).
+		Where(cols.Status, consts.PayReconcileStatusWait).
+		Data(g.Map{
+			cols.Status:    consts.PayReconcileStatusProcessed,
+			cols.Remark:    in.Remark,
+			cols.HandledBy: contexts.GetUserId(ctx),
+			cols.HandledAt: gtime.Now(),
+		}).Update()
+	if err != nil {
+		return
+	}
+
+	ret, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if ret == 0 {
+		err = gerror.New("差异记录不存在或已被处理")
+	}
+	return
+}
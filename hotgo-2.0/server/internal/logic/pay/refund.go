@@ -139,6 +139,43 @@ func (s *sPayRefund) Refund(ctx context.Context, in *payin.PayRefundInp) (res *p
 	return
 }
 
+// Query 查询第三方退款状态，退款成功后回写退款交易号
+func (s *sPayRefund) Query(ctx context.Context, in *payin.PayRefundQueryInp) (res *payin.PayRefundQueryModel, err error) {
+	var models *entity.PayLog
+	if err = service.Pay().Model(ctx).Where(dao.PayLog.Columns().OrderSn, in.OrderSn).Scan(&models); err != nil {
+		return
+	}
+
+	if models == nil {
+		err = gerror.Newf("业务订单号[%v]不存在支付记录，请检查", in.OrderSn)
+		return
+	}
+
+	if models.RefundSn == "" {
+		err = gerror.Newf("业务订单号[%v]没有发起过退款", in.OrderSn)
+		return
+	}
+
+	query, err := payment.New(models.PayType).QueryRefund(ctx, payin.QueryRefundInp{Pay: models, RefundSn: models.RefundSn})
+	if err != nil {
+		return
+	}
+
+	if query.RefundState == consts.RefundStateSuccess && query.RefundId != "" {
+		if _, err = s.Model(ctx).
+			Where(dao.PayRefund.Columns().OrderSn, in.OrderSn).
+			Data(g.Map{
+				dao.PayRefund.Columns().RefundTradeNo: query.RefundId,
+			}).Update(); err != nil {
+			return
+		}
+	}
+
+	res = new(payin.PayRefundQueryModel)
+	res.QueryRefundModel = *query
+	return
+}
+
 // List 获取交易退款列表
 func (s *sPayRefund) List(ctx context.Context, in *payin.PayRefundListInp) (list []*payin.PayRefundListModel, totalCount int, err error) {
 	mod := s.Model(ctx)
@@ -9,15 +9,22 @@ import (
 	"context"
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/consts"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/cache"
 	"hotgo/internal/library/contexts"
 	"hotgo/internal/library/dict"
 	"hotgo/internal/library/hgorm/handler"
 	"hotgo/internal/library/storager"
 	"hotgo/internal/model"
+	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/sysin"
 	"hotgo/internal/service"
+	"hotgo/internal/websocket"
 	"hotgo/utility/format"
+	"hotgo/utility/simple"
 )
 
 type sSysAttachment struct{}
@@ -38,12 +45,35 @@ func (s *sSysAttachment) Model(ctx context.Context, option ...*handler.Option) *
 
 // Delete 删除附件
 func (s *sSysAttachment) Delete(ctx context.Context, in *sysin.AttachmentDeleteInp) (err error) {
+	var models *entity.SysAttachment
+	if err = s.Model(ctx).WherePri(in.Id).Scan(&models); err != nil {
+		err = gerror.Wrap(err, "获取附件信息失败，请稍后重试！")
+		return
+	}
+
+	if models == nil {
+		err = gerror.New("附件不存在或已被删除")
+		return
+	}
+
 	if _, err = s.Model(ctx).WherePri(in.Id).Delete(); err != nil {
 		err = gerror.Wrap(err, "删除附件失败，请稍后重试！")
+		return
 	}
+
+	s.releaseFiles(ctx, models)
 	return
 }
 
+// releaseFiles 删除不再被引用的存储文件，失败时只记录日志，不影响附件记录的删除
+func (s *sSysAttachment) releaseFiles(ctx context.Context, list ...*entity.SysAttachment) {
+	for _, v := range list {
+		if err := storager.ReleaseFile(ctx, v); err != nil {
+			g.Log().Warningf(ctx, "release attachment file failed, drive:%v, path:%v, err:%+v", v.Drive, v.Path, err)
+		}
+	}
+}
+
 // View 获取附件信息
 func (s *sSysAttachment) View(ctx context.Context, in *sysin.AttachmentViewInp) (res *sysin.AttachmentViewModel, err error) {
 	if err = s.Model(ctx).WherePri(in.Id).Scan(&res); err != nil {
@@ -108,10 +138,23 @@ func (s *sSysAttachment) List(ctx context.Context, in *sysin.AttachmentListInp)
 
 // ClearKind 清空上传类型
 func (s *sSysAttachment) ClearKind(ctx context.Context, in *sysin.AttachmentClearKindInp) (err error) {
-	memberId := contexts.GetUserId(ctx)
-	if _, err = s.Model(ctx).Where(dao.SysAttachment.Columns().MemberId, memberId).Where(dao.SysAttachment.Columns().Kind, in.Kind).Delete(); err != nil {
+	var (
+		memberId = contexts.GetUserId(ctx)
+		mod      = s.Model(ctx).Where(dao.SysAttachment.Columns().MemberId, memberId).Where(dao.SysAttachment.Columns().Kind, in.Kind)
+		list     []*entity.SysAttachment
+	)
+
+	if err = mod.Clone().Scan(&list); err != nil {
+		err = gerror.Wrap(err, "获取附件列表失败，请稍后重试！")
+		return
+	}
+
+	if _, err = mod.Delete(); err != nil {
 		err = gerror.Wrap(err, "删除附件上传类型失败，请稍后重试！")
+		return
 	}
+
+	s.releaseFiles(ctx, list...)
 	return
 }
 
@@ -126,3 +169,145 @@ func (s *sSysAttachment) AttachmentKindOption(ctx context.Context) (opts []*mode
 	opts = append(opts, dict.GenSuccessOption(storager.KindOther, "其他", "PlusOutlined"))
 	return
 }
+
+// Usage 获取存储用量
+func (s *sSysAttachment) Usage(ctx context.Context, in *sysin.AttachmentUsageInp) (res *sysin.AttachmentUsageModel, err error) {
+	memberId := contexts.GetUserId(ctx)
+
+	// 超管允许查看指定用户的用量
+	if service.AdminMember().VerifySuperId(ctx, memberId) && in.MemberId > 0 {
+		memberId = in.MemberId
+	}
+
+	usage, err := storager.GetUsage(ctx, memberId, contexts.GetModule(ctx))
+	if err != nil {
+		return
+	}
+
+	res = &sysin.AttachmentUsageModel{StorageUsage: usage}
+	return
+}
+
+// Collect 回收超过指定天数且未被业务数据引用的附件
+// 每次从上次的进度开始检查limit条记录，全部检查完后从头开始，避免被引用的附件一直占用检查名额
+func (s *sSysAttachment) Collect(ctx context.Context, in *sysin.AttachmentCollectInp) (res *sysin.AttachmentCollectModel, err error) {
+	if len(storager.Refs()) == 0 {
+		err = gerror.New("未声明任何附件引用，为避免误删已停止回收")
+		return
+	}
+
+	if in.Days < 1 {
+		err = gerror.New("回收天数必须大于0")
+		return
+	}
+
+	if in.Limit < 1 {
+		in.Limit = 500
+	}
+
+	res = new(sysin.AttachmentCollectModel)
+
+	// 申请直传后一直没有完成的文件没有附件记录，需要单独清理
+	if res.Expired, err = storager.CleanDirectUploads(ctx, in.Limit); err != nil {
+		err = gerror.Wrap(err, "清理未完成的直传文件失败")
+		return
+	}
+
+	var (
+		cols = dao.SysAttachment.Columns()
+		list []*entity.SysAttachment
+	)
+
+	cursor, err := cache.Instance().Get(ctx, consts.CacheAttachmentGC)
+	if err != nil {
+		return
+	}
+
+	err = dao.SysAttachment.Ctx(ctx).
+		WhereGT(cols.Id, cursor.Int64()).
+		WhereLT(cols.UpdatedAt, gtime.Now().AddDate(0, 0, -in.Days)).
+		OrderAsc(cols.Id).
+		Limit(in.Limit).
+		Scan(&list)
+	if err != nil {
+		err = gerror.Wrap(err, "获取待回收附件失败")
+		return
+	}
+
+	for _, v := range list {
+		res.Scanned++
+
+		referenced, err := storager.IsReferenced(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+
+		if referenced {
+			res.Referenced++
+			continue
+		}
+
+		if _, err = dao.SysAttachment.Ctx(ctx).WherePri(v.Id).Delete(); err != nil {
+			return nil, gerror.Wrap(err, "删除附件失败")
+		}
+
+		s.releaseFiles(ctx, v)
+		res.Deleted++
+		res.FreedSize += v.Size
+	}
+
+	// 本轮未取满说明已检查到末尾，下次从头开始
+	next := int64(0)
+	if len(list) == in.Limit {
+		next = list[len(list)-1].Id
+	}
+
+	if err = cache.Instance().Set(ctx, consts.CacheAttachmentGC, next, 0); err != nil {
+		return
+	}
+	return
+}
+
+// Migrate 在后台将附件从源驱动迁移到目标驱动，进度通过websocket推送给操作人
+func (s *sSysAttachment) Migrate(ctx context.Context, in *sysin.AttachmentMigrateInp) (err error) {
+	params := &storager.MigrateParams{
+		From:         in.From,
+		To:           in.To,
+		Concurrency:  in.Concurrency,
+		DeleteSource: in.DeleteSource,
+	}
+
+	if err = storager.CheckMigrate(ctx, params); err != nil {
+		return
+	}
+
+	memberId := contexts.GetUserId(ctx)
+	notify := func(progress *storager.MigrateProgress) {
+		websocket.SendToUser(memberId, &websocket.WResponse{
+			Event: "admin/attachment/migrate",
+			Data:  progress,
+		})
+	}
+
+	// 迁移耗时较长，不能随请求结束而取消
+	simple.SafeGo(context.WithoutCancel(ctx), func(ctx context.Context) {
+		res, err := storager.Migrate(ctx, params, notify)
+		if err != nil {
+			g.Log().Warningf(ctx, "attachment migrate %v -> %v err:%+v", in.From, in.To, err)
+			return
+		}
+		g.Log().Infof(ctx, "attachment migrate %v -> %v finished, success:%v, failed:%v, skipped:%v", in.From, in.To, res.Success, res.Failed, res.Skipped)
+	})
+	return
+}
+
+// MigrateStatus 获取最近一次存储迁移的进度
+func (s *sSysAttachment) MigrateStatus(ctx context.Context, _ *sysin.AttachmentMigrateStatusInp) (res *sysin.AttachmentMigrateStatusModel, err error) {
+	progress, err := storager.GetMigrateProgress(ctx)
+	if err != nil {
+		return
+	}
+
+	res = &sysin.AttachmentMigrateStatusModel{MigrateProgress: progress}
+	return
+}
@@ -38,6 +38,7 @@ func NewSysConfig() *sSysConfig {
 
 func init() {
 	service.RegisterSysConfig(NewSysConfig())
+	storager.RegisterRef(dao.SysConfig.Table(), dao.SysConfig.Columns().Value, storager.RefTypeUrl)
 }
 
 // InitConfig 初始化系统配置
@@ -16,6 +16,7 @@ import (
 	"hotgo/internal/service"
 	"hotgo/utility/simple"
 	"hotgo/utility/validate"
+	"strings"
 
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -31,9 +32,13 @@ func NewSysCron() *sSysCron {
 
 func init() {
 	service.RegisterSysCron(NewSysCron())
+	cron.RegisterRecordHandler(service.SysCron().RecordExecute)
 }
 
 func (s *sSysCron) StartCron(ctx context.Context) {
+	// 节点标签需要在每个节点的配置文件中单独设置，未设置时使用主机名
+	cron.SetNodeLabel(g.Cfg().MustGet(ctx, "system.cronNodeLabel").String())
+
 	var list []*entity.SysCron
 	if err := dao.SysCron.Ctx(ctx).
 		Where("status", consts.StatusEnabled).
@@ -47,6 +52,8 @@ func (s *sSysCron) StartCron(ctx context.Context) {
 		cron.Logger().Fatalf(ctx, "定时任务启动失败, err . %v", err)
 		return
 	}
+
+	s.startLogCleanup(ctx)
 }
 
 // Delete 删除
@@ -78,6 +85,38 @@ func (s *sSysCron) Edit(ctx context.Context, in *sysin.CronEditInp) (err error)
 		return
 	}
 
+	if in.ClusterMode == 0 {
+		in.ClusterMode = consts.CronClusterAll
+	}
+
+	if in.ClusterMode == consts.CronClusterPinned && in.NodeLabel == "" {
+		err = gerror.New("指定节点执行时，节点标签不能为空")
+		return
+	}
+
+	if in.Timeout < 0 {
+		err = gerror.New("执行超时不能小于0")
+		return
+	}
+
+	if in.Retry < 0 || in.Retry > 10 {
+		err = gerror.New("失败重试次数需在0~10之间")
+		return
+	}
+
+	if in.Alert == 0 {
+		in.Alert = consts.StatusDisable
+	}
+
+	if in.AlertEmail != "" {
+		for _, email := range strings.Split(in.AlertEmail, consts.CronSplitStr) {
+			if !validate.IsEmail(strings.TrimSpace(email)) {
+				err = gerror.Newf("通知邮箱格式不正确：%v", email)
+				return
+			}
+		}
+	}
+
 	// 修改
 	if in.Id > 0 {
 		if _, err = dao.SysCron.Ctx(ctx).Where("id", in.Id).Data(in).Update(); err != nil {
@@ -197,6 +236,11 @@ func (s *sSysCron) List(ctx context.Context, in *sysin.CronListInp) (list []*sys
 
 	for _, v := range list {
 		v.GroupName, _ = s.GetName(ctx, v.GroupId)
+		v.LastExecute, _ = cron.GetLastRecord(ctx, &v.SysCron)
+	}
+
+	if err = s.fillSuccessRate(ctx, list); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
 	}
 	return
 }
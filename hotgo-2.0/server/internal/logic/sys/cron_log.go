@@ -0,0 +1,231 @@
+// Package sys
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sys
+
+import (
+	"context"
+	"fmt"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/cron"
+	"hotgo/internal/library/ems"
+	"hotgo/internal/model/do"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/sysin"
+	"hotgo/internal/service"
+	"hotgo/internal/websocket"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/os/gtimer"
+)
+
+// 执行记录保留天数
+const cronLogKeepDays = 30
+
+// 成功率统计的时间范围
+const cronStatDays = 7
+
+// RecordExecute 保存任务执行记录，失败时按任务配置发送通知
+func (s *sSysCron) RecordExecute(ctx context.Context, sysCron *entity.SysCron, record *cron.ExecuteRecord) {
+	data := &do.SysCronLog{
+		CronId:     sysCron.Id,
+		Title:      sysCron.Title,
+		Name:       sysCron.Name,
+		Node:       record.Node,
+		Status:     record.Status,
+		Attempts:   record.Attempts,
+		Error:      record.Err,
+		Output:     record.Output,
+		StartedAt:  record.At,
+		FinishedAt: record.EndAt,
+		Took:       record.Took,
+		CreatedAt:  gtime.Now(),
+	}
+
+	if _, err := dao.SysCronLog.Ctx(ctx).Data(data).Insert(); err != nil {
+		cron.Logger().Warningf(ctx, "cron %v save execute record err:%+v", sysCron.Name, err)
+	}
+
+	if record.Status != consts.CronLogStatusSuccess && sysCron.Alert == consts.StatusEnabled {
+		s.alert(ctx, sysCron, record)
+	}
+}
+
+// alert 发送任务执行失败通知
+func (s *sSysCron) alert(ctx context.Context, sysCron *entity.SysCron, record *cron.ExecuteRecord) {
+	var (
+		title   = fmt.Sprintf("定时任务执行失败：%v", sysCron.Title)
+		content = fmt.Sprintf("任务：%v(%v)<br/>节点：%v<br/>开始时间：%v<br/>耗时：%vms<br/>执行次数：%v<br/>错误信息：%v",
+			sysCron.Title, sysCron.Name, record.Node, record.At, record.Took, record.Attempts, record.Err)
+	)
+
+	// 邮件通知
+	if sysCron.AlertEmail != "" {
+		config, err := service.SysConfig().GetSmtp(ctx)
+		if err == nil {
+			err = ems.Send(config, strings.ReplaceAll(sysCron.AlertEmail, consts.CronSplitStr, ";"), title, content)
+		}
+		if err != nil {
+			cron.Logger().Warningf(ctx, "cron %v send alert email err:%+v", sysCron.Name, err)
+		}
+	}
+
+	// 私信通知超管
+	receiver, err := s.superMemberIds(ctx)
+	if err != nil || len(receiver) == 0 {
+		return
+	}
+
+	notice := &entity.AdminNotice{
+		Title:     title,
+		Type:      consts.NoticeTypeLetter,
+		Content:   content,
+		Receiver:  gjson.New(receiver),
+		Remark:    "定时任务失败通知",
+		Status:    consts.StatusEnabled,
+		CreatedAt: gtime.Now(),
+		UpdatedAt: gtime.Now(),
+	}
+
+	notice.Id, err = dao.AdminNotice.Ctx(ctx).Data(notice).OmitEmptyData().InsertAndGetId()
+	if err != nil {
+		cron.Logger().Warningf(ctx, "cron %v save alert notice err:%+v", sysCron.Name, err)
+		return
+	}
+
+	response := &websocket.WResponse{
+		Event: "notice",
+		Data:  notice,
+	}
+	for _, id := range receiver {
+		websocket.SendToUser(id, response)
+	}
+}
+
+// superMemberIds 获取超管用户ID
+func (s *sSysCron) superMemberIds(ctx context.Context) (ids []int64, err error) {
+	roleId, err := dao.AdminRole.Ctx(ctx).Fields(dao.AdminRole.Columns().Id).Where(dao.AdminRole.Columns().Key, consts.SuperRoleKey).Value()
+	if err != nil || roleId.IsEmpty() {
+		return
+	}
+
+	array, err := dao.AdminMember.Ctx(ctx).Fields(dao.AdminMember.Columns().Id).Where(dao.AdminMember.Columns().RoleId, roleId).Array()
+	if err != nil {
+		return
+	}
+
+	for _, v := range array {
+		ids = append(ids, v.Int64())
+	}
+	return
+}
+
+// startLogCleanup 定期清理过期的执行记录
+func (s *sSysCron) startLogCleanup(ctx context.Context) {
+	gtimer.AddSingleton(ctx, time.Hour, func(ctx context.Context) {
+		before := gtime.Now().AddDate(0, 0, -cronLogKeepDays)
+		if _, err := dao.SysCronLog.Ctx(ctx).WhereLT(dao.SysCronLog.Columns().StartedAt, before).Delete(); err != nil {
+			cron.Logger().Warningf(ctx, "cron clean execute record err:%+v", err)
+		}
+	})
+}
+
+// LogList 获取执行记录列表
+func (s *sSysCron) LogList(ctx context.Context, in *sysin.CronLogListInp) (list []*sysin.CronLogListModel, totalCount int, err error) {
+	var (
+		cols = dao.SysCronLog.Columns()
+		mod  = dao.SysCronLog.Ctx(ctx)
+	)
+
+	if in.CronId > 0 {
+		mod = mod.Where(cols.CronId, in.CronId)
+	}
+
+	if in.Status > 0 {
+		mod = mod.Where(cols.Status, in.Status)
+	}
+
+	if in.Node != "" {
+		mod = mod.Where(cols.Node, in.Node)
+	}
+
+	if len(in.StartedAt) == 2 {
+		mod = mod.WhereBetween(cols.StartedAt, in.StartedAt[0], in.StartedAt[1])
+	}
+
+	totalCount, err = mod.Count()
+	if err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+
+	if totalCount == 0 {
+		return
+	}
+
+	if err = mod.FieldsEx(cols.Output).Page(in.Page, in.PerPage).OrderDesc(cols.Id).Scan(&list); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+	}
+	return
+}
+
+// LogView 获取执行记录详情
+func (s *sSysCron) LogView(ctx context.Context, in *sysin.CronLogViewInp) (res *sysin.CronLogViewModel, err error) {
+	if err = dao.SysCronLog.Ctx(ctx).Where(dao.SysCronLog.Columns().Id, in.Id).Scan(&res); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+
+	if res == nil {
+		err = gerror.New("执行记录不存在或已被清理")
+	}
+	return
+}
+
+// fillSuccessRate 统计任务近期的执行次数和成功率
+func (s *sSysCron) fillSuccessRate(ctx context.Context, list []*sysin.CronListModel) (err error) {
+	if len(list) == 0 {
+		return
+	}
+
+	var (
+		cols = dao.SysCronLog.Columns()
+		ids  = make([]int64, 0, len(list))
+		stat []struct {
+			CronId       int64
+			RunCount     int
+			SuccessCount int
+		}
+	)
+
+	for _, v := range list {
+		ids = append(ids, v.Id)
+	}
+
+	err = dao.SysCronLog.Ctx(ctx).
+		Fields(cols.CronId, "COUNT(1) AS run_count", fmt.Sprintf("SUM(CASE WHEN %s = %d THEN 1 ELSE 0 END) AS success_count", cols.Status, consts.CronLogStatusSuccess)).
+		WhereIn(cols.CronId, ids).
+		WhereGTE(cols.StartedAt, gtime.Now().AddDate(0, 0, -cronStatDays)).
+		Group(cols.CronId).
+		Scan(&stat)
+	if err != nil {
+		return
+	}
+
+	for _, v := range list {
+		for _, st := range stat {
+			if st.CronId == v.Id && st.RunCount > 0 {
+				v.RunCount = st.RunCount
+				v.SuccessRate = float64(st.SuccessCount*10000/st.RunCount) / 100
+			}
+		}
+	}
+	return
+}
@@ -14,6 +14,7 @@ import (
 	"hotgo/internal/library/hgorm"
 	"hotgo/internal/library/hgorm/handler"
 	"hotgo/internal/library/hgorm/hook"
+	"hotgo/internal/library/storager"
 	"hotgo/internal/model/input/form"
 	"hotgo/internal/model/input/sysin"
 	"hotgo/internal/service"
@@ -37,6 +38,12 @@ func NewSysCurdDemo() *sSysCurdDemo {
 
 func init() {
 	service.RegisterSysCurdDemo(NewSysCurdDemo())
+
+	// 声明引用附件的字段，避免被附件回收任务删除
+	cols := dao.SysGenCurdDemo.Columns()
+	for _, column := range []string{cols.Content, cols.Image, cols.Attachfile} {
+		storager.RegisterRef(dao.SysGenCurdDemo.Table(), column, storager.RefTypeUrl)
+	}
 }
 
 // Model CURD列表ORM模型
@@ -0,0 +1,200 @@
+// Package sys
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sys
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/library/queue"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/sysin"
+	"hotgo/internal/service"
+)
+
+type sSysQueue struct{}
+
+func NewSysQueue() *sSysQueue {
+	return &sSysQueue{}
+}
+
+func init() {
+	service.RegisterSysQueue(NewSysQueue())
+	queue.RegisterFailHandler(service.SysQueue().RecordFail)
+}
+
+// Model 队列失败消息ORM模型
+func (s *sSysQueue) Model(ctx context.Context) *gdb.Model {
+	return dao.SysQueueFail.Ctx(ctx)
+}
+
+// Stats 获取主题统计
+func (s *sSysQueue) Stats(ctx context.Context) (res *sysin.QueueStatsModel, err error) {
+	conf := queue.GetConfig()
+	res = &sysin.QueueStatsModel{
+		Driver: conf.Driver,
+		Group:  conf.GroupName,
+	}
+
+	if res.List, err = queue.GetTopicStats(ctx); err != nil {
+		err = gerror.Wrap(err, "获取队列统计失败，请检查redis是否可用！")
+		return
+	}
+
+	res.OutboxPending, err = dao.SysQueueOutbox.Ctx(ctx).WhereNot(dao.SysQueueOutbox.Columns().Status, consts.QueueOutboxStatusSent).Count()
+	return
+}
+
+// ResetStats 重置主题统计
+func (s *sSysQueue) ResetStats(ctx context.Context, in *sysin.QueueResetStatsInp) (err error) {
+	return queue.ResetTopicStats(ctx, in.Topic)
+}
+
+// RecordFail 记录消费失败的消息
+func (s *sSysQueue) RecordFail(ctx context.Context, topic string, mqMsg queue.MqMsg, err error, dead bool) {
+	status := consts.QueueFailStatusFailed
+	if dead {
+		status = consts.QueueFailStatusDead
+	}
+
+	data := entity.SysQueueFail{
+		Topic:  topic,
+		MsgId:  mqMsg.MsgId,
+		MsgKey: mqMsg.Key,
+		Body:   mqMsg.BodyString(),
+		Status: status,
+	}
+
+	if err != nil {
+		data.Error = err.Error()
+	}
+
+	if _, err = s.Model(ctx).Data(data).OmitEmptyData().Insert(); err != nil {
+		g.Log().Warningf(ctx, "queue RecordFail topic:%v, msgId:%v insert err:%+v", topic, mqMsg.MsgId, err)
+	}
+}
+
+// FailList 获取失败消息列表
+func (s *sSysQueue) FailList(ctx context.Context, in *sysin.QueueFailListInp) (list []*sysin.QueueFailListModel, totalCount int, err error) {
+	var (
+		mod  = s.Model(ctx)
+		cols = dao.SysQueueFail.Columns()
+	)
+
+	if in.Topic != "" {
+		mod = mod.Where(cols.Topic, in.Topic)
+	}
+
+	if in.MsgId != "" {
+		mod = mod.Where(cols.MsgId, in.MsgId)
+	}
+
+	if in.Status > 0 {
+		mod = mod.Where(cols.Status, in.Status)
+	}
+
+	if len(in.CreatedAt) == 2 {
+		mod = mod.WhereBetween(cols.CreatedAt, in.CreatedAt[0], in.CreatedAt[1])
+	}
+
+	totalCount, err = mod.Clone().Count()
+	if err != nil {
+		err = gerror.Wrap(err, "获取失败消息数据行失败！")
+		return
+	}
+
+	if totalCount == 0 {
+		return
+	}
+
+	if err = mod.Fields(sysin.QueueFailListModel{}).Page(in.Page, in.PerPage).OrderDesc(cols.Id).Scan(&list); err != nil {
+		err = gerror.Wrap(err, "获取失败消息列表失败！")
+	}
+	return
+}
+
+// FailView 获取失败消息详情
+func (s *sSysQueue) FailView(ctx context.Context, in *sysin.QueueFailViewInp) (res *sysin.QueueFailViewModel, err error) {
+	if err = s.Model(ctx).WherePri(in.Id).Scan(&res); err != nil {
+		err = gerror.Wrap(err, "获取失败消息详情失败，请稍后重试！")
+	}
+	return
+}
+
+// Replay 重放失败消息，将原消息内容和幂等键重新推送到原主题
+// 推送前先将记录抢占为已重放，同一条消息不会被重复重放
+func (s *sSysQueue) Replay(ctx context.Context, in *sysin.QueueReplayInp) (err error) {
+	var (
+		list []*entity.SysQueueFail
+		cols = dao.SysQueueFail.Columns()
+		wait = []int{consts.QueueFailStatusFailed, consts.QueueFailStatusDead}
+	)
+
+	if err = s.Model(ctx).WherePri(in.Id).WhereIn(cols.Status, wait).Scan(&list); err != nil {
+		return
+	}
+
+	if len(list) == 0 {
+		err = gerror.New("没有可重放的消息")
+		return
+	}
+
+	for _, v := range list {
+		result, err := s.Model(ctx).WherePri(v.Id).Where(cols.Status, v.Status).Data(g.Map{
+			cols.Status:    consts.QueueFailStatusReplayed,
+			cols.HandledBy: contexts.GetUserId(ctx),
+			cols.HandledAt: gtime.Now(),
+		}).Update()
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		// 已被其他请求重放或丢弃
+		if affected == 0 {
+			continue
+		}
+
+		if v.MsgKey != "" {
+			err = queue.PushWithKey(v.Topic, v.MsgKey, v.Body)
+		} else {
+			err = queue.Push(v.Topic, v.Body)
+		}
+
+		if err != nil {
+			// 推送失败时恢复原状态，以便再次重放
+			if _, rollbackErr := s.Model(ctx).WherePri(v.Id).Data(cols.Status, v.Status).Update(); rollbackErr != nil {
+				g.Log().Warningf(ctx, "queue Replay id:%v restore status err:%+v", v.Id, rollbackErr)
+			}
+			return gerror.Wrapf(err, "重放消息[%v]失败", v.Id)
+		}
+
+		if _, err = s.Model(ctx).WherePri(v.Id).Data(cols.ReplayCount, gdb.Raw(cols.ReplayCount+"+1")).Update(); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// Discard 丢弃失败消息
+func (s *sSysQueue) Discard(ctx context.Context, in *sysin.QueueDiscardInp) (err error) {
+	cols := dao.SysQueueFail.Columns()
+	_, err = s.Model(ctx).WherePri(in.Id).Data(g.Map{
+		cols.Status:    consts.QueueFailStatusDiscarded,
+		cols.HandledBy: contexts.GetUserId(ctx),
+		cols.HandledAt: gtime.Now(),
+	}).Update()
+	return
+}
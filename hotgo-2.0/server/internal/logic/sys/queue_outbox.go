@@ -0,0 +1,154 @@
+// Package sys
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sys
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/os/gtimer"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/gogf/gf/v2/util/guid"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/queue"
+	"hotgo/internal/model/entity"
+	"time"
+)
+
+const (
+	outboxRelayBatch     = 100             // 每轮最多投递的消息数量
+	outboxSendingTimeout = 5 * time.Minute // 投递中的消息超过该时长未完成，视为中继异常退出，重新投递
+	outboxMaxBackoff     = 300             // 投递失败后的最大重试间隔(秒)
+	outboxRetentionDays  = 7               // 已投递消息的保留天数
+)
+
+// Outbox 将消息写入发件箱
+// 需要在业务事务中使用携带事务的ctx调用，保证消息与业务数据同时提交或回滚，事务提交后由发件箱中继投递到消息队列
+// key为消息幂等键，同一业务消息应保持不变，为空时自动生成
+func (s *sSysQueue) Outbox(ctx context.Context, topic, key string, data interface{}) (err error) {
+	if topic == "" {
+		err = gerror.New("消息主题不能为空")
+		return
+	}
+
+	if key == "" {
+		key = guid.S()
+	}
+
+	_, err = dao.SysQueueOutbox.Ctx(ctx).Data(entity.SysQueueOutbox{
+		Topic:  topic,
+		MsgKey: key,
+		Body:   gconv.String(data),
+		Status: consts.QueueOutboxStatusPending,
+		NextAt: gtime.Now(),
+	}).OmitEmptyData().Insert()
+	if err != nil {
+		err = gerror.Wrapf(err, "写入发件箱失败, topic:%v, key:%v", topic, key)
+	}
+	return
+}
+
+// StartOutboxRelay 启动发件箱中继，定时将已提交的消息投递到消息队列
+func (s *sSysQueue) StartOutboxRelay(ctx context.Context) {
+	gtimer.AddSingleton(ctx, time.Second, func(ctx context.Context) {
+		if err := s.RelayOutbox(ctx); err != nil {
+			queue.Logger().Warningf(ctx, "queue RelayOutbox err:%+v", err)
+		}
+	})
+
+	gtimer.AddSingleton(ctx, time.Hour, func(ctx context.Context) {
+		cols := dao.SysQueueOutbox.Columns()
+		_, err := dao.SysQueueOutbox.Ctx(ctx).
+			Where(cols.Status, consts.QueueOutboxStatusSent).
+			WhereLT(cols.SentAt, gtime.Now().AddDate(0, 0, -outboxRetentionDays)).
+			Delete()
+		if err != nil {
+			queue.Logger().Warningf(ctx, "queue clean outbox err:%+v", err)
+		}
+	})
+}
+
+// RelayOutbox 投递一批发件箱中待投递的消息，投递语义为至少一次，消费端按幂等键去重
+func (s *sSysQueue) RelayOutbox(ctx context.Context) (err error) {
+	var (
+		cols = dao.SysQueueOutbox.Columns()
+		list []*entity.SysQueueOutbox
+	)
+
+	// 中继在投递过程中退出时，消息会停留在投递中，超时后重新投递
+	_, err = dao.SysQueueOutbox.Ctx(ctx).
+		Where(cols.Status, consts.QueueOutboxStatusSending).
+		WhereLT(cols.UpdatedAt, gtime.Now().Add(-outboxSendingTimeout)).
+		Data(cols.Status, consts.QueueOutboxStatusPending).
+		Update()
+	if err != nil {
+		return
+	}
+
+	err = dao.SysQueueOutbox.Ctx(ctx).
+		Where(cols.Status, consts.QueueOutboxStatusPending).
+		WhereLTE(cols.NextAt, gtime.Now()).
+		OrderAsc(cols.Id).
+		Limit(outboxRelayBatch).
+		Scan(&list)
+	if err != nil {
+		return
+	}
+
+	for _, v := range list {
+		// 多节点同时运行中继时，只有抢占成功的节点进行投递
+		result, err := dao.SysQueueOutbox.Ctx(ctx).
+			Where(cols.Id, v.Id).
+			Where(cols.Status, consts.QueueOutboxStatusPending).
+			Data(cols.Status, consts.QueueOutboxStatusSending).
+			Update()
+		if err != nil {
+			return err
+		}
+
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			continue
+		}
+
+		s.relayOutboxMsg(ctx, v)
+	}
+	return
+}
+
+// relayOutboxMsg 投递单条发件箱消息并更新投递状态
+func (s *sSysQueue) relayOutboxMsg(ctx context.Context, v *entity.SysQueueOutbox) {
+	var (
+		cols = dao.SysQueueOutbox.Columns()
+		data g.Map
+	)
+
+	if err := queue.PushWithKey(v.Topic, v.MsgKey, v.Body); err != nil {
+		backoff := (v.Attempts + 1) * (v.Attempts + 1)
+		if backoff > outboxMaxBackoff {
+			backoff = outboxMaxBackoff
+		}
+
+		data = g.Map{
+			cols.Status:   consts.QueueOutboxStatusPending,
+			cols.Attempts: v.Attempts + 1,
+			cols.Error:    err.Error(),
+			cols.NextAt:   gtime.Now().Add(time.Duration(backoff) * time.Second),
+		}
+	} else {
+		data = g.Map{
+			cols.Status:   consts.QueueOutboxStatusSent,
+			cols.Attempts: v.Attempts + 1,
+			cols.Error:    "",
+			cols.SentAt:   gtime.Now(),
+		}
+	}
+
+	if _, err := dao.SysQueueOutbox.Ctx(ctx).Where(cols.Id, v.Id).Data(data).Update(); err != nil {
+		queue.Logger().Warningf(ctx, "queue update outbox id:%v err:%+v", v.Id, err)
+	}
+}
@@ -95,7 +95,7 @@ func (s *sSysServeLicense) List(ctx context.Context, in *sysin.ServeLicenseListI
 
 	serv := service.TCPServer().Instance()
 	for _, v := range list {
-		v.Online = serv.GetAppIdOnline(v.Appid)
+		v.Online = serv.ClusterAppIdOnline(ctx, v.Appid)
 	}
 	return
 }
@@ -52,8 +52,13 @@ func (s *sAuthClient) Start(ctx context.Context) {
 
 	// 创建客户端配置
 	clientConfig := &tcp.ClientConfig{
-		Addr:          config.Client.Auth.Address,
-		AutoReconnect: true,
+		Addr:              config.Client.Auth.Address,
+		AutoReconnect:     true,
+		TLS:               newTLSConfig(config.Client.Auth.TLS),
+		Codec:             config.Client.Auth.Codec,
+		CompressThreshold: config.Client.Auth.CompressThreshold,
+		MaxInflight:       config.Client.Auth.MaxInflight,
+		Write:             newWriteConfig(config.Client.Auth.Write),
 		Auth: &tcp.AuthMeta{
 			Name: config.Client.Auth.Name,
 			Extra: g.Map{
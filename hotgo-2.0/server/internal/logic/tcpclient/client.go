@@ -4,3 +4,35 @@
 // @Author  Ms <133814250@qq.com>
 // @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
 package tcpclient
+
+import (
+	"hotgo/internal/library/network/tcp"
+	"hotgo/internal/model"
+)
+
+// newWriteConfig 将本地配置转换为tcp连接的写队列配置，未配置时返回nil使用默认配置
+func newWriteConfig(conf *model.TCPWriteConfig) *tcp.WriteConfig {
+	if conf == nil {
+		return nil
+	}
+	return &tcp.WriteConfig{
+		BufferSize:   conf.BufferSize,
+		Overflow:     conf.Overflow,
+		BlockTimeout: conf.BlockTimeout,
+		WriteTimeout: conf.WriteTimeout,
+	}
+}
+
+// newTLSConfig 将本地配置转换为tcp客户端的传输层加密配置，未开启时返回nil使用明文传输
+func newTLSConfig(conf *model.TCPTLSConfig) *tcp.TLSConfig {
+	if conf == nil || !conf.Enable {
+		return nil
+	}
+	return &tcp.TLSConfig{
+		CertFile:           conf.CertFile,
+		KeyFile:            conf.KeyFile,
+		CAFile:             conf.CaFile,
+		ServerName:         conf.ServerName,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+	}
+}
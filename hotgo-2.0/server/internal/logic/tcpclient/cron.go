@@ -49,8 +49,13 @@ func (s *sCronClient) Start(ctx context.Context) {
 
 	// 创建客户端配置
 	clientConfig := &tcp.ClientConfig{
-		Addr:          config.Client.Cron.Address,
-		AutoReconnect: true,
+		Addr:              config.Client.Cron.Address,
+		AutoReconnect:     true,
+		TLS:               newTLSConfig(config.Client.Cron.TLS),
+		Codec:             config.Client.Cron.Codec,
+		CompressThreshold: config.Client.Cron.CompressThreshold,
+		MaxInflight:       config.Client.Cron.MaxInflight,
+		Write:             newWriteConfig(config.Client.Cron.Write),
 		Auth: &tcp.AuthMeta{
 			Name:      config.Client.Cron.Name,
 			Group:     config.Client.Cron.Group,
@@ -70,7 +70,7 @@ func (s *sTCPServer) OnAuthSummary(ctx context.Context, req *servmsg.AuthSummary
 
 	data := new(servmsgin.AuthSummaryModel)
 	data.EndAt = models.EndAt
-	data.Online = service.TCPServer().Instance().GetAppIdOnline(models.Appid)
+	data.Online = service.TCPServer().Instance().ClusterAppIdOnline(ctx, models.Appid)
 
 	// 请填充你的授权数据
 	// ...
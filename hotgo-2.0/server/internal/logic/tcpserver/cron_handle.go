@@ -15,7 +15,11 @@ import (
 
 // CronDelete 删除任务
 func (s *sTCPServer) CronDelete(ctx context.Context, in *servmsg.CronDeleteReq) (err error) {
-	clients := s.serv.GetGroupClients(consts.LicenseGroupCron)
+	clients, err := s.serv.ClusterGroupClients(ctx, consts.LicenseGroupCron)
+	if err != nil {
+		return
+	}
+
 	if len(clients) == 0 {
 		err = gerror.New("没有在线的定时任务服务")
 		return
@@ -23,7 +27,7 @@ func (s *sTCPServer) CronDelete(ctx context.Context, in *servmsg.CronDeleteReq)
 
 	for _, client := range clients {
 		var res servmsg.CronDeleteRes
-		if err = s.serv.RequestScan(ctx, client, in, &res); err != nil {
+		if err = s.serv.RequestScanTo(ctx, client, in, &res); err != nil {
 			return
 		}
 
@@ -36,7 +40,11 @@ func (s *sTCPServer) CronDelete(ctx context.Context, in *servmsg.CronDeleteReq)
 
 // CronEdit 编辑任务
 func (s *sTCPServer) CronEdit(ctx context.Context, in *servmsg.CronEditReq) (err error) {
-	clients := s.serv.GetGroupClients(consts.LicenseGroupCron)
+	clients, err := s.serv.ClusterGroupClients(ctx, consts.LicenseGroupCron)
+	if err != nil {
+		return
+	}
+
 	if len(clients) == 0 {
 		err = gerror.New("没有在线的定时任务服务")
 		return
@@ -44,7 +52,7 @@ func (s *sTCPServer) CronEdit(ctx context.Context, in *servmsg.CronEditReq) (err
 
 	for _, client := range clients {
 		var res servmsg.CronEditRes
-		if err = s.serv.RequestScan(ctx, client, in, &res); err != nil {
+		if err = s.serv.RequestScanTo(ctx, client, in, &res); err != nil {
 			return
 		}
 
@@ -57,7 +65,11 @@ func (s *sTCPServer) CronEdit(ctx context.Context, in *servmsg.CronEditReq) (err
 
 // CronStatus 修改任务状态
 func (s *sTCPServer) CronStatus(ctx context.Context, in *servmsg.CronStatusReq) (err error) {
-	clients := s.serv.GetGroupClients(consts.LicenseGroupCron)
+	clients, err := s.serv.ClusterGroupClients(ctx, consts.LicenseGroupCron)
+	if err != nil {
+		return
+	}
+
 	if len(clients) == 0 {
 		err = gerror.New("没有在线的定时任务服务")
 		return
@@ -65,7 +77,7 @@ func (s *sTCPServer) CronStatus(ctx context.Context, in *servmsg.CronStatusReq)
 
 	for _, client := range clients {
 		var res servmsg.CronStatusRes
-		if err = s.serv.RequestScan(ctx, client, in, &res); err != nil {
+		if err = s.serv.RequestScanTo(ctx, client, in, &res); err != nil {
 			return
 		}
 
@@ -78,7 +90,11 @@ func (s *sTCPServer) CronStatus(ctx context.Context, in *servmsg.CronStatusReq)
 
 // CronOnlineExec 执行一次任务
 func (s *sTCPServer) CronOnlineExec(ctx context.Context, in *servmsg.CronOnlineExecReq) (err error) {
-	clients := s.serv.GetGroupClients(consts.LicenseGroupCron)
+	clients, err := s.serv.ClusterGroupClients(ctx, consts.LicenseGroupCron)
+	if err != nil {
+		return
+	}
+
 	if len(clients) == 0 {
 		err = gerror.New("没有在线的定时任务服务")
 		return
@@ -86,7 +102,7 @@ func (s *sTCPServer) CronOnlineExec(ctx context.Context, in *servmsg.CronOnlineE
 
 	for _, client := range clients {
 		var res servmsg.CronOnlineExecRes
-		if err = s.serv.RequestScan(ctx, client, in, &res); err != nil {
+		if err = s.serv.RequestScanTo(ctx, client, in, &res); err != nil {
 			return
 		}
 
@@ -99,14 +115,18 @@ func (s *sTCPServer) CronOnlineExec(ctx context.Context, in *servmsg.CronOnlineE
 
 // DispatchLog 查看调度日志
 func (s *sTCPServer) DispatchLog(ctx context.Context, in *servmsg.CronDispatchLogReq) (log *cron.Log, err error) {
-	clients := s.serv.GetGroupClients(consts.LicenseGroupCron)
+	clients, err := s.serv.ClusterGroupClients(ctx, consts.LicenseGroupCron)
+	if err != nil {
+		return
+	}
+
 	if len(clients) == 0 {
 		err = gerror.New("没有在线的定时任务服务")
 		return
 	}
 
 	var res servmsg.CronDispatchLogRes
-	if err = s.serv.RequestScan(ctx, clients[0], in, &res); err != nil {
+	if err = s.serv.RequestScanTo(ctx, clients[0], in, &res); err != nil {
 		return
 	}
 
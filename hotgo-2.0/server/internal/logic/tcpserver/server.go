@@ -11,6 +11,7 @@ import (
 	"hotgo/internal/library/network/tcp"
 	"hotgo/internal/service"
 	"hotgo/utility/simple"
+	"time"
 )
 
 type sTCPServer struct {
@@ -35,10 +36,49 @@ func (s *sTCPServer) Start(ctx context.Context) {
 	simple.SafeGo(ctx, func(ctx context.Context) {
 		g.Log().Debug(ctx, "TCPServer start..")
 
-		s.serv = tcp.NewServer(&tcp.ServerConfig{
-			Name: simple.AppName(ctx),
-			Addr: g.Cfg().MustGet(ctx, "tcp.server.address").String(),
-		})
+		config, err := service.SysConfig().GetLoadTCP(ctx)
+		if err != nil || config == nil || config.Server == nil {
+			g.Log().Errorf(ctx, "TCPServer config is invalid, err:%+v", err)
+			return
+		}
+
+		serverConfig := &tcp.ServerConfig{
+			Name:              simple.AppName(ctx),
+			Addr:              config.Server.Address,
+			Codecs:            config.Server.Codecs,
+			CompressThreshold: config.Server.CompressThreshold,
+			MaxInflight:       config.Server.MaxInflight,
+		}
+
+		// 集群部署时通过redis登记各节点的连接，并在节点间转发消息
+		if simple.IsCluster(ctx) {
+			serverConfig.Cluster = &tcp.ClusterConfig{
+				Node: config.Server.Node,
+				TTL:  time.Duration(config.Server.NodeTTL) * time.Second,
+			}
+		}
+
+		// 连接写队列
+		if conf := config.Server.Write; conf != nil {
+			serverConfig.Write = &tcp.WriteConfig{
+				BufferSize:   conf.BufferSize,
+				Overflow:     conf.Overflow,
+				BlockTimeout: conf.BlockTimeout,
+				WriteTimeout: conf.WriteTimeout,
+			}
+		}
+
+		// 传输层加密
+		if conf := config.Server.TLS; conf != nil && conf.Enable {
+			serverConfig.TLS = &tcp.TLSConfig{
+				CertFile:   conf.CertFile,
+				KeyFile:    conf.KeyFile,
+				CAFile:     conf.CaFile,
+				ClientAuth: conf.ClientAuth,
+			}
+		}
+
+		s.serv = tcp.NewServer(serverConfig)
 
 		// 注册路由
 		s.serv.RegisterRouter(
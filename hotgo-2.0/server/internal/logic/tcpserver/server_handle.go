@@ -18,6 +18,7 @@ import (
 	"hotgo/internal/model/entity"
 	"hotgo/utility/convert"
 	"hotgo/utility/encrypt"
+	"strings"
 )
 
 // onServerLogin 处理客户端登录
@@ -76,6 +77,22 @@ func (s *sTCPServer) onServerLogin(ctx context.Context, req *tcp.ServerLoginReq)
 		return
 	}
 
+	// 验证客户端证书指纹
+	if models.CertFingerprint != "" {
+		fingerprint := conn.PeerFingerprint()
+		if fingerprint == "" {
+			res.SetError(gerror.New("该授权已绑定客户端证书，请使用TLS双向认证连接"))
+			_ = conn.Send(ctx, res)
+			return
+		}
+
+		if !matchFingerprint(models.CertFingerprint, fingerprint) {
+			res.SetError(gerror.New("客户端证书未授权，请联系管理员"))
+			_ = conn.Send(ctx, res)
+			return
+		}
+	}
+
 	var routes []string
 	if err := models.Routes.Scan(&routes); err != nil {
 		res.SetError(gerror.New("授权路由解析失败，请联系管理员"))
@@ -83,13 +100,18 @@ func (s *sTCPServer) onServerLogin(ctx context.Context, req *tcp.ServerLoginReq)
 		return
 	}
 
-	// 拿出当前登录应用的所有客户端
-	clients := s.serv.GetAppIdClients(models.Appid)
+	// 拿出当前登录应用在集群中的所有客户端
+	clients, err := s.serv.ClusterAppIdClients(ctx, models.Appid)
+	if err != nil {
+		res.SetError(gerror.New("获取在线客户端失败，请稍后重试"))
+		_ = conn.Send(ctx, res)
+		return
+	}
 
 	// 检查多地登录，如果连接超过上限，则断开当前许可证下的所有连接
 	if len(clients)+1 > models.OnlineLimit {
 		for _, client := range clients {
-			client.Close()
+			_ = s.serv.CloseClient(ctx, client)
 		}
 		res.SetError(gerror.New("授权登录端超出上限，请勿多地登录"))
 		_ = conn.Send(ctx, res)
@@ -115,6 +137,10 @@ func (s *sTCPServer) onServerLogin(ctx context.Context, req *tcp.ServerLoginReq)
 	}
 	s.serv.AuthClient(conn, auth)
 
+	// 协商后续消息使用的编码和协议版本
+	res.Codec = s.serv.NegotiateCodec(conn, req.Codec)
+	res.Protocol = s.serv.NegotiateProtocol(conn, req.Protocol)
+
 	update := g.Map{
 		cols.LoginTimes:   models.LoginTimes + 1,
 		cols.LastLoginAt:  gtime.Now(),
@@ -165,3 +191,13 @@ func (s *sTCPServer) onServerHeartbeat(ctx context.Context, req *tcp.ServerHeart
 
 	_ = conn.Send(ctx, res)
 }
+
+// matchFingerprint 检查证书指纹是否在授权绑定的指纹列表中
+func matchFingerprint(fingerprints, fingerprint string) bool {
+	for _, v := range strings.Split(fingerprints, ",") {
+		if tcp.NormalizeFingerprint(v) == fingerprint {
+			return true
+		}
+	}
+	return false
+}
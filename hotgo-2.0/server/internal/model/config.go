@@ -59,6 +59,15 @@ type UploadConfig struct {
 	FileType  string `json:"uploadFileType"`
 	ImageSize int64  `json:"uploadImageSize"`
 	ImageType string `json:"uploadImageType"`
+	// 图片处理配置
+	ImageAutoOrient  int    `json:"uploadImageAutoOrient"`
+	ImageSizes       string `json:"uploadImageSizes"`
+	ImageVariantMode int    `json:"uploadImageVariantMode"`
+	ImageWebp        int    `json:"uploadImageWebp"`
+	// 存储容量配置
+	QuotaMember int64  `json:"uploadQuotaMember"`
+	QuotaTenant int64  `json:"uploadQuotaTenant"`
+	QuotaApp    string `json:"uploadQuotaApp"`
 	// 本地存储配置
 	LocalPath string `json:"uploadLocalPath"`
 	// UCloud对象存储配置
@@ -148,6 +157,10 @@ type PayConfig struct {
 	QQPayAppId  string `json:"payQQPayAppId"`
 	QQPayMchId  string `json:"payQQPayMchId"`
 	QQPayApiKey string `json:"payQQPayApiKey"`
+	// 模拟支付
+	MockPayEnabled    bool   `json:"payMockPayEnabled"`
+	MockPaySecret     string `json:"payMockPaySecret"`
+	MockPayRefundFail bool   `json:"payMockPayRefundFail"`
 }
 
 // WechatOfficialAccountConfig 微信公众号配置
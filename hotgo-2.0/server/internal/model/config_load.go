@@ -5,6 +5,8 @@
 // @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
 package model
 
+import "time"
+
 // 本地配置.
 
 // LogConfig 日志配置
@@ -80,7 +82,14 @@ type BuildAddonConfig struct {
 
 // TCPServerConfig tcp服务器配置
 type TCPServerConfig struct {
-	Address string `json:"address"`
+	Address           string          `json:"address"`
+	TLS               *TCPTLSConfig   `json:"tls"`
+	Codecs            []string        `json:"codecs"`
+	CompressThreshold int             `json:"compressThreshold"`
+	MaxInflight       int             `json:"maxInflight"`
+	Node              string          `json:"node"`
+	NodeTTL           int64           `json:"nodeTTL"`
+	Write             *TCPWriteConfig `json:"write"`
 }
 
 // TCPClientConfig tcp客户端配置
@@ -91,11 +100,35 @@ type TCPClientConfig struct {
 
 // TCPClientConnConfig tcp客户端认证
 type TCPClientConnConfig struct {
-	Group     string `json:"group"`
-	Name      string `json:"name"`
-	Address   string `json:"address"`
-	AppId     string `json:"appId"`
-	SecretKey string `json:"secretKey"`
+	Group             string          `json:"group"`
+	Name              string          `json:"name"`
+	Address           string          `json:"address"`
+	AppId             string          `json:"appId"`
+	SecretKey         string          `json:"secretKey"`
+	TLS               *TCPTLSConfig   `json:"tls"`
+	Codec             string          `json:"codec"`
+	CompressThreshold int             `json:"compressThreshold"`
+	MaxInflight       int             `json:"maxInflight"`
+	Write             *TCPWriteConfig `json:"write"`
+}
+
+// TCPWriteConfig tcp连接写队列配置
+type TCPWriteConfig struct {
+	BufferSize   int           `json:"bufferSize"`
+	Overflow     string        `json:"overflow"`
+	BlockTimeout time.Duration `json:"blockTimeout"`
+	WriteTimeout time.Duration `json:"writeTimeout"`
+}
+
+// TCPTLSConfig tcp传输层加密配置
+type TCPTLSConfig struct {
+	Enable             bool   `json:"enable"`
+	CertFile           string `json:"certFile"`
+	KeyFile            string `json:"keyFile"`
+	CaFile             string `json:"caFile"`
+	ClientAuth         bool   `json:"clientAuth"`
+	ServerName         string `json:"serverName"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
 }
 
 // TCPConfig tcp服务器配置
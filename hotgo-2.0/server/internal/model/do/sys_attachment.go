@@ -15,6 +15,7 @@ type SysAttachment struct {
 	Id        any         // 文件ID
 	AppId     any         // 应用ID
 	MemberId  any         // 管理员ID
+	TenantId  any         // 租户ID
 	CateId    any         // 上传分类
 	Drive     any         // 上传驱动
 	Name      any         // 文件原始名
@@ -24,6 +25,8 @@ type SysAttachment struct {
 	Path      any         // 本地路径
 	FileUrl   any         // url
 	Size      any         // 文件大小
+	Width     any         // 图片宽度
+	Height    any         // 图片高度
 	Ext       any         // 扩展名
 	Md5       any         // md5校验码
 	Status    any         // 状态
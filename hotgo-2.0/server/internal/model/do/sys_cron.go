@@ -11,18 +11,24 @@ import (
 
 // SysCron is the golang structure of table hg_sys_cron for DAO operations like Where/Data.
 type SysCron struct {
-	g.Meta    `orm:"table:hg_sys_cron, do:true"`
-	Id        any         // 任务ID
-	GroupId   any         // 分组ID
-	Title     any         // 任务标题
-	Name      any         // 任务方法
-	Params    any         // 函数参数
-	Pattern   any         // 表达式
-	Policy    any         // 策略
-	Count     any         // 执行次数
-	Sort      any         // 排序
-	Remark    any         // 备注
-	Status    any         // 任务状态
-	CreatedAt *gtime.Time // 创建时间
-	UpdatedAt *gtime.Time // 更新时间
+	g.Meta      `orm:"table:hg_sys_cron, do:true"`
+	Id          any         // 任务ID
+	GroupId     any         // 分组ID
+	Title       any         // 任务标题
+	Name        any         // 任务方法
+	Params      any         // 函数参数
+	Pattern     any         // 表达式
+	Policy      any         // 策略
+	Count       any         // 执行次数
+	ClusterMode any         // 集群模式(1所有节点,2单节点,3指定节点)
+	NodeLabel   any         // 指定节点标签，多个用,隔开
+	Timeout     any         // 执行超时(秒)，0不限制
+	Retry       any         // 失败重试次数
+	Alert       any         // 失败通知(1开启,2关闭)
+	AlertEmail  any         // 通知邮箱，多个用,隔开
+	Sort        any         // 排序
+	Remark      any         // 备注
+	Status      any         // 任务状态
+	CreatedAt   *gtime.Time // 创建时间
+	UpdatedAt   *gtime.Time // 更新时间
 }
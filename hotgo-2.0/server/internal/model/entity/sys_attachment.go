@@ -13,6 +13,7 @@ type SysAttachment struct {
 	Id        int64       `json:"id"        orm:"id"         description:"文件ID"`
 	AppId     string      `json:"appId"     orm:"app_id"     description:"应用ID"`
 	MemberId  int64       `json:"memberId"  orm:"member_id"  description:"管理员ID"`
+	TenantId  int64       `json:"tenantId"  orm:"tenant_id"  description:"租户ID"`
 	CateId    int64       `json:"cateId"    orm:"cate_id"    description:"上传分类"`
 	Drive     string      `json:"drive"     orm:"drive"      description:"上传驱动"`
 	Name      string      `json:"name"      orm:"name"       description:"文件原始名"`
@@ -22,6 +23,8 @@ type SysAttachment struct {
 	Path      string      `json:"path"      orm:"path"       description:"本地路径"`
 	FileUrl   string      `json:"fileUrl"   orm:"file_url"   description:"url"`
 	Size      int64       `json:"size"      orm:"size"       description:"文件大小"`
+	Width     int         `json:"width"     orm:"width"      description:"图片宽度"`
+	Height    int         `json:"height"    orm:"height"     description:"图片高度"`
 	Ext       string      `json:"ext"       orm:"ext"        description:"扩展名"`
 	Md5       string      `json:"md5"       orm:"md5"        description:"md5校验码"`
 	Status    int         `json:"status"    orm:"status"     description:"状态"`
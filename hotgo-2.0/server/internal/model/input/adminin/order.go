@@ -154,3 +154,42 @@ func (in *OrderStatusInp) Filter(ctx context.Context) (err error) {
 }
 
 type OrderStatusModel struct{}
+
+// OrderCloseInp 关闭充值订单
+type OrderCloseInp struct {
+	OrderSn string `json:"orderSn" v:"required#业务订单号不能为空" dc:"业务订单号"`
+	Remark  string `json:"remark"                           dc:"关闭原因"`
+}
+
+func (in *OrderCloseInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type OrderCloseModel struct{}
+
+// OrderHistoryInp 获取充值订单流转记录
+type OrderHistoryInp struct {
+	Id int64 `json:"id" v:"required#ID不能为空" dc:"ID"`
+}
+
+func (in *OrderHistoryInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type OrderHistoryModel struct {
+	entity.AdminOrderHistory
+	CreatedBySumma *hook.MemberSumma `json:"createdBySumma" dc:"操作人摘要信息"`
+}
+
+// OrderTransitEvent 订单状态流转事件，通过消息队列发布
+type OrderTransitEvent struct {
+	HistoryId  int64       `json:"historyId"  dc:"流转记录ID"`
+	OrderId    int64       `json:"orderId"    dc:"订单ID"`
+	OrderSn    string      `json:"orderSn"    dc:"业务订单号"`
+	MemberId   int64       `json:"memberId"   dc:"下单用户ID"`
+	Event      string      `json:"event"      dc:"事件"`
+	EventName  string      `json:"eventName"  dc:"事件名称"`
+	FromStatus int         `json:"fromStatus" dc:"原状态"`
+	ToStatus   int         `json:"toStatus"   dc:"新状态"`
+	CreatedAt  *gtime.Time `json:"createdAt"  dc:"流转时间"`
+}
@@ -6,6 +6,9 @@
 package payin
 
 import (
+	"bytes"
+	"net/http"
+
 	"github.com/go-pay/gopay/wechat/v3"
 	"github.com/gogf/gf/v2/os/gtime"
 	officialJs "github.com/silenceper/wechat/v2/officialaccount/js"
@@ -31,8 +34,23 @@ type JSAPI struct {
 	Params *wechat.JSAPIPayParams `json:"params" description:"支付参数"`
 }
 
-// NotifyInp 统一异步通知处理入口
+// NotifyInp 统一异步通知处理入口，使用原始报文处理，以便失败后可以从存档中重放
 type NotifyInp struct {
+	Header http.Header `json:"header"        dc:"请求头"`
+	Body   []byte      `json:"body"          dc:"请求报文"`
+}
+
+// Request 使用原始报文还原通知请求，供第三方SDK解析
+func (in NotifyInp) Request() (r *http.Request, err error) {
+	r, err = http.NewRequest(http.MethodPost, "/", bytes.NewReader(in.Body))
+	if err != nil {
+		return
+	}
+
+	if in.Header != nil {
+		r.Header = in.Header.Clone()
+	}
+	return
 }
 
 type NotifyModel struct {
@@ -40,6 +58,8 @@ type NotifyModel struct {
 	TransactionId string      `json:"transactionId" description:"交易号"`
 	PayAt         *gtime.Time `json:"payAt"         description:"支付时间"`
 	ActualAmount  float64     `json:"actualAmount"  description:"实付金额"`
+	TotalAmount   float64     `json:"totalAmount"   description:"订单金额"`
+	MchId         string      `json:"mchId"         description:"商户号"`
 }
 
 // NotifyCallFuncInp 异步通知回调，用于异步通知验签通过后回调到具体的业务中
@@ -47,6 +67,13 @@ type NotifyCallFuncInp struct {
 	Pay *entity.PayLog
 }
 
+// NotifyCallEvent 支付成功回调业务的发件箱消息
+type NotifyCallEvent struct {
+	PayLogId   int64  `json:"payLogId"   description:"支付记录ID"`
+	OrderGroup string `json:"orderGroup" description:"订单分组"`
+	OrderSn    string `json:"orderSn"    description:"业务订单号"`
+}
+
 // RefundInp 统一退款处理入口
 type RefundInp struct {
 	Pay         *entity.PayLog
@@ -58,3 +85,57 @@ type RefundInp struct {
 
 type RefundModel struct {
 }
+
+// QueryOrderInp 统一查询订单入口
+type QueryOrderInp struct {
+	Pay *entity.PayLog
+}
+
+type QueryOrderModel struct {
+	OutTradeNo    string      `json:"outTradeNo"    description:"商户订单号"`
+	TransactionId string      `json:"transactionId" description:"交易号"`
+	TradeState    string      `json:"tradeState"    description:"交易状态"`
+	PayAt         *gtime.Time `json:"payAt"         description:"支付时间"`
+	ActualAmount  float64     `json:"actualAmount"  description:"实付金额"`
+}
+
+// CloseOrderInp 统一关闭订单入口
+type CloseOrderInp struct {
+	Pay *entity.PayLog
+}
+
+type CloseOrderModel struct {
+}
+
+// QueryRefundInp 统一查询退款入口
+type QueryRefundInp struct {
+	Pay      *entity.PayLog
+	RefundSn string `json:"refundSn"      dc:"退款单号"`
+}
+
+type QueryRefundModel struct {
+	RefundSn    string      `json:"refundSn"      description:"退款单号"`
+	RefundId    string      `json:"refundId"      description:"第三方退款单号"`
+	RefundState string      `json:"refundState"   description:"退款状态"`
+	RefundMoney float64     `json:"refundMoney"   description:"退款金额"`
+	RefundAt    *gtime.Time `json:"refundAt"      description:"退款时间"`
+}
+
+// DownloadBillInp 统一下载交易账单入口
+type DownloadBillInp struct {
+	BillDate *gtime.Time `json:"billDate"      dc:"账单日期"`
+}
+
+type DownloadBillModel struct {
+	List []*BillItem `json:"list"          description:"账单明细"`
+}
+
+// BillItem 交易账单明细
+type BillItem struct {
+	Kind          string      `json:"kind"          description:"明细类型"`
+	OutTradeNo    string      `json:"outTradeNo"    description:"商户订单号"`
+	TransactionId string      `json:"transactionId" description:"交易号"`
+	RefundSn      string      `json:"refundSn"      description:"退款单号"`
+	Amount        float64     `json:"amount"        description:"金额"`
+	TradeAt       *gtime.Time `json:"tradeAt"       description:"交易时间"`
+}
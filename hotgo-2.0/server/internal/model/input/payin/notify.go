@@ -0,0 +1,68 @@
+// Package payin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package payin
+
+import (
+	"context"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/form"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// PayNotifyListInp 获取支付通知列表
+type PayNotifyListInp struct {
+	form.PageReq
+	PayType    string        `json:"payType"    dc:"支付方式"`
+	OutTradeNo string        `json:"outTradeNo" dc:"商户订单号"`
+	Status     int           `json:"status"     dc:"处理状态"`
+	CreatedAt  []*gtime.Time `json:"createdAt"  dc:"创建时间"`
+}
+
+func (in *PayNotifyListInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type PayNotifyListModel struct {
+	Id            int64       `json:"id"            dc:"记录ID"`
+	PayType       string      `json:"payType"       dc:"支付方式"`
+	OutTradeNo    string      `json:"outTradeNo"    dc:"商户订单号"`
+	TransactionId string      `json:"transactionId" dc:"交易号"`
+	ClientIp      string      `json:"clientIp"      dc:"通知IP"`
+	TraceId       string      `json:"traceId"       dc:"链路ID"`
+	Status        int         `json:"status"        dc:"处理状态"`
+	ErrMsg        string      `json:"errMsg"        dc:"失败原因"`
+	ReplayCount   int         `json:"replayCount"   dc:"重放次数"`
+	ProcessedAt   *gtime.Time `json:"processedAt"   dc:"处理时间"`
+	CreatedAt     *gtime.Time `json:"createdAt"     dc:"创建时间"`
+}
+
+// PayNotifyViewInp 获取支付通知详情
+type PayNotifyViewInp struct {
+	Id int64 `json:"id" v:"required#ID不能为空" dc:"ID"`
+}
+
+func (in *PayNotifyViewInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type PayNotifyViewModel struct {
+	entity.PayNotify
+}
+
+// PayNotifyReplayInp 使用存档报文重放处理失败的支付通知
+type PayNotifyReplayInp struct {
+	Id int64 `json:"id" v:"required#ID不能为空" dc:"ID"`
+}
+
+func (in *PayNotifyReplayInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type PayNotifyReplayModel struct {
+	Status int    `json:"status" dc:"处理状态"`
+	ErrMsg string `json:"errMsg" dc:"失败原因"`
+}
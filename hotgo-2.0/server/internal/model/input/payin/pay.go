@@ -8,6 +8,7 @@ package payin
 import (
 	"context"
 	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/form"
 
@@ -196,3 +197,65 @@ func (in *PaySwitchInp) Filter(ctx context.Context) (err error) {
 }
 
 type PaySwitchModel struct{}
+
+// PayQueryOrderInp 主动查询支付订单
+type PayQueryOrderInp struct {
+	OrderSn    string `json:"orderSn"    dc:"业务订单号"`
+	OutTradeNo string `json:"outTradeNo" dc:"商户订单号"`
+}
+
+func (in *PayQueryOrderInp) Filter(ctx context.Context) (err error) {
+	if in.OrderSn == "" && in.OutTradeNo == "" {
+		err = gerror.New("业务订单号和商户订单号不能同时为空")
+	}
+	return
+}
+
+type PayQueryOrderModel struct {
+	OutTradeNo    string  `json:"outTradeNo"    dc:"商户订单号"`
+	TradeState    string  `json:"tradeState"    dc:"第三方交易状态"`
+	PayStatus     int     `json:"payStatus"     dc:"本地支付状态"`
+	TransactionId string  `json:"transactionId" dc:"交易号"`
+	ActualAmount  float64 `json:"actualAmount"  dc:"实付金额"`
+	Settled       bool    `json:"settled"       dc:"本次查询是否补单成功"`
+}
+
+// PayCloseOrderInp 关闭支付订单
+type PayCloseOrderInp struct {
+	OrderSn string `json:"orderSn" v:"required#业务订单号不能为空" dc:"业务订单号"`
+}
+
+func (in *PayCloseOrderInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type PayCloseOrderModel struct{}
+
+// PayCompensateInp 补偿查询待支付订单
+type PayCompensateInp struct {
+	Minutes int `json:"minutes" dc:"创建超过多少分钟后开始查询"`
+	Expire  int `json:"expire"  dc:"创建超过多少分钟后仍未支付则关闭"`
+	Limit   int `json:"limit"   dc:"单次处理数量"`
+}
+
+func (in *PayCompensateInp) Filter(ctx context.Context) (err error) {
+	if in.Minutes <= 0 {
+		in.Minutes = 5
+	}
+
+	if in.Expire <= in.Minutes {
+		in.Expire = 1440
+	}
+
+	if in.Limit <= 0 {
+		in.Limit = 100
+	}
+	return
+}
+
+type PayCompensateModel struct {
+	Scanned int `json:"scanned" dc:"扫描数量"`
+	Settled int `json:"settled" dc:"补单数量"`
+	Closed  int `json:"closed"  dc:"关闭数量"`
+	Failed  int `json:"failed"  dc:"查询失败数量"`
+}
@@ -0,0 +1,74 @@
+// Package payin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package payin
+
+import (
+	"context"
+	"hotgo/internal/consts"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/form"
+	"hotgo/utility/validate"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// PayReconcileInp 执行对账
+type PayReconcileInp struct {
+	PayType  string      `json:"payType"  v:"required#支付方式不能为空" dc:"支付方式"`
+	BillDate *gtime.Time `json:"billDate" v:"required#账单日期不能为空" dc:"账单日期"`
+}
+
+func (in *PayReconcileInp) Filter(ctx context.Context) (err error) {
+	if !validate.InSlice(consts.PayTypeSlice, in.PayType) {
+		err = gerror.Newf("未被支持的支付方式：%v", in.PayType)
+		return
+	}
+
+	if in.BillDate.StartOfDay().Timestamp() >= gtime.Now().StartOfDay().Timestamp() {
+		err = gerror.New("只能对今天以前的账单进行对账")
+		return
+	}
+	return
+}
+
+type PayReconcileModel struct {
+	BillCount  int `json:"billCount"  dc:"账单明细数量"`
+	LocalCount int `json:"localCount" dc:"本地记录数量"`
+	Matched    int `json:"matched"    dc:"一致数量"`
+	DiffCount  int `json:"diffCount"  dc:"差异数量"`
+}
+
+// PayReconcileListInp 获取对账差异列表
+type PayReconcileListInp struct {
+	form.PageReq
+	PayType    string        `json:"payType"    dc:"支付方式"`
+	BillDate   *gtime.Time   `json:"billDate"   dc:"账单日期"`
+	OutTradeNo string        `json:"outTradeNo" dc:"商户订单号"`
+	Type       int           `json:"type"       dc:"差异类型"`
+	Status     int           `json:"status"     dc:"处理状态"`
+	CreatedAt  []*gtime.Time `json:"createdAt"  dc:"创建时间"`
+}
+
+func (in *PayReconcileListInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type PayReconcileListModel struct {
+	entity.PayReconcile
+}
+
+// PayReconcileHandleInp 标记对账差异已处理
+type PayReconcileHandleInp struct {
+	Id     int64  `json:"id"     v:"required#ID不能为空" dc:"ID"`
+	Remark string `json:"remark" v:"required#处理说明不能为空" dc:"处理说明"`
+}
+
+func (in *PayReconcileHandleInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type PayReconcileHandleModel struct{}
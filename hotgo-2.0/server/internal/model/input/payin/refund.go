@@ -57,3 +57,16 @@ type PayRefundListModel struct {
 type PayRefundExportModel struct {
 	entity.PayRefund
 }
+
+// PayRefundQueryInp 查询第三方退款状态
+type PayRefundQueryInp struct {
+	OrderSn string `json:"orderSn" v:"required#业务订单号不能为空" dc:"业务订单号"`
+}
+
+func (in *PayRefundQueryInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type PayRefundQueryModel struct {
+	QueryRefundModel
+}
@@ -60,6 +60,29 @@ type AttachmentListModel struct {
 	SizeFormat string `json:"sizeFormat"      dc:"大小"`
 }
 
+// AttachmentUsageInp 获取存储用量
+type AttachmentUsageInp struct {
+	MemberId int64 `json:"memberId" dc:"用户ID，超管可查看指定用户"`
+}
+
+type AttachmentUsageModel struct {
+	*storager.StorageUsage
+}
+
+// AttachmentCollectInp 回收未引用附件
+type AttachmentCollectInp struct {
+	Days  int `json:"days"  dc:"回收多少天前的附件"`
+	Limit int `json:"limit" dc:"单次处理数量"`
+}
+
+type AttachmentCollectModel struct {
+	Scanned    int   `json:"scanned"    dc:"检查数量"`
+	Referenced int   `json:"referenced" dc:"被引用数量"`
+	Deleted    int   `json:"deleted"    dc:"删除数量"`
+	FreedSize  int64 `json:"freedSize"  dc:"释放大小"`
+	Expired    int   `json:"expired"    dc:"清理的未完成直传文件数量"`
+}
+
 // AttachmentChooserListInp 获取附件列表
 type AttachmentChooserListInp struct {
 	form.PageReq
@@ -96,6 +119,35 @@ type UploadPartModel struct {
 	*storager.UploadPartModel
 }
 
+// PresignUploadInp 申请直传
+type PresignUploadInp struct {
+	*storager.PresignUploadParams
+}
+
+type PresignUploadModel struct {
+	*storager.PresignUploadModel
+}
+
+// CompleteUploadInp 直传完成
+type CompleteUploadInp struct {
+	*storager.CompleteUploadParams
+}
+
+// AttachmentMigrateInp 存储迁移
+type AttachmentMigrateInp struct {
+	From         string `json:"from"         v:"required#源驱动不能为空"   dc:"源驱动"`
+	To           string `json:"to"           v:"required#目标驱动不能为空" dc:"目标驱动"`
+	Concurrency  int    `json:"concurrency"  dc:"并发数，默认4"`
+	DeleteSource bool   `json:"deleteSource" dc:"迁移成功后删除源文件"`
+}
+
+// AttachmentMigrateStatusInp 存储迁移进度
+type AttachmentMigrateStatusInp struct{}
+
+type AttachmentMigrateStatusModel struct {
+	*storager.MigrateProgress
+}
+
 // ImageTransferStorageInp 图片链接转存
 type ImageTransferStorageInp struct {
 	Url string `json:"url" v:"required#图片链接不能为空" dc:"图片链接"`
@@ -6,6 +6,7 @@
 package sysin
 
 import (
+	"github.com/gogf/gf/v2/os/gtime"
 	"hotgo/internal/library/cron"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/form"
@@ -51,7 +52,10 @@ type CronListInp struct {
 
 type CronListModel struct {
 	entity.SysCron
-	GroupName string `json:"groupName"`
+	GroupName   string              `json:"groupName"`
+	LastExecute *cron.ExecuteRecord `json:"lastExecute" dc:"最近一次执行记录"`
+	RunCount    int                 `json:"runCount"    dc:"近7天执行次数"`
+	SuccessRate float64             `json:"successRate" dc:"近7天成功率(%)"`
 }
 
 // CronStatusInp 更新状态
@@ -73,3 +77,35 @@ type DispatchLogInp struct {
 type DispatchLogModel struct {
 	*cron.Log
 }
+
+// CronLogListInp 获取执行记录列表
+type CronLogListInp struct {
+	form.PageReq
+	CronId    int64         `json:"cronId"    dc:"任务ID"`
+	Status    int           `json:"status"    dc:"执行状态"`
+	Node      string        `json:"node"      dc:"执行节点"`
+	StartedAt []*gtime.Time `json:"startedAt" dc:"开始时间"`
+}
+
+type CronLogListModel struct {
+	Id         int64       `json:"id"         dc:"记录ID"`
+	CronId     int64       `json:"cronId"     dc:"任务ID"`
+	Title      string      `json:"title"      dc:"任务标题"`
+	Name       string      `json:"name"       dc:"任务方法"`
+	Node       string      `json:"node"       dc:"执行节点"`
+	Status     int         `json:"status"     dc:"执行状态"`
+	Attempts   int         `json:"attempts"   dc:"执行次数"`
+	Error      string      `json:"error"      dc:"错误信息"`
+	StartedAt  *gtime.Time `json:"startedAt"  dc:"开始时间"`
+	FinishedAt *gtime.Time `json:"finishedAt" dc:"结束时间"`
+	Took       int64       `json:"took"       dc:"耗时(ms)"`
+}
+
+// CronLogViewInp 获取执行记录详情
+type CronLogViewInp struct {
+	Id int64 `json:"id" v:"required#记录ID不能为空" dc:"记录ID"`
+}
+
+type CronLogViewModel struct {
+	entity.SysCronLog
+}
@@ -0,0 +1,71 @@
+// Package sysin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sysin
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/library/queue"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/form"
+)
+
+// QueueStatsModel 队列主题统计
+type QueueStatsModel struct {
+	Driver        string              `json:"driver"        dc:"队列驱动"`
+	Group         string              `json:"group"         dc:"群组名称"`
+	OutboxPending int                 `json:"outboxPending" dc:"发件箱待投递数量"`
+	List          []*queue.TopicStats `json:"list"          dc:"主题统计"`
+}
+
+// QueueResetStatsInp 重置主题统计
+type QueueResetStatsInp struct {
+	Topic string `json:"topic" v:"required#主题不能为空" dc:"主题"`
+}
+
+// QueueFailListInp 获取失败消息列表
+type QueueFailListInp struct {
+	form.PageReq
+	Topic     string        `json:"topic"     dc:"消息主题"`
+	MsgId     string        `json:"msgId"     dc:"消息ID"`
+	Status    int           `json:"status"    dc:"状态"`
+	CreatedAt []*gtime.Time `json:"createdAt" dc:"创建时间"`
+}
+
+func (in *QueueFailListInp) Filter(ctx context.Context) (err error) {
+	return
+}
+
+type QueueFailListModel struct {
+	Id          int64       `json:"id"          dc:"主键"`
+	Topic       string      `json:"topic"       dc:"消息主题"`
+	MsgId       string      `json:"msgId"       dc:"消息ID"`
+	Error       string      `json:"error"       dc:"错误信息"`
+	ReplayCount int         `json:"replayCount" dc:"重放次数"`
+	HandledBy   int64       `json:"handledBy"   dc:"处理人"`
+	HandledAt   *gtime.Time `json:"handledAt"   dc:"处理时间"`
+	Status      int         `json:"status"      dc:"状态"`
+	CreatedAt   *gtime.Time `json:"createdAt"   dc:"创建时间"`
+}
+
+// QueueFailViewInp 获取失败消息详情
+type QueueFailViewInp struct {
+	Id int64 `json:"id" v:"required#消息ID不能为空" dc:"消息ID"`
+}
+
+type QueueFailViewModel struct {
+	entity.SysQueueFail
+}
+
+// QueueReplayInp 重放失败消息
+type QueueReplayInp struct {
+	Id interface{} `json:"id" v:"required#消息ID不能为空" dc:"消息ID"`
+}
+
+// QueueDiscardInp 丢弃失败消息
+type QueueDiscardInp struct {
+	Id interface{} `json:"id" v:"required#消息ID不能为空" dc:"消息ID"`
+}
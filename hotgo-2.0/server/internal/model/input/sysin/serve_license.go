@@ -12,10 +12,13 @@ import (
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gregex"
 	"hotgo/internal/consts"
+	"hotgo/internal/library/network/tcp"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/form"
 	"hotgo/utility/validate"
+	"strings"
 )
 
 // ServeLicenseUpdateFields 修改服务许可证字段过滤
@@ -26,10 +29,11 @@ type ServeLicenseUpdateFields struct {
 	SecretKey   string `json:"secretKey"   dc:"应用秘钥"`
 	OnlineLimit int    `json:"onlineLimit" dc:"在线数量限制，默认1"`
 	// Routes      *gjson.Json `json:"routes"      dc:"路由表，空使用默认分组路由"`
-	AllowedIps string      `json:"allowedIps"  dc:"白名单，*代表所有，只有允许的IP才能连接到tcp服务"`
-	EndAt      *gtime.Time `json:"endAt"       dc:"授权结束时间"`
-	Remark     string      `json:"remark"      dc:"备注"`
-	Status     int         `json:"status"      dc:"状态"`
+	AllowedIps      string      `json:"allowedIps"      dc:"白名单，*代表所有，只有允许的IP才能连接到tcp服务"`
+	CertFingerprint string      `json:"certFingerprint" dc:"绑定的客户端证书SHA256指纹，多个用,隔开，为空不限制"`
+	EndAt           *gtime.Time `json:"endAt"           dc:"授权结束时间"`
+	Remark          string      `json:"remark"      dc:"备注"`
+	Status          int         `json:"status"      dc:"状态"`
 }
 
 // ServeLicenseInsertFields 新增服务许可证字段过滤
@@ -40,10 +44,11 @@ type ServeLicenseInsertFields struct {
 	SecretKey   string `json:"secretKey"   dc:"应用秘钥"`
 	OnlineLimit int    `json:"onlineLimit" dc:"在线数量限制，默认1"`
 	// Routes      *gjson.Json `json:"routes"      dc:"路由表，空使用默认分组路由"`
-	AllowedIps string      `json:"allowedIps"  dc:"白名单，*代表所有，只有允许的IP才能连接到tcp服务"`
-	EndAt      *gtime.Time `json:"endAt"       dc:"授权结束时间"`
-	Remark     string      `json:"remark"      dc:"备注"`
-	Status     int         `json:"status"      dc:"状态"`
+	AllowedIps      string      `json:"allowedIps"      dc:"白名单，*代表所有，只有允许的IP才能连接到tcp服务"`
+	CertFingerprint string      `json:"certFingerprint" dc:"绑定的客户端证书SHA256指纹，多个用,隔开，为空不限制"`
+	EndAt           *gtime.Time `json:"endAt"           dc:"授权结束时间"`
+	Remark          string      `json:"remark"      dc:"备注"`
+	Status          int         `json:"status"      dc:"状态"`
 }
 
 // ServeLicenseEditInp 修改/新增服务许可证
@@ -72,6 +77,22 @@ func (in *ServeLicenseEditInp) Filter(ctx context.Context) (err error) {
 		return err.Current()
 	}
 
+	// 统一证书指纹格式
+	if in.CertFingerprint != "" {
+		var fingerprints []string
+		for _, v := range strings.Split(in.CertFingerprint, ",") {
+			fingerprint := tcp.NormalizeFingerprint(v)
+			if fingerprint == "" {
+				continue
+			}
+			if len(fingerprint) != 64 || !gregex.IsMatchString(`^[0-9a-f]+$`, fingerprint) {
+				return gerror.Newf("证书指纹[%v]格式不正确，请填写SHA256指纹", v)
+			}
+			fingerprints = append(fingerprints, fingerprint)
+		}
+		in.CertFingerprint = strings.Join(fingerprints, ",")
+	}
+
 	return
 }
 
@@ -14,3 +14,9 @@ type SendToTagInp struct {
 	Tag      string              `json:"tag" v:"required#tag不能为空" description:"标签"`
 	Response websocket.WResponse `json:"response" v:"required#response不能为空"  description:"响应内容"`
 }
+
+// SendToChannelInp 发送频道消息
+type SendToChannelInp struct {
+	Channel  string              `json:"channel" v:"required#channel不能为空" description:"频道"`
+	Response websocket.WResponse `json:"response" v:"required#response不能为空"  description:"响应内容"`
+}
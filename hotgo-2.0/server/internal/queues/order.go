@@ -0,0 +1,44 @@
+// Package queues
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package queues
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hotgo/internal/consts"
+	"hotgo/internal/library/queue"
+	"hotgo/internal/model/input/adminin"
+	"hotgo/internal/websocket"
+)
+
+func init() {
+	queue.RegisterConsumer(Order)
+}
+
+// Order 订单状态流转
+var Order = &qOrder{}
+
+type qOrder struct{}
+
+// GetTopic 主题
+func (q *qOrder) GetTopic() string {
+	return consts.QueueOrderTopic
+}
+
+// Handle 处理消息，将流转事件推送给订阅了该订单频道的客户端
+func (q *qOrder) Handle(ctx context.Context, mqMsg queue.MqMsg) (err error) {
+	var data adminin.OrderTransitEvent
+	if err = json.Unmarshal(mqMsg.Body, &data); err != nil {
+		return err
+	}
+
+	websocket.SendToChannel(fmt.Sprintf("order:%v", data.OrderId), &websocket.WResponse{
+		Event: "admin/order/transit",
+		Data:  data,
+	})
+	return
+}
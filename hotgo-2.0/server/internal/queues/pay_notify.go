@@ -0,0 +1,43 @@
+// Package queues
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package queues
+
+import (
+	"context"
+	"encoding/json"
+	"hotgo/internal/consts"
+	"hotgo/internal/library/queue"
+	"hotgo/internal/model/input/payin"
+	"hotgo/internal/service"
+)
+
+func init() {
+	queue.RegisterConsumer(PayNotify)
+}
+
+// PayNotify 支付成功回调业务
+var PayNotify = &qPayNotify{}
+
+type qPayNotify struct{}
+
+// GetTopic 主题
+func (q *qPayNotify) GetTopic() string {
+	return consts.QueuePayNotifyTopic
+}
+
+// MaxRetry 最大重试次数，重试用尽后进入死信，可以在后台修复问题后重放
+func (q *qPayNotify) MaxRetry() int {
+	return 3
+}
+
+// Handle 处理消息
+func (q *qPayNotify) Handle(ctx context.Context, mqMsg queue.MqMsg) (err error) {
+	var data payin.NotifyCallEvent
+	if err = json.Unmarshal(mqMsg.Body, &data); err != nil {
+		return err
+	}
+	return service.Pay().NotifyCall(ctx, &data)
+}
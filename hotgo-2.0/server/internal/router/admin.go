@@ -48,6 +48,7 @@ func Admin(ctx context.Context, group *ghttp.RouterGroup) {
 			sys.ServeLog,     // 服务日志
 			sys.SmsLog,       // 短信记录
 			sys.ServeLicense, // 服务许可证
+			sys.Queue,        // 消息队列
 			admin.Member,     // 用户
 			admin.Monitor,    // 监控
 			admin.Role,       // 路由
@@ -58,7 +59,10 @@ func Admin(ctx context.Context, group *ghttp.RouterGroup) {
 			admin.Order,      // 充值订单
 			admin.CreditsLog, // 资金变动
 			admin.Cash,       // 提现
+			pay.Pay,          // 支付订单
 			pay.Refund,       // 交易退款
+			pay.Reconcile,    // 交易对账
+			pay.Notify,       // 支付通知
 		)
 
 		group.Middleware(service.Middleware().Develop)
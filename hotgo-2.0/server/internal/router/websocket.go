@@ -43,4 +43,7 @@ func WebSocket(ctx context.Context, group *ghttp.RouterGroup) {
 		"admin/monitor/trends":  admin.Monitor.Trends,  // 后台监控，动态数据
 		"admin/monitor/runInfo": admin.Monitor.RunInfo, // 后台监控，运行信息
 	})
+
+	// 注册可订阅的频道
+	websocket.RegisterChannel("order", admin.Order.AuthChannel) // 充值订单，如：order:1
 }
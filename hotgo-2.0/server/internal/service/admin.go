@@ -187,19 +187,23 @@ type (
 		ApplyRefund(ctx context.Context, in *adminin.OrderApplyRefundInp) (err error)
 		// PayNotify 支付成功通知
 		PayNotify(ctx context.Context, in *payin.NotifyCallFuncInp) (err error)
+		// Close 关闭未支付的充值订单
+		Close(ctx context.Context, in *adminin.OrderCloseInp) (err error)
+		// History 获取充值订单流转记录
+		History(ctx context.Context, in *adminin.OrderHistoryInp) (list []*adminin.OrderHistoryModel, err error)
 		// Create 创建充值订单
 		Create(ctx context.Context, in *adminin.OrderCreateInp) (res *adminin.OrderCreateModel, err error)
 		// List 获取充值订单列表
 		List(ctx context.Context, in *adminin.OrderListInp) (list []*adminin.OrderListModel, totalCount int, err error)
 		// Export 导出充值订单
 		Export(ctx context.Context, in *adminin.OrderListInp) (err error)
-		// Edit 修改/新增充值订单
+		// Edit 修改/新增充值订单，修改时订单状态只能通过状态机流转
 		Edit(ctx context.Context, in *adminin.OrderEditInp) (err error)
 		// Delete 删除充值订单
 		Delete(ctx context.Context, in *adminin.OrderDeleteInp) (err error)
 		// View 获取充值订单指定信息
 		View(ctx context.Context, in *adminin.OrderViewInp) (res *adminin.OrderViewModel, err error)
-		// Status 更新充值订单状态
+		// Status 更新充值订单状态，只能变更为状态机中允许手动触发的目标状态
 		Status(ctx context.Context, in *adminin.OrderStatusInp) (err error)
 	}
 	IAdminPost interface {
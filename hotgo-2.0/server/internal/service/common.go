@@ -21,6 +21,10 @@ type (
 		CheckMultipart(ctx context.Context, in *sysin.CheckMultipartInp) (res *sysin.CheckMultipartModel, err error)
 		// UploadPart 上传分片
 		UploadPart(ctx context.Context, in *sysin.UploadPartInp) (res *sysin.UploadPartModel, err error)
+		// PresignUpload 申请客户端直传
+		PresignUpload(ctx context.Context, in *sysin.PresignUploadInp) (res *sysin.PresignUploadModel, err error)
+		// CompleteUpload 客户端直传完成
+		CompleteUpload(ctx context.Context, in *sysin.CompleteUploadInp) (res *sysin.AttachmentListModel, err error)
 		// ImageTransferStorage 图片链接转存
 		ImageTransferStorage(ctx context.Context, in *sysin.ImageTransferStorageInp) (res *sysin.ImageTransferStorageModel, err error)
 	}
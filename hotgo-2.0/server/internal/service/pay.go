@@ -21,8 +21,21 @@ type (
 		GenNotifyURL(ctx context.Context, in payin.PayCreateInp) (notifyURL string, err error)
 		// RegisterNotifyCall 注册支付成功回调方法
 		RegisterNotifyCall()
+		// NotifyCall 根据发件箱消息执行支付成功回调业务
+		NotifyCall(ctx context.Context, in *payin.NotifyCallEvent) (err error)
+		// CheckQueue 检查支付成功回调依赖的消息队列是否开启
+		// 支付成功后只在发件箱写入消息，由发件箱中继推送到队列后在queue服务中执行回调，队列关闭时已支付的业务订单不会被处理
+		CheckQueue(ctx context.Context) (err error)
 		// Notify 异步通知
+		// 原始报文先存档再处理，处理失败时返回错误让第三方继续重试，也可以在后台使用存档报文重放
 		Notify(ctx context.Context, in *payin.PayNotifyInp) (res *payin.PayNotifyModel, err error)
+		// QueryOrder 主动查询第三方支付订单，已支付但本地未更新时进行补单
+		QueryOrder(ctx context.Context, in *payin.PayQueryOrderInp) (res *payin.PayQueryOrderModel, err error)
+		// CloseOrder 关闭业务订单下所有待支付的第三方订单
+		CloseOrder(ctx context.Context, in *payin.PayCloseOrderInp) (err error)
+		// Compensate 补偿查询超过指定时间仍未收到通知的待支付订单
+		// 第三方已支付的进行补单，已关闭、不存在或超过过期时间的关闭本地支付记录
+		Compensate(ctx context.Context, in *payin.PayCompensateInp) (res *payin.PayCompensateModel, err error)
 		// Model 支付日志ORM模型
 		Model(ctx context.Context, option ...*handler.Option) *gdb.Model
 		// List 获取支付日志列表
@@ -38,11 +51,34 @@ type (
 		// Status 更新支付日志状态
 		Status(ctx context.Context, in payin.PayStatusInp) (err error)
 	}
+	IPayNotify interface {
+		// Model 支付通知ORM模型
+		Model(ctx context.Context, option ...*handler.Option) *gdb.Model
+		// List 获取支付通知列表
+		List(ctx context.Context, in *payin.PayNotifyListInp) (list []*payin.PayNotifyListModel, totalCount int, err error)
+		// View 获取支付通知详情，包含原始报文
+		View(ctx context.Context, in *payin.PayNotifyViewInp) (res *payin.PayNotifyViewModel, err error)
+		// Replay 使用存档的原始报文重新验签和处理失败的支付通知
+		Replay(ctx context.Context, in *payin.PayNotifyReplayInp) (res *payin.PayNotifyReplayModel, err error)
+	}
+	IPayReconcile interface {
+		// Model 对账差异ORM模型
+		Model(ctx context.Context, option ...*handler.Option) *gdb.Model
+		// Reconcile 下载指定日期的第三方账单，与本地支付和退款记录逐笔核对
+		// 重复执行时会清除该日期未处理的差异后重新生成，已处理的差异保持不变
+		Reconcile(ctx context.Context, in *payin.PayReconcileInp) (res *payin.PayReconcileModel, err error)
+		// List 获取对账差异列表
+		List(ctx context.Context, in *payin.PayReconcileListInp) (list []*payin.PayReconcileListModel, totalCount int, err error)
+		// Handle 标记对账差异已处理
+		Handle(ctx context.Context, in *payin.PayReconcileHandleInp) (err error)
+	}
 	IPayRefund interface {
 		// Model 交易退款ORM模型
 		Model(ctx context.Context, option ...*handler.Option) *gdb.Model
 		// Refund 订单退款
 		Refund(ctx context.Context, in *payin.PayRefundInp) (res *payin.PayRefundModel, err error)
+		// Query 查询第三方退款状态，退款成功后回写退款交易号
+		Query(ctx context.Context, in *payin.PayRefundQueryInp) (res *payin.PayRefundQueryModel, err error)
 		// List 获取交易退款列表
 		List(ctx context.Context, in *payin.PayRefundListInp) (list []*payin.PayRefundListModel, totalCount int, err error)
 		// Export 导出交易退款
@@ -51,8 +87,10 @@ type (
 )
 
 var (
-	localPay       IPay
-	localPayRefund IPayRefund
+	localPay          IPay
+	localPayNotify    IPayNotify
+	localPayReconcile IPayReconcile
+	localPayRefund    IPayRefund
 )
 
 func Pay() IPay {
@@ -66,6 +104,28 @@ func RegisterPay(i IPay) {
 	localPay = i
 }
 
+func PayNotify() IPayNotify {
+	if localPayNotify == nil {
+		panic("implement not found for interface IPayNotify, forgot register?")
+	}
+	return localPayNotify
+}
+
+func RegisterPayNotify(i IPayNotify) {
+	localPayNotify = i
+}
+
+func PayReconcile() IPayReconcile {
+	if localPayReconcile == nil {
+		panic("implement not found for interface IPayReconcile, forgot register?")
+	}
+	return localPayReconcile
+}
+
+func RegisterPayReconcile(i IPayReconcile) {
+	localPayReconcile = i
+}
+
 func PayRefund() IPayRefund {
 	if localPayRefund == nil {
 		panic("implement not found for interface IPayRefund, forgot register?")
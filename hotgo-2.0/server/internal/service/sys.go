@@ -7,7 +7,9 @@ package service
 
 import (
 	"context"
+	"hotgo/internal/library/cron"
 	"hotgo/internal/library/hgorm/handler"
+	"hotgo/internal/library/queue"
 	"hotgo/internal/model"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/sysin"
@@ -53,6 +55,14 @@ type (
 		ClearKind(ctx context.Context, in *sysin.AttachmentClearKindInp) (err error)
 		// AttachmentKindOption 上传类型选项
 		AttachmentKindOption(ctx context.Context) (opts []*model.Option, err error)
+		// Usage 获取存储用量
+		Usage(ctx context.Context, in *sysin.AttachmentUsageInp) (res *sysin.AttachmentUsageModel, err error)
+		// Collect 回收超过指定天数且未被业务数据引用的附件
+		Collect(ctx context.Context, in *sysin.AttachmentCollectInp) (res *sysin.AttachmentCollectModel, err error)
+		// Migrate 在后台将附件从源驱动迁移到目标驱动，进度通过websocket推送给操作人
+		Migrate(ctx context.Context, in *sysin.AttachmentMigrateInp) (err error)
+		// MigrateStatus 获取最近一次存储迁移的进度
+		MigrateStatus(ctx context.Context, in *sysin.AttachmentMigrateStatusInp) (res *sysin.AttachmentMigrateStatusModel, err error)
 	}
 	ISysBlacklist interface {
 		// Delete 删除
@@ -134,6 +144,12 @@ type (
 		OnlineExec(ctx context.Context, in *sysin.OnlineExecInp) (err error)
 		// DispatchLog 查看指定任务的调度日志
 		DispatchLog(ctx context.Context, in *sysin.DispatchLogInp) (res *sysin.DispatchLogModel, err error)
+		// RecordExecute 保存任务执行记录，失败时按任务配置发送通知
+		RecordExecute(ctx context.Context, sysCron *entity.SysCron, record *cron.ExecuteRecord)
+		// LogList 获取执行记录列表
+		LogList(ctx context.Context, in *sysin.CronLogListInp) (list []*sysin.CronLogListModel, totalCount int, err error)
+		// LogView 获取执行记录详情
+		LogView(ctx context.Context, in *sysin.CronLogViewInp) (res *sysin.CronLogViewModel, err error)
 	}
 	ISysCronGroup interface {
 		// Delete 删除
@@ -335,6 +351,32 @@ type (
 		// Select 省市区选项
 		Select(ctx context.Context, in *sysin.ProvincesSelectInp) (res *sysin.ProvincesSelectModel, err error)
 	}
+	ISysQueue interface {
+		// Model 队列失败消息ORM模型
+		Model(ctx context.Context) *gdb.Model
+		// Stats 获取主题统计
+		Stats(ctx context.Context) (res *sysin.QueueStatsModel, err error)
+		// ResetStats 重置主题统计
+		ResetStats(ctx context.Context, in *sysin.QueueResetStatsInp) (err error)
+		// RecordFail 记录消费失败的消息
+		RecordFail(ctx context.Context, topic string, mqMsg queue.MqMsg, err error, dead bool)
+		// FailList 获取失败消息列表
+		FailList(ctx context.Context, in *sysin.QueueFailListInp) (list []*sysin.QueueFailListModel, totalCount int, err error)
+		// FailView 获取失败消息详情
+		FailView(ctx context.Context, in *sysin.QueueFailViewInp) (res *sysin.QueueFailViewModel, err error)
+		// Replay 重放失败消息，将原消息内容重新推送到原主题
+		Replay(ctx context.Context, in *sysin.QueueReplayInp) (err error)
+		// Discard 丢弃失败消息
+		Discard(ctx context.Context, in *sysin.QueueDiscardInp) (err error)
+		// Outbox 将消息写入发件箱
+		// 需要在业务事务中使用携带事务的ctx调用，保证消息与业务数据同时提交或回滚，事务提交后由发件箱中继投递到消息队列
+		// key为消息幂等键，同一业务消息应保持不变，为空时自动生成
+		Outbox(ctx context.Context, topic, key string, data interface{}) (err error)
+		// StartOutboxRelay 启动发件箱中继，定时将已提交的消息投递到消息队列
+		StartOutboxRelay(ctx context.Context)
+		// RelayOutbox 投递一批发件箱中待投递的消息，投递语义为至少一次，消费端按幂等键去重
+		RelayOutbox(ctx context.Context) (err error)
+	}
 	ISysServeLicense interface {
 		// Model 服务许可证ORM模型
 		Model(ctx context.Context, option ...*handler.Option) *gdb.Model
@@ -423,6 +465,7 @@ var (
 	localSysNormalTreeDemo ISysNormalTreeDemo
 	localSysOptionTreeDemo ISysOptionTreeDemo
 	localSysProvinces      ISysProvinces
+	localSysQueue          ISysQueue
 	localSysServeLicense   ISysServeLicense
 	localSysServeLog       ISysServeLog
 	localSysSmsLog         ISysSmsLog
@@ -616,6 +659,17 @@ func RegisterSysProvinces(i ISysProvinces) {
 	localSysProvinces = i
 }
 
+func SysQueue() ISysQueue {
+	if localSysQueue == nil {
+		panic("implement not found for interface ISysQueue, forgot register?")
+	}
+	return localSysQueue
+}
+
+func RegisterSysQueue(i ISysQueue) {
+	localSysQueue = i
+}
+
 func SysServeLicense() ISysServeLicense {
 	if localSysServeLicense == nil {
 		panic("implement not found for interface ISysServeLicense, forgot register?")
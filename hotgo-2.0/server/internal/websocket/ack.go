@@ -0,0 +1,100 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/util/guid"
+	"time"
+)
+
+// EventAck 客户端确认收到消息
+const EventAck = "ack"
+
+const (
+	ackTimeout = 5 * time.Second // 等待客户端确认的时间，超时后重发
+	ackRetries = 3               // 最多重发次数
+)
+
+// ackItem 等待客户端确认的消息
+type ackItem struct {
+	msg      *WResponse
+	attempts int
+	timer    *time.Timer
+}
+
+// WithAck 标记消息需要客户端确认，未确认的消息会按间隔重发
+// 可用于SendToUser、SendToClientID等所有发送方法，重发只针对收到消息时在线的连接
+func WithAck(response *WResponse) *WResponse {
+	res := *response
+	res.AckId = guid.S()
+	return &res
+}
+
+// trackAck 记录等待确认的消息，重复记录时忽略
+func (c *Client) trackAck(msg *WResponse) {
+	c.acksLock.Lock()
+	defer c.acksLock.Unlock()
+
+	if c.SendClose {
+		return
+	}
+
+	if _, ok := c.acks[msg.AckId]; ok {
+		return
+	}
+
+	id := msg.AckId
+	c.acks[id] = &ackItem{
+		msg:   msg,
+		timer: time.AfterFunc(ackTimeout, func() { c.redeliver(id) }),
+	}
+}
+
+// redeliver 重发未确认的消息，超过重发次数后放弃
+func (c *Client) redeliver(id string) {
+	c.acksLock.Lock()
+	item, ok := c.acks[id]
+	if !ok {
+		c.acksLock.Unlock()
+		return
+	}
+
+	if item.attempts >= ackRetries {
+		delete(c.acks, id)
+		c.acksLock.Unlock()
+		g.Log().Warningf(mctx, "websocket message not acked, event:%v, ackId:%v, user:%+v", item.msg.Event, id, c.User)
+		return
+	}
+
+	item.attempts++
+	item.timer.Reset(ackTimeout)
+	c.acksLock.Unlock()
+
+	c.send(item.msg)
+}
+
+// ack 客户端确认收到消息
+func (c *Client) ack(id string) {
+	c.acksLock.Lock()
+	defer c.acksLock.Unlock()
+
+	if item, ok := c.acks[id]; ok {
+		item.timer.Stop()
+		delete(c.acks, id)
+	}
+}
+
+// clearAcks 连接关闭时停止所有重发
+func (c *Client) clearAcks() {
+	c.acksLock.Lock()
+	defer c.acksLock.Unlock()
+
+	for id, item := range c.acks {
+		item.timer.Stop()
+		delete(c.acks, id)
+	}
+}
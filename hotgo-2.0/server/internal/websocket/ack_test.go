@@ -0,0 +1,44 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"github.com/gogf/gf/v2/test/gtest"
+	"testing"
+)
+
+func TestRedeliver(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		c := newTestClient()
+		defer c.clearAcks()
+
+		msg := WithAck(&WResponse{Event: "test"})
+		c.SendMsg(msg)
+		c.SendMsg(msg)
+		t.Assert(len(c.acks), 1)
+		t.Assert(len(c.Send), 2)
+
+		// 未确认时按次数重发
+		for i := 0; i < ackRetries; i++ {
+			c.redeliver(msg.AckId)
+		}
+		t.Assert(len(c.Send), 2+ackRetries)
+		t.Assert(c.acks[msg.AckId].attempts, ackRetries)
+
+		// 超过重发次数后放弃
+		c.redeliver(msg.AckId)
+		t.Assert(len(c.Send), 2+ackRetries)
+		t.Assert(len(c.acks), 0)
+
+		// 确认后不再重发
+		msg = WithAck(&WResponse{Event: "test"})
+		c.SendMsg(msg)
+		c.ack(msg.AckId)
+		c.redeliver(msg.AckId)
+		t.Assert(len(c.acks), 0)
+		t.Assert(len(c.Send), 3+ackRetries)
+	})
+}
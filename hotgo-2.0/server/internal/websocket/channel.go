@@ -0,0 +1,182 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// 频道内置事件
+const (
+	EventSubscribe    = "subscribe"     // 订阅频道
+	EventUnsubscribe  = "unsubscribe"   // 取消订阅频道
+	EventPresence     = "presence"      // 获取频道成员
+	EventChannelJoin  = "channel/join"  // 成员加入频道通知
+	EventChannelLeave = "channel/leave" // 成员离开频道通知
+)
+
+// ChannelAuthorizer 频道订阅鉴权，返回错误时拒绝订阅
+type ChannelAuthorizer func(client *Client, channel string) error
+
+// channelAuths 可订阅的频道，key为频道前缀
+var channelAuths = make(map[string]ChannelAuthorizer)
+
+// ChannelWResponse 频道消息
+type ChannelWResponse struct {
+	Channel   string
+	WResponse *WResponse
+}
+
+// ChannelMember 频道成员
+type ChannelMember struct {
+	Node     string `json:"node"`     // 所在节点
+	ID       string `json:"id"`       // 连接唯一标识
+	UserId   int64  `json:"userId"`   // 用户ID
+	Username string `json:"username"` // 用户名
+	Avatar   string `json:"avatar"`   // 头像
+}
+
+// ChannelEvent 频道成员变动通知
+type ChannelEvent struct {
+	Channel string         `json:"channel"` // 频道名称
+	Member  *ChannelMember `json:"member"`  // 变动的成员
+}
+
+// RegisterChannel 注册可订阅的频道
+// 频道名称格式为`前缀:标识`，如`order:123`，prefix为`order`。auth为nil时允许所有已登录用户订阅
+func RegisterChannel(prefix string, auth ChannelAuthorizer) {
+	if _, ok := channelAuths[prefix]; ok {
+		g.Log().Fatalf(mctx, "RegisterChannel prefix %v: already registered", prefix)
+		return
+	}
+	channelAuths[prefix] = auth
+}
+
+// channelPrefix 获取频道前缀
+func channelPrefix(channel string) string {
+	prefix, _, _ := strings.Cut(channel, ":")
+	return prefix
+}
+
+// authorizeChannel 验证客户端是否可以订阅频道，未注册的频道不允许订阅
+func authorizeChannel(client *Client, channel string) error {
+	if channel == "" {
+		return gerror.New("频道名称不能为空")
+	}
+
+	auth, ok := channelAuths[channelPrefix(channel)]
+	if !ok {
+		return gerror.Newf("频道[%v]不允许订阅", channel)
+	}
+
+	if client.User == nil {
+		return gerror.New("登录后才能订阅频道")
+	}
+
+	if auth == nil {
+		return nil
+	}
+	return auth(client, channel)
+}
+
+// Subscribe 客户端订阅频道，订阅前会进行鉴权，订阅成功后通知频道内的成员
+func Subscribe(client *Client, channel string) error {
+	if err := authorizeChannel(client, channel); err != nil {
+		return err
+	}
+
+	if clientManager.AddChannel(client, channel) {
+		SendToChannel(channel, newChannelEvent(EventChannelJoin, channel, client))
+	}
+	return nil
+}
+
+// Unsubscribe 客户端取消订阅频道，并通知频道内的成员
+func Unsubscribe(client *Client, channel string) {
+	if clientManager.DelChannel(client, channel) {
+		SendToChannel(channel, newChannelEvent(EventChannelLeave, channel, client))
+	}
+}
+
+// SendToChannel 发送频道消息，开启集群时同时推送到其他节点
+func SendToChannel(channel string, response *WResponse) {
+	clientManager.ChannelBroadcast <- &ChannelWResponse{
+		Channel:   channel,
+		WResponse: response,
+	}
+	publishCluster(&clusterMessage{Kind: clusterSendChannel, Target: channel, Response: response})
+}
+
+// ChannelPresence 获取频道成员，开启集群时包含所有节点的成员，其他节点的成员存在最多一个同步周期的延迟
+func ChannelPresence(ctx context.Context, channel string) (list []*ChannelMember, err error) {
+	clients, err := ClusterClients(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, v := range clients {
+		if !slices.Contains(v.Channels, channel) {
+			continue
+		}
+		list = append(list, &ChannelMember{
+			Node:     v.Node,
+			ID:       v.ID,
+			UserId:   v.User.Id,
+			Username: v.User.Username,
+			Avatar:   v.User.Avatar,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].UserId == list[j].UserId {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].UserId < list[j].UserId
+	})
+	return
+}
+
+// newChannelEvent 生成频道成员变动通知
+func newChannelEvent(event, channel string, client *Client) *WResponse {
+	member := &ChannelMember{Node: node, ID: client.ID}
+	if client.User != nil {
+		member.UserId = client.User.Id
+		member.Username = client.User.Username
+		member.Avatar = client.User.Avatar
+	}
+
+	return &WResponse{
+		Event:     event,
+		Data:      &ChannelEvent{Channel: channel, Member: member},
+		Timestamp: gtime.Now().Unix(),
+	}
+}
+
+// leaveChannels 连接断开时退出所有频道，在管道处理协程中执行，直接投递本节点的通知
+func (manager *ClientManager) leaveChannels(client *Client) {
+	for _, channel := range manager.DelClientChannels(client) {
+		response := newChannelEvent(EventChannelLeave, channel, client)
+		manager.sendToChannel(channel, response)
+		publishCluster(&clusterMessage{Kind: clusterSendChannel, Target: channel, Response: response})
+	}
+}
+
+// sendToChannel 向本节点订阅了频道的连接发送消息
+func (manager *ClientManager) sendToChannel(channel string, response *WResponse) {
+	if response.Timestamp == 0 {
+		response.Timestamp = gtime.Now().Timestamp()
+	}
+
+	for _, conn := range manager.GetChannelClients(channel) {
+		conn.SendMsg(response)
+	}
+}
@@ -7,6 +7,7 @@ package websocket
 
 import (
 	"context"
+	"errors"
 	"github.com/gogf/gf/v2/container/garray"
 	"github.com/gogf/gf/v2/errors/gcode"
 	"github.com/gogf/gf/v2/frame/g"
@@ -15,15 +16,21 @@ import (
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/guid"
 	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
 	"hotgo/internal/library/contexts"
 	"hotgo/internal/library/location"
 	"hotgo/internal/model"
 	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
 	// 用户连接超时时间
 	heartbeatExpirationTime = 5 * 60
+	// 关闭连接前发送剩余消息的最长时间
+	flushTimeout = time.Second
 )
 
 // 用户登录
@@ -39,19 +46,27 @@ func (l *login) GetKey() string {
 
 // Client 客户端连接
 type Client struct {
-	Addr          string          // 客户端地址
-	ID            string          // 连接唯一标识
-	Socket        *websocket.Conn // 用户连接
-	Send          chan *WResponse // 待发送的数据
-	SendClose     bool            // 发送是否关闭
-	closeSignal   chan struct{}   // 关闭信号
-	FirstTime     uint64          // 首次连接时间
-	HeartbeatTime uint64          // 用户上次心跳时间
-	Tags          garray.StrArray // 标签
-	User          *model.Identity // 用户信息
-	context       context.Context // Custom context for internal usage purpose.
-	IP            string          // 客户端IP
-	UserAgent     string          // 用户代理
+	Addr          string                   // 客户端地址
+	ID            string                   // 连接唯一标识
+	Socket        *websocket.Conn          // 用户连接
+	Send          chan *WResponse          // 待发送的数据
+	SendClose     bool                     // 发送是否关闭
+	closeSignal   chan struct{}            // 关闭信号
+	FirstTime     uint64                   // 首次连接时间
+	HeartbeatTime uint64                   // 用户上次心跳时间
+	Tags          garray.StrArray          // 标签
+	User          *model.Identity          // 用户信息
+	context       context.Context          // Custom context for internal usage purpose.
+	IP            string                   // 客户端IP
+	UserAgent     string                   // 用户代理
+	channels      map[string]struct{}      // 订阅的频道，由ClientManager.ChannelLock保护
+	acks          map[string]*ackItem      // 等待确认的消息
+	acksLock      sync.Mutex               // 等待确认的消息锁
+	limiters      map[string]*rate.Limiter // 各事件的限流器
+	limitersLock  sync.Mutex               // 限流器锁
+	inflight      atomic.Int64             // 正在处理的消息数
+	violations    atomic.Int64             // 超出限制次数
+	recvMsgs      atomic.Int64             // 已接收的消息数
 }
 
 // NewClient 初始化
@@ -68,6 +83,10 @@ func NewClient(r *ghttp.Request, socket *websocket.Conn, firstTime uint64) (clie
 		User:          contexts.GetUser(r.Context()),
 		IP:            location.GetClientIp(r),
 		UserAgent:     r.UserAgent(),
+		context:       contexts.Detach(r.Context()),
+		channels:      make(map[string]struct{}),
+		acks:          make(map[string]*ackItem),
+		limiters:      make(map[string]*rate.Limiter),
 	}
 	return
 }
@@ -82,11 +101,16 @@ func (c *Client) read() {
 
 	defer c.close()
 
+	c.Socket.SetReadLimit(limit.MaxMessageSize)
 	for {
 		_, message, err := c.Socket.ReadMessage()
 		if err != nil {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				c.violate(nil, limitMessageSize)
+			}
 			return
 		}
+		c.recvMsgs.Add(1)
 		// 处理消息
 		handlerMsg(c, message)
 	}
@@ -107,6 +131,7 @@ func (c *Client) write() {
 		select {
 		case <-c.closeSignal:
 			g.Log().Infof(mctx, "websocket client quit, user:%+v", c.User)
+			c.flush()
 			return
 		case message, ok := <-c.Send:
 			if !ok {
@@ -119,11 +144,48 @@ func (c *Client) write() {
 	}
 }
 
-// SendMsg 发送数据
+// flush 关闭前尽量发送队列中剩余的消息，如下线通知、超出限制的错误消息
+func (c *Client) flush() {
+	_ = c.Socket.SetWriteDeadline(time.Now().Add(flushTimeout))
+	for {
+		select {
+		case message := <-c.Send:
+			if err := c.Socket.WriteJSON(message); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// RecvMsgs 已接收的消息数
+func (c *Client) RecvMsgs() int64 {
+	return c.recvMsgs.Load()
+}
+
+// Violations 超出限制次数
+func (c *Client) Violations() int64 {
+	return c.violations.Load()
+}
+
+// SendMsg 发送数据，需要确认的消息会在未确认时重发
 func (c *Client) SendMsg(msg *WResponse) {
 	if c == nil || c.SendClose {
 		return
 	}
+
+	if msg.AckId != "" {
+		c.trackAck(msg)
+	}
+	c.send(msg)
+}
+
+// send 将数据放入发送队列
+func (c *Client) send(msg *WResponse) {
+	if c.SendClose {
+		return
+	}
 	defer func() {
 		if r := recover(); r != nil {
 			g.Log().Infof(mctx, "SendMsg err:%+v, stack:%+v", r, string(debug.Stack()))
@@ -159,6 +221,7 @@ func (c *Client) close() {
 		return
 	}
 	c.SendClose = true
+	c.clearAcks()
 	c.closeSignal <- struct{}{}
 }
 
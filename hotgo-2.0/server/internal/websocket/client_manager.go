@@ -8,39 +8,47 @@ package websocket
 import (
 	"context"
 	"fmt"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gcron"
 	"github.com/gogf/gf/v2/os/gtime"
 	"runtime/debug"
+	"sort"
 	"sync"
 )
 
 // ClientManager 客户端管理
 type ClientManager struct {
-	Clients         map[*Client]bool      // 全部的连接
-	ClientsLock     sync.RWMutex          // 读写锁
-	Users           map[string][]*Client  // 登录的用户
-	UserLock        sync.RWMutex          // 读写锁
-	Register        chan *Client          // 连接连接处理
-	Login           chan *login           // 用户登录处理
-	Unregister      chan *Client          // 断开连接处理程序
-	Broadcast       chan *WResponse       // 广播 向全部成员发送数据
-	ClientBroadcast chan *ClientWResponse // 广播 向某个客户端发送数据
-	TagBroadcast    chan *TagWResponse    // 广播 向某个标签成员发送数据
-	UserBroadcast   chan *UserWResponse   // 广播 向某个用户的所有链接发送数据
-	closeSignal     chan struct{}         // 关闭信号
+	Clients          map[*Client]bool                // 全部的连接
+	ClientsLock      sync.RWMutex                    // 读写锁
+	Users            map[string][]*Client            // 登录的用户
+	UserLock         sync.RWMutex                    // 读写锁
+	Channels         map[string]map[*Client]struct{} // 频道订阅
+	ChannelLock      sync.RWMutex                    // 读写锁
+	Register         chan *Client                    // 连接连接处理
+	Login            chan *login                     // 用户登录处理
+	Unregister       chan *Client                    // 断开连接处理程序
+	Broadcast        chan *WResponse                 // 广播 向全部成员发送数据
+	ClientBroadcast  chan *ClientWResponse           // 广播 向某个客户端发送数据
+	TagBroadcast     chan *TagWResponse              // 广播 向某个标签成员发送数据
+	UserBroadcast    chan *UserWResponse             // 广播 向某个用户的所有链接发送数据
+	ChannelBroadcast chan *ChannelWResponse          // 广播 向订阅了某个频道的连接发送数据
+	closeSignal      chan struct{}                   // 关闭信号
 }
 
 func NewClientManager() (clientManager *ClientManager) {
 	clientManager = &ClientManager{
-		Clients:       make(map[*Client]bool),
-		Users:         make(map[string][]*Client),
-		Register:      make(chan *Client, 1000),
-		Unregister:    make(chan *Client, 1000),
-		Broadcast:     make(chan *WResponse, 1000),
-		TagBroadcast:  make(chan *TagWResponse, 1000),
-		UserBroadcast: make(chan *UserWResponse, 1000),
-		closeSignal:   make(chan struct{}, 1),
+		Clients:          make(map[*Client]bool),
+		Users:            make(map[string][]*Client),
+		Channels:         make(map[string]map[*Client]struct{}),
+		Register:         make(chan *Client, 1000),
+		Unregister:       make(chan *Client, 1000),
+		Broadcast:        make(chan *WResponse, 1000),
+		ClientBroadcast:  make(chan *ClientWResponse, 1000),
+		TagBroadcast:     make(chan *TagWResponse, 1000),
+		UserBroadcast:    make(chan *UserWResponse, 1000),
+		ChannelBroadcast: make(chan *ChannelWResponse, 1000),
+		closeSignal:      make(chan struct{}, 1),
 	}
 	return
 }
@@ -106,6 +114,8 @@ func (manager *ClientManager) DelClients(client *Client) {
 
 // GetClient 通过socket ID获取客户端的连接
 func (manager *ClientManager) GetClient(id string) (client *Client) {
+	manager.ClientsLock.RLock()
+	defer manager.ClientsLock.RUnlock()
 	for c := range manager.Clients {
 		if c.ID == id {
 			return c
@@ -156,6 +166,75 @@ func (manager *ClientManager) GetUsersLen() (userLen int) {
 	return
 }
 
+// AddChannel 添加频道订阅，已订阅时返回false
+func (manager *ClientManager) AddChannel(client *Client, channel string) bool {
+	manager.ChannelLock.Lock()
+	defer manager.ChannelLock.Unlock()
+	if _, ok := client.channels[channel]; ok {
+		return false
+	}
+
+	if manager.Channels[channel] == nil {
+		manager.Channels[channel] = make(map[*Client]struct{})
+	}
+	manager.Channels[channel][client] = struct{}{}
+	client.channels[channel] = struct{}{}
+	return true
+}
+
+// DelChannel 删除频道订阅，未订阅时返回false
+func (manager *ClientManager) DelChannel(client *Client, channel string) bool {
+	manager.ChannelLock.Lock()
+	defer manager.ChannelLock.Unlock()
+	if _, ok := client.channels[channel]; !ok {
+		return false
+	}
+
+	delete(client.channels, channel)
+	delete(manager.Channels[channel], client)
+	if len(manager.Channels[channel]) == 0 {
+		delete(manager.Channels, channel)
+	}
+	return true
+}
+
+// DelClientChannels 删除客户端的全部频道订阅，返回删除前订阅的频道
+func (manager *ClientManager) DelClientChannels(client *Client) (channels []string) {
+	manager.ChannelLock.Lock()
+	defer manager.ChannelLock.Unlock()
+	for channel := range client.channels {
+		channels = append(channels, channel)
+		delete(manager.Channels[channel], client)
+		if len(manager.Channels[channel]) == 0 {
+			delete(manager.Channels, channel)
+		}
+	}
+	client.channels = make(map[string]struct{})
+	sort.Strings(channels)
+	return
+}
+
+// GetChannelClients 获取订阅了频道的连接
+func (manager *ClientManager) GetChannelClients(channel string) (clients []*Client) {
+	manager.ChannelLock.RLock()
+	defer manager.ChannelLock.RUnlock()
+	for client := range manager.Channels[channel] {
+		clients = append(clients, client)
+	}
+	return
+}
+
+// GetClientChannels 获取客户端订阅的频道
+func (manager *ClientManager) GetClientChannels(client *Client) (channels []string) {
+	manager.ChannelLock.RLock()
+	defer manager.ChannelLock.RUnlock()
+	for channel := range client.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return
+}
+
 // EventRegister 用户建立连接事件
 func (manager *ClientManager) EventRegister(client *Client) {
 	if client == nil {
@@ -183,12 +262,16 @@ func (manager *ClientManager) EventLogin(login *login) {
 	if manager.InClient(client) {
 		userKey := login.GetKey()
 		manager.AddUsers(userKey, login.Client)
+		registerClient(client)
 	}
 }
 
 // EventUnregister 用户断开连接事件
 func (manager *ClientManager) EventUnregister(client *Client) {
 	manager.DelClients(client)
+	unregisterClient(client)
+	// 退出订阅的频道
+	manager.leaveChannels(client)
 	// 删除用户连接
 	deleteResult := manager.DelUsers(client)
 	if !deleteResult {
@@ -277,7 +360,7 @@ func (manager *ClientManager) start() {
 			clients := manager.GetClients()
 
 			for conn := range clients {
-				if conn.User.Id == message.UserID {
+				if conn.User != nil && conn.User.Id == message.UserID {
 					if message.WResponse.Timestamp == 0 {
 						message.WResponse.Timestamp = gtime.Now().Timestamp()
 					}
@@ -295,6 +378,9 @@ func (manager *ClientManager) start() {
 					conn.SendMsg(message.WResponse)
 				}
 			}
+		case message := <-manager.ChannelBroadcast:
+			// 频道广播事件
+			manager.sendToChannel(message.Channel, message.WResponse)
 		case <-manager.closeSignal:
 			g.Log().Debug(mctx, "websocket closeSignal quit..")
 			return
@@ -302,13 +388,19 @@ func (manager *ClientManager) start() {
 	}
 }
 
-// SendToAll 发送全部客户端
+// SendToAll 发送全部客户端，开启集群时同时推送到其他节点
 func SendToAll(response *WResponse) {
 	clientManager.Broadcast <- response
+	publishCluster(&clusterMessage{Kind: clusterSendAll, Response: response})
 }
 
-// SendToClientID  发送单个客户端
+// SendToClientID  发送单个客户端，连接不在当前节点时推送到其他节点
 func SendToClientID(id string, response *WResponse) {
+	if clientManager.GetClient(id) == nil {
+		publishCluster(&clusterMessage{Kind: clusterSendClient, Target: id, Response: response})
+		return
+	}
+
 	clientRes := &ClientWResponse{
 		ID:        id,
 		WResponse: response,
@@ -316,20 +408,53 @@ func SendToClientID(id string, response *WResponse) {
 	clientManager.ClientBroadcast <- clientRes
 }
 
-// SendToUser 发送单个用户
+// SendToUser 发送单个用户，开启集群时同时推送到其他节点
+// 开启离线消息时会为消息分配用户序号并存入信箱，用户重连后可按序号补发
 func SendToUser(userID int64, response *WResponse) {
+	response = storeMailbox(mctx, userID, response)
 	userRes := &UserWResponse{
 		UserID:    userID,
 		WResponse: response,
 	}
 	clientManager.UserBroadcast <- userRes
+	publishCluster(&clusterMessage{Kind: clusterSendUser, UserId: userID, Response: response})
 }
 
-// SendToTag 发送某个标签
+// SendToTag 发送某个标签，开启集群时同时推送到其他节点
 func SendToTag(tag string, response *WResponse) {
 	tagRes := &TagWResponse{
 		Tag:       tag,
 		WResponse: response,
 	}
 	clientManager.TagBroadcast <- tagRes
+	publishCluster(&clusterMessage{Kind: clusterSendTag, Target: tag, Response: response})
+}
+
+// KickClient 下线指定连接，连接不在当前节点时从注册表查找所在节点，存在时推送到其他节点处理
+func KickClient(id string) error {
+	if client := clientManager.GetClient(id); client != nil {
+		kick(client)
+		return nil
+	}
+
+	if !isCluster {
+		return gerror.New("客户端已离线")
+	}
+
+	remote, err := locateClient(mctx, id)
+	if err != nil {
+		return gerror.Wrap(err, "查找客户端所在节点失败")
+	}
+
+	if remote == "" {
+		return gerror.New("客户端已离线")
+	}
+	publishCluster(&clusterMessage{Kind: clusterKick, Target: id})
+	return nil
+}
+
+// kick 通知客户端被下线并关闭连接
+func kick(client *Client) {
+	SendSuccess(client, "kick")
+	Close(client)
 }
@@ -0,0 +1,53 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/util/guid"
+	"golang.org/x/time/rate"
+	"testing"
+)
+
+// newTestClient 创建不带网络连接的客户端
+func newTestClient() *Client {
+	return &Client{
+		ID:          guid.S(),
+		Send:        make(chan *WResponse, 100),
+		closeSignal: make(chan struct{}, 1),
+		channels:    make(map[string]struct{}),
+		acks:        make(map[string]*ackItem),
+		limiters:    make(map[string]*rate.Limiter),
+	}
+}
+
+func TestClientManagerChannel(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			manager = NewClientManager()
+			c1      = newTestClient()
+			c2      = newTestClient()
+		)
+
+		t.Assert(manager.AddChannel(c1, "order:1"), true)
+		t.Assert(manager.AddChannel(c1, "order:1"), false)
+		t.Assert(manager.AddChannel(c1, "order:2"), true)
+		t.Assert(manager.AddChannel(c2, "order:1"), true)
+
+		t.Assert(len(manager.GetChannelClients("order:1")), 2)
+		t.Assert(manager.GetClientChannels(c1), []string{"order:1", "order:2"})
+
+		t.Assert(manager.DelChannel(c2, "order:2"), false)
+		t.Assert(manager.DelChannel(c2, "order:1"), true)
+		t.Assert(manager.GetChannelClients("order:1"), []*Client{c1})
+
+		// 断开连接时删除全部订阅，没有订阅者的频道一并删除
+		t.Assert(manager.DelClientChannels(c1), []string{"order:1", "order:2"})
+		t.Assert(len(manager.Channels), 0)
+		t.Assert(len(manager.GetClientChannels(c1)), 0)
+		t.Assert(len(manager.DelClientChannels(c1)), 0)
+	})
+}
@@ -0,0 +1,320 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/util/guid"
+	"hotgo/internal/consts"
+	"hotgo/internal/library/hgrds/pubsub"
+	"hotgo/internal/library/hgrds/registry"
+	"hotgo/internal/model"
+	"hotgo/utility/simple"
+	"time"
+)
+
+// 集群消息类型
+const (
+	clusterSendAll     = "all"     // 发送全部客户端
+	clusterSendClient  = "client"  // 发送单个客户端
+	clusterSendUser    = "user"    // 发送单个用户
+	clusterSendTag     = "tag"     // 发送某个标签
+	clusterSendChannel = "channel" // 发送某个频道
+	clusterKick        = "kick"    // 下线客户端
+)
+
+const (
+	clusterNodeTTL   = 30                          // 节点存活时间(秒)，超过该时间未上报心跳的节点及其连接会被移除
+	clusterDedupTTL  = time.Minute                 // 消息去重记录保留时长
+	clusterCronName  = "websocketClusterHeartbeat" // 心跳定时任务名称
+	clusterCronEvery = "@every 10s"                // 心跳及在线连接同步间隔
+)
+
+var (
+	clusterRegistry = registry.New("websocket:cluster", "", clusterNodeTTL*time.Second) // 节点注册表，以连接ID为成员记录节点上已登录的连接
+	node            = clusterRegistry.Node()                                            // 当前节点名称
+	isCluster       bool                                                                // 是否开启集群
+	dedup           = gcache.New()                                                      // 已处理的集群消息
+)
+
+// clusterMessage 节点间转发的消息
+type clusterMessage struct {
+	Id       string     `json:"id"`               // 消息ID，用于去重
+	Node     string     `json:"node"`             // 来源节点
+	Kind     string     `json:"kind"`             // 消息类型
+	Target   string     `json:"target,omitempty"` // 客户端ID、标签或频道
+	UserId   int64      `json:"userId,omitempty"` // 用户ID
+	Response *WResponse `json:"response,omitempty"`
+}
+
+// OnlineClient 集群中已登录的在线连接
+type OnlineClient struct {
+	Node          string          `json:"node"`               // 所在节点
+	ID            string          `json:"id"`                 // 连接唯一标识
+	IP            string          `json:"ip"`                 // 客户端IP
+	UserAgent     string          `json:"userAgent"`          // 用户代理
+	FirstTime     uint64          `json:"firstTime"`          // 首次连接时间
+	HeartbeatTime uint64          `json:"heartbeatTime"`      // 用户上次心跳时间，其他节点的连接为最近一次同步时的值
+	User          *model.Identity `json:"user"`               // 用户信息
+	Channels      []string        `json:"channels,omitempty"` // 订阅的频道
+	RecvMsgs      int64           `json:"recvMsgs"`           // 已接收的消息数
+	Violations    int64           `json:"violations"`         // 超出限制次数
+}
+
+// Node 当前节点名称
+func Node() string {
+	return node
+}
+
+// statsKey 节点超出限制统计
+func statsKey(node string) string {
+	return clusterRegistry.Key("stats", node)
+}
+
+// startCluster 开启集群部署时订阅集群消息并定时同步在线连接
+func startCluster() {
+	if !simple.IsCluster(mctx) {
+		return
+	}
+
+	if err := pubsub.Subscribe(consts.ClusterSyncWebsocket, handleClusterMessage); err != nil {
+		g.Log().Warningf(mctx, "websocket subscribe cluster err:%+v", err)
+		return
+	}
+	isCluster = true
+
+	heartbeat(mctx)
+	_, _ = gcron.AddSingleton(mctx, clusterCronEvery, heartbeat, clusterCronName)
+}
+
+// stopCluster 移除当前节点的注册信息
+func stopCluster() {
+	if !isCluster {
+		return
+	}
+
+	gcron.Remove(clusterCronName)
+	if err := clusterRegistry.Leave(mctx, statsKey(node)); err != nil {
+		g.Log().Warningf(mctx, "websocket leave cluster err:%+v", err)
+	}
+}
+
+// heartbeat 上报节点心跳，并将当前节点的在线连接全量同步到注册表
+func heartbeat(ctx context.Context) {
+	fields := make(map[string]any)
+	for _, client := range localOnlineClients() {
+		fields[client.ID] = client
+	}
+
+	if err := clusterRegistry.Heartbeat(ctx, fields); err != nil {
+		g.Log().Warningf(ctx, "websocket cluster heartbeat err:%+v", err)
+		return
+	}
+
+	seconds := int64(clusterNodeTTL)
+	_, _ = g.Redis().Set(ctx, statsKey(node), GetLimitStats(), gredis.SetOption{TTLOption: gredis.TTLOption{EX: &seconds}})
+}
+
+// registerClient 登录后立即登记在线连接，无需等待下一次心跳，便于其他节点定位连接
+func registerClient(client *Client) {
+	if !isCluster || client.User == nil {
+		return
+	}
+
+	if err := clusterRegistry.Register(mctx, client.ID, newOnlineClient(client)); err != nil {
+		g.Log().Warningf(mctx, "websocket cluster register client err:%+v", err)
+	}
+}
+
+// unregisterClient 断开连接后立即注销在线连接
+func unregisterClient(client *Client) {
+	if !isCluster {
+		return
+	}
+
+	if err := clusterRegistry.Unregister(mctx, client.ID); err != nil {
+		g.Log().Warningf(mctx, "websocket cluster unregister client err:%+v", err)
+	}
+}
+
+// newOnlineClient 将当前节点的连接转换为在线连接
+func newOnlineClient(client *Client) *OnlineClient {
+	return &OnlineClient{
+		Node:          node,
+		ID:            client.ID,
+		IP:            client.IP,
+		UserAgent:     client.UserAgent,
+		FirstTime:     client.FirstTime,
+		HeartbeatTime: client.HeartbeatTime,
+		User:          client.User,
+		Channels:      clientManager.GetClientChannels(client),
+		RecvMsgs:      client.RecvMsgs(),
+		Violations:    client.Violations(),
+	}
+}
+
+// localOnlineClients 当前节点已登录的在线连接
+func localOnlineClients() (list []*OnlineClient) {
+	clientManager.ClientsRange(func(client *Client, _ bool) bool {
+		if !client.SendClose && client.User != nil {
+			list = append(list, newOnlineClient(client))
+		}
+		return true
+	})
+	return
+}
+
+// remoteOnlineClients 其他节点已登录的在线连接，同时清理已过期的节点
+func remoteOnlineClients(ctx context.Context) (list []*OnlineClient, err error) {
+	nodes, err := clusterRegistry.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range nodes {
+		members, err := clusterRegistry.Members(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range members {
+			var client *OnlineClient
+			if err = item.Scan(&client); err != nil || client == nil {
+				continue
+			}
+			list = append(list, client)
+		}
+	}
+	return
+}
+
+// locateClient 查找持有指定连接的其他节点，未找到时返回空
+func locateClient(ctx context.Context, id string) (string, error) {
+	return clusterRegistry.Lookup(ctx, id)
+}
+
+// ClusterClients 获取集群中已登录的在线连接，未开启集群时只包含当前节点的连接
+func ClusterClients(ctx context.Context) ([]*OnlineClient, error) {
+	list := localOnlineClients()
+	if !isCluster {
+		return list, nil
+	}
+
+	remote, err := remoteOnlineClients(ctx)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取集群在线连接失败")
+	}
+	return append(list, remote...), nil
+}
+
+// ClusterLimitStats 获取集群中超出限制的统计，其他节点的统计为最近一次同步时的值
+func ClusterLimitStats(ctx context.Context) (*LimitStats, error) {
+	stats := GetLimitStats()
+	if !isCluster {
+		return stats, nil
+	}
+
+	nodes, err := clusterRegistry.Nodes(ctx)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取集群节点失败")
+	}
+
+	for _, name := range nodes {
+		v, err := g.Redis().Get(ctx, statsKey(name))
+		if err != nil {
+			return nil, gerror.Wrap(err, "获取节点统计失败")
+		}
+
+		var remote *LimitStats
+		if err = v.Scan(&remote); err != nil || remote == nil {
+			continue
+		}
+		stats.MessageSize += remote.MessageSize
+		stats.Rate += remote.Rate
+		stats.Inflight += remote.Inflight
+		stats.Disconnects += remote.Disconnects
+	}
+	return stats, nil
+}
+
+// ClusterOnline 获取集群中的在线连接数和在线用户数
+func ClusterOnline(ctx context.Context) (clients, users int, err error) {
+	list, err := ClusterClients(ctx)
+	if err != nil {
+		return
+	}
+
+	userIds := make(map[int64]struct{})
+	for _, v := range list {
+		userIds[v.User.Id] = struct{}{}
+	}
+	return len(list), len(userIds), nil
+}
+
+// publishCluster 推送集群消息，未开启集群时不推送
+func publishCluster(msg *clusterMessage) {
+	if !isCluster {
+		return
+	}
+
+	msg.Id = guid.S()
+	msg.Node = node
+	_, _ = dedup.SetIfNotExist(mctx, msg.Id, struct{}{}, clusterDedupTTL)
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		g.Log().Warningf(mctx, "websocket publishCluster marshal err:%+v", err)
+		return
+	}
+
+	if _, err = pubsub.Publish(mctx, consts.ClusterSyncWebsocket, string(b)); err != nil {
+		g.Log().Warningf(mctx, "websocket publishCluster err:%+v", err)
+	}
+}
+
+// handleClusterMessage 处理其他节点推送的消息，只投递给当前节点的连接
+func handleClusterMessage(ctx context.Context, message *gredis.Message) {
+	var msg *clusterMessage
+	if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil || msg == nil {
+		g.Log().Warningf(ctx, "websocket cluster message invalid:%v, err:%+v", message.Payload, err)
+		return
+	}
+
+	// 来源节点已在本地投递，重复的消息直接丢弃
+	if msg.Node == node {
+		return
+	}
+	if ok, _ := dedup.SetIfNotExist(ctx, msg.Id, struct{}{}, clusterDedupTTL); !ok {
+		return
+	}
+
+	switch msg.Kind {
+	case clusterSendAll:
+		clientManager.Broadcast <- msg.Response
+	case clusterSendClient:
+		if clientManager.GetClient(msg.Target) != nil {
+			clientManager.ClientBroadcast <- &ClientWResponse{ID: msg.Target, WResponse: msg.Response}
+		}
+	case clusterSendUser:
+		if len(clientManager.GetUserClient(msg.UserId)) > 0 {
+			clientManager.UserBroadcast <- &UserWResponse{UserID: msg.UserId, WResponse: msg.Response}
+		}
+	case clusterSendTag:
+		clientManager.TagBroadcast <- &TagWResponse{Tag: msg.Target, WResponse: msg.Response}
+	case clusterSendChannel:
+		clientManager.ChannelBroadcast <- &ChannelWResponse{Channel: msg.Target, WResponse: msg.Response}
+	case clusterKick:
+		if client := clientManager.GetClient(msg.Target); client != nil {
+			kick(client)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"encoding/json"
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/util/guid"
+	"testing"
+)
+
+// clusterPayload 生成集群消息
+func clusterPayload(msg *clusterMessage) *gredis.Message {
+	b, _ := json.Marshal(msg)
+	return &gredis.Message{Payload: string(b)}
+}
+
+func TestHandleClusterMessage(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx = gctx.New()
+			msg = &clusterMessage{Id: guid.S(), Node: "other", Kind: clusterSendAll, Response: &WResponse{Event: "test"}}
+		)
+
+		// 同一条消息只投递一次
+		handleClusterMessage(ctx, clusterPayload(msg))
+		handleClusterMessage(ctx, clusterPayload(msg))
+		t.Assert(len(clientManager.Broadcast), 1)
+		t.Assert((<-clientManager.Broadcast).Event, "test")
+
+		// 当前节点发出的消息已在本地投递
+		msg = &clusterMessage{Id: guid.S(), Node: node, Kind: clusterSendAll, Response: &WResponse{Event: "test"}}
+		handleClusterMessage(ctx, clusterPayload(msg))
+		t.Assert(len(clientManager.Broadcast), 0)
+
+		// 当前节点没有目标连接时不投递
+		msg = &clusterMessage{Id: guid.S(), Node: "other", Kind: clusterSendClient, Target: guid.S(), Response: &WResponse{Event: "test"}}
+		handleClusterMessage(ctx, clusterPayload(msg))
+		t.Assert(len(clientManager.ClientBroadcast), 0)
+
+		handleClusterMessage(ctx, &gredis.Message{Payload: "invalid"})
+		t.Assert(len(clientManager.Broadcast), 0)
+	})
+}
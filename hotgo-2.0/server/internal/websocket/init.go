@@ -26,11 +26,15 @@ var (
 func Start() {
 	go clientManager.start()
 	go clientManager.ping()
+	loadMailbox(mctx)
+	loadLimit(mctx)
+	startCluster()
 	g.Log().Debug(mctx, "start websocket..")
 }
 
 // Stop 关闭
 func Stop() {
+	stopCluster()
 	clientManager.closeSignal <- struct{}{}
 }
 
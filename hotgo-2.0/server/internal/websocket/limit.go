@@ -0,0 +1,214 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"golang.org/x/time/rate"
+	"sync/atomic"
+	"time"
+)
+
+// EventLimit 超出限制通知
+const EventLimit = "limit"
+
+// 超出限制的类型
+const (
+	limitMessageSize = "messageSize" // 消息过大
+	limitRate        = "rate"        // 发送过于频繁
+	limitInflight    = "inflight"    // 同时处理的消息过多
+)
+
+const (
+	defaultMaxMessageSize = 64 * 1024        // 单条消息默认最大字节数
+	defaultMaxInflight    = 10               // 每个连接默认同时处理的消息数
+	defaultMaxViolations  = 3                // 默认超出限制多少次后断开连接
+	userLimiterTTL        = 10 * time.Minute // 用户限流器闲置保留时长
+)
+
+// RateLimit 令牌桶限流配置，Rate为0时不限制
+type RateLimit struct {
+	Rate  float64 `json:"rate"`  // 每秒生成的令牌数
+	Burst int     `json:"burst"` // 令牌桶容量，默认与Rate相同
+}
+
+// LimitConfig 连接限制配置
+type LimitConfig struct {
+	MaxMessageSize int64                 `json:"maxMessageSize"` // 单条消息最大字节数，默认64KB
+	MaxInflight    int64                 `json:"maxInflight"`    // 每个连接同时处理的消息数，默认10
+	MaxViolations  int64                 `json:"maxViolations"`  // 超出限制多少次后断开连接，默认3
+	Client         *RateLimit            `json:"client"`         // 每个连接每个事件的限流
+	User           *RateLimit            `json:"user"`           // 每个用户每个事件的限流，同一用户的多个连接共享，仅在当前节点内生效
+	Events         map[string]*RateLimit `json:"events"`         // 指定事件的连接限流，覆盖client配置
+}
+
+// LimitStats 超出限制统计
+type LimitStats struct {
+	MessageSize int64 `json:"messageSize"` // 消息过大次数
+	Rate        int64 `json:"rate"`        // 发送过于频繁次数
+	Inflight    int64 `json:"inflight"`    // 同时处理的消息过多次数
+	Disconnects int64 `json:"disconnects"` // 因超出限制断开的连接数
+}
+
+var (
+	limit        = normalizeLimit(nil) // 连接限制配置
+	userLimiters = gcache.New()        // 用户限流器
+	limitStats   struct {
+		messageSize atomic.Int64
+		rate        atomic.Int64
+		inflight    atomic.Int64
+		disconnects atomic.Int64
+	}
+)
+
+// normalizeLimit 补全连接限制配置的默认值
+func normalizeLimit(config *LimitConfig) *LimitConfig {
+	c := LimitConfig{}
+	if config != nil {
+		c = *config
+	}
+
+	if c.MaxMessageSize <= 0 {
+		c.MaxMessageSize = defaultMaxMessageSize
+	}
+	if c.MaxInflight <= 0 {
+		c.MaxInflight = defaultMaxInflight
+	}
+	if c.MaxViolations <= 0 {
+		c.MaxViolations = defaultMaxViolations
+	}
+	return &c
+}
+
+// loadLimit 加载连接限制配置
+func loadLimit(ctx context.Context) {
+	var config *LimitConfig
+	if err := g.Cfg().MustGet(ctx, "router.websocket.limit").Scan(&config); err != nil {
+		g.Log().Warningf(ctx, "websocket load limit config err:%+v", err)
+		return
+	}
+	limit = normalizeLimit(config)
+}
+
+// newLimiter 创建令牌桶，未配置或Rate为0时返回nil
+func newLimiter(config *RateLimit) *rate.Limiter {
+	if config == nil || config.Rate <= 0 {
+		return nil
+	}
+
+	burst := config.Burst
+	if burst <= 0 {
+		burst = max(int(config.Rate), 1)
+	}
+	return rate.NewLimiter(rate.Limit(config.Rate), burst)
+}
+
+// clientLimiter 获取连接指定事件的限流器
+func (c *Client) clientLimiter(event string) *rate.Limiter {
+	c.limitersLock.Lock()
+	defer c.limitersLock.Unlock()
+
+	if limiter, ok := c.limiters[event]; ok {
+		return limiter
+	}
+
+	config := limit.Client
+	if v, ok := limit.Events[event]; ok {
+		config = v
+	}
+
+	limiter := newLimiter(config)
+	c.limiters[event] = limiter
+	return limiter
+}
+
+// userLimiter 获取用户指定事件的限流器
+func userLimiter(userId int64, event string) *rate.Limiter {
+	if limit.User == nil || limit.User.Rate <= 0 {
+		return nil
+	}
+
+	v, err := userLimiters.GetOrSetFuncLock(mctx, fmt.Sprintf("%d:%s", userId, event), func(ctx context.Context) (interface{}, error) {
+		return newLimiter(limit.User), nil
+	}, userLimiterTTL)
+	if err != nil || v == nil {
+		return nil
+	}
+
+	limiter, _ := v.Val().(*rate.Limiter)
+	return limiter
+}
+
+// allow 检查消息是否超出发送频率限制
+func (c *Client) allow(event string) bool {
+	if limiter := c.clientLimiter(event); limiter != nil && !limiter.Allow() {
+		return false
+	}
+
+	if c.User != nil {
+		if limiter := userLimiter(c.User.Id, event); limiter != nil && !limiter.Allow() {
+			return false
+		}
+	}
+	return true
+}
+
+// acquire 占用一个处理名额，超出同时处理的消息数时返回false
+func (c *Client) acquire() bool {
+	if c.inflight.Add(1) > limit.MaxInflight {
+		c.inflight.Add(-1)
+		return false
+	}
+	return true
+}
+
+// release 释放处理名额
+func (c *Client) release() {
+	c.inflight.Add(-1)
+}
+
+// violate 记录超出限制，回复错误消息，超出次数达到上限后断开连接
+func (c *Client) violate(req *WRequest, kind string) {
+	var err error
+	switch kind {
+	case limitMessageSize:
+		limitStats.messageSize.Add(1)
+		err = gerror.Newf("消息不能超过%d字节", limit.MaxMessageSize)
+	case limitRate:
+		limitStats.rate.Add(1)
+		err = gerror.New("发送过于频繁，请稍后再试")
+	case limitInflight:
+		limitStats.inflight.Add(1)
+		err = gerror.New("同时处理的消息过多，请稍后再试")
+	}
+
+	if req != nil && req.Id != "" {
+		Reply(c, req, nil, err)
+	} else {
+		SendError(c, EventLimit, err)
+	}
+
+	// 消息过大时读取已中断，直接断开连接
+	if c.violations.Add(1) >= limit.MaxViolations || kind == limitMessageSize {
+		limitStats.disconnects.Add(1)
+		g.Log().Warningf(mctx, "websocket client exceeded limit:%v, close conn, ip:%v, user:%+v", kind, c.IP, c.User)
+		c.close()
+	}
+}
+
+// GetLimitStats 获取当前节点的超出限制统计
+func GetLimitStats() *LimitStats {
+	return &LimitStats{
+		MessageSize: limitStats.messageSize.Load(),
+		Rate:        limitStats.rate.Load(),
+		Inflight:    limitStats.inflight.Load(),
+		Disconnects: limitStats.disconnects.Load(),
+	}
+}
@@ -0,0 +1,71 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"github.com/gogf/gf/v2/test/gtest"
+	"testing"
+)
+
+func TestNormalizeLimit(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		c := normalizeLimit(nil)
+		t.Assert(c.MaxMessageSize, defaultMaxMessageSize)
+		t.Assert(c.MaxInflight, defaultMaxInflight)
+		t.Assert(c.MaxViolations, defaultMaxViolations)
+
+		c = normalizeLimit(&LimitConfig{MaxMessageSize: 1024, MaxInflight: -1, Client: &RateLimit{Rate: 5}})
+		t.Assert(c.MaxMessageSize, 1024)
+		t.Assert(c.MaxInflight, defaultMaxInflight)
+		t.Assert(c.MaxViolations, defaultMaxViolations)
+		t.Assert(c.Client.Rate, 5)
+	})
+}
+
+func TestClientLimit(t *testing.T) {
+	old := limit
+	defer func() { limit = old }()
+
+	gtest.C(t, func(t *gtest.T) {
+		limit = normalizeLimit(&LimitConfig{
+			MaxInflight:   2,
+			MaxViolations: 2,
+			Client:        &RateLimit{Rate: 1, Burst: 2},
+			Events:        map[string]*RateLimit{"free": {}},
+		})
+		c := newTestClient()
+
+		// 同时处理的消息数
+		t.Assert(c.acquire(), true)
+		t.Assert(c.acquire(), true)
+		t.Assert(c.acquire(), false)
+		c.release()
+		t.Assert(c.acquire(), true)
+		t.Assert(c.inflight.Load(), 2)
+
+		// 令牌桶限流，指定事件的配置覆盖默认配置
+		t.Assert(c.allow("ping"), true)
+		t.Assert(c.allow("ping"), true)
+		t.Assert(c.allow("ping"), false)
+		for i := 0; i < 5; i++ {
+			t.Assert(c.allow("free"), true)
+		}
+
+		// 超出限制时回复错误，达到次数上限后断开连接
+		stats := GetLimitStats()
+		c.violate(nil, limitRate)
+		t.Assert(c.SendClose, false)
+		t.Assert((<-c.Send).Event, EventLimit)
+
+		c.violate(nil, limitInflight)
+		t.Assert(c.SendClose, true)
+		t.Assert(len(c.closeSignal), 1)
+		t.Assert(c.Violations(), 2)
+		t.Assert(GetLimitStats().Rate-stats.Rate, 1)
+		t.Assert(GetLimitStats().Inflight-stats.Inflight, 1)
+		t.Assert(GetLimitStats().Disconnects-stats.Disconnects, 1)
+	})
+}
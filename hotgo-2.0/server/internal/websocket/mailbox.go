@@ -0,0 +1,175 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gogf/gf/v2/container/gvar"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// EventReplay 补发离线消息
+const EventReplay = "replay"
+
+const (
+	defaultMailboxTTL  = 86400 // 离线消息默认保留时长(秒)
+	defaultMailboxSize = 100   // 每个用户默认最多保留的消息数
+)
+
+// MailboxConfig 离线消息配置
+type MailboxConfig struct {
+	Enable bool  `json:"enable"` // 是否开启
+	TTL    int64 `json:"ttl"`    // 消息保留时长(秒)，从用户最近一条消息开始计算
+	Size   int64 `json:"size"`   // 每个用户最多保留的消息数，超出时丢弃最早的消息
+}
+
+// ReplayResult 补发离线消息结果
+type ReplayResult struct {
+	Count int   `json:"count"` // 补发的消息数
+	Seq   int64 `json:"seq"`   // 用户当前的最新序号
+	Lost  bool  `json:"lost"`  // 是否有消息已过期或超出数量上限无法补发，客户端应主动拉取最新数据
+}
+
+// mailbox 离线消息配置，未开启时为nil
+var mailbox *MailboxConfig
+
+// loadMailbox 加载离线消息配置
+func loadMailbox(ctx context.Context) {
+	var config *MailboxConfig
+	if err := g.Cfg().MustGet(ctx, "router.websocket.mailbox").Scan(&config); err != nil {
+		g.Log().Warningf(ctx, "websocket load mailbox config err:%+v", err)
+		return
+	}
+
+	if config == nil || !config.Enable {
+		return
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = defaultMailboxTTL
+	}
+	if config.Size <= 0 {
+		config.Size = defaultMailboxSize
+	}
+	mailbox = config
+}
+
+// seqKey 用户消息序号
+func seqKey(userId int64) string {
+	return fmt.Sprintf("websocket:mailbox:seq:%d", userId)
+}
+
+// mailboxKey 用户离线消息
+func mailboxKey(userId int64) string {
+	return fmt.Sprintf("websocket:mailbox:%d", userId)
+}
+
+// storeScript 分配消息序号并存入信箱，序号和信箱使用相同的保留时长，返回分配的序号
+// 消息在redis内补写序号，保证信箱中的消息始终按序号排列
+var storeScript = `
+local seq = redis.call("INCR", KEYS[1])
+redis.call("EXPIRE", KEYS[1], ARGV[3])
+redis.call("RPUSH", KEYS[2], '{"seq":' .. seq .. ',' .. string.sub(ARGV[1], 2))
+redis.call("LTRIM", KEYS[2], -tonumber(ARGV[2]), -1)
+redis.call("EXPIRE", KEYS[2], ARGV[3])
+return seq
+`
+
+// storeMailbox 为用户消息分配递增序号并存入信箱，返回带序号的消息副本
+// 未开启离线消息或存储失败时返回原消息，不影响在线投递
+func storeMailbox(ctx context.Context, userId int64, response *WResponse) *WResponse {
+	if mailbox == nil {
+		return response
+	}
+
+	res := *response
+	res.Seq = 0
+	if res.Timestamp == 0 {
+		res.Timestamp = gtime.Now().Unix()
+	}
+
+	b, err := json.Marshal(&res)
+	if err != nil {
+		g.Log().Warningf(ctx, "websocket mailbox marshal err:%+v", err)
+		return response
+	}
+
+	keys := []string{seqKey(userId), mailboxKey(userId)}
+	seq, err := g.Redis().GroupScript().Eval(ctx, storeScript, 2, keys, []interface{}{string(b), mailbox.Size, mailbox.TTL})
+	if err != nil {
+		g.Log().Warningf(ctx, "websocket mailbox store err:%+v", err)
+		return response
+	}
+
+	res.Seq = seq.Int64()
+	return &res
+}
+
+// replayMailbox 按顺序补发序号大于lastSeq的消息
+func replayMailbox(ctx context.Context, client *Client, lastSeq int64) (result *ReplayResult, err error) {
+	result = new(ReplayResult)
+	if mailbox == nil || client.User == nil {
+		return
+	}
+
+	seq, err := g.Redis().Get(ctx, seqKey(client.User.Id))
+	if err != nil {
+		return
+	}
+	result.Seq = seq.Int64()
+
+	// 序号被重置，客户端记录的序号已失效
+	if lastSeq > result.Seq {
+		lastSeq = 0
+	}
+
+	if lastSeq >= result.Seq {
+		return
+	}
+
+	list, err := g.Redis().LRange(ctx, mailboxKey(client.User.Id), 0, -1)
+	if err != nil {
+		return
+	}
+
+	msgs, lost := replayMessages(list, lastSeq, result.Seq)
+	for _, res := range msgs {
+		client.SendMsg(res)
+	}
+	result.Count = len(msgs)
+	result.Lost = lost
+	return
+}
+
+// replayMessages 按顺序筛选信箱中序号大于lastSeq的消息，seq为用户当前的最新序号
+// 序号不连续或未能补发到最新序号时说明有消息已过期或超出数量上限被丢弃
+func replayMessages(list gvar.Vars, lastSeq, seq int64) (msgs []*WResponse, lost bool) {
+	next := lastSeq + 1
+	for _, v := range list {
+		var res *WResponse
+		if err := v.Scan(&res); err != nil || res == nil {
+			continue
+		}
+
+		if res.Seq <= lastSeq {
+			continue
+		}
+
+		if res.Seq != next {
+			lost = true
+		}
+		next = res.Seq + 1
+		msgs = append(msgs, res)
+	}
+
+	if next <= seq {
+		lost = true
+	}
+	return
+}
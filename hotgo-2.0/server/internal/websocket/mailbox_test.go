@@ -0,0 +1,65 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"fmt"
+	"github.com/gogf/gf/v2/container/gvar"
+	"github.com/gogf/gf/v2/test/gtest"
+	"testing"
+)
+
+// mailboxList 生成信箱中的消息
+func mailboxList(seqs ...int64) (list gvar.Vars) {
+	for _, seq := range seqs {
+		list = append(list, gvar.New(fmt.Sprintf(`{"event":"test","seq":%d}`, seq)))
+	}
+	return
+}
+
+// replaySeqs 补发的消息序号
+func replaySeqs(msgs []*WResponse) (seqs []int64) {
+	for _, v := range msgs {
+		seqs = append(seqs, v.Seq)
+	}
+	return
+}
+
+func TestReplayMessages(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		// 连续的消息全部补发
+		msgs, lost := replayMessages(mailboxList(1, 2, 3, 4), 2, 4)
+		t.Assert(replaySeqs(msgs), []int64{3, 4})
+		t.Assert(lost, false)
+
+		// 无需补发
+		msgs, lost = replayMessages(mailboxList(1, 2, 3), 3, 3)
+		t.Assert(len(msgs), 0)
+		t.Assert(lost, false)
+
+		// 中间的消息缺失
+		msgs, lost = replayMessages(mailboxList(1, 2, 4, 5), 1, 5)
+		t.Assert(replaySeqs(msgs), []int64{2, 4, 5})
+		t.Assert(lost, true)
+
+		// 最早的消息超出数量上限被丢弃
+		msgs, lost = replayMessages(mailboxList(4, 5, 6), 1, 6)
+		t.Assert(replaySeqs(msgs), []int64{4, 5, 6})
+		t.Assert(lost, true)
+
+		// 信箱已过期，最新序号仍然存在
+		msgs, lost = replayMessages(nil, 3, 6)
+		t.Assert(len(msgs), 0)
+		t.Assert(lost, true)
+
+		// 无法解析的消息跳过
+		list := mailboxList(1, 2)
+		list = append(list, gvar.New("invalid"))
+		msgs, lost = replayMessages(list, 0, 2)
+		t.Assert(replaySeqs(msgs), []int64{1, 2})
+		t.Assert(lost, false)
+	})
+}
@@ -3,24 +3,27 @@
 // @Copyright  Copyright (c) 2023 HotGo CLI
 // @Author  Ms <133814250@qq.com>
 // @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
-//
 package websocket
 
 import "github.com/gogf/gf/v2/frame/g"
 
 // WRequest 输入对象
 type WRequest struct {
-	Event string `json:"event"` // 事件名称
-	Data  g.Map  `json:"data"`  // 数据
+	Id    string `json:"id,omitempty"` // 请求ID，可选。传入后服务端会在响应中携带该ID
+	Event string `json:"event"`        // 事件名称
+	Data  g.Map  `json:"data"`         // 数据
 }
 
 // WResponse 输出对象
 type WResponse struct {
-	Event     string      `json:"event"`              // 事件名称
-	Data      interface{} `json:"data,omitempty"`     // 数据
-	Code      int         `json:"code"`               // 状态码
-	ErrorMsg  string      `json:"errorMsg,omitempty"` // 错误消息
-	Timestamp int64       `json:"timestamp"`          // 服务器时间
+	Event     string      `json:"event"`               // 事件名称
+	Data      interface{} `json:"data,omitempty"`      // 数据
+	Code      int         `json:"code"`                // 状态码
+	ErrorMsg  string      `json:"errorMsg,omitempty"`  // 错误消息
+	Timestamp int64       `json:"timestamp"`           // 服务器时间
+	Seq       int64       `json:"seq,omitempty"`       // 用户消息序号，开启离线消息后发送给用户的消息按用户递增
+	RequestId string      `json:"requestId,omitempty"` // 对应的请求ID，回复客户端请求时携带
+	AckId     string      `json:"ackId,omitempty"`     // 确认ID，不为空时客户端需回复ack消息，否则服务端会重发
 }
 
 type TagWResponse struct {
@@ -42,3 +45,8 @@ type ClientWResponse struct {
 type EventHandler func(client *Client, req *WRequest)
 
 type EventHandlers map[string]EventHandler
+
+// RequestHandler 请求消息处理器，返回的结果或错误会自动回复给客户端
+type RequestHandler func(client *Client, req *WRequest) (data interface{}, err error)
+
+type RequestHandlers map[string]RequestHandler
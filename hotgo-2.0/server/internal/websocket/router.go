@@ -7,11 +7,28 @@ package websocket
 
 import (
 	"context"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/gconv"
 	"runtime/debug"
+	"slices"
 )
 
+func init() {
+	// 注册内置消息
+	RegisterMsg(EventHandlers{
+		EventAck: ackHandler, // 确认收到消息
+	})
+	RegisterRequest(RequestHandlers{
+		EventSubscribe:   subscribeHandler,   // 订阅频道
+		EventUnsubscribe: unsubscribeHandler, // 取消订阅频道
+		EventPresence:    presenceHandler,    // 获取频道成员
+		EventReplay:      replayHandler,      // 补发离线消息
+	})
+}
+
 // handlerMsg 处理消息
 func handlerMsg(client *Client, message []byte) {
 	defer func() {
@@ -34,24 +51,76 @@ func handlerMsg(client *Client, message []byte) {
 	fun, ok := routers[request.Event]
 	if !ok {
 		g.Log().Warningf(mctx, "handlerMsg function id %v: not registered", request.Event)
+		if request.Id != "" {
+			Reply(client, request, nil, gerror.Newf("消息[%v]未注册", request.Event))
+		}
+		return
+	}
+
+	if !client.allow(request.Event) {
+		client.violate(request, limitRate)
+		return
+	}
+
+	if !client.acquire() {
+		client.violate(request, limitInflight)
 		return
 	}
 
 	err := msgGo.AddWithRecover(mctx,
 		func(ctx context.Context) {
+			defer client.release()
 			fun(client, request)
 		},
 		func(ctx context.Context, err error) {
 			g.Log().Warningf(mctx, "handlerMsg msgGo exec err:%+v", err)
+			if request.Id != "" {
+				Reply(client, request, nil, gerror.New("消息处理异常"))
+			}
 		},
 	)
 
 	if err != nil {
+		client.release()
 		g.Log().Warningf(mctx, "handlerMsg msgGo Add err:%+v", err)
+		if request.Id != "" {
+			Reply(client, request, nil, gerror.New("服务繁忙，请稍后重试"))
+		}
 		return
 	}
 }
 
+// Reply 回复客户端请求，携带请求ID以便客户端关联，err不为nil时回复错误消息
+func Reply(client *Client, req *WRequest, data interface{}, err error) {
+	res := &WResponse{
+		Event:     req.Event,
+		RequestId: req.Id,
+		Timestamp: gtime.Now().Unix(),
+	}
+
+	if err != nil {
+		res.Code = gcode.CodeNil.Code()
+		res.ErrorMsg = err.Error()
+	} else {
+		res.Code = gcode.CodeOK.Code()
+		res.Data = data
+	}
+	client.SendMsg(res)
+	before(client)
+}
+
+// RegisterRequest 注册请求消息，处理完成后自动将结果或错误回复给客户端
+func RegisterRequest(handlers RequestHandlers) {
+	events := make(EventHandlers, len(handlers))
+	for id, f := range handlers {
+		events[id] = func(client *Client, req *WRequest) {
+			data, err := f(client, req)
+			Reply(client, req, data, err)
+		}
+	}
+	RegisterMsg(events)
+}
+
 // RegisterMsg 注册消息
 func RegisterMsg(handlers EventHandlers) {
 	for id, f := range handlers {
@@ -62,3 +131,43 @@ func RegisterMsg(handlers EventHandlers) {
 		routers[id] = f
 	}
 }
+
+// subscribeHandler 订阅频道
+func subscribeHandler(client *Client, req *WRequest) (interface{}, error) {
+	channel := gconv.String(req.Data["channel"])
+	if err := Subscribe(client, channel); err != nil {
+		return nil, err
+	}
+	return g.Map{"channel": channel, "channels": clientManager.GetClientChannels(client)}, nil
+}
+
+// unsubscribeHandler 取消订阅频道
+func unsubscribeHandler(client *Client, req *WRequest) (interface{}, error) {
+	channel := gconv.String(req.Data["channel"])
+	Unsubscribe(client, channel)
+	return g.Map{"channel": channel, "channels": clientManager.GetClientChannels(client)}, nil
+}
+
+// presenceHandler 获取频道成员，只有订阅了频道的客户端才能获取
+func presenceHandler(client *Client, req *WRequest) (interface{}, error) {
+	channel := gconv.String(req.Data["channel"])
+	if !slices.Contains(clientManager.GetClientChannels(client), channel) {
+		return nil, gerror.Newf("未订阅频道[%v]", channel)
+	}
+
+	members, err := ChannelPresence(client.Context(), channel)
+	if err != nil {
+		return nil, err
+	}
+	return g.Map{"channel": channel, "members": members}, nil
+}
+
+// replayHandler 补发离线消息，客户端重连后传入最后收到的消息序号
+func replayHandler(client *Client, req *WRequest) (interface{}, error) {
+	return replayMailbox(client.Context(), client, gconv.Int64(req.Data["seq"]))
+}
+
+// ackHandler 客户端确认收到消息，停止重发
+func ackHandler(client *Client, req *WRequest) {
+	client.ack(gconv.String(req.Data["id"]))
+}
@@ -487,6 +487,35 @@ COMMENT ON COLUMN hg_admin_order.status IS '状态';
 COMMENT ON COLUMN hg_admin_order.created_at IS '创建时间';
 COMMENT ON COLUMN hg_admin_order.updated_at IS '修改时间';
 
+-- hg_admin_order_history
+
+CREATE TABLE IF NOT EXISTS hg_admin_order_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    order_sn VARCHAR(64) DEFAULT '',
+    event VARCHAR(32) NOT NULL,
+    event_name VARCHAR(64) DEFAULT '',
+    from_status SMALLINT NOT NULL,
+    to_status SMALLINT NOT NULL,
+    created_by BIGINT DEFAULT 0,
+    remark VARCHAR(255),
+    trace_id VARCHAR(64),
+    created_at TIMESTAMP
+);
+
+COMMENT ON TABLE hg_admin_order_history IS '管理员_充值订单流转记录';
+COMMENT ON COLUMN hg_admin_order_history.id IS '记录ID';
+COMMENT ON COLUMN hg_admin_order_history.order_id IS '订单ID';
+COMMENT ON COLUMN hg_admin_order_history.order_sn IS '业务订单号';
+COMMENT ON COLUMN hg_admin_order_history.event IS '事件';
+COMMENT ON COLUMN hg_admin_order_history.event_name IS '事件名称';
+COMMENT ON COLUMN hg_admin_order_history.from_status IS '原状态';
+COMMENT ON COLUMN hg_admin_order_history.to_status IS '新状态';
+COMMENT ON COLUMN hg_admin_order_history.created_by IS '操作人，0为系统';
+COMMENT ON COLUMN hg_admin_order_history.remark IS '备注';
+COMMENT ON COLUMN hg_admin_order_history.trace_id IS '链路ID';
+COMMENT ON COLUMN hg_admin_order_history.created_at IS '创建时间';
+
 -- hg_admin_post
 
 CREATE TABLE IF NOT EXISTS hg_admin_post (
@@ -637,6 +666,82 @@ COMMENT ON COLUMN hg_pay_log.status IS '状态';
 COMMENT ON COLUMN hg_pay_log.created_at IS '创建时间';
 COMMENT ON COLUMN hg_pay_log.updated_at IS '修改时间';
 
+-- hg_pay_notify
+
+CREATE TABLE IF NOT EXISTS hg_pay_notify (
+    id BIGSERIAL PRIMARY KEY,
+    pay_type VARCHAR(13) NOT NULL,
+    out_trade_no VARCHAR(128),
+    transaction_id VARCHAR(128),
+    header TEXT,
+    body TEXT,
+    client_ip VARCHAR(128),
+    trace_id VARCHAR(64),
+    status SMALLINT DEFAULT 1,
+    err_msg VARCHAR(1000),
+    replay_count INTEGER DEFAULT 0,
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+COMMENT ON TABLE hg_pay_notify IS '支付_异步通知';
+COMMENT ON COLUMN hg_pay_notify.id IS '记录ID';
+COMMENT ON COLUMN hg_pay_notify.pay_type IS '支付方式';
+COMMENT ON COLUMN hg_pay_notify.out_trade_no IS '商户订单号';
+COMMENT ON COLUMN hg_pay_notify.transaction_id IS '交易号';
+COMMENT ON COLUMN hg_pay_notify.header IS '请求头';
+COMMENT ON COLUMN hg_pay_notify.body IS '请求报文';
+COMMENT ON COLUMN hg_pay_notify.client_ip IS '通知IP';
+COMMENT ON COLUMN hg_pay_notify.trace_id IS '链路ID';
+COMMENT ON COLUMN hg_pay_notify.status IS '处理状态';
+COMMENT ON COLUMN hg_pay_notify.err_msg IS '失败原因';
+COMMENT ON COLUMN hg_pay_notify.replay_count IS '重放次数';
+COMMENT ON COLUMN hg_pay_notify.processed_at IS '处理时间';
+COMMENT ON COLUMN hg_pay_notify.created_at IS '创建时间';
+COMMENT ON COLUMN hg_pay_notify.updated_at IS '更新时间';
+
+-- hg_pay_reconcile
+
+CREATE TABLE IF NOT EXISTS hg_pay_reconcile (
+    id BIGSERIAL PRIMARY KEY,
+    pay_type VARCHAR(13) NOT NULL,
+    bill_date DATE NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    out_trade_no VARCHAR(128),
+    transaction_id VARCHAR(128),
+    refund_sn VARCHAR(128),
+    order_sn VARCHAR(64),
+    type SMALLINT NOT NULL,
+    bill_amount NUMERIC(10,2) DEFAULT 0.00,
+    local_amount NUMERIC(10,2) DEFAULT 0.00,
+    remark VARCHAR(255),
+    handled_by BIGINT DEFAULT 0,
+    handled_at TIMESTAMP,
+    status SMALLINT DEFAULT 1,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+COMMENT ON TABLE hg_pay_reconcile IS '支付_对账差异';
+COMMENT ON COLUMN hg_pay_reconcile.id IS '记录ID';
+COMMENT ON COLUMN hg_pay_reconcile.pay_type IS '支付方式';
+COMMENT ON COLUMN hg_pay_reconcile.bill_date IS '账单日期';
+COMMENT ON COLUMN hg_pay_reconcile.kind IS '明细类型';
+COMMENT ON COLUMN hg_pay_reconcile.out_trade_no IS '商户订单号';
+COMMENT ON COLUMN hg_pay_reconcile.transaction_id IS '交易号';
+COMMENT ON COLUMN hg_pay_reconcile.refund_sn IS '退款单号';
+COMMENT ON COLUMN hg_pay_reconcile.order_sn IS '业务订单号';
+COMMENT ON COLUMN hg_pay_reconcile.type IS '差异类型';
+COMMENT ON COLUMN hg_pay_reconcile.bill_amount IS '账单金额';
+COMMENT ON COLUMN hg_pay_reconcile.local_amount IS '本地金额';
+COMMENT ON COLUMN hg_pay_reconcile.remark IS '处理说明';
+COMMENT ON COLUMN hg_pay_reconcile.handled_by IS '处理人';
+COMMENT ON COLUMN hg_pay_reconcile.handled_at IS '处理时间';
+COMMENT ON COLUMN hg_pay_reconcile.status IS '处理状态';
+COMMENT ON COLUMN hg_pay_reconcile.created_at IS '创建时间';
+COMMENT ON COLUMN hg_pay_reconcile.updated_at IS '更新时间';
+
 -- hg_pay_refund
 
 CREATE TABLE IF NOT EXISTS hg_pay_refund (
@@ -730,6 +835,7 @@ CREATE TABLE IF NOT EXISTS hg_sys_attachment (
     id BIGSERIAL PRIMARY KEY,
     app_id VARCHAR(64) NOT NULL,
     member_id BIGINT DEFAULT 0,
+    tenant_id BIGINT DEFAULT 0,
     cate_id BIGINT DEFAULT 0,
     drive VARCHAR(64),
     name VARCHAR(1000),
@@ -739,6 +845,8 @@ CREATE TABLE IF NOT EXISTS hg_sys_attachment (
     path VARCHAR(1000),
     file_url VARCHAR(1000),
     size BIGINT DEFAULT 0,
+    width INTEGER DEFAULT 0,
+    height INTEGER DEFAULT 0,
     ext VARCHAR(50),
     md5 VARCHAR(32),
     status SMALLINT NOT NULL DEFAULT 1,
@@ -750,6 +858,7 @@ COMMENT ON TABLE hg_sys_attachment IS '系统_附件管理';
 COMMENT ON COLUMN hg_sys_attachment.id IS '文件ID';
 COMMENT ON COLUMN hg_sys_attachment.app_id IS '应用ID';
 COMMENT ON COLUMN hg_sys_attachment.member_id IS '管理员ID';
+COMMENT ON COLUMN hg_sys_attachment.tenant_id IS '租户ID';
 COMMENT ON COLUMN hg_sys_attachment.cate_id IS '上传分类';
 COMMENT ON COLUMN hg_sys_attachment.drive IS '上传驱动';
 COMMENT ON COLUMN hg_sys_attachment.name IS '文件原始名';
@@ -759,6 +868,8 @@ COMMENT ON COLUMN hg_sys_attachment.naive_type IS 'NaiveUI类型';
 COMMENT ON COLUMN hg_sys_attachment.path IS '本地路径';
 COMMENT ON COLUMN hg_sys_attachment.file_url IS 'url';
 COMMENT ON COLUMN hg_sys_attachment.size IS '文件大小';
+COMMENT ON COLUMN hg_sys_attachment.width IS '图片宽度';
+COMMENT ON COLUMN hg_sys_attachment.height IS '图片高度';
 COMMENT ON COLUMN hg_sys_attachment.ext IS '扩展名';
 COMMENT ON COLUMN hg_sys_attachment.md5 IS 'md5校验码';
 COMMENT ON COLUMN hg_sys_attachment.status IS '状态';
@@ -828,6 +939,12 @@ CREATE TABLE IF NOT EXISTS hg_sys_cron (
     pattern VARCHAR(64) NOT NULL,
     policy BIGINT NOT NULL DEFAULT 1,
     count BIGINT NOT NULL DEFAULT 0,
+    cluster_mode SMALLINT NOT NULL DEFAULT 1,
+    node_label VARCHAR(255),
+    timeout INTEGER NOT NULL DEFAULT 0,
+    retry INTEGER NOT NULL DEFAULT 0,
+    alert SMALLINT NOT NULL DEFAULT 2,
+    alert_email VARCHAR(255),
     sort INTEGER DEFAULT 0,
     remark VARCHAR(500),
     status SMALLINT DEFAULT 1,
@@ -844,6 +961,12 @@ COMMENT ON COLUMN hg_sys_cron.params IS '函数参数';
 COMMENT ON COLUMN hg_sys_cron.pattern IS '表达式';
 COMMENT ON COLUMN hg_sys_cron.policy IS '策略';
 COMMENT ON COLUMN hg_sys_cron.count IS '执行次数';
+COMMENT ON COLUMN hg_sys_cron.cluster_mode IS '集群模式(1所有节点,2单节点,3指定节点)';
+COMMENT ON COLUMN hg_sys_cron.node_label IS '指定节点标签，多个用,隔开';
+COMMENT ON COLUMN hg_sys_cron.timeout IS '执行超时(秒)，0不限制';
+COMMENT ON COLUMN hg_sys_cron.retry IS '失败重试次数';
+COMMENT ON COLUMN hg_sys_cron.alert IS '失败通知(1开启,2关闭)';
+COMMENT ON COLUMN hg_sys_cron.alert_email IS '通知邮箱，多个用,隔开';
 COMMENT ON COLUMN hg_sys_cron.sort IS '排序';
 COMMENT ON COLUMN hg_sys_cron.remark IS '备注';
 COMMENT ON COLUMN hg_sys_cron.status IS '任务状态';
@@ -877,6 +1000,38 @@ COMMENT ON COLUMN hg_sys_cron_group.updated_at IS '更新时间';
 
 -- hg_sys_dict_data
 
+-- hg_sys_cron_log
+CREATE TABLE IF NOT EXISTS hg_sys_cron_log (
+    id BIGSERIAL PRIMARY KEY,
+    cron_id BIGINT NOT NULL DEFAULT 0,
+    title VARCHAR(128),
+    name VARCHAR(100),
+    node VARCHAR(64),
+    status SMALLINT NOT NULL DEFAULT 1,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    error TEXT,
+    output TEXT,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    took BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP
+);
+
+COMMENT ON TABLE hg_sys_cron_log IS '系统_定时任务执行记录';
+COMMENT ON COLUMN hg_sys_cron_log.id IS '记录ID';
+COMMENT ON COLUMN hg_sys_cron_log.cron_id IS '任务ID';
+COMMENT ON COLUMN hg_sys_cron_log.title IS '任务标题';
+COMMENT ON COLUMN hg_sys_cron_log.name IS '任务方法';
+COMMENT ON COLUMN hg_sys_cron_log.node IS '执行节点';
+COMMENT ON COLUMN hg_sys_cron_log.status IS '执行状态(1成功,2失败,3超时)';
+COMMENT ON COLUMN hg_sys_cron_log.attempts IS '执行次数';
+COMMENT ON COLUMN hg_sys_cron_log.error IS '错误信息';
+COMMENT ON COLUMN hg_sys_cron_log.output IS '执行输出';
+COMMENT ON COLUMN hg_sys_cron_log.started_at IS '开始时间';
+COMMENT ON COLUMN hg_sys_cron_log.finished_at IS '结束时间';
+COMMENT ON COLUMN hg_sys_cron_log.took IS '耗时(ms)';
+COMMENT ON COLUMN hg_sys_cron_log.created_at IS '创建时间';
+
 CREATE TABLE IF NOT EXISTS hg_sys_dict_data (
     id BIGSERIAL PRIMARY KEY,
     label VARCHAR(100),
@@ -1188,6 +1343,64 @@ COMMENT ON COLUMN hg_sys_provinces.status IS '状态';
 COMMENT ON COLUMN hg_sys_provinces.created_at IS '创建时间';
 COMMENT ON COLUMN hg_sys_provinces.updated_at IS '更新时间';
 
+-- hg_sys_queue_fail
+CREATE TABLE IF NOT EXISTS hg_sys_queue_fail (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(128) NOT NULL,
+    msg_id VARCHAR(64),
+    msg_key VARCHAR(64),
+    body TEXT,
+    error TEXT,
+    replay_count INTEGER NOT NULL DEFAULT 0,
+    handled_by BIGINT DEFAULT 0,
+    handled_at TIMESTAMP,
+    status SMALLINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+COMMENT ON TABLE hg_sys_queue_fail IS '系统_队列失败消息';
+COMMENT ON COLUMN hg_sys_queue_fail.id IS '主键';
+COMMENT ON COLUMN hg_sys_queue_fail.topic IS '消息主题';
+COMMENT ON COLUMN hg_sys_queue_fail.msg_id IS '消息ID';
+COMMENT ON COLUMN hg_sys_queue_fail.msg_key IS '幂等键';
+COMMENT ON COLUMN hg_sys_queue_fail.body IS '消息内容';
+COMMENT ON COLUMN hg_sys_queue_fail.error IS '错误信息';
+COMMENT ON COLUMN hg_sys_queue_fail.replay_count IS '重放次数';
+COMMENT ON COLUMN hg_sys_queue_fail.handled_by IS '处理人';
+COMMENT ON COLUMN hg_sys_queue_fail.handled_at IS '处理时间';
+COMMENT ON COLUMN hg_sys_queue_fail.status IS '状态(1消费失败,2死信,3已重放,4已丢弃)';
+COMMENT ON COLUMN hg_sys_queue_fail.created_at IS '创建时间';
+COMMENT ON COLUMN hg_sys_queue_fail.updated_at IS '修改时间';
+
+-- hg_sys_queue_outbox
+CREATE TABLE IF NOT EXISTS hg_sys_queue_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(128) NOT NULL,
+    msg_key VARCHAR(64) NOT NULL,
+    body TEXT,
+    status SMALLINT NOT NULL DEFAULT 1,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    next_at TIMESTAMP,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+COMMENT ON TABLE hg_sys_queue_outbox IS '系统_队列发件箱';
+COMMENT ON COLUMN hg_sys_queue_outbox.id IS '主键';
+COMMENT ON COLUMN hg_sys_queue_outbox.topic IS '消息主题';
+COMMENT ON COLUMN hg_sys_queue_outbox.msg_key IS '幂等键';
+COMMENT ON COLUMN hg_sys_queue_outbox.body IS '消息内容';
+COMMENT ON COLUMN hg_sys_queue_outbox.status IS '状态(1待投递,2投递中,3已投递)';
+COMMENT ON COLUMN hg_sys_queue_outbox.attempts IS '投递次数';
+COMMENT ON COLUMN hg_sys_queue_outbox.error IS '最近一次投递错误';
+COMMENT ON COLUMN hg_sys_queue_outbox.next_at IS '下次投递时间';
+COMMENT ON COLUMN hg_sys_queue_outbox.sent_at IS '投递成功时间';
+COMMENT ON COLUMN hg_sys_queue_outbox.created_at IS '创建时间';
+COMMENT ON COLUMN hg_sys_queue_outbox.updated_at IS '修改时间';
+
 CREATE TABLE IF NOT EXISTS hg_sys_serve_license (
     id BIGSERIAL PRIMARY KEY,
     "group" VARCHAR(50) NOT NULL,
@@ -1201,6 +1414,7 @@ CREATE TABLE IF NOT EXISTS hg_sys_serve_license (
     last_active_at TIMESTAMP,
     routes JSONB,
     allowed_ips VARCHAR(512),
+    cert_fingerprint VARCHAR(1024),
     end_at TIMESTAMP NOT NULL,
     remark VARCHAR(512),
     status SMALLINT DEFAULT 1,
@@ -1221,6 +1435,7 @@ COMMENT ON COLUMN hg_sys_serve_license.last_login_at IS '最后登录时间';
 COMMENT ON COLUMN hg_sys_serve_license.last_active_at IS '最后心跳';
 COMMENT ON COLUMN hg_sys_serve_license.routes IS '路由表，空使用默认分组路由';
 COMMENT ON COLUMN hg_sys_serve_license.allowed_ips IS 'IP白名单';
+COMMENT ON COLUMN hg_sys_serve_license.cert_fingerprint IS '绑定的客户端证书SHA256指纹，多个用,隔开，为空不限制';
 COMMENT ON COLUMN hg_sys_serve_license.end_at IS '授权有效期';
 COMMENT ON COLUMN hg_sys_serve_license.remark IS '备注';
 COMMENT ON COLUMN hg_sys_serve_license.status IS '状态';
@@ -2231,7 +2446,22 @@ INSERT INTO hg_admin_menu (
       (2419, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '多租户功能演示详情', 'tenantOrderView', '', '', 3, '', '/hgexample/tenantOrder/view', '', '', 1, '', 0, 0, '', 0, 1, 0, 10, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30'),
       (2420, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '编辑/新增多租户功能演示', 'tenantOrderEdit', '', '', 3, '', '/hgexample/tenantOrder/edit', '', '', 1, '', 0, 0, '', 0, 1, 0, 20, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30'),
       (2421, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '删除多租户功能演示', 'tenantOrderDelete', '', '', 3, '', '/hgexample/tenantOrder/delete', '', '', 1, '', 0, 0, '', 0, 0, 0, 40, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30'),
-      (2422, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '导出多租户功能演示', 'tenantOrderExport', '', '', 3, '', '/hgexample/tenantOrder/export', '', '', 1, '', 0, 0, '', 0, 0, 0, 70, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30');
+      (2422, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '导出多租户功能演示', 'tenantOrderExport', '', '', 3, '', '/hgexample/tenantOrder/export', '', '', 1, '', 0, 0, '', 0, 0, 0, 70, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30'),
+      (2431, 2090, 2, 'tr_2090 ', '消息队列', 'monitor_queue', 'queue', '', 2, '', '/queue/stats', '', '/monitor/queue/index', 1, '', 0, 0, '', 0, 0, 0, 30, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2432, 2431, 3, 'tr_2090 tr_2431 ', '失败消息列表', 'monitorQueueFailList', '', '', 3, '', '/queue/failList,/queue/failView', '', '', 1, '', 0, 0, '', 0, 0, 0, 10, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2433, 2431, 3, 'tr_2090 tr_2431 ', '重放失败消息', 'monitorQueueReplay', '', '', 3, '', '/queue/replay', '', '', 1, '', 0, 0, '', 0, 0, 0, 20, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2434, 2431, 3, 'tr_2090 tr_2431 ', '丢弃失败消息', 'monitorQueueDiscard', '', '', 3, '', '/queue/discard', '', '', 1, '', 0, 0, '', 0, 0, 0, 30, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2435, 2431, 3, 'tr_2090 tr_2431 ', '重置主题统计', 'monitorQueueResetStats', '', '', 3, '', '/queue/resetStats', '', '', 1, '', 0, 0, '', 0, 0, 0, 40, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2436, 2071, 3, 'tr_2068 tr_2071 ', '执行记录', '/cron/logList', '', '', 3, '', '/cron/logList,/cron/logView', '', '', 1, '', 0, 0, '', 0, 0, 0, 75, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2437, 2095, 3, 'tr_2093 tr_2095 ', '存储用量', 'attachmentUsage', '', '', 3, '', '/attachment/usage', '', '', 1, '', 0, 0, '', 0, 0, 0, 20, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2438, 2095, 3, 'tr_2093 tr_2095 ', '存储迁移', 'attachmentMigrate', '', '', 3, '', '/attachment/migrate,/attachment/migrateStatus', '', '', 1, '', 0, 0, '', 0, 0, 0, 30, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2439, 2237, 3, 'tr_2093 tr_2237 ', '交易对账', 'asset_pay_reconcile', 'payReconcile', '', 2, '', '/payReconcile/list', '', '/asset/payReconcile/index', 1, '', 0, 0, '', 0, 0, 0, 50, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2440, 2439, 4, 'tr_2093 tr_2237 tr_2439 ', '执行对账', 'payReconcileRun', '', '', 3, '', '/payReconcile/run', '', '', 1, '', 0, 0, '', 0, 0, 0, 10, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2441, 2439, 4, 'tr_2093 tr_2237 tr_2439 ', '处理对账差异', 'payReconcileHandle', '', '', 3, '', '/payReconcile/handle', '', '', 1, '', 0, 0, '', 0, 0, 0, 20, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2442, 2232, 4, 'tr_2093 tr_2237 tr_2232 ', '查询支付订单', 'asset_recharge_query', '', '', 3, '', '/pay/queryOrder,/pay/closeOrder', '', '', 1, '', 0, 0, '', 0, 0, 0, 50, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2443, 2237, 3, 'tr_2093 tr_2237 ', '支付通知', 'asset_pay_notify', 'payNotify', '', 2, '', '/payNotify/list,/payNotify/view', '', '/asset/payNotify/index', 1, '', 0, 0, '', 0, 0, 0, 60, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2444, 2443, 4, 'tr_2093 tr_2237 tr_2443 ', '重放支付通知', 'payNotifyReplay', '', '', 3, '', '/payNotify/replay', '', '', 1, '', 0, 0, '', 0, 0, 0, 10, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+      (2445, 2232, 4, 'tr_2093 tr_2237 tr_2232 ', '订单流转记录', 'asset_recharge_history', '', '', 3, '', '/order/history', '', '', 1, '', 0, 0, '', 0, 0, 0, 60, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00');
 
 -- --------------------------------------------------------
 
@@ -2945,7 +3175,17 @@ INSERT INTO hg_sys_config (id, "group", name, type, key, value, default_value, s
     (125, 'upload', 'minio是否启用SSL', 'int', 'uploadMinioUseSSL', '1', '', 650, '', 1, 1, '2021-01-30 13:27:43', '2024-02-28 16:56:35'),
     (126, 'upload', 'minio存储路径', 'string', 'uploadMinioPath', 'hotgo/attachment/', '', 650, '', 1, 1, '2021-01-30 13:27:43', '2024-02-28 16:56:35'),
     (127, 'upload', 'minio桶名称', 'string', 'uploadMinioBucket', '', '', 650, '', 1, 1, '2021-01-30 13:27:43', '2024-02-28 16:56:35'),
-    (128, 'upload', 'minio对外访问域名', 'string', 'uploadMinioDomain', '', '', 650, '', 1, 1, '2021-01-30 13:27:43', '2024-02-28 16:56:35');
+    (128, 'upload', 'minio对外访问域名', 'string', 'uploadMinioDomain', '', '', 650, '', 1, 1, '2021-01-30 13:27:43', '2024-02-28 16:56:35'),
+    (130, 'upload', '图片纠正方向', 'int', 'uploadImageAutoOrient', '1', '1', 342, '按EXIF方向信息旋转JPEG图片并去除EXIF信息，1：开启，2：关闭', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (131, 'upload', '图片预设尺寸', 'string', 'uploadImageSizes', '200x200,800x0', '200x200', 344, '格式：宽x高，多个用英文逗号分隔，宽或高为0时按比例缩放，第一个尺寸作为缩略图', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (132, 'upload', '图片尺寸生成方式', 'int', 'uploadImageVariantMode', '2', '2', 346, '1：上传时生成，2：首次访问时生成', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (133, 'upload', '图片转换WebP', 'int', 'uploadImageWebp', '1', '2', 348, '1：开启，2：关闭', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (134, 'upload', '用户存储容量', 'int', 'uploadQuotaMember', '0', '0', 350, '单个用户的存储容量，单位：MB，0为不限制', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (135, 'upload', '租户存储容量', 'int', 'uploadQuotaTenant', '0', '0', 352, '单个租户及其下属商户、用户的存储容量，单位：MB，0为不限制', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (136, 'upload', '应用存储容量', 'string', 'uploadQuotaApp', '', '', 354, '格式：应用:容量MB，多个用英文逗号分隔，如：api:10240,home:1024，未配置的应用不限制', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (137, 'pay', '模拟支付开关', 'bool', 'payMockPayEnabled', 'false', 'false', 940, '开启后可使用模拟支付进行本地开发和自动化测试，生产环境请关闭', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (138, 'pay', '模拟支付签名密钥', 'string', 'payMockPaySecret', '', '', 950, '模拟支付通知的HMAC-SHA256签名密钥，未配置时无法使用模拟支付', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (139, 'pay', '模拟退款失败', 'bool', 'payMockPayRefundFail', 'false', 'false', 960, '开启后模拟支付的退款申请全部返回失败', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00');
 
 -- --------------------------------------------------------
 
@@ -2958,7 +3198,10 @@ INSERT INTO hg_sys_cron (id, group_id, title, name, params, pattern, policy, cou
     (2, 1, '测试带参数', 'test2', 'hotGo,3,欢迎使用hotGo！', '* * * * * *', 1, 0, 10, '测试有参数任务', 2, '2022-10-01 06:02:09', '2023-11-17 18:38:49'),
     (3, 1, '测试带参数-多任务', 'test2', 'hotGo,3,这是同一个执行方法开多个定时任务的实例！', '* * * * * *', 1, 1, 10, '相同的执行方法，可以开启多个任务', 2, '2023-11-17 16:12:26', '2023-11-20 10:11:47'),
     (4, 1, '测试带参数-错误', 'test2', '666', '* * * * * *', 1, 1, 10, '参入一个错误的参数格式，来模拟执行出错示例', 2, '2023-11-17 18:23:39', '2023-11-17 18:39:59'),
-    (10, 1, '关闭过期订单', 'close_order', '', '0 */10 * * * *', 1, 1, 100, '取消过期订单，10分钟运行一次', 2, '2023-04-22 21:58:47', '2023-11-18 11:54:07');
+    (10, 1, '关闭过期订单', 'close_order', '', '0 */10 * * * *', 1, 1, 100, '取消过期订单，10分钟运行一次', 2, '2023-04-22 21:58:47', '2023-11-18 11:54:07'),
+    (11, 1, '回收未引用附件', 'attachment_gc', '7,500', '0 30 3 * * *', 2, 0, 110, '删除超过指定天数且未被业务数据引用的附件，参数：天数,单次处理数量。开启前请确认业务表已声明附件引用', 2, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (12, 1, '支付掉单补偿', 'pay_compensate', '5,1440,100', '0 */5 * * * *', 2, 0, 120, '主动查询超时仍未收到通知的待支付订单，已支付的补单，过期未支付的关闭。参数：创建超过多少分钟开始查询,超过多少分钟未支付则关闭,单次处理数量', 2, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+    (13, 1, '每日交易对账', 'pay_reconcile', '', '0 0 10 * * *', 2, 0, 130, '下载前一天的第三方账单与本地支付、退款记录逐笔核对。参数：支付方式，多个用,隔开，为空时核对所有已配置的支付方式', 2, '2026-10-18 10:00:00', '2026-10-18 10:00:00');
 
 -- --------------------------------------------------------
 
@@ -6901,10 +7144,21 @@ CREATE INDEX ON hg_admin_oauth (member_id);
 CREATE INDEX ON hg_admin_order (order_sn);
 CREATE INDEX ON hg_admin_order (member_id);
 
+-- hg_admin_order_history
+CREATE INDEX admin_order_history_order_id_idx ON hg_admin_order_history (order_id);
+
 -- hg_pay_log
 CREATE UNIQUE INDEX ON hg_pay_log (order_sn);
 CREATE INDEX ON hg_pay_log (member_id);
 
+-- hg_pay_notify
+CREATE INDEX pay_notify_out_trade_no_idx ON hg_pay_notify (out_trade_no);
+CREATE INDEX pay_notify_status_idx ON hg_pay_notify (status);
+
+-- hg_pay_reconcile
+CREATE INDEX pay_reconcile_bill_date_idx ON hg_pay_reconcile (pay_type, bill_date);
+CREATE INDEX pay_reconcile_out_trade_no_idx ON hg_pay_reconcile (out_trade_no);
+
 -- hg_pay_refund
 CREATE INDEX ON hg_pay_refund (order_sn);
 
@@ -6918,6 +7172,8 @@ CREATE UNIQUE INDEX ON hg_sys_addons_install (name);
 
 -- hg_sys_attachment
 CREATE INDEX ON hg_sys_attachment (md5);
+CREATE INDEX ON hg_sys_attachment (member_id);
+CREATE INDEX ON hg_sys_attachment (tenant_id);
 
 -- hg_sys_blacklist
 CREATE UNIQUE INDEX ON hg_sys_blacklist (ip);
@@ -6931,6 +7187,10 @@ CREATE INDEX ON hg_sys_config (key);
 --
 CREATE INDEX dict_data_idx ON hg_sys_dict_data (type);
 
+-- hg_sys_cron_log
+CREATE INDEX cron_log_cron_id_started_at_idx ON hg_sys_cron_log (cron_id, started_at);
+CREATE INDEX cron_log_started_at_idx ON hg_sys_cron_log (started_at);
+
 -- hg_sys_dict_type
 CREATE UNIQUE INDEX dict_type_idx ON hg_sys_dict_type (type);
 
@@ -6949,6 +7209,14 @@ CREATE INDEX login_log_req_id_idx ON hg_sys_login_log (req_id);
 -- hg_sys_provinces
 CREATE INDEX provinces_pid_idx ON hg_sys_provinces (pid);
 
+-- hg_sys_queue_fail
+CREATE INDEX queue_fail_topic_idx ON hg_sys_queue_fail (topic);
+CREATE INDEX queue_fail_status_idx ON hg_sys_queue_fail (status);
+
+-- hg_sys_queue_outbox
+CREATE UNIQUE INDEX queue_outbox_msg_key_idx ON hg_sys_queue_outbox (msg_key);
+CREATE INDEX queue_outbox_status_next_at_idx ON hg_sys_queue_outbox (status, next_at);
+
 -- hg_sys_serve_license
 CREATE UNIQUE INDEX serve_license_appid_idx ON hg_sys_serve_license (appid);
 
@@ -6979,7 +7247,7 @@ ALTER SEQUENCE hg_admin_dept_id_seq RESTART WITH 113;
 ALTER SEQUENCE hg_admin_member_id_seq RESTART WITH 14;
 
 -- hg_admin_menu
-ALTER SEQUENCE hg_admin_menu_id_seq RESTART WITH 2431;
+ALTER SEQUENCE hg_admin_menu_id_seq RESTART WITH 2446;
 
 -- hg_admin_notice
 ALTER SEQUENCE hg_admin_notice_id_seq RESTART WITH 33;
@@ -7021,10 +7289,10 @@ ALTER SEQUENCE hg_sys_attachment_id_seq RESTART WITH 9;
 ALTER SEQUENCE hg_sys_blacklist_id_seq RESTART WITH 8;
 
 -- hg_sys_config
-ALTER SEQUENCE hg_sys_config_id_seq RESTART WITH 129;
+ALTER SEQUENCE hg_sys_config_id_seq RESTART WITH 140;
 
 -- hg_sys_cron
-ALTER SEQUENCE hg_sys_cron_id_seq RESTART WITH 11;
+ALTER SEQUENCE hg_sys_cron_id_seq RESTART WITH 14;
 
 -- hg_sys_cron_group
 ALTER SEQUENCE hg_sys_cron_group_id_seq RESTART WITH 3;
@@ -329,7 +329,7 @@ CREATE TABLE IF NOT EXISTS `hg_admin_menu` (
   `status` tinyint(1) DEFAULT '1' COMMENT '菜单状态',
   `updated_at` datetime DEFAULT NULL COMMENT '更新时间',
   `created_at` datetime DEFAULT NULL COMMENT '创建时间'
-) ENGINE=InnoDB AUTO_INCREMENT=2431 DEFAULT CHARSET=utf8mb4 COMMENT='管理员_菜单权限';
+) ENGINE=InnoDB AUTO_INCREMENT=2446 DEFAULT CHARSET=utf8mb4 COMMENT='管理员_菜单权限';
 
 --
 -- 转存表中的数据 `hg_admin_menu`
@@ -519,7 +519,22 @@ INSERT INTO `hg_admin_menu` (`id`, `pid`, `level`, `tree`, `title`, `name`, `pat
 (2419, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '多租户功能演示详情', 'tenantOrderView', '', '', 3, '', '/hgexample/tenantOrder/view', '', '', 1, '', 0, 0, '', 0, 1, 0, 10, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30'),
 (2420, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '编辑/新增多租户功能演示', 'tenantOrderEdit', '', '', 3, '', '/hgexample/tenantOrder/edit', '', '', 1, '', 0, 0, '', 0, 1, 0, 20, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30'),
 (2421, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '删除多租户功能演示', 'tenantOrderDelete', '', '', 3, '', '/hgexample/tenantOrder/delete', '', '', 1, '', 0, 0, '', 0, 0, 0, 40, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30'),
-(2422, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '导出多租户功能演示', 'tenantOrderExport', '', '', 3, '', '/hgexample/tenantOrder/export', '', '', 1, '', 0, 0, '', 0, 0, 0, 70, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30');
+(2422, 2418, 5, 'tr_2227 tr_2228 tr_2417 tr_2418 ', '导出多租户功能演示', 'tenantOrderExport', '', '', 3, '', '/hgexample/tenantOrder/export', '', '', 1, '', 0, 0, '', 0, 0, 0, 70, '', 1, '2024-04-13 23:37:30', '2024-04-13 23:37:30'),
+(2431, 2090, 2, 'tr_2090 ', '消息队列', 'monitor_queue', 'queue', '', 2, '', '/queue/stats', '', '/monitor/queue/index', 1, '', 0, 0, '', 0, 0, 0, 30, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2432, 2431, 3, 'tr_2090 tr_2431 ', '失败消息列表', 'monitorQueueFailList', '', '', 3, '', '/queue/failList,/queue/failView', '', '', 1, '', 0, 0, '', 0, 0, 0, 10, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2433, 2431, 3, 'tr_2090 tr_2431 ', '重放失败消息', 'monitorQueueReplay', '', '', 3, '', '/queue/replay', '', '', 1, '', 0, 0, '', 0, 0, 0, 20, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2434, 2431, 3, 'tr_2090 tr_2431 ', '丢弃失败消息', 'monitorQueueDiscard', '', '', 3, '', '/queue/discard', '', '', 1, '', 0, 0, '', 0, 0, 0, 30, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2435, 2431, 3, 'tr_2090 tr_2431 ', '重置主题统计', 'monitorQueueResetStats', '', '', 3, '', '/queue/resetStats', '', '', 1, '', 0, 0, '', 0, 0, 0, 40, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2436, 2071, 3, 'tr_2068 tr_2071 ', '执行记录', '/cron/logList', '', '', 3, '', '/cron/logList,/cron/logView', '', '', 1, '', 0, 0, '', 0, 0, 0, 75, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2437, 2095, 3, 'tr_2093 tr_2095 ', '存储用量', 'attachmentUsage', '', '', 3, '', '/attachment/usage', '', '', 1, '', 0, 0, '', 0, 0, 0, 20, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2438, 2095, 3, 'tr_2093 tr_2095 ', '存储迁移', 'attachmentMigrate', '', '', 3, '', '/attachment/migrate,/attachment/migrateStatus', '', '', 1, '', 0, 0, '', 0, 0, 0, 30, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2439, 2237, 3, 'tr_2093 tr_2237 ', '交易对账', 'asset_pay_reconcile', 'payReconcile', '', 2, '', '/payReconcile/list', '', '/asset/payReconcile/index', 1, '', 0, 0, '', 0, 0, 0, 50, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2440, 2439, 4, 'tr_2093 tr_2237 tr_2439 ', '执行对账', 'payReconcileRun', '', '', 3, '', '/payReconcile/run', '', '', 1, '', 0, 0, '', 0, 0, 0, 10, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2441, 2439, 4, 'tr_2093 tr_2237 tr_2439 ', '处理对账差异', 'payReconcileHandle', '', '', 3, '', '/payReconcile/handle', '', '', 1, '', 0, 0, '', 0, 0, 0, 20, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2442, 2232, 4, 'tr_2093 tr_2237 tr_2232 ', '查询支付订单', 'asset_recharge_query', '', '', 3, '', '/pay/queryOrder,/pay/closeOrder', '', '', 1, '', 0, 0, '', 0, 0, 0, 50, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2443, 2237, 3, 'tr_2093 tr_2237 ', '支付通知', 'asset_pay_notify', 'payNotify', '', 2, '', '/payNotify/list,/payNotify/view', '', '/asset/payNotify/index', 1, '', 0, 0, '', 0, 0, 0, 60, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2444, 2443, 4, 'tr_2093 tr_2237 tr_2443 ', '重放支付通知', 'payNotifyReplay', '', '', 3, '', '/payNotify/replay', '', '', 1, '', 0, 0, '', 0, 0, 0, 10, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(2445, 2232, 4, 'tr_2093 tr_2237 tr_2232 ', '订单流转记录', 'asset_recharge_history', '', '', 3, '', '/order/history', '', '', 1, '', 0, 0, '', 0, 0, 0, 60, '', 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00');
 
 -- --------------------------------------------------------
 
@@ -630,6 +645,26 @@ CREATE TABLE IF NOT EXISTS `hg_admin_order` (
 
 -- --------------------------------------------------------
 
+--
+-- 表的结构 `hg_admin_order_history`
+--
+
+CREATE TABLE IF NOT EXISTS `hg_admin_order_history` (
+  `id` bigint(20) NOT NULL COMMENT '记录ID',
+  `order_id` bigint(20) NOT NULL COMMENT '订单ID',
+  `order_sn` varchar(64) DEFAULT '' COMMENT '业务订单号',
+  `event` varchar(32) NOT NULL COMMENT '事件',
+  `event_name` varchar(64) DEFAULT '' COMMENT '事件名称',
+  `from_status` tinyint(4) NOT NULL COMMENT '原状态',
+  `to_status` tinyint(4) NOT NULL COMMENT '新状态',
+  `created_by` bigint(20) DEFAULT '0' COMMENT '操作人，0为系统',
+  `remark` varchar(255) DEFAULT NULL COMMENT '备注',
+  `trace_id` varchar(64) DEFAULT NULL COMMENT '链路ID',
+  `created_at` datetime DEFAULT NULL COMMENT '创建时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='管理员_充值订单流转记录';
+
+-- --------------------------------------------------------
+
 --
 -- 表的结构 `hg_admin_post`
 --
@@ -1267,6 +1302,55 @@ CREATE TABLE IF NOT EXISTS `hg_pay_log` (
 
 -- --------------------------------------------------------
 
+--
+-- 表的结构 `hg_pay_notify`
+--
+
+CREATE TABLE IF NOT EXISTS `hg_pay_notify` (
+  `id` bigint(20) NOT NULL COMMENT '记录ID',
+  `pay_type` varchar(13) NOT NULL COMMENT '支付方式',
+  `out_trade_no` varchar(128) DEFAULT NULL COMMENT '商户订单号',
+  `transaction_id` varchar(128) DEFAULT NULL COMMENT '交易号',
+  `header` text COMMENT '请求头',
+  `body` longtext COMMENT '请求报文',
+  `client_ip` varchar(128) DEFAULT NULL COMMENT '通知IP',
+  `trace_id` varchar(64) DEFAULT NULL COMMENT '链路ID',
+  `status` tinyint(1) DEFAULT '1' COMMENT '处理状态',
+  `err_msg` varchar(1000) DEFAULT NULL COMMENT '失败原因',
+  `replay_count` int(11) DEFAULT '0' COMMENT '重放次数',
+  `processed_at` datetime DEFAULT NULL COMMENT '处理时间',
+  `created_at` datetime DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime DEFAULT NULL COMMENT '更新时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='支付_异步通知';
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `hg_pay_reconcile`
+--
+
+CREATE TABLE IF NOT EXISTS `hg_pay_reconcile` (
+  `id` bigint(20) NOT NULL COMMENT '记录ID',
+  `pay_type` varchar(13) NOT NULL COMMENT '支付方式',
+  `bill_date` date NOT NULL COMMENT '账单日期',
+  `kind` varchar(16) NOT NULL COMMENT '明细类型',
+  `out_trade_no` varchar(128) DEFAULT NULL COMMENT '商户订单号',
+  `transaction_id` varchar(128) DEFAULT NULL COMMENT '交易号',
+  `refund_sn` varchar(128) DEFAULT NULL COMMENT '退款单号',
+  `order_sn` varchar(64) DEFAULT NULL COMMENT '业务订单号',
+  `type` tinyint(1) NOT NULL COMMENT '差异类型',
+  `bill_amount` decimal(10,2) DEFAULT '0.00' COMMENT '账单金额',
+  `local_amount` decimal(10,2) DEFAULT '0.00' COMMENT '本地金额',
+  `remark` varchar(255) DEFAULT NULL COMMENT '处理说明',
+  `handled_by` bigint(20) DEFAULT '0' COMMENT '处理人',
+  `handled_at` datetime DEFAULT NULL COMMENT '处理时间',
+  `status` tinyint(1) DEFAULT '1' COMMENT '处理状态',
+  `created_at` datetime DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime DEFAULT NULL COMMENT '更新时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='支付_对账差异';
+
+-- --------------------------------------------------------
+
 --
 -- 表的结构 `hg_pay_refund`
 --
@@ -1349,6 +1433,7 @@ CREATE TABLE IF NOT EXISTS `hg_sys_attachment` (
   `id` bigint(20) NOT NULL COMMENT '文件ID',
   `app_id` varchar(64) NOT NULL COMMENT '应用ID',
   `member_id` bigint(20) DEFAULT '0' COMMENT '管理员ID',
+  `tenant_id` bigint(20) DEFAULT '0' COMMENT '租户ID',
   `cate_id` bigint(20) unsigned DEFAULT '0' COMMENT '上传分类',
   `drive` varchar(64) DEFAULT NULL COMMENT '上传驱动',
   `name` varchar(1000) DEFAULT NULL COMMENT '文件原始名',
@@ -1358,6 +1443,8 @@ CREATE TABLE IF NOT EXISTS `hg_sys_attachment` (
   `path` varchar(1000) DEFAULT NULL COMMENT '本地路径',
   `file_url` varchar(1000) DEFAULT NULL COMMENT 'url',
   `size` bigint(20) DEFAULT '0' COMMENT '文件大小',
+  `width` int(11) DEFAULT '0' COMMENT '图片宽度',
+  `height` int(11) DEFAULT '0' COMMENT '图片高度',
   `ext` varchar(50) DEFAULT NULL COMMENT '扩展名',
   `md5` varchar(32) DEFAULT NULL COMMENT 'md5校验码',
   `status` tinyint(1) NOT NULL DEFAULT '1' COMMENT '状态',
@@ -1412,7 +1499,7 @@ CREATE TABLE IF NOT EXISTS `hg_sys_config` (
   `status` tinyint(1) DEFAULT '1' COMMENT '状态',
   `created_at` datetime DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime DEFAULT NULL COMMENT '更新时间'
-) ENGINE=InnoDB AUTO_INCREMENT=129 DEFAULT CHARSET=utf8mb4 COMMENT='系统_配置';
+) ENGINE=InnoDB AUTO_INCREMENT=137 DEFAULT CHARSET=utf8mb4 COMMENT='系统_配置';
 
 --
 -- 转存表中的数据 `hg_sys_config`
@@ -1528,7 +1615,17 @@ INSERT INTO `hg_sys_config` (`id`, `group`, `name`, `type`, `key`, `value`, `def
 (126, 'upload', 'minio存储路径', 'string', 'uploadMinioPath', 'hotgo/attachment/', '', 650, '', 1, 1, '2021-01-30 13:27:43', '2024-02-28 16:56:35'),
 (127, 'upload', 'minio桶名称', 'string', 'uploadMinioBucket', '', '', 650, '', 1, 1, '2021-01-30 13:27:43', '2024-02-28 16:56:35'),
 (128, 'upload', 'minio对外访问域名', 'string', 'uploadMinioDomain', '', '', 650, '', 1, 1, '2021-01-30 13:27:43', '2024-02-28 16:56:35'),
-(129, 'login', '验证码方式', 'int', 'loginCaptchaType', '1', '2', 1200, '', 1, 1, '2025-06-25 17:04:39', '2025-06-25 17:23:15');
+(129, 'login', '验证码方式', 'int', 'loginCaptchaType', '1', '2', 1200, '', 1, 1, '2025-06-25 17:04:39', '2025-06-25 17:23:15'),
+(130, 'upload', '图片纠正方向', 'int', 'uploadImageAutoOrient', '1', '1', 342, '按EXIF方向信息旋转JPEG图片并去除EXIF信息，1：开启，2：关闭', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(131, 'upload', '图片预设尺寸', 'string', 'uploadImageSizes', '200x200,800x0', '200x200', 344, '格式：宽x高，多个用英文逗号分隔，宽或高为0时按比例缩放，第一个尺寸作为缩略图', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(132, 'upload', '图片尺寸生成方式', 'int', 'uploadImageVariantMode', '2', '2', 346, '1：上传时生成，2：首次访问时生成', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(133, 'upload', '图片转换WebP', 'int', 'uploadImageWebp', '1', '2', 348, '1：开启，2：关闭', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(134, 'upload', '用户存储容量', 'int', 'uploadQuotaMember', '0', '0', 350, '单个用户的存储容量，单位：MB，0为不限制', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(135, 'upload', '租户存储容量', 'int', 'uploadQuotaTenant', '0', '0', 352, '单个租户及其下属商户、用户的存储容量，单位：MB，0为不限制', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(136, 'upload', '应用存储容量', 'string', 'uploadQuotaApp', '', '', 354, '格式：应用:容量MB，多个用英文逗号分隔，如：api:10240,home:1024，未配置的应用不限制', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(137, 'pay', '模拟支付开关', 'bool', 'payMockPayEnabled', 'false', 'false', 940, '开启后可使用模拟支付进行本地开发和自动化测试，生产环境请关闭', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(138, 'pay', '模拟支付签名密钥', 'string', 'payMockPaySecret', '', '', 950, '模拟支付通知的HMAC-SHA256签名密钥，未配置时无法使用模拟支付', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(139, 'pay', '模拟退款失败', 'bool', 'payMockPayRefundFail', 'false', 'false', 960, '开启后模拟支付的退款申请全部返回失败', 1, 1, '2026-10-18 10:00:00', '2026-10-18 10:00:00');
 -- --------------------------------------------------------
 
 --
@@ -1544,12 +1641,18 @@ CREATE TABLE IF NOT EXISTS `hg_sys_cron` (
   `pattern` varchar(64) NOT NULL COMMENT '表达式',
   `policy` bigint(20) NOT NULL DEFAULT '1' COMMENT '策略',
   `count` bigint(20) NOT NULL DEFAULT '0' COMMENT '执行次数',
+  `cluster_mode` tinyint(1) NOT NULL DEFAULT '1' COMMENT '集群模式(1所有节点,2单节点,3指定节点)',
+  `node_label` varchar(255) DEFAULT NULL COMMENT '指定节点标签，多个用,隔开',
+  `timeout` int(11) NOT NULL DEFAULT '0' COMMENT '执行超时(秒)，0不限制',
+  `retry` int(11) NOT NULL DEFAULT '0' COMMENT '失败重试次数',
+  `alert` tinyint(1) NOT NULL DEFAULT '2' COMMENT '失败通知(1开启,2关闭)',
+  `alert_email` varchar(255) DEFAULT NULL COMMENT '通知邮箱，多个用,隔开',
   `sort` int(11) DEFAULT '0' COMMENT '排序',
   `remark` varchar(500) DEFAULT NULL COMMENT '备注',
   `status` tinyint(1) DEFAULT '1' COMMENT '任务状态',
   `created_at` datetime DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime DEFAULT NULL COMMENT '更新时间'
-) ENGINE=InnoDB AUTO_INCREMENT=11 DEFAULT CHARSET=utf8mb4 COMMENT='系统_定时任务';
+) ENGINE=InnoDB AUTO_INCREMENT=14 DEFAULT CHARSET=utf8mb4 COMMENT='系统_定时任务';
 
 --
 -- 转存表中的数据 `hg_sys_cron`
@@ -1560,7 +1663,10 @@ INSERT INTO `hg_sys_cron` (`id`, `group_id`, `title`, `name`, `params`, `pattern
 (2, 1, '测试带参数', 'test2', 'hotGo,3,欢迎使用hotGo！', '* * * * * *', 1, 0, 10, '测试有参数任务', 2, '2022-10-01 06:02:09', '2023-11-17 18:38:49'),
 (3, 1, '测试带参数-多任务', 'test2', 'hotGo,3,这是同一个执行方法开多个定时任务的实例！', '* * * * * *', 1, 1, 10, '相同的执行方法，可以开启多个任务', 2, '2023-11-17 16:12:26', '2023-11-20 10:11:47'),
 (4, 1, '测试带参数-错误', 'test2', '666', '* * * * * *', 1, 1, 10, '参入一个错误的参数格式，来模拟执行出错示例', 2, '2023-11-17 18:23:39', '2023-11-17 18:39:59'),
-(10, 1, '关闭过期订单', 'close_order', '', '0 */10 * * * *', 1, 1, 100, '取消过期订单，10分钟运行一次', 2, '2023-04-22 21:58:47', '2023-11-18 11:54:07');
+(10, 1, '关闭过期订单', 'close_order', '', '0 */10 * * * *', 1, 1, 100, '取消过期订单，10分钟运行一次', 2, '2023-04-22 21:58:47', '2023-11-18 11:54:07'),
+(11, 1, '回收未引用附件', 'attachment_gc', '7,500', '0 30 3 * * *', 2, 0, 110, '删除超过指定天数且未被业务数据引用的附件，参数：天数,单次处理数量。开启前请确认业务表已声明附件引用', 2, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(12, 1, '支付掉单补偿', 'pay_compensate', '5,1440,100', '0 */5 * * * *', 2, 0, 120, '主动查询超时仍未收到通知的待支付订单，已支付的补单，过期未支付的关闭。参数：创建超过多少分钟开始查询,超过多少分钟未支付则关闭,单次处理数量', 2, '2026-10-18 10:00:00', '2026-10-18 10:00:00'),
+(13, 1, '每日交易对账', 'pay_reconcile', '', '0 0 10 * * *', 2, 0, 130, '下载前一天的第三方账单与本地支付、退款记录逐笔核对。参数：支付方式，多个用,隔开，为空时核对所有已配置的支付方式', 2, '2026-10-18 10:00:00', '2026-10-18 10:00:00');
 
 -- --------------------------------------------------------
 
@@ -1590,6 +1696,28 @@ INSERT INTO `hg_sys_cron_group` (`id`, `pid`, `name`, `is_default`, `sort`, `rem
 
 -- --------------------------------------------------------
 
+--
+-- 表的结构 `hg_sys_cron_log`
+--
+
+CREATE TABLE IF NOT EXISTS `hg_sys_cron_log` (
+  `id` bigint(20) NOT NULL COMMENT '记录ID',
+  `cron_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '任务ID',
+  `title` varchar(128) DEFAULT NULL COMMENT '任务标题',
+  `name` varchar(100) DEFAULT NULL COMMENT '任务方法',
+  `node` varchar(64) DEFAULT NULL COMMENT '执行节点',
+  `status` tinyint(1) NOT NULL DEFAULT '1' COMMENT '执行状态(1成功,2失败,3超时)',
+  `attempts` int(11) NOT NULL DEFAULT '1' COMMENT '执行次数',
+  `error` text COMMENT '错误信息',
+  `output` text COMMENT '执行输出',
+  `started_at` datetime DEFAULT NULL COMMENT '开始时间',
+  `finished_at` datetime DEFAULT NULL COMMENT '结束时间',
+  `took` bigint(20) NOT NULL DEFAULT '0' COMMENT '耗时(ms)',
+  `created_at` datetime DEFAULT NULL COMMENT '创建时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='系统_定时任务执行记录';
+
+-- --------------------------------------------------------
+
 --
 -- 表的结构 `hg_sys_dict_data`
 --
@@ -5637,6 +5765,47 @@ INSERT INTO `hg_sys_provinces` (`id`, `title`, `pinyin`, `lng`, `lat`, `pid`, `l
 
 -- --------------------------------------------------------
 
+--
+-- 表的结构 `hg_sys_queue_fail`
+--
+
+CREATE TABLE IF NOT EXISTS `hg_sys_queue_fail` (
+  `id` bigint(20) NOT NULL COMMENT '主键',
+  `topic` varchar(128) NOT NULL COMMENT '消息主题',
+  `msg_id` varchar(64) DEFAULT NULL COMMENT '消息ID',
+  `msg_key` varchar(64) DEFAULT NULL COMMENT '幂等键',
+  `body` longtext COMMENT '消息内容',
+  `error` text COMMENT '错误信息',
+  `replay_count` int(11) NOT NULL DEFAULT '0' COMMENT '重放次数',
+  `handled_by` bigint(20) DEFAULT '0' COMMENT '处理人',
+  `handled_at` datetime DEFAULT NULL COMMENT '处理时间',
+  `status` tinyint(1) NOT NULL DEFAULT '1' COMMENT '状态(1消费失败,2死信,3已重放,4已丢弃)',
+  `created_at` datetime DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime DEFAULT NULL COMMENT '修改时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='系统_队列失败消息';
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `hg_sys_queue_outbox`
+--
+
+CREATE TABLE IF NOT EXISTS `hg_sys_queue_outbox` (
+  `id` bigint(20) NOT NULL COMMENT '主键',
+  `topic` varchar(128) NOT NULL COMMENT '消息主题',
+  `msg_key` varchar(64) NOT NULL COMMENT '幂等键',
+  `body` longtext COMMENT '消息内容',
+  `status` tinyint(1) NOT NULL DEFAULT '1' COMMENT '状态(1待投递,2投递中,3已投递)',
+  `attempts` int(11) NOT NULL DEFAULT '0' COMMENT '投递次数',
+  `error` text COMMENT '最近一次投递错误',
+  `next_at` datetime DEFAULT NULL COMMENT '下次投递时间',
+  `sent_at` datetime DEFAULT NULL COMMENT '投递成功时间',
+  `created_at` datetime DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime DEFAULT NULL COMMENT '修改时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='系统_队列发件箱';
+
+-- --------------------------------------------------------
+
 --
 -- 表的结构 `hg_sys_serve_license`
 --
@@ -5654,6 +5823,7 @@ CREATE TABLE IF NOT EXISTS `hg_sys_serve_license` (
   `last_active_at` datetime DEFAULT NULL COMMENT '最后心跳',
   `routes` json DEFAULT NULL COMMENT '路由表，空使用默认分组路由',
   `allowed_ips` varchar(512) DEFAULT NULL COMMENT 'IP白名单',
+  `cert_fingerprint` varchar(1024) DEFAULT NULL COMMENT '绑定的客户端证书SHA256指纹，多个用,隔开，为空不限制',
   `end_at` datetime NOT NULL COMMENT '授权有效期',
   `remark` varchar(512) DEFAULT NULL COMMENT '备注',
   `status` tinyint(1) DEFAULT '1' COMMENT '状态',
@@ -5843,6 +6013,13 @@ ALTER TABLE `hg_admin_order`
   ADD KEY `order_sn` (`order_sn`),
   ADD KEY `member_id` (`member_id`);
 
+--
+-- Indexes for table `hg_admin_order_history`
+--
+ALTER TABLE `hg_admin_order_history`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `order_id` (`order_id`);
+
 --
 -- Indexes for table `hg_admin_post`
 --
@@ -5875,6 +6052,22 @@ ALTER TABLE `hg_pay_log`
   ADD UNIQUE KEY `order_sn` (`order_sn`),
   ADD KEY `member_id` (`member_id`);
 
+--
+-- Indexes for table `hg_pay_notify`
+--
+ALTER TABLE `hg_pay_notify`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `out_trade_no` (`out_trade_no`),
+  ADD KEY `status` (`status`);
+
+--
+-- Indexes for table `hg_pay_reconcile`
+--
+ALTER TABLE `hg_pay_reconcile`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `bill_date` (`pay_type`,`bill_date`),
+  ADD KEY `out_trade_no` (`out_trade_no`);
+
 --
 -- Indexes for table `hg_pay_refund`
 --
@@ -5903,7 +6096,9 @@ ALTER TABLE `hg_sys_addons_install`
 --
 ALTER TABLE `hg_sys_attachment`
   ADD PRIMARY KEY (`id`),
-  ADD KEY `md5` (`md5`);
+  ADD KEY `md5` (`md5`),
+  ADD KEY `member_id` (`member_id`),
+  ADD KEY `tenant_id` (`tenant_id`);
 
 --
 -- Indexes for table `hg_sys_blacklist`
@@ -5932,6 +6127,14 @@ ALTER TABLE `hg_sys_cron`
 ALTER TABLE `hg_sys_cron_group`
   ADD PRIMARY KEY (`id`) USING BTREE;
 
+--
+-- Indexes for table `hg_sys_cron_log`
+--
+ALTER TABLE `hg_sys_cron_log`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `cron_id` (`cron_id`,`started_at`),
+  ADD KEY `started_at` (`started_at`);
+
 --
 -- Indexes for table `hg_sys_dict_data`
 --
@@ -5995,6 +6198,22 @@ ALTER TABLE `hg_sys_provinces`
   ADD PRIMARY KEY (`id`),
   ADD KEY `pid` (`pid`);
 
+--
+-- Indexes for table `hg_sys_queue_fail`
+--
+ALTER TABLE `hg_sys_queue_fail`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `topic` (`topic`),
+  ADD KEY `status` (`status`);
+
+--
+-- Indexes for table `hg_sys_queue_outbox`
+--
+ALTER TABLE `hg_sys_queue_outbox`
+  ADD PRIMARY KEY (`id`),
+  ADD UNIQUE KEY `msg_key` (`msg_key`),
+  ADD KEY `status` (`status`,`next_at`);
+
 --
 -- Indexes for table `hg_sys_serve_license`
 --
@@ -6061,7 +6280,7 @@ ALTER TABLE `hg_admin_member`
 -- AUTO_INCREMENT for table `hg_admin_menu`
 --
 ALTER TABLE `hg_admin_menu`
-  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '菜单ID',AUTO_INCREMENT=2431;
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '菜单ID',AUTO_INCREMENT=2446;
 --
 -- AUTO_INCREMENT for table `hg_admin_notice`
 --
@@ -6083,6 +6302,11 @@ ALTER TABLE `hg_admin_oauth`
 ALTER TABLE `hg_admin_order`
   MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键',AUTO_INCREMENT=2;
 --
+-- AUTO_INCREMENT for table `hg_admin_order_history`
+--
+ALTER TABLE `hg_admin_order_history`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '记录ID';
+--
 -- AUTO_INCREMENT for table `hg_admin_post`
 --
 ALTER TABLE `hg_admin_post`
@@ -6103,6 +6327,16 @@ ALTER TABLE `hg_admin_role_casbin`
 ALTER TABLE `hg_pay_log`
   MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键',AUTO_INCREMENT=2;
 --
+-- AUTO_INCREMENT for table `hg_pay_notify`
+--
+ALTER TABLE `hg_pay_notify`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '记录ID';
+--
+-- AUTO_INCREMENT for table `hg_pay_reconcile`
+--
+ALTER TABLE `hg_pay_reconcile`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '记录ID';
+--
 -- AUTO_INCREMENT for table `hg_pay_refund`
 --
 ALTER TABLE `hg_pay_refund`
@@ -6131,18 +6365,23 @@ ALTER TABLE `hg_sys_blacklist`
 -- AUTO_INCREMENT for table `hg_sys_config`
 --
 ALTER TABLE `hg_sys_config`
-  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '配置ID',AUTO_INCREMENT=129;
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '配置ID',AUTO_INCREMENT=140;
 --
 -- AUTO_INCREMENT for table `hg_sys_cron`
 --
 ALTER TABLE `hg_sys_cron`
-  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '任务ID',AUTO_INCREMENT=11;
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '任务ID',AUTO_INCREMENT=14;
 --
 -- AUTO_INCREMENT for table `hg_sys_cron_group`
 --
 ALTER TABLE `hg_sys_cron_group`
   MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '任务分组ID',AUTO_INCREMENT=3;
 --
+-- AUTO_INCREMENT for table `hg_sys_cron_log`
+--
+ALTER TABLE `hg_sys_cron_log`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '记录ID';
+--
 -- AUTO_INCREMENT for table `hg_sys_dict_data`
 --
 ALTER TABLE `hg_sys_dict_data`
@@ -6183,6 +6422,16 @@ ALTER TABLE `hg_sys_log`
 ALTER TABLE `hg_sys_login_log`
   MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '日志ID';
 --
+-- AUTO_INCREMENT for table `hg_sys_queue_fail`
+--
+ALTER TABLE `hg_sys_queue_fail`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键';
+--
+-- AUTO_INCREMENT for table `hg_sys_queue_outbox`
+--
+ALTER TABLE `hg_sys_queue_outbox`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键';
+--
 -- AUTO_INCREMENT for table `hg_sys_serve_license`
 --
 ALTER TABLE `hg_sys_serve_license`
@@ -238,7 +238,22 @@ INSERT INTO `hg_admin_menu` (`id`, `pid`, `level`, `tree`, `title`, `name`, `pat
 (2419,	2418,	5,	'tr_2227 tr_2228 tr_2417 tr_2418 ',	'多租户功能演示详情',	'tenantOrderView',	'',	'',	3,	'',	'/hgexample/tenantOrder/view',	'',	'',	1,	'',	0,	0,	'',	0,	1,	0,	10,	'',	1,	'2024-04-13 23:37:30',	'2024-04-13 23:37:30'),
 (2420,	2418,	5,	'tr_2227 tr_2228 tr_2417 tr_2418 ',	'编辑/新增多租户功能演示',	'tenantOrderEdit',	'',	'',	3,	'',	'/hgexample/tenantOrder/edit',	'',	'',	1,	'',	0,	0,	'',	0,	1,	0,	20,	'',	1,	'2024-04-13 23:37:30',	'2024-04-13 23:37:30'),
 (2421,	2418,	5,	'tr_2227 tr_2228 tr_2417 tr_2418 ',	'删除多租户功能演示',	'tenantOrderDelete',	'',	'',	3,	'',	'/hgexample/tenantOrder/delete',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	40,	'',	1,	'2024-04-13 23:37:30',	'2024-04-13 23:37:30'),
-(2422,	2418,	5,	'tr_2227 tr_2228 tr_2417 tr_2418 ',	'导出多租户功能演示',	'tenantOrderExport',	'',	'',	3,	'',	'/hgexample/tenantOrder/export',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	70,	'',	1,	'2024-04-13 23:37:30',	'2024-04-13 23:37:30');
+(2422,	2418,	5,	'tr_2227 tr_2228 tr_2417 tr_2418 ',	'导出多租户功能演示',	'tenantOrderExport',	'',	'',	3,	'',	'/hgexample/tenantOrder/export',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	70,	'',	1,	'2024-04-13 23:37:30',	'2024-04-13 23:37:30'),
+(2431,	2090,	2,	'tr_2090 ',	'消息队列',	'monitor_queue',	'queue',	'',	2,	'',	'/queue/stats',	'',	'/monitor/queue/index',	1,	'',	0,	0,	'',	0,	0,	0,	30,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2432,	2431,	3,	'tr_2090 tr_2431 ',	'失败消息列表',	'monitorQueueFailList',	'',	'',	3,	'',	'/queue/failList,/queue/failView',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	10,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2433,	2431,	3,	'tr_2090 tr_2431 ',	'重放失败消息',	'monitorQueueReplay',	'',	'',	3,	'',	'/queue/replay',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	20,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2434,	2431,	3,	'tr_2090 tr_2431 ',	'丢弃失败消息',	'monitorQueueDiscard',	'',	'',	3,	'',	'/queue/discard',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	30,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2435,	2431,	3,	'tr_2090 tr_2431 ',	'重置主题统计',	'monitorQueueResetStats',	'',	'',	3,	'',	'/queue/resetStats',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	40,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2436,	2071,	3,	'tr_2068 tr_2071 ',	'执行记录',	'/cron/logList',	'',	'',	3,	'',	'/cron/logList,/cron/logView',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	75,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2437,	2095,	3,	'tr_2093 tr_2095 ',	'存储用量',	'attachmentUsage',	'',	'',	3,	'',	'/attachment/usage',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	20,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2438,	2095,	3,	'tr_2093 tr_2095 ',	'存储迁移',	'attachmentMigrate',	'',	'',	3,	'',	'/attachment/migrate,/attachment/migrateStatus',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	30,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2439,	2237,	3,	'tr_2093 tr_2237 ',	'交易对账',	'asset_pay_reconcile',	'payReconcile',	'',	2,	'',	'/payReconcile/list',	'',	'/asset/payReconcile/index',	1,	'',	0,	0,	'',	0,	0,	0,	50,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2440,	2439,	4,	'tr_2093 tr_2237 tr_2439 ',	'执行对账',	'payReconcileRun',	'',	'',	3,	'',	'/payReconcile/run',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	10,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2441,	2439,	4,	'tr_2093 tr_2237 tr_2439 ',	'处理对账差异',	'payReconcileHandle',	'',	'',	3,	'',	'/payReconcile/handle',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	20,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2442,	2232,	4,	'tr_2093 tr_2237 tr_2232 ',	'查询支付订单',	'asset_recharge_query',	'',	'',	3,	'',	'/pay/queryOrder,/pay/closeOrder',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	50,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2443,	2237,	3,	'tr_2093 tr_2237 ',	'支付通知',	'asset_pay_notify',	'payNotify',	'',	2,	'',	'/payNotify/list,/payNotify/view',	'',	'/asset/payNotify/index',	1,	'',	0,	0,	'',	0,	0,	0,	60,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2444,	2443,	4,	'tr_2093 tr_2237 tr_2443 ',	'重放支付通知',	'payNotifyReplay',	'',	'',	3,	'',	'/payNotify/replay',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	10,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(2445,	2232,	4,	'tr_2093 tr_2237 tr_2232 ',	'订单流转记录',	'asset_recharge_history',	'',	'',	3,	'',	'/order/history',	'',	'',	1,	'',	0,	0,	'',	0,	0,	0,	60,	'',	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00');
 
 INSERT INTO `hg_admin_notice` (`id`, `title`, `type`, `tag`, `content`, `receiver`, `remark`, `sort`, `status`, `created_by`, `updated_by`, `created_at`, `updated_at`, `deleted_at`) VALUES
 (29,	'2023年春季学期开学工作通知！',	1,	1,	'1.学生：2月11日、2月12日报到，2月13日起安排考试。\n\n2.教职工：2月10日（周五）起正式上班（2月11日、2月12日正常上班）。\n\n3.校内进行的各类社会服务项目，主办部门、单位须关注参与人员的健康状况，如有异常第一时间报告。感染后仍在康复期内的师生，不参加剧烈活动。开学后两周内，原则上不组织各类竞技性较强的体育比赛等活动。\n\n4.全校师生员工要牢固树立健康第一的观念，切实增强个人责任感和防护意识，掌握防护技能，坚持戴口罩、勤洗手等良好卫生习惯，加强身体锻炼，保持健康生活方式，提升健康素养和自我防护能力，当好自身健康第一责任人。符合条件的师生，积极有序接种第二剂次加强针疫苗。',	'null',	'',	10,	1,	1,	1,	'2023-02-09 12:25:39',	'2023-02-09 12:48:08',	NULL),
@@ -1267,14 +1282,27 @@ INSERT INTO `hg_sys_config` (`id`, `group`, `name`, `type`, `key`, `value`, `def
 (125,	'upload',	'minio是否启用SSL',	'int',	'uploadMinioUseSSL',	'1',	'',	650,	'',	1,	1,	'2021-01-30 13:27:43',	'2024-02-28 16:56:35'),
 (126,	'upload',	'minio存储路径',	'string',	'uploadMinioPath',	'hotgo/attachment/',	'',	650,	'',	1,	1,	'2021-01-30 13:27:43',	'2024-02-28 16:56:35'),
 (127,	'upload',	'minio桶名称',	'string',	'uploadMinioBucket',	'',	'',	650,	'',	1,	1,	'2021-01-30 13:27:43',	'2024-02-28 16:56:35'),
-(128,	'upload',	'minio对外访问域名',	'string',	'uploadMinioDomain',	'',	'',	650,	'',	1,	1,	'2021-01-30 13:27:43',	'2024-02-28 16:56:35');
+(128,	'upload',	'minio对外访问域名',	'string',	'uploadMinioDomain',	'',	'',	650,	'',	1,	1,	'2021-01-30 13:27:43',	'2024-02-28 16:56:35'),
+(130,	'upload',	'图片纠正方向',	'int',	'uploadImageAutoOrient',	'1',	'1',	342,	'按EXIF方向信息旋转JPEG图片并去除EXIF信息，1：开启，2：关闭',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(131,	'upload',	'图片预设尺寸',	'string',	'uploadImageSizes',	'200x200,800x0',	'200x200',	344,	'格式：宽x高，多个用英文逗号分隔，宽或高为0时按比例缩放，第一个尺寸作为缩略图',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(132,	'upload',	'图片尺寸生成方式',	'int',	'uploadImageVariantMode',	'2',	'2',	346,	'1：上传时生成，2：首次访问时生成',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(133,	'upload',	'图片转换WebP',	'int',	'uploadImageWebp',	'1',	'2',	348,	'1：开启，2：关闭',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(134,	'upload',	'用户存储容量',	'int',	'uploadQuotaMember',	'0',	'0',	350,	'单个用户的存储容量，单位：MB，0为不限制',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(135,	'upload',	'租户存储容量',	'int',	'uploadQuotaTenant',	'0',	'0',	352,	'单个租户及其下属商户、用户的存储容量，单位：MB，0为不限制',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(136,	'upload',	'应用存储容量',	'string',	'uploadQuotaApp',	'',	'',	354,	'格式：应用:容量MB，多个用英文逗号分隔，如：api:10240,home:1024，未配置的应用不限制',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(137,	'pay',	'模拟支付开关',	'bool',	'payMockPayEnabled',	'false',	'false',	940,	'开启后可使用模拟支付进行本地开发和自动化测试，生产环境请关闭',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(138,	'pay',	'模拟支付签名密钥',	'string',	'payMockPaySecret',	'',	'',	950,	'模拟支付通知的HMAC-SHA256签名密钥，未配置时无法使用模拟支付',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(139,	'pay',	'模拟退款失败',	'bool',	'payMockPayRefundFail',	'false',	'false',	960,	'开启后模拟支付的退款申请全部返回失败',	1,	1,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00');
 
 INSERT INTO `hg_sys_cron` (`id`, `group_id`, `title`, `name`, `params`, `pattern`, `policy`, `count`, `sort`, `remark`, `status`, `created_at`, `updated_at`) VALUES
 (1,	1,	'测试任务',	'test',	'',	'* * * * * *',	1,	3,	10,	'测试无参数任务',	2,	'2022-10-01 22:02:09',	'2023-11-25 14:33:05'),
 (2,	1,	'测试带参数',	'test2',	'hotGo,3,欢迎使用hotGo！',	'* * * * * *',	1,	0,	10,	'测试有参数任务',	2,	'2022-10-01 06:02:09',	'2023-11-17 18:38:49'),
 (3,	1,	'测试带参数-多任务',	'test2',	'hotGo,3,这是同一个执行方法开多个定时任务的实例！',	'* * * * * *',	1,	1,	10,	'相同的执行方法，可以开启多个任务',	2,	'2023-11-17 16:12:26',	'2023-11-20 10:11:47'),
 (4,	1,	'测试带参数-错误',	'test2',	'666',	'* * * * * *',	1,	1,	10,	'参入一个错误的参数格式，来模拟执行出错示例',	2,	'2023-11-17 18:23:39',	'2023-11-17 18:39:59'),
-(10,	1,	'关闭过期订单',	'close_order',	'',	'0 */10 * * * *',	1,	1,	100,	'取消过期订单，10分钟运行一次',	2,	'2023-04-22 21:58:47',	'2023-11-18 11:54:07');
+(10,	1,	'关闭过期订单',	'close_order',	'',	'0 */10 * * * *',	1,	1,	100,	'取消过期订单，10分钟运行一次',	2,	'2023-04-22 21:58:47',	'2023-11-18 11:54:07'),
+(11,	1,	'回收未引用附件',	'attachment_gc',	'7,500',	'0 30 3 * * *',	2,	0,	110,	'删除超过指定天数且未被业务数据引用的附件，参数：天数,单次处理数量。开启前请确认业务表已声明附件引用',	2,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(12,	1,	'支付掉单补偿',	'pay_compensate',	'5,1440,100',	'0 */5 * * * *',	2,	0,	120,	'主动查询超时仍未收到通知的待支付订单，已支付的补单，过期未支付的关闭。参数：创建超过多少分钟开始查询,超过多少分钟未支付则关闭,单次处理数量',	2,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00'),
+(13,	1,	'每日交易对账',	'pay_reconcile',	'',	'0 0 10 * * *',	2,	0,	130,	'下载前一天的第三方账单与本地支付、退款记录逐笔核对。参数：支付方式，多个用,隔开，为空时核对所有已配置的支付方式',	2,	'2026-10-18 10:00:00',	'2026-10-18 10:00:00');
 
 INSERT INTO `hg_sys_cron_group` (`id`, `pid`, `name`, `is_default`, `sort`, `remark`, `status`, `created_at`, `updated_at`) VALUES
 (1,	0,	'系统默认',	1,	0,	'这是系统默认的任务分组，无法删除！',	1,	'2021-02-25 17:38:07',	'2021-02-25 19:32:57'),
@@ -222,6 +222,19 @@ CREATE TABLE `hg_admin_order` (                           -- 管理员_充值订
   `created_at` datetime DEFAULT NULL,                     -- 创建时间
   `updated_at` datetime DEFAULT NULL                      -- 修改时间
 );
+CREATE TABLE `hg_admin_order_history` (                   -- 管理员_充值订单流转记录
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,        -- 记录ID
+  `order_id` INTEGER NOT NULL,                            -- 订单ID
+  `order_sn` TEXT DEFAULT '',                             -- 业务订单号
+  `event` TEXT NOT NULL,                                  -- 事件
+  `event_name` TEXT DEFAULT '',                           -- 事件名称
+  `from_status` INTEGER NOT NULL,                         -- 原状态
+  `to_status` INTEGER NOT NULL,                           -- 新状态
+  `created_by` INTEGER DEFAULT 0,                         -- 操作人，0为系统
+  `remark` TEXT DEFAULT NULL,                             -- 备注
+  `trace_id` TEXT DEFAULT NULL,                           -- 链路ID
+  `created_at` datetime DEFAULT NULL                      -- 创建时间
+);
 CREATE TABLE `hg_admin_post` (                            -- 管理员_岗位
   `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,        -- 岗位ID
   `code` TEXT NOT NULL,                                   -- 岗位编码
@@ -295,6 +308,41 @@ CREATE TABLE `hg_pay_log` (                               -- 支付_支付日志
   `created_at` datetime DEFAULT NULL,                     -- 创建时间
   `updated_at` datetime DEFAULT NULL                      -- 修改时间
 );
+CREATE TABLE `hg_pay_notify` (                            -- 支付_异步通知
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,        -- 记录ID
+  `pay_type` TEXT NOT NULL,                               -- 支付方式
+  `out_trade_no` TEXT DEFAULT NULL,                       -- 商户订单号
+  `transaction_id` TEXT DEFAULT NULL,                     -- 交易号
+  `header` TEXT,                                          -- 请求头
+  `body` TEXT,                                            -- 请求报文
+  `client_ip` TEXT DEFAULT NULL,                          -- 通知IP
+  `trace_id` TEXT DEFAULT NULL,                           -- 链路ID
+  `status` INTEGER DEFAULT 1,                             -- 处理状态
+  `err_msg` TEXT DEFAULT NULL,                            -- 失败原因
+  `replay_count` INTEGER DEFAULT 0,                       -- 重放次数
+  `processed_at` datetime DEFAULT NULL,                   -- 处理时间
+  `created_at` datetime DEFAULT NULL,                     -- 创建时间
+  `updated_at` datetime DEFAULT NULL                      -- 更新时间
+);
+CREATE TABLE `hg_pay_reconcile` (                         -- 支付_对账差异
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,        -- 记录ID
+  `pay_type` TEXT NOT NULL,                               -- 支付方式
+  `bill_date` date NOT NULL,                              -- 账单日期
+  `kind` TEXT NOT NULL,                                   -- 明细类型
+  `out_trade_no` TEXT DEFAULT NULL,                       -- 商户订单号
+  `transaction_id` TEXT DEFAULT NULL,                     -- 交易号
+  `refund_sn` TEXT DEFAULT NULL,                          -- 退款单号
+  `order_sn` TEXT DEFAULT NULL,                           -- 业务订单号
+  `type` INTEGER NOT NULL,                                -- 差异类型
+  `bill_amount` decimal(10,2) DEFAULT 0.00,               -- 账单金额
+  `local_amount` decimal(10,2) DEFAULT 0.00,              -- 本地金额
+  `remark` TEXT DEFAULT NULL,                             -- 处理说明
+  `handled_by` INTEGER DEFAULT 0,                         -- 处理人
+  `handled_at` datetime DEFAULT NULL,                     -- 处理时间
+  `status` INTEGER DEFAULT 1,                             -- 处理状态
+  `created_at` datetime DEFAULT NULL,                     -- 创建时间
+  `updated_at` datetime DEFAULT NULL                      -- 更新时间
+);
 CREATE TABLE `hg_pay_refund` (                            -- 支付_退款记录
   `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,        -- 主键ID
   `member_id` INTEGER DEFAULT 0,                          -- 会员ID
@@ -338,6 +386,7 @@ CREATE TABLE `hg_sys_attachment` (                        -- 系统_附件管理
   `id` INTEGER NOT NULL PRIMARY KEY ,                     -- 文件ID
   `app_id` TEXT NOT NULL,                                 -- 应用ID
   `member_id` INTEGER DEFAULT 0,                          -- 管理员ID
+  `tenant_id` INTEGER DEFAULT 0,                          -- 租户ID
   `cate_id` INTEGER DEFAULT 0,                            -- 上传分类
   `drive` TEXT DEFAULT NULL,                              -- 上传驱动
   `name` TEXT DEFAULT NULL,                               -- 文件原始名
@@ -347,6 +396,8 @@ CREATE TABLE `hg_sys_attachment` (                        -- 系统_附件管理
   `path` TEXT DEFAULT NULL,                               -- 本地路径
   `file_url` TEXT DEFAULT NULL,                           -- url
   `size` INTEGER DEFAULT 0,                               -- 文件大小
+  `width` INTEGER DEFAULT 0,                              -- 图片宽度
+  `height` INTEGER DEFAULT 0,                             -- 图片高度
   `ext` TEXT DEFAULT NULL,                                -- 扩展名
   `md5` TEXT DEFAULT NULL,                                -- md5校验码
   `status` INTEGER NOT NULL DEFAULT 1,                    -- 状态
@@ -385,6 +436,12 @@ CREATE TABLE `hg_sys_cron` (                              -- 系统_定时任务
   `pattern` TEXT NOT NULL,                                -- 表达式
   `policy` INTEGER NOT NULL DEFAULT 1,                    -- 策略
   `count` INTEGER NOT NULL DEFAULT 0,                     -- 执行次数
+  `cluster_mode` INTEGER NOT NULL DEFAULT 1,              -- 集群模式(1所有节点,2单节点,3指定节点)
+  `node_label` TEXT DEFAULT NULL,                         -- 指定节点标签，多个用,隔开
+  `timeout` INTEGER NOT NULL DEFAULT 0,                   -- 执行超时(秒)，0不限制
+  `retry` INTEGER NOT NULL DEFAULT 0,                     -- 失败重试次数
+  `alert` INTEGER NOT NULL DEFAULT 2,                     -- 失败通知(1开启,2关闭)
+  `alert_email` TEXT DEFAULT NULL,                        -- 通知邮箱，多个用,隔开
   `sort` INTEGER DEFAULT 0,                               -- 排序
   `remark` TEXT DEFAULT NULL,                             -- 备注
   `status` INTEGER DEFAULT 1,                             -- 任务状态
@@ -543,6 +600,7 @@ CREATE TABLE `hg_sys_serve_license` (                     -- 系统_服务许可
   `last_active_at` datetime DEFAULT NULL,                 -- 最后心跳
   `routes` TEXT,                                          -- 路由表，空使用默认分组路由
   `allowed_ips` TEXT DEFAULT NULL,                        -- IP白名单
+  `cert_fingerprint` TEXT DEFAULT NULL,                   -- 绑定的客户端证书SHA256指纹，多个用,隔开，为空不限制
   `end_at` datetime NOT NULL,                             -- 授权有效期
   `remark` TEXT DEFAULT NULL,                             -- 备注
   `status` INTEGER DEFAULT 1,                             -- 状态
@@ -613,6 +671,51 @@ CREATE TABLE `hg_gen_curd_test` (                         -- 测试_代码生成
   "deleted_at" DATETIME                                   -- 删除时间
 );
 
+CREATE TABLE `hg_sys_queue_fail` (                        -- 系统_队列失败消息
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,        -- 主键
+  `topic` TEXT NOT NULL,                                  -- 消息主题
+  `msg_id` TEXT DEFAULT NULL,                             -- 消息ID
+  `msg_key` TEXT DEFAULT NULL,                            -- 幂等键
+  `body` text DEFAULT NULL,                               -- 消息内容
+  `error` text DEFAULT NULL,                              -- 错误信息
+  `replay_count` INTEGER NOT NULL DEFAULT 0,              -- 重放次数
+  `handled_by` INTEGER DEFAULT 0,                         -- 处理人
+  `handled_at` datetime DEFAULT NULL,                     -- 处理时间
+  `status` INTEGER NOT NULL DEFAULT 1,                    -- 状态(1消费失败,2死信,3已重放,4已丢弃)
+  `created_at` datetime DEFAULT NULL,                     -- 创建时间
+  `updated_at` datetime DEFAULT NULL                      -- 修改时间
+);
+
+CREATE TABLE `hg_sys_queue_outbox` (                      -- 系统_队列发件箱
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,        -- 主键
+  `topic` TEXT NOT NULL,                                  -- 消息主题
+  `msg_key` TEXT NOT NULL,                                -- 幂等键
+  `body` text DEFAULT NULL,                               -- 消息内容
+  `status` INTEGER NOT NULL DEFAULT 1,                    -- 状态(1待投递,2投递中,3已投递)
+  `attempts` INTEGER NOT NULL DEFAULT 0,                  -- 投递次数
+  `error` text DEFAULT NULL,                              -- 最近一次投递错误
+  `next_at` datetime DEFAULT NULL,                        -- 下次投递时间
+  `sent_at` datetime DEFAULT NULL,                        -- 投递成功时间
+  `created_at` datetime DEFAULT NULL,                     -- 创建时间
+  `updated_at` datetime DEFAULT NULL                      -- 修改时间
+);
+
+CREATE TABLE `hg_sys_cron_log` (                          -- 系统_定时任务执行记录
+  `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,        -- 记录ID
+  `cron_id` INTEGER NOT NULL DEFAULT 0,                   -- 任务ID
+  `title` TEXT DEFAULT NULL,                              -- 任务标题
+  `name` TEXT DEFAULT NULL,                               -- 任务方法
+  `node` TEXT DEFAULT NULL,                               -- 执行节点
+  `status` INTEGER NOT NULL DEFAULT 1,                    -- 执行状态(1成功,2失败,3超时)
+  `attempts` INTEGER NOT NULL DEFAULT 1,                  -- 执行次数
+  `error` text DEFAULT NULL,                              -- 错误信息
+  `output` text DEFAULT NULL,                             -- 执行输出
+  `started_at` datetime DEFAULT NULL,                     -- 开始时间
+  `finished_at` datetime DEFAULT NULL,                    -- 结束时间
+  `took` INTEGER NOT NULL DEFAULT 0,                      -- 耗时(ms)
+  `created_at` datetime DEFAULT NULL                      -- 创建时间
+);
+
 CREATE INDEX `hg_addon_hgexample_tenant_order_order_sn` ON `hg_addon_hgexample_tenant_order` (`order_sn`);
 CREATE INDEX `hg_addon_hgexample_tenant_order_member_id` ON `hg_addon_hgexample_tenant_order` (`user_id`);
 CREATE INDEX `hg_addon_hgexample_tenant_order_merchant_id` ON `hg_addon_hgexample_tenant_order` (`merchant_id`);
@@ -631,13 +734,20 @@ CREATE INDEX `hg_admin_oauth_oauth_client` ON `hg_admin_oauth` (`oauth_client`);
 CREATE INDEX `hg_admin_oauth_member_id` ON `hg_admin_oauth` (`member_id`);
 CREATE INDEX `hg_admin_order_order_sn` ON `hg_admin_order` (`order_sn`);
 CREATE INDEX `hg_admin_order_member_id` ON `hg_admin_order` (`member_id`);
+CREATE INDEX `hg_admin_order_history_order_id` ON `hg_admin_order_history` (`order_id`);
 CREATE UNIQUE INDEX `hg_pay_log_order_sn` ON `hg_pay_log` (`order_sn`);
 CREATE INDEX `hg_pay_log_member_id` ON `hg_pay_log` (`member_id`);
+CREATE INDEX `hg_pay_notify_out_trade_no` ON `hg_pay_notify` (`out_trade_no`);
+CREATE INDEX `hg_pay_notify_status` ON `hg_pay_notify` (`status`);
+CREATE INDEX `hg_pay_reconcile_bill_date` ON `hg_pay_reconcile` (`pay_type`, `bill_date`);
+CREATE INDEX `hg_pay_reconcile_out_trade_no` ON `hg_pay_reconcile` (`out_trade_no`);
 CREATE INDEX `hg_pay_refund_order_sn` ON `hg_pay_refund` (`order_sn`);
 CREATE UNIQUE INDEX `hg_sys_addons_config_addon_name_2` ON `hg_sys_addons_config` (`addon_name`);
 CREATE INDEX `hg_addons_config_addon_name` ON `hg_sys_addons_config` (`addon_name`);
 CREATE UNIQUE INDEX `hg_sys_addons_install_name` ON `hg_sys_addons_install` (`name`);
 CREATE INDEX `hg_sys_attachment_md5` ON `hg_sys_attachment` (`md5`);
+CREATE INDEX `hg_sys_attachment_member_id` ON `hg_sys_attachment` (`member_id`);
+CREATE INDEX `hg_sys_attachment_tenant_id` ON `hg_sys_attachment` (`tenant_id`);
 CREATE UNIQUE INDEX `hg_sys_blacklist_name` ON `hg_sys_blacklist` (`ip`);
 CREATE INDEX `hg_sys_config_group` ON `hg_sys_config` (`group`);
 CREATE INDEX `hg_sys_config_key` ON `hg_sys_config` (`key`);
@@ -653,4 +763,10 @@ CREATE INDEX `hg_sys_provinces_pid` ON `hg_sys_provinces` (`pid`);
 CREATE UNIQUE INDEX `hg_sys_serve_license_appid` ON `hg_sys_serve_license` (`appid`);
 CREATE INDEX `hg_sys_serve_log_member_id` ON `hg_sys_serve_log` (`level_format`);
 CREATE INDEX `hg_sys_serve_log_traceid` ON `hg_sys_serve_log` (`trace_id`);
-CREATE INDEX `hg_sys_sms_log_mobile` ON `hg_sys_sms_log` (`mobile`);
\ No newline at end of file
+CREATE INDEX `hg_sys_sms_log_mobile` ON `hg_sys_sms_log` (`mobile`);
+CREATE INDEX `hg_sys_queue_fail_topic` ON `hg_sys_queue_fail` (`topic`);
+CREATE INDEX `hg_sys_queue_fail_status` ON `hg_sys_queue_fail` (`status`);
+CREATE UNIQUE INDEX `hg_sys_queue_outbox_msg_key` ON `hg_sys_queue_outbox` (`msg_key`);
+CREATE INDEX `hg_sys_queue_outbox_status` ON `hg_sys_queue_outbox` (`status`, `next_at`);
+CREATE INDEX `hg_sys_cron_log_cron_id` ON `hg_sys_cron_log` (`cron_id`, `started_at`);
+CREATE INDEX `hg_sys_cron_log_started_at` ON `hg_sys_cron_log` (`started_at`);
@@ -40,6 +40,32 @@ export function View(params) {
   });
 }
 
+// 存储用量
+export function Usage(params?) {
+  return http.request({
+    url: '/attachment/usage',
+    method: 'GET',
+    params,
+  });
+}
+
+// 存储迁移
+export function Migrate(params) {
+  return http.request({
+    url: '/attachment/migrate',
+    method: 'POST',
+    params,
+  });
+}
+
+// 存储迁移进度
+export function MigrateStatus() {
+  return http.request({
+    url: '/attachment/migrateStatus',
+    method: 'GET',
+  });
+}
+
 export function ClearKind(params) {
   return http.request({
     url: '/attachment/clearKind',
@@ -35,6 +35,24 @@ export function CheckMultipart(params) {
   });
 }
 
+// 申请客户端直传
+export function PresignUpload(params) {
+  return http.request({
+    url: '/upload/presign',
+    method: 'post',
+    params,
+  });
+}
+
+// 客户端直传完成
+export function CompleteUpload(params) {
+  return http.request({
+    url: '/upload/complete',
+    method: 'post',
+    params,
+  });
+}
+
 // 分片上传
 export function UploadPart(params: UploadFileParams) {
   return http.uploadFile(
@@ -0,0 +1,48 @@
+import { http } from '@/utils/http/axios';
+
+export function QueueStats() {
+  return http.request({
+    url: '/queue/stats',
+    method: 'get',
+  });
+}
+
+export function ResetStats(params) {
+  return http.request({
+    url: '/queue/resetStats',
+    method: 'POST',
+    params,
+  });
+}
+
+export function FailList(params) {
+  return http.request({
+    url: '/queue/failList',
+    method: 'get',
+    params,
+  });
+}
+
+export function FailView(params) {
+  return http.request({
+    url: '/queue/failView',
+    method: 'get',
+    params,
+  });
+}
+
+export function Replay(params) {
+  return http.request({
+    url: '/queue/replay',
+    method: 'POST',
+    params,
+  });
+}
+
+export function Discard(params) {
+  return http.request({
+    url: '/queue/discard',
+    method: 'POST',
+    params,
+  });
+}
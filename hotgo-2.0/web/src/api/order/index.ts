@@ -97,6 +97,15 @@ export function AcceptRefund(params) {
   });
 }
 
+// 获取充值订单流转记录
+export function History(params) {
+  return http.request({
+    url: '/order/history',
+    method: 'get',
+    params,
+  });
+}
+
 // 导出充值订单
 export function Export(params) {
   jumpExport('/order/export', params);
@@ -0,0 +1,28 @@
+import { http } from '@/utils/http/axios';
+
+// 获取支付通知列表
+export function List(params) {
+  return http.request({
+    url: '/payNotify/list',
+    method: 'get',
+    params,
+  });
+}
+
+// 获取支付通知详情
+export function View(params) {
+  return http.request({
+    url: '/payNotify/view',
+    method: 'get',
+    params,
+  });
+}
+
+// 重放处理失败的支付通知
+export function Replay(params) {
+  return http.request({
+    url: '/payNotify/replay',
+    method: 'POST',
+    params,
+  });
+}
@@ -0,0 +1,19 @@
+import { http } from '@/utils/http/axios';
+
+// 主动查询第三方支付订单
+export function QueryOrder(params) {
+  return http.request({
+    url: '/pay/queryOrder',
+    method: 'POST',
+    params,
+  });
+}
+
+// 关闭第三方支付订单
+export function CloseOrder(params) {
+  return http.request({
+    url: '/pay/closeOrder',
+    method: 'POST',
+    params,
+  });
+}
@@ -0,0 +1,28 @@
+import { http } from '@/utils/http/axios';
+
+// 获取对账差异列表
+export function List(params) {
+  return http.request({
+    url: '/payReconcile/list',
+    method: 'get',
+    params,
+  });
+}
+
+// 下载第三方账单执行对账
+export function Run(params) {
+  return http.request({
+    url: '/payReconcile/run',
+    method: 'POST',
+    params,
+  });
+}
+
+// 标记对账差异已处理
+export function Handle(params) {
+  return http.request({
+    url: '/payReconcile/handle',
+    method: 'POST',
+    params,
+  });
+}
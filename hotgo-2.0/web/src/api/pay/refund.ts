@@ -13,3 +13,12 @@ export function List(params) {
 export function Export(params) {
   jumpExport('/payRefund/export', params);
 }
+
+// 查询第三方退款状态
+export function Query(params) {
+  return http.request({
+    url: '/payRefund/query',
+    method: 'POST',
+    params,
+  });
+}
@@ -103,3 +103,19 @@ export function DispatchLog(params) {
     params,
   });
 }
+
+export function LogList(params) {
+  return http.request({
+    url: '/cron/logList',
+    method: 'get',
+    params,
+  });
+}
+
+export function LogView(params) {
+  return http.request({
+    url: '/cron/logView',
+    method: 'get',
+    params,
+  });
+}
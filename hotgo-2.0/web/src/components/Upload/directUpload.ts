@@ -0,0 +1,80 @@
+import { CompleteUpload, PresignUpload } from '@/api/base';
+import { Attachment } from '@/components/FileChooser/src/model';
+
+export interface DirectUploadParams {
+  uploadType: string;
+  fileName: string;
+  size: number;
+  md5: string;
+}
+
+export interface DirectUploadResult {
+  // 是否支持直传，不支持时需要使用分片上传
+  direct: boolean;
+  attachment?: Attachment;
+}
+
+// 直传签名
+interface PresignedUpload {
+  method: string;
+  url: string;
+  headers?: Record<string, string>;
+  formData?: Record<string, string>;
+  fileKey?: string;
+}
+
+// 上传文件到存储平台
+function send(upload: PresignedUpload, file: File, onProgress: (percent: number) => void) {
+  return new Promise<void>((resolve, reject) => {
+    const xhr = new XMLHttpRequest();
+    xhr.open(upload.method, upload.url);
+
+    let body: File | FormData = file;
+    if (upload.method.toUpperCase() === 'POST') {
+      const form = new FormData();
+      Object.entries(upload.formData || {}).forEach(([k, v]) => form.append(k, v));
+      form.append(upload.fileKey || 'file', file);
+      body = form;
+    } else {
+      Object.entries(upload.headers || {}).forEach(([k, v]) => xhr.setRequestHeader(k, v));
+    }
+
+    xhr.upload.onprogress = (e) => {
+      if (e.lengthComputable) {
+        onProgress(Math.floor((e.loaded / e.total) * 100));
+      }
+    };
+    xhr.onload = () => {
+      if (xhr.status >= 200 && xhr.status < 300) {
+        resolve();
+      } else {
+        reject(new Error(`直传失败，状态码:${xhr.status}`));
+      }
+    };
+    xhr.onerror = () => reject(new Error('直传失败，请检查存储空间的跨域配置'));
+    xhr.send(body);
+  });
+}
+
+/**
+ * 直传文件到存储平台，上传完成后通知服务端写入附件
+ * 当前存储驱动不支持直传时返回direct=false，由调用方使用分片上传
+ */
+export async function directUpload(
+  file: File,
+  params: DirectUploadParams,
+  onProgress: (percent: number) => void
+): Promise<DirectUploadResult> {
+  const res = await PresignUpload(params);
+  if (res.attachment) {
+    return { direct: true, attachment: res.attachment };
+  }
+
+  if (!res.direct) {
+    return { direct: false };
+  }
+
+  await send(res.upload, file, onProgress);
+  const attachment = await CompleteUpload({ uploadId: res.uploadId });
+  return { direct: true, attachment: attachment };
+}
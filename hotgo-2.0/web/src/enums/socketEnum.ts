@@ -3,9 +3,17 @@ export enum SocketEnum {
   EventKick = 'kick',
   EventNotice = 'notice',
   EventConnected = 'connected',
+  EventSubscribe = 'subscribe',
+  EventUnsubscribe = 'unsubscribe',
+  EventPresence = 'presence',
+  EventChannelJoin = 'channel/join',
+  EventChannelLeave = 'channel/leave',
+  EventReplay = 'replay',
+  EventAck = 'ack',
   EventAdminMonitorTrends = 'admin/monitor/trends',
   EventAdminMonitorRunInfo = 'admin/monitor/runInfo',
   EventAdminOrderNotify = 'admin/order/notify',
+  EventAdminAttachmentMigrate = 'admin/attachment/migrate',
   HeartBeatInterval = 1000,
   CodeSuc = 0,
   CodeErr = -1,
@@ -9,11 +9,21 @@ export interface WebSocketMessage {
   data: any;
   code: number;
   timestamp: number;
+  errorMsg?: string;
+  seq?: number;
+  requestId?: string;
+  ackId?: string;
 }
 
 let socket: WebSocket;
 let isActive: boolean;
 const messageHandler: Map<string, Function> = new Map();
+const channels: Set<string> = new Set();
+const receivedSeq: Set<number> = new Set();
+const receivedAck: Set<string> = new Set();
+const pendingRequests: Map<string, { resolve: Function; reject: Function; timer: ReturnType<typeof setTimeout> }> =
+  new Map();
+let requestIndex = 0;
 
 export default () => {
   const heartCheck = {
@@ -89,6 +99,12 @@ export default () => {
       console.log('[WebSocket] 已连接');
       heartCheck.reset().start();
       isActive = true;
+      // 补发断线期间未收到的消息
+      sendMsg(SocketEnum.EventReplay, { seq: getLastSeq() });
+      // 重连后恢复订阅的频道
+      channels.forEach((channel) => {
+        sendMsg(SocketEnum.EventSubscribe, { channel });
+      });
     };
 
     socket.onmessage = function (event) {
@@ -129,7 +145,55 @@ export default () => {
   registerGlobalMessage();
 };
 
+// 最后收到的用户消息序号，按用户保存
+function lastSeqKey(): string {
+  const useUserStore = useUserStoreWidthOut();
+  return `WS_LAST_SEQ_${useUserStore.info?.id ?? 0}`;
+}
+
+function getLastSeq(): number {
+  return Number(localStorage.getItem(lastSeqKey()) ?? 0);
+}
+
 function onMessage(message: WebSocketMessage) {
+  if (message.ackId) {
+    // 需要确认的消息，回复ack后服务端停止重发，重发的消息只处理一次
+    sendMsg(SocketEnum.EventAck, { id: message.ackId }, false);
+    if (receivedAck.has(message.ackId)) {
+      return;
+    }
+    receivedAck.add(message.ackId);
+  }
+
+  if (message.requestId && pendingRequests.has(message.requestId)) {
+    const pending = pendingRequests.get(message.requestId)!;
+    pendingRequests.delete(message.requestId);
+    clearTimeout(pending.timer);
+    if (message.code === SocketEnum.CodeSuc) {
+      pending.resolve(message.data);
+    } else {
+      pending.reject(new Error(message.errorMsg));
+    }
+  }
+
+  if (message.event === SocketEnum.EventReplay && message.code === SocketEnum.CodeSuc) {
+    // 服务端序号被重置时，以服务端为准
+    if (message.data?.seq < getLastSeq()) {
+      localStorage.setItem(lastSeqKey(), String(message.data.seq));
+    }
+  }
+
+  if (message.seq) {
+    // 丢弃重复收到的消息，补发和实时推送可能同时到达
+    if (receivedSeq.has(message.seq)) {
+      return;
+    }
+    receivedSeq.add(message.seq);
+    if (message.seq > getLastSeq()) {
+      localStorage.setItem(lastSeqKey(), String(message.seq));
+    }
+  }
+
   let handled = false;
   messageHandler.forEach((value: Function, key: string) => {
     if (message.event === key || key === '*') {
@@ -145,6 +209,10 @@ function onMessage(message: WebSocketMessage) {
 
 // 发送消息
 export function sendMsg(event: string, data: any = null, isRetry = true) {
+  sendMsgWithId('', event, data, isRetry);
+}
+
+function sendMsgWithId(id: string, event: string, data: any = null, isRetry = true) {
   if (socket === undefined || !isActive) {
     if (!isRetry) {
       console.log('[WebSocket] 连接异常，发送失败！');
@@ -152,13 +220,13 @@ export function sendMsg(event: string, data: any = null, isRetry = true) {
     }
     console.log('[WebSocket] 连接异常，等待重试..');
     setTimeout(() => {
-      sendMsg(event, data);
+      sendMsgWithId(id, event, data);
     }, 200);
     return;
   }
 
   try {
-    socket.send(JSON.stringify({ event, data }));
+    socket.send(JSON.stringify(id ? { id, event, data } : { event, data }));
   } catch (err: any) {
     console.log('[WebSocket] 发送消息失败，err：', err.message);
     if (!isRetry) {
@@ -167,11 +235,41 @@ export function sendMsg(event: string, data: any = null, isRetry = true) {
 
     console.log('[WebSocket] 等待重试..');
     setTimeout(() => {
-      sendMsg(event, data);
+      sendMsgWithId(id, event, data);
     }, 100);
   }
 }
 
+// 发送请求并等待服务端回复，服务端处理失败或超时时reject
+export function sendRequest(event: string, data: any = null, timeout = 10000): Promise<any> {
+  const id = `${Date.now()}-${++requestIndex}`;
+  return new Promise((resolve, reject) => {
+    const timer = setTimeout(() => {
+      pendingRequests.delete(id);
+      reject(new Error(`[WebSocket] request ${event} timeout`));
+    }, timeout);
+    pendingRequests.set(id, { resolve, reject, timer });
+    sendMsgWithId(id, event, data);
+  });
+}
+
+// 订阅频道，断线重连后会自动重新订阅
+export function subscribe(channel: string) {
+  channels.add(channel);
+  sendMsg(SocketEnum.EventSubscribe, { channel });
+}
+
+// 取消订阅频道
+export function unsubscribe(channel: string) {
+  channels.delete(channel);
+  sendMsg(SocketEnum.EventUnsubscribe, { channel });
+}
+
+// 获取频道成员，结果通过presence消息返回
+export function presence(channel: string) {
+  sendMsg(SocketEnum.EventPresence, { channel });
+}
+
 // 添加消息处理
 export function addOnMessage(key: string, value: Function): void {
   messageHandler.set(key, value);
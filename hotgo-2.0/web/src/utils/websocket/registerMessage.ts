@@ -27,6 +27,14 @@ export function registerGlobalMessage() {
     notificationStore.triggerNewMessages(message.data);
   });
 
+  // 补发离线消息，有消息已过期无法补发时重新拉取通知
+  addOnMessage(SocketEnum.EventReplay, function (message: WebSocketMessage) {
+    if (message.code === SocketEnum.CodeSuc && message.data?.lost) {
+      const notificationStore = notificationStoreWidthOut();
+      notificationStore.pullMessages();
+    }
+  });
+
   // 更多全局消息处理都可以在这里注册
   // ...
 }
@@ -0,0 +1,119 @@
+import { ref } from 'vue';
+import { FormSchema } from '@/components/Form';
+import { defRangeShortcuts } from '@/utils/dateUtil';
+import { useDictStore } from '@/store/modules/dict';
+import { renderOptionTag } from '@/utils';
+
+const dict = useDictStore();
+
+export const schemas = ref<FormSchema[]>([
+  {
+    field: 'payType',
+    component: 'NSelect',
+    label: '支付方式',
+    defaultValue: null,
+    componentProps: {
+      placeholder: '请选择支付方式',
+      options: dict.getOption('payType'),
+    },
+  },
+  {
+    field: 'outTradeNo',
+    component: 'NInput',
+    label: '商户订单号',
+    componentProps: {
+      placeholder: '请输入商户订单号',
+    },
+  },
+  {
+    field: 'status',
+    component: 'NSelect',
+    label: '处理状态',
+    defaultValue: null,
+    componentProps: {
+      placeholder: '请选择处理状态',
+      options: dict.getOption('payNotifyStatus'),
+    },
+  },
+  {
+    field: 'createdAt',
+    component: 'NDatePicker',
+    label: '接收时间',
+    componentProps: {
+      type: 'datetimerange',
+      clearable: true,
+      shortcuts: defRangeShortcuts(),
+    },
+  },
+]);
+
+export const columns = [
+  {
+    title: 'ID',
+    key: 'id',
+    width: 80,
+  },
+  {
+    title: '支付方式',
+    key: 'payType',
+    width: 100,
+    render(row) {
+      return renderOptionTag('payType', row.payType);
+    },
+  },
+  {
+    title: '商户订单号',
+    key: 'outTradeNo',
+    width: 200,
+  },
+  {
+    title: '交易号',
+    key: 'transactionId',
+    width: 240,
+  },
+  {
+    title: '处理状态',
+    key: 'status',
+    width: 100,
+    render(row) {
+      return renderOptionTag('payNotifyStatus', row.status);
+    },
+  },
+  {
+    title: '失败原因',
+    key: 'errMsg',
+    width: 220,
+    ellipsis: {
+      tooltip: true,
+    },
+  },
+  {
+    title: '重放次数',
+    key: 'replayCount',
+    width: 90,
+  },
+  {
+    title: '通知IP',
+    key: 'clientIp',
+    width: 140,
+  },
+  {
+    title: '链路ID',
+    key: 'traceId',
+    width: 280,
+  },
+  {
+    title: '处理时间',
+    key: 'processedAt',
+    width: 180,
+  },
+  {
+    title: '接收时间',
+    key: 'createdAt',
+    width: 180,
+  },
+];
+
+export function loadOptions() {
+  dict.loadOptions(['payType', 'payNotifyStatus']);
+}
@@ -0,0 +1,168 @@
+import { ref } from 'vue';
+import { FormSchema } from '@/components/Form';
+import { defRangeShortcuts } from '@/utils/dateUtil';
+import { useDictStore } from '@/store/modules/dict';
+import { renderOptionTag } from '@/utils';
+
+const dict = useDictStore();
+
+export const schemas = ref<FormSchema[]>([
+  {
+    field: 'payType',
+    component: 'NSelect',
+    label: '支付方式',
+    defaultValue: null,
+    componentProps: {
+      placeholder: '请选择支付方式',
+      options: dict.getOption('payType'),
+    },
+  },
+  {
+    field: 'billDate',
+    component: 'NDatePicker',
+    label: '账单日期',
+    componentProps: {
+      type: 'date',
+      clearable: true,
+      valueFormat: 'yyyy-MM-dd',
+    },
+  },
+  {
+    field: 'outTradeNo',
+    component: 'NInput',
+    label: '商户订单号',
+    componentProps: {
+      placeholder: '请输入商户订单号',
+    },
+  },
+  {
+    field: 'type',
+    component: 'NSelect',
+    label: '差异类型',
+    defaultValue: null,
+    componentProps: {
+      placeholder: '请选择差异类型',
+      options: dict.getOption('payReconcileType'),
+    },
+  },
+  {
+    field: 'status',
+    component: 'NSelect',
+    label: '处理状态',
+    defaultValue: null,
+    componentProps: {
+      placeholder: '请选择处理状态',
+      options: dict.getOption('payReconcileStatus'),
+    },
+  },
+  {
+    field: 'createdAt',
+    component: 'NDatePicker',
+    label: '创建时间',
+    componentProps: {
+      type: 'datetimerange',
+      clearable: true,
+      shortcuts: defRangeShortcuts(),
+    },
+  },
+]);
+
+export const columns = [
+  {
+    title: 'ID',
+    key: 'id',
+    width: 80,
+  },
+  {
+    title: '支付方式',
+    key: 'payType',
+    width: 100,
+    render(row) {
+      return renderOptionTag('payType', row.payType);
+    },
+  },
+  {
+    title: '账单日期',
+    key: 'billDate',
+    width: 110,
+    render(row) {
+      return row.billDate?.substring(0, 10);
+    },
+  },
+  {
+    title: '明细类型',
+    key: 'kind',
+    width: 80,
+    render(row) {
+      return row.kind === 'refund' ? '退款' : '支付';
+    },
+  },
+  {
+    title: '差异类型',
+    key: 'type',
+    width: 110,
+    render(row) {
+      return renderOptionTag('payReconcileType', row.type);
+    },
+  },
+  {
+    title: '商户订单号',
+    key: 'outTradeNo',
+    width: 200,
+  },
+  {
+    title: '交易号',
+    key: 'transactionId',
+    width: 240,
+  },
+  {
+    title: '退款单号',
+    key: 'refundSn',
+    width: 180,
+  },
+  {
+    title: '业务订单号',
+    key: 'orderSn',
+    width: 200,
+  },
+  {
+    title: '账单金额',
+    key: 'billAmount',
+    width: 100,
+  },
+  {
+    title: '本地金额',
+    key: 'localAmount',
+    width: 100,
+  },
+  {
+    title: '处理状态',
+    key: 'status',
+    width: 100,
+    render(row) {
+      return renderOptionTag('payReconcileStatus', row.status);
+    },
+  },
+  {
+    title: '处理说明',
+    key: 'remark',
+    width: 200,
+    ellipsis: {
+      tooltip: true,
+    },
+  },
+  {
+    title: '处理时间',
+    key: 'handledAt',
+    width: 180,
+  },
+  {
+    title: '创建时间',
+    key: 'createdAt',
+    width: 180,
+  },
+];
+
+export function loadOptions() {
+  dict.loadOptions(['payType', 'payReconcileType', 'payReconcileStatus']);
+}
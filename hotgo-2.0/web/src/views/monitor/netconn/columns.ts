@@ -33,6 +33,11 @@ export const columns = [
       );
     },
   },
+  {
+    title: '所在节点',
+    key: 'node',
+    width: 120,
+  },
   {
     title: '服务端口',
     key: 'port',
@@ -119,6 +124,15 @@ export const columns = [
       return formatBefore(new Date(rows.heartbeatTime * 1000));
     },
   },
+  {
+    title: '发送队列',
+    key: 'queuedBytes',
+    width: 100,
+    render: (rows, _) => {
+      const queued = (rows.queuedBytes / 1024).toFixed(1) + ' KB';
+      return rows.droppedMsgs > 0 ? queued + ' / 丢弃' + rows.droppedMsgs : queued;
+    },
+  },
   {
     title: '登录时间',
     key: 'firstTime',
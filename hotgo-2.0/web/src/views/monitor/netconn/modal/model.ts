@@ -20,6 +20,7 @@ export interface State {
   lastActiveAt: string;
   routes: any;
   allowedIps: string;
+  certFingerprint: string;
   endAt: string;
   remark: string;
   status: number;
@@ -40,6 +41,7 @@ export const defaultState = {
   lastActiveAt: '',
   routes: null,
   allowedIps: '',
+  certFingerprint: '',
   endAt: '',
   remark: '',
   status: 1,
@@ -23,6 +23,11 @@ export const columns = [
       );
     },
   },
+  {
+    title: '所在节点',
+    key: 'node',
+    width: 120,
+  },
   {
     title: '登录应用',
     key: 'app',
@@ -103,6 +108,23 @@ export const columns = [
       return row.os;
     },
   },
+  {
+    title: '已收消息',
+    key: 'recvMsgs',
+    width: 100,
+  },
+  {
+    title: '超限次数',
+    key: 'violations',
+    width: 100,
+    render(row) {
+      return h(
+        NTag,
+        { type: row.violations > 0 ? 'warning' : 'default', bordered: false },
+        { default: () => row.violations }
+      );
+    },
+  },
   {
     title: '最后活跃',
     key: 'heartbeatTime',
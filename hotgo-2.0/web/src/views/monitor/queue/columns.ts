@@ -0,0 +1,106 @@
+import { h } from 'vue';
+import { NTag } from 'naive-ui';
+
+export const statsColumns = [
+  {
+    title: '主题',
+    key: 'topic',
+    width: 180,
+    render(row) {
+      return h(
+        NTag,
+        {
+          type: row.registered ? 'info' : 'default',
+          bordered: false,
+        },
+        {
+          default: () => row.topic,
+        }
+      );
+    },
+  },
+  {
+    title: '生产数量',
+    key: 'produced',
+    width: 100,
+  },
+  {
+    title: '消费成功',
+    key: 'consumed',
+    width: 100,
+  },
+  {
+    title: '消费失败',
+    key: 'failed',
+    width: 100,
+  },
+  {
+    title: '消费堆积',
+    key: 'lag',
+    width: 100,
+    render(row) {
+      return row.lagSupported ? row.lag : '驱动不支持';
+    },
+  },
+];
+
+const failStatusMap = {
+  1: { label: '消费失败', type: 'warning' },
+  2: { label: '死信', type: 'error' },
+  3: { label: '已重放', type: 'success' },
+  4: { label: '已丢弃', type: 'default' },
+};
+
+export const failColumns = [
+  {
+    title: 'ID',
+    key: 'id',
+    width: 80,
+  },
+  {
+    title: '主题',
+    key: 'topic',
+    width: 150,
+  },
+  {
+    title: '消息ID',
+    key: 'msgId',
+    width: 200,
+  },
+  {
+    title: '错误信息',
+    key: 'error',
+    width: 300,
+    ellipsis: {
+      tooltip: true,
+    },
+  },
+  {
+    title: '重放次数',
+    key: 'replayCount',
+    width: 80,
+  },
+  {
+    title: '状态',
+    key: 'status',
+    width: 100,
+    render(row) {
+      const status = failStatusMap[row.status] ?? { label: row.status, type: 'default' };
+      return h(
+        NTag,
+        {
+          type: status.type,
+          bordered: false,
+        },
+        {
+          default: () => status.label,
+        }
+      );
+    },
+  },
+  {
+    title: '失败时间',
+    key: 'createdAt',
+    width: 180,
+  },
+];
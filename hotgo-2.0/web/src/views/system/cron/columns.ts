@@ -8,6 +8,12 @@ const policyOptions = {
   4: '多次策略',
 };
 
+const clusterModeOptions = {
+  1: '所有节点',
+  2: '单节点',
+  3: '指定节点',
+};
+
 export const columns = [
   {
     title: 'ID',
@@ -56,11 +62,81 @@ export const columns = [
     },
     width: 100,
   },
+  {
+    title: '集群模式',
+    key: 'clusterMode',
+    render(row) {
+      return h(
+        NTag,
+        {
+          style: {
+            marginRight: '6px',
+          },
+          type: row.clusterMode == 1 ? 'default' : 'info',
+          bordered: false,
+        },
+        {
+          default: () =>
+            row.clusterMode == 3
+              ? clusterModeOptions[3] + '：' + row.nodeLabel
+              : clusterModeOptions[row.clusterMode] ?? clusterModeOptions[1],
+        }
+      );
+    },
+    width: 150,
+  },
   {
     title: '表达式',
     key: 'pattern',
     width: 150,
   },
+  {
+    title: '最近执行',
+    key: 'lastExecute',
+    render(row) {
+      if (!row.lastExecute) {
+        return '暂未执行';
+      }
+      return h(
+        NTag,
+        {
+          style: {
+            marginRight: '6px',
+          },
+          type: row.lastExecute.status == 3 ? 'warning' : row.lastExecute.err ? 'error' : 'success',
+          bordered: false,
+        },
+        {
+          default: () =>
+            row.lastExecute.node + ' ' + row.lastExecute.at + ' ' + row.lastExecute.took + 'ms',
+        }
+      );
+    },
+    width: 260,
+  },
+  {
+    title: '近7天成功率',
+    key: 'successRate',
+    render(row) {
+      if (!row.runCount) {
+        return '-';
+      }
+      return h(
+        NTag,
+        {
+          style: {
+            marginRight: '6px',
+          },
+          type: row.successRate >= 100 ? 'success' : row.successRate >= 80 ? 'warning' : 'error',
+          bordered: false,
+        },
+        {
+          default: () => row.successRate + '%（' + row.runCount + '次）',
+        }
+      );
+    },
+    width: 140,
+  },
   // {
   //   title: '执行次数',
   //   key: 'count',
@@ -0,0 +1,86 @@
+import { h } from 'vue';
+import { NTag } from 'naive-ui';
+
+export const logStatusOptions = [
+  {
+    value: 1,
+    label: '成功',
+    type: 'success',
+  },
+  {
+    value: 2,
+    label: '失败',
+    type: 'error',
+  },
+  {
+    value: 3,
+    label: '超时',
+    type: 'warning',
+  },
+];
+
+export function renderLogStatus(status) {
+  const option = logStatusOptions.find((item) => item.value == status);
+  return h(
+    NTag,
+    {
+      type: (option?.type ?? 'default') as any,
+      bordered: false,
+    },
+    {
+      default: () => option?.label ?? '未知',
+    }
+  );
+}
+
+export const columns = [
+  {
+    title: 'ID',
+    key: 'id',
+    width: 80,
+  },
+  {
+    title: '执行节点',
+    key: 'node',
+    width: 120,
+  },
+  {
+    title: '执行状态',
+    key: 'status',
+    render(row) {
+      return renderLogStatus(row.status);
+    },
+    width: 90,
+  },
+  {
+    title: '执行次数',
+    key: 'attempts',
+    width: 80,
+  },
+  {
+    title: '耗时',
+    key: 'took',
+    render(row) {
+      return row.took + 'ms';
+    },
+    width: 100,
+  },
+  {
+    title: '错误信息',
+    key: 'error',
+    ellipsis: {
+      tooltip: true,
+    },
+    width: 240,
+  },
+  {
+    title: '开始时间',
+    key: 'startedAt',
+    width: 180,
+  },
+  {
+    title: '结束时间',
+    key: 'finishedAt',
+    width: 180,
+  },
+];